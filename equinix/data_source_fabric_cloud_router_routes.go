@@ -0,0 +1,215 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// routeTableEntrySearchRequest is v4.RouteTableEntrySearchRequest with
+// filters, which are empty structs in fabric-go
+type routeTableEntrySearchRequest struct {
+	Filter     *routeTableEntryFilters          `json:"filter,omitempty"`
+	Pagination *v4.PaginationRequest            `json:"pagination,omitempty"`
+	Sort       []v4.RouteTableEntrySortCriteria `json:"sort,omitempty"`
+}
+
+type routeTableEntryFilters struct {
+	And []v4.RouteTableEntrySimpleExpression `json:"and"`
+}
+
+// routeTableEntryPageSize is the largest page of the route search
+const routeTableEntryPageSize = 100
+
+func dataSourceFabricCloudRouterRoutes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFabricCloudRouterRoutesRead,
+		Schema: map[string]*schema.Schema{
+			"cloud_router_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the Fabric Cloud Router",
+			},
+			"prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the routes to this prefix, e.g. 10.0.0.0/24",
+			},
+			"next_hop": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the routes with this next hop IP address",
+			},
+			"connection_uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the routes learned from the connection with this UUID",
+			},
+			"routes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Routes of the Fabric Cloud Router route table, sorted by prefix",
+				Elem: &schema.Resource{
+					Schema: readRouteTableEntrySch(),
+				},
+			},
+		},
+		Description: "Fabric V4 API compatible data resource that allow user to fetch the route table of a Fabric Cloud Router\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func readRouteTableEntrySch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Route type, e.g. IPv4_BGP_ROUTE",
+		},
+		"protocol_type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Protocol the route was learned with. One of BGP, STATIC, DIRECT",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Route state",
+		},
+		"age": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Age of the route",
+		},
+		"prefix": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Route prefix",
+		},
+		"next_hop": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Next hop IP address",
+		},
+		"metric": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Route metric",
+		},
+		"local_preference": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "BGP local preference",
+		},
+		"as_path": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "BGP AS path",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"connection_uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "UUID of the connection the route was learned from",
+		},
+		"connection_name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the connection the route was learned from",
+		},
+	}
+}
+
+func dataSourceFabricCloudRouterRoutesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	routerUuid := d.Get("cloud_router_uuid").(string)
+	var filters []v4.RouteTableEntrySimpleExpression
+	for _, f := range []struct{ attribute, property string }{
+		{"prefix", "/prefix"},
+		{"next_hop", "/nextHop"},
+		{"connection_uuid", "/connection/uuid"},
+	} {
+		if value := d.Get(f.attribute).(string); value != "" {
+			filters = append(filters, v4.RouteTableEntrySimpleExpression{Property: f.property, Operator: "=", Values: []string{value}})
+		}
+	}
+
+	routes, err := searchCloudRouterRoutes(ctx, meta, routerUuid, filters)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(routerUuid)
+	err = equinix_schema.SetMap(d, map[string]interface{}{
+		"routes": routeTableEntriesToTerra(routes),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// searchCloudRouterRoutes returns all routes of the cloud router which match
+// all filters, sorted by prefix
+func searchCloudRouterRoutes(ctx context.Context, meta interface{}, routerUuid string, filters []v4.RouteTableEntrySimpleExpression) ([]v4.RouteTableEntry, error) {
+	direction := v4.ASC_RouteTableEntrySortDirection
+	property := v4.PREFIX_RouteTableEntrySortBy
+	searchRequest := routeTableEntrySearchRequest{
+		Pagination: &v4.PaginationRequest{Limit: routeTableEntryPageSize},
+		Sort:       []v4.RouteTableEntrySortCriteria{{Direction: &direction, Property: &property}},
+	}
+	if len(filters) > 0 {
+		searchRequest.Filter = &routeTableEntryFilters{And: filters}
+	}
+
+	var routes []v4.RouteTableEntry
+	for {
+		page := v4.RouteTableEntrySearchResponse{}
+		path := fmt.Sprintf("/fabric/v4/routers/%s/routes/search", routerUuid)
+		if err := fabricRequest(ctx, meta, http.MethodPost, path, searchRequest, &page); err != nil {
+			return nil, err
+		}
+		routes = append(routes, page.Data...)
+		if page.Pagination == nil || len(page.Data) == 0 || int32(len(routes)) >= page.Pagination.Total {
+			return routes, nil
+		}
+		searchRequest.Pagination.Offset = int32(len(routes))
+	}
+}
+
+func routeTableEntriesToTerra(routes []v4.RouteTableEntry) []interface{} {
+	mappedRoutes := make([]interface{}, len(routes))
+	for i, route := range routes {
+		asPath := make([]interface{}, len(route.AsPath))
+		for j, as := range route.AsPath {
+			asPath[j] = int(as)
+		}
+		mappedRoute := map[string]interface{}{
+			"age":              route.Age,
+			"prefix":           route.Prefix,
+			"next_hop":         route.NextHop,
+			"metric":           int(route.Metric),
+			"local_preference": int(route.LocalPreference),
+			"as_path":          asPath,
+		}
+		if route.Type_ != nil {
+			mappedRoute["type"] = string(*route.Type_)
+		}
+		if route.ProtocolType != nil {
+			mappedRoute["protocol_type"] = string(*route.ProtocolType)
+		}
+		if route.State != nil {
+			mappedRoute["state"] = string(*route.State)
+		}
+		if route.Connection != nil {
+			mappedRoute["connection_uuid"] = route.Connection.Uuid
+			mappedRoute["connection_name"] = route.Connection.Name
+		}
+		mappedRoutes[i] = mappedRoute
+	}
+	return mappedRoutes
+}
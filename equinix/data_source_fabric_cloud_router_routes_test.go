@@ -0,0 +1,47 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCloudRouterUuid = "4d3c2b1a-0f9e-4d8c-b7a6-958473625140"
+
+const recordedCloudRouterRoutes = `{
+  "pagination": {"offset": 0, "limit": 100, "total": 4},
+  "data": [
+    {"type": "IPv4_BGP_ROUTE", "protocolType": "BGP", "state": "ACTIVE", "prefix": "10.1.0.0/16", "nextHop": "169.254.0.2", "asPath": [64512], "connection": {"uuid": "` + testRouteConnectionUuid + `", "name": "aws"}},
+    {"type": "IPv4_BGP_ROUTE", "protocolType": "BGP", "state": "ACTIVE", "prefix": "10.2.0.0/16", "nextHop": "169.254.0.2", "asPath": [64512], "connection": {"uuid": "` + testRouteConnectionUuid + `", "name": "aws"}},
+    {"type": "IPv4_DIRECT_ROUTE", "protocolType": "DIRECT", "state": "ACTIVE", "prefix": "169.254.0.0/30", "connection": {"uuid": "` + testRouteConnectionUuid + `", "name": "aws"}},
+    {"type": "IPv4_BGP_ROUTE", "protocolType": "BGP", "state": "ACTIVE", "prefix": "10.3.0.0/16", "nextHop": "169.254.1.2", "asPath": [64513, 64514], "connection": {"uuid": "0b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e", "name": "azure"}}
+  ]
+}`
+
+func TestDataSourceFabricCloudRouterRoutesRead(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/routers/" + testCloudRouterUuid + "/routes/search": recordedCloudRouterRoutes,
+	})
+
+	d := dataSourceFabricCloudRouterRoutes().TestResourceData()
+	d.Set("cloud_router_uuid", testCloudRouterUuid)
+	d.Set("next_hop", "169.254.0.2")
+	d.Set("connection_uuid", testRouteConnectionUuid)
+	diags := dataSourceFabricCloudRouterRoutesRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	body, _ := fake.request("POST /fabric/v4/routers/" + testCloudRouterUuid + "/routes/search")
+	assert.JSONEq(t, `{
+  "filter": {"and": [
+    {"property": "/nextHop", "operator": "=", "values": ["169.254.0.2"]},
+    {"property": "/connection/uuid", "operator": "=", "values": ["`+testRouteConnectionUuid+`"]}
+  ]},
+  "pagination": {"limit": 100},
+  "sort": [{"direction": "ASC", "property": "/prefix"}]
+}`, body)
+	assert.Equal(t, 4, d.Get("routes.#"))
+	assert.Equal(t, "10.1.0.0/16", d.Get("routes.0.prefix"))
+	assert.Equal(t, "aws", d.Get("routes.0.connection_name"))
+	assert.Equal(t, []interface{}{64513, 64514}, d.Get("routes.3.as_path"))
+}
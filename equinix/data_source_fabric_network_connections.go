@@ -0,0 +1,110 @@
+package equinix
+
+import (
+	"context"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceFabricNetworkConnections() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFabricNetworkConnectionsRead,
+		Schema: map[string]*schema.Schema{
+			"network_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the Fabric Network",
+			},
+			"connections": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Connections attached to the Fabric Network",
+				Elem: &schema.Resource{
+					Schema: readNetworkConnectionSch(),
+				},
+			},
+		},
+		Description: "Fabric V4 API compatible data resource that allow user to fetch the connections attached to a Fabric Network\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func readNetworkConnectionSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned connection identifier",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Connection name",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Connection type, e.g. EVPLAN_VC or EPLAN_VC",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Connection overall state",
+		},
+		"bandwidth": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Connection bandwidth in Mbps",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Connection URI information",
+		},
+	}
+}
+
+func dataSourceFabricNetworkConnectionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	networkUuid := d.Get("network_uuid").(string)
+	networkConnections, _, err := client.NetworksApi.GetConnectionsByNetworkUuid(ctx, networkUuid)
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	d.SetId(networkUuid)
+	err = equinix_schema.SetMap(d, map[string]interface{}{
+		"connections": networkConnectionsToTerra(networkConnections.Data),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func networkConnectionsToTerra(connections []v4.Connection) []interface{} {
+	mappedConnections := make([]interface{}, len(connections))
+	for i, connection := range connections {
+		mappedConnection := map[string]interface{}{
+			"uuid":      connection.Uuid,
+			"name":      connection.Name,
+			"bandwidth": int(connection.Bandwidth),
+			"href":      connection.Href,
+		}
+		if connection.Type_ != nil {
+			mappedConnection["type"] = string(*connection.Type_)
+		}
+		if connection.State != nil {
+			mappedConnection["state"] = string(*connection.State)
+		}
+		mappedConnections[i] = mappedConnection
+	}
+	return mappedConnections
+}
@@ -18,7 +18,7 @@ func dataSourceFabricPort() *schema.Resource {
 func dataSourceFabricPortRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	uuid, _ := d.Get("uuid").(string)
 	d.SetId(uuid)
-	return resourceFabricPortRead(ctx, d, meta)
+	return readFabricPortByUuid(ctx, d, meta)
 }
 
 func dataSourceFabricGetPortsByName() *schema.Resource {
@@ -0,0 +1,419 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/hashcode"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceFabricPrice() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFabricPriceRead,
+		Schema:      createFabricPriceSch(),
+		Description: "Fabric V4 API compatible data resource that allow user to fetch the price of a Fabric connection, Cloud Router package or port before ordering or changing it\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func createPriceLocationSch() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Location of the priced product",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"metro_code": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Metro code, e.g. SV",
+				},
+			},
+		},
+	}
+}
+
+func createPriceConnectionSideSch(withProfile bool) map[string]*schema.Schema {
+	accessPoint := map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"COLO", "VD", "SP", "CLOUD_ROUTER", "NETWORK"}, false),
+			Description:  "Access point type - COLO, VD, SP, CLOUD_ROUTER, NETWORK",
+		},
+		"location": createPriceLocationSch(),
+	}
+	if withProfile {
+		accessPoint["profile"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Service profile of the service provider access point",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"uuid": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Equinix-assigned service profile identifier",
+					},
+				},
+			},
+		}
+	}
+	return map[string]*schema.Schema{
+		"access_point": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Point of access details",
+			Elem: &schema.Resource{
+				Schema: accessPoint,
+			},
+		},
+	}
+}
+
+func createPriceConnectionSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"uuid": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Equinix-assigned identifier of an existing connection, instead of the other attributes",
+		},
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"EVPL_VC", "EPL_VC", "IP_VC", "EVPLAN_VC", "EPLAN_VC"}, false),
+			Description:  "Connection type - EVPL_VC, EPL_VC, IP_VC, EVPLAN_VC, EPLAN_VC",
+		},
+		"bandwidth": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Connection bandwidth in Mbps",
+		},
+		"a_side": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Requester or Customer side of the connection",
+			Elem: &schema.Resource{
+				Schema: createPriceConnectionSideSch(false),
+			},
+		},
+		"z_side": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Destination or Provider side of the connection",
+			Elem: &schema.Resource{
+				Schema: createPriceConnectionSideSch(true),
+			},
+		},
+	}
+}
+
+func createPriceRouterSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"package": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Cloud Router package",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"code": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"LAB", "BASIC", "STANDARD", "ADVANCED", "PREMIUM"}, false),
+						Description:  "Cloud Router package code - LAB, BASIC, STANDARD, ADVANCED, PREMIUM",
+					},
+				},
+			},
+		},
+		"location": createPriceLocationSch(),
+	}
+}
+
+func createPricePortSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "XF_PORT",
+			ValidateFunc: validation.StringInSlice([]string{"XF_PORT", "IX_PORT"}, false),
+			Description:  "Port type - XF_PORT, IX_PORT",
+		},
+		"physical_ports_speed": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Speed of the physical ports in Mbps, e.g. 1000, 10000, 100000",
+		},
+		"physical_ports_count": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Default:     1,
+			Description: "Number of physical ports",
+		},
+		"connectivity_source_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"COLO", "BMMR", "REMOTE"}, false),
+			Description:  "Port connectivity type - COLO, BMMR, REMOTE",
+		},
+		"lag_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Price the port as a Link Aggregation Group",
+		},
+		"location": createPriceLocationSch(),
+	}
+}
+
+func readPriceSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"code": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned product code",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Full product name",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Product description",
+		},
+		"currency": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Price currency, e.g. USD",
+		},
+		"term_length": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Term length in months. 0 means unlimited",
+		},
+		"monthly_price": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Sum of the monthly recurring charges",
+		},
+		"charges": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Charges of the product",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Charge type, e.g. MONTHLY_RECURRING, NON_RECURRING",
+					},
+					"price": {
+						Type:        schema.TypeFloat,
+						Computed:    true,
+						Description: "Charge amount",
+					},
+				},
+			},
+		},
+	}
+}
+
+func createFabricPriceSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"VIRTUAL_CONNECTION_PRODUCT", "CLOUD_ROUTER_PRODUCT", "VIRTUAL_PORT_PRODUCT"}, false),
+			Description:  "Product type - VIRTUAL_CONNECTION_PRODUCT, CLOUD_ROUTER_PRODUCT, VIRTUAL_PORT_PRODUCT",
+		},
+		"virtual_connection": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{"router", "port"},
+			Description:   "Connection to price, with the attributes of the equinix_fabric_connection resource",
+			Elem: &schema.Resource{
+				Schema: createPriceConnectionSch(),
+			},
+		},
+		"router": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{"virtual_connection", "port"},
+			Description:   "Cloud Router package to price",
+			Elem: &schema.Resource{
+				Schema: createPriceRouterSch(),
+			},
+		},
+		"port": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: []string{"virtual_connection", "router"},
+			Description:   "Port to price, with the attributes of the equinix_fabric_port resource",
+			Elem: &schema.Resource{
+				Schema: createPricePortSch(),
+			},
+		},
+		"warning_threshold": {
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			ValidateFunc: validation.FloatAtLeast(0),
+			Description:  "Monthly price above which reading the data source, e.g. during plan, shows a warning",
+		},
+		"prices": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Prices matching the product",
+			Elem: &schema.Resource{
+				Schema: readPriceSch(),
+			},
+		},
+	}
+}
+
+func dataSourceFabricPriceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	filters := priceSearchFilters(d)
+	prices, _, err := client.PricesApi.SearchPrices(ctx, v4.FilterBody{Filter: &v4.SearchExpression{And: &filters}})
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	var id strings.Builder
+	for _, filter := range filters {
+		id.WriteString(fmt.Sprintf("%s=%s-", filter.Property, strings.Join(filter.Values, ",")))
+	}
+	d.SetId(strconv.Itoa(hashcode.String(id.String())))
+	err = equinix_schema.SetMap(d, map[string]interface{}{
+		"prices": pricesToTerra(prices.Data),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return priceWarningDiags(d.Get("warning_threshold").(float64), prices.Data)
+}
+
+// priceSearchFilters translates the product attributes to the price search
+// filters. Attributes left unset are not filtered on
+func priceSearchFilters(d *schema.ResourceData) []v4.SearchExpression {
+	filters := []v4.SearchExpression{
+		{Property: "/type", Operator: "=", Values: []string{d.Get("type").(string)}},
+	}
+	addFilter := func(property string, value interface{}) {
+		values := fmt.Sprint(value)
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				return
+			}
+		case int:
+			if v == 0 {
+				return
+			}
+		}
+		filters = append(filters, v4.SearchExpression{Property: property, Operator: "=", Values: []string{values}})
+	}
+
+	if d.Get("virtual_connection.#").(int) > 0 {
+		addFilter("/connection/uuid", d.Get("virtual_connection.0.uuid"))
+		addFilter("/connection/type", d.Get("virtual_connection.0.type"))
+		addFilter("/connection/bandwidth", d.Get("virtual_connection.0.bandwidth"))
+		for _, side := range []struct{ key, path string }{{"a_side", "aSide"}, {"z_side", "zSide"}} {
+			accessPoint := fmt.Sprintf("virtual_connection.0.%s.0.access_point.0.", side.key)
+			property := fmt.Sprintf("/connection/%s/accessPoint/", side.path)
+			addFilter(property+"type", d.Get(accessPoint+"type"))
+			addFilter(property+"location/metroCode", d.Get(accessPoint+"location.0.metro_code"))
+			if side.key == "z_side" {
+				addFilter(property+"profile/uuid", d.Get(accessPoint+"profile.0.uuid"))
+			}
+		}
+	}
+	if d.Get("router.#").(int) > 0 {
+		addFilter("/router/package/code", d.Get("router.0.package.0.code"))
+		addFilter("/router/location/metroCode", d.Get("router.0.location.0.metro_code"))
+	}
+	if d.Get("port.#").(int) > 0 {
+		count := d.Get("port.0.physical_ports_count").(int)
+		addFilter("/port/type", d.Get("port.0.type"))
+		addFilter("/port/bandwidth", d.Get("port.0.physical_ports_speed").(int)*count)
+		addFilter("/port/physicalPortsQuantity", count)
+		addFilter("/port/connectivitySource/type", d.Get("port.0.connectivity_source_type"))
+		if d.Get("port.0.lag_enabled").(bool) {
+			addFilter("/port/lag/enabled", "true")
+		}
+		addFilter("/port/location/metroCode", d.Get("port.0.location.0.metro_code"))
+	}
+	return filters
+}
+
+// priceMonthly sums the monthly recurring charges of a price
+func priceMonthly(price v4.Price) float64 {
+	monthly := 0.0
+	for _, charge := range price.Charges {
+		if charge.Type_ == "MONTHLY_RECURRING" {
+			monthly += charge.Price
+		}
+	}
+	return monthly
+}
+
+// priceWarningDiags warns about the prices above the threshold, so that the
+// billing impact of a change shows in the plan
+func priceWarningDiags(threshold float64, prices []v4.Price) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if threshold <= 0 {
+		return diags
+	}
+	for _, price := range prices {
+		if monthly := priceMonthly(price); monthly > threshold {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%s costs %.2f %s per month", price.Name, monthly, price.Currency),
+				Detail:   fmt.Sprintf("The monthly price of product %s is above the warning threshold of %.2f", price.Code, threshold),
+			})
+		}
+	}
+	return diags
+}
+
+func pricesToTerra(prices []v4.Price) []interface{} {
+	mappedPrices := make([]interface{}, len(prices))
+	for i, price := range prices {
+		charges := make([]interface{}, len(price.Charges))
+		for j, charge := range price.Charges {
+			charges[j] = map[string]interface{}{
+				"type":  charge.Type_,
+				"price": charge.Price,
+			}
+		}
+		mappedPrices[i] = map[string]interface{}{
+			"code":          price.Code,
+			"name":          price.Name,
+			"description":   price.Description,
+			"currency":      price.Currency,
+			"term_length":   int(price.TermLength),
+			"monthly_price": priceMonthly(price),
+			"charges":       charges,
+		}
+	}
+	return mappedPrices
+}
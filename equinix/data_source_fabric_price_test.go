@@ -0,0 +1,119 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedConnectionPrices = `{
+  "pagination": {"offset": 0, "limit": 20, "total": 1},
+  "data": [{
+    "type": "VIRTUAL_CONNECTION_PRODUCT",
+    "code": "VC-SV-SV-1000",
+    "name": "Fabric Virtual Connection - 1 Gbps",
+    "currency": "USD",
+    "termLength": 1,
+    "charges": [
+      {"type": "MONTHLY_RECURRING", "price": 1065.0},
+      {"type": "NON_RECURRING", "price": 0}
+    ],
+    "connection": {"type": "EVPL_VC", "bandwidth": 1000}
+  }]
+}`
+
+func testPriceConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "VIRTUAL_CONNECTION_PRODUCT",
+		"virtual_connection": []interface{}{map[string]interface{}{
+			"type":      "EVPL_VC",
+			"bandwidth": 1000,
+			"a_side": []interface{}{map[string]interface{}{
+				"access_point": []interface{}{map[string]interface{}{
+					"type":     "COLO",
+					"location": []interface{}{map[string]interface{}{"metro_code": "SV"}},
+				}},
+			}},
+			"z_side": []interface{}{map[string]interface{}{
+				"access_point": []interface{}{map[string]interface{}{
+					"type":     "SP",
+					"location": []interface{}{map[string]interface{}{"metro_code": "SV"}},
+					"profile":  []interface{}{map[string]interface{}{"uuid": "69ee618d-be52-468d-bc99-00566f2dd2b9"}},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestDataSourceFabricPriceRead(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/prices/search": recordedConnectionPrices,
+	})
+
+	d := schema.TestResourceDataRaw(t, createFabricPriceSch(), testPriceConfig())
+	diags := dataSourceFabricPriceRead(context.Background(), d, meta)
+	assert.Empty(t, diags, "no warning without a threshold")
+
+	body, _ := fake.request("POST /fabric/v4/prices/search")
+	assert.JSONEq(t, `{
+  "filter": {"and": [
+    {"property": "/type", "operator": "=", "values": ["VIRTUAL_CONNECTION_PRODUCT"]},
+    {"property": "/connection/type", "operator": "=", "values": ["EVPL_VC"]},
+    {"property": "/connection/bandwidth", "operator": "=", "values": ["1000"]},
+    {"property": "/connection/aSide/accessPoint/type", "operator": "=", "values": ["COLO"]},
+    {"property": "/connection/aSide/accessPoint/location/metroCode", "operator": "=", "values": ["SV"]},
+    {"property": "/connection/zSide/accessPoint/type", "operator": "=", "values": ["SP"]},
+    {"property": "/connection/zSide/accessPoint/location/metroCode", "operator": "=", "values": ["SV"]},
+    {"property": "/connection/zSide/accessPoint/profile/uuid", "operator": "=", "values": ["69ee618d-be52-468d-bc99-00566f2dd2b9"]}
+  ]}
+}`, body)
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, 1, d.Get("prices.#"))
+	assert.Equal(t, "VC-SV-SV-1000", d.Get("prices.0.code"))
+	assert.Equal(t, 1065.0, d.Get("prices.0.monthly_price"))
+	assert.Equal(t, 2, d.Get("prices.0.charges.#"))
+}
+
+func TestDataSourceFabricPriceWarning(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/prices/search": recordedConnectionPrices,
+	})
+
+	config := testPriceConfig()
+	config["warning_threshold"] = 1000.0
+	d := schema.TestResourceDataRaw(t, createFabricPriceSch(), config)
+	diags := dataSourceFabricPriceRead(context.Background(), d, meta)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "Fabric Virtual Connection - 1 Gbps costs 1065.00 USD per month", diags[0].Summary)
+	}
+
+	config["warning_threshold"] = 2000.0
+	d = schema.TestResourceDataRaw(t, createFabricPriceSch(), config)
+	assert.Empty(t, dataSourceFabricPriceRead(context.Background(), d, meta))
+}
+
+func TestDataSourceFabricPricePortFilters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricPriceSch(), map[string]interface{}{
+		"type": "VIRTUAL_PORT_PRODUCT",
+		"port": []interface{}{map[string]interface{}{
+			"physical_ports_speed": 10000,
+			"physical_ports_count": 2,
+			"lag_enabled":          true,
+			"location":             []interface{}{map[string]interface{}{"metro_code": "SV"}},
+		}},
+	})
+
+	filters := map[string][]string{}
+	for _, filter := range priceSearchFilters(d) {
+		filters[filter.Property] = filter.Values
+	}
+	assert.Equal(t, []string{"XF_PORT"}, filters["/port/type"])
+	assert.Equal(t, []string{"20000"}, filters["/port/bandwidth"], "the port bandwidth aggregates its physical ports")
+	assert.Equal(t, []string{"2"}, filters["/port/physicalPortsQuantity"])
+	assert.Equal(t, []string{"true"}, filters["/port/lag/enabled"])
+	assert.NotContains(t, filters, "/port/connectivitySource/type")
+}
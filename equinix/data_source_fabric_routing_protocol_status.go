@@ -0,0 +1,244 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// routingProtocolStatus holds the operational data of a routing protocol,
+// which fabric-go does not decode
+type routingProtocolStatus struct {
+	Type      string                          `json:"type"`
+	State     string                          `json:"state"`
+	BgpIpv4   *routingProtocolSessionStatus   `json:"bgpIpv4"`
+	BgpIpv6   *routingProtocolSessionStatus   `json:"bgpIpv6"`
+	Bfd       *v4.RoutingProtocolBfd          `json:"bfd"`
+	Operation *routingProtocolOperationStatus `json:"operation"`
+}
+
+type routingProtocolSessionStatus struct {
+	Enabled   bool                            `json:"enabled"`
+	Operation *routingProtocolOperationStatus `json:"operation"`
+}
+
+type routingProtocolOperationStatus struct {
+	OperationalStatus string          `json:"operationalStatus"`
+	OpStatusChangedAt string          `json:"opStatusChangedAt"`
+	Errors            []v4.ModelError `json:"errors"`
+}
+
+func (s *routingProtocolSessionStatus) status() string {
+	if s == nil || s.Operation == nil {
+		return ""
+	}
+	return s.Operation.OperationalStatus
+}
+
+func dataSourceFabricRoutingProtocolStatus() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFabricRoutingProtocolStatusRead,
+		Schema: map[string]*schema.Schema{
+			"connection_uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the connection of the routing protocol",
+			},
+			"uuid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the routing protocol",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Routing protocol type. One of BGP, DIRECT",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Provisioning state of the routing protocol",
+			},
+			"operational_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operational status of the routing protocol, e.g. UP or DOWN",
+			},
+			"op_status_changed_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the operational status last changed",
+			},
+			"bgp_ipv4_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Admin status of the IPv4 BGP session",
+			},
+			"bgp_ipv4_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operational status of the IPv4 BGP session, e.g. UP or DOWN",
+			},
+			"bgp_ipv6_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Admin status of the IPv6 BGP session",
+			},
+			"bgp_ipv6_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operational status of the IPv6 BGP session, e.g. UP or DOWN",
+			},
+			"bfd_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Bidirectional Forwarding Detection enablement",
+			},
+			"bfd_interval": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Interval range between the received BFD control packets",
+			},
+			"errors": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Messages of the operational errors of the routing protocol",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cloud_router_uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the Fabric Cloud Router of the connection",
+			},
+			"received_prefix_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of BGP routes the Fabric Cloud Router learned from the connection",
+			},
+			"advertised_prefix_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of routes the Fabric Cloud Router advertises to the connection",
+			},
+		},
+		Description: "Fabric V4 API compatible data resource that allow user to fetch the operational status of a routing protocol, e.g. to check that BGP is up after an apply\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func dataSourceFabricRoutingProtocolStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	connUuid := d.Get("connection_uuid").(string)
+	uuid := d.Get("uuid").(string)
+
+	rp := routingProtocolStatus{}
+	if err := fabricRequest(ctx, meta, http.MethodGet, fmt.Sprintf("/fabric/v4/connections/%s/routingProtocols/%s", connUuid, uuid), nil, &rp); err != nil {
+		return diag.FromErr(err)
+	}
+
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	conn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, connUuid, nil)
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	routerUuid := connectionCloudRouterUuid(conn)
+	received, advertised := 0, 0
+	if routerUuid != "" {
+		routes, err := searchCloudRouterRoutes(ctx, meta, routerUuid, nil)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		received = countReceivedPrefixes(routes, connUuid)
+		if advertised, err = countAdvertisedPrefixes(ctx, meta, connUuid); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	m := map[string]interface{}{
+		"type":                    rp.Type,
+		"state":                   rp.State,
+		"bgp_ipv4_status":         rp.BgpIpv4.status(),
+		"bgp_ipv6_status":         rp.BgpIpv6.status(),
+		"cloud_router_uuid":       routerUuid,
+		"received_prefix_count":   received,
+		"advertised_prefix_count": advertised,
+	}
+	if rp.Operation != nil {
+		m["operational_status"] = rp.Operation.OperationalStatus
+		m["op_status_changed_at"] = rp.Operation.OpStatusChangedAt
+		var errs []string
+		for _, e := range rp.Operation.Errors {
+			errs = append(errs, strings.TrimSpace(fmt.Sprintf("%s %s", e.ErrorCode, e.ErrorMessage)))
+		}
+		m["errors"] = errs
+	}
+	if rp.BgpIpv4 != nil {
+		m["bgp_ipv4_enabled"] = rp.BgpIpv4.Enabled
+	}
+	if rp.BgpIpv6 != nil {
+		m["bgp_ipv6_enabled"] = rp.BgpIpv6.Enabled
+	}
+	if rp.Bfd != nil {
+		m["bfd_enabled"] = rp.Bfd.Enabled
+		m["bfd_interval"] = rp.Bfd.Interval
+	}
+	d.SetId(uuid)
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// connectionCloudRouterUuid returns the UUID of the cloud router at either
+// side of the connection
+func connectionCloudRouterUuid(conn v4.Connection) string {
+	for _, side := range []*v4.ConnectionSide{conn.ASide, conn.ZSide} {
+		if side != nil && side.AccessPoint != nil && side.AccessPoint.Router != nil {
+			return side.AccessPoint.Router.Uuid
+		}
+	}
+	return ""
+}
+
+// countAdvertisedPrefixes counts the routes a Fabric Cloud Router advertises
+// to the connection. fabric-go has no connection route search, so the
+// advertised routes are searched with a single result per page and counted
+// from the pagination total.
+func countAdvertisedPrefixes(ctx context.Context, meta interface{}, connUuid string) (int, error) {
+	searchRequest := map[string]interface{}{
+		"pagination": v4.PaginationRequest{Limit: 1},
+	}
+	page := struct {
+		Pagination *v4.Pagination `json:"pagination"`
+	}{}
+	path := fmt.Sprintf("/fabric/v4/connections/%s/advertisedRoutes/search", connUuid)
+	if err := fabricRequest(ctx, meta, http.MethodPost, path, searchRequest, &page); err != nil {
+		return 0, err
+	}
+	if page.Pagination == nil {
+		return 0, nil
+	}
+	return int(page.Pagination.Total), nil
+}
+
+// countReceivedPrefixes counts the BGP routes of the route table learned
+// from the connection
+func countReceivedPrefixes(routes []v4.RouteTableEntry, connUuid string) int {
+	received := 0
+	for _, route := range routes {
+		fromConnection := route.Connection != nil && route.Connection.Uuid == connUuid
+		if fromConnection && route.ProtocolType != nil && *route.ProtocolType == v4.BGP_RouteTableEntryProtocolType {
+			received++
+		}
+	}
+	return received
+}
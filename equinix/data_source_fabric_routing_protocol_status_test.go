@@ -0,0 +1,51 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRoutingProtocolUuid = "7e6d5c4b-3a29-4180-9f7e-6d5c4b3a2910"
+
+func TestDataSourceFabricRoutingProtocolStatusRead(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/connections/" + testRouteConnectionUuid + "/routingProtocols/" + testRoutingProtocolUuid: `{
+  "type": "BGP",
+  "uuid": "` + testRoutingProtocolUuid + `",
+  "state": "PROVISIONED",
+  "bgpIpv4": {"customerPeerIp": "169.254.0.2", "enabled": true, "operation": {"operationalStatus": "UP", "opStatusChangedAt": "2023-06-01T10:00:00Z"}},
+  "bfd": {"enabled": true, "interval": "100"},
+  "operation": {"operationalStatus": "UP", "opStatusChangedAt": "2023-06-01T10:00:00Z"}
+}`,
+		"GET /fabric/v4/connections/" + testRouteConnectionUuid: `{
+  "uuid": "` + testRouteConnectionUuid + `",
+  "aSide": {"accessPoint": {"type": "CLOUD_ROUTER", "router": {"uuid": "` + testCloudRouterUuid + `"}}}
+}`,
+		"POST /fabric/v4/routers/" + testCloudRouterUuid + "/routes/search": recordedCloudRouterRoutes,
+		"POST /fabric/v4/connections/" + testRouteConnectionUuid + "/advertisedRoutes/search": `{
+  "pagination": {"offset": 0, "limit": 1, "total": 3},
+  "data": [{"type": "IPv4_BGP_ROUTE", "prefix": "10.10.0.0/16", "state": "ACTIVE"}]
+}`,
+	})
+
+	d := dataSourceFabricRoutingProtocolStatus().TestResourceData()
+	d.Set("connection_uuid", testRouteConnectionUuid)
+	d.Set("uuid", testRoutingProtocolUuid)
+	diags := dataSourceFabricRoutingProtocolStatusRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, "UP", d.Get("bgp_ipv4_status"))
+	assert.Equal(t, true, d.Get("bgp_ipv4_enabled"))
+	assert.Equal(t, "", d.Get("bgp_ipv6_status"))
+	assert.Equal(t, "UP", d.Get("operational_status"))
+	assert.Equal(t, true, d.Get("bfd_enabled"))
+	assert.Equal(t, "100", d.Get("bfd_interval"))
+	assert.Equal(t, testCloudRouterUuid, d.Get("cloud_router_uuid"))
+	assert.Equal(t, 2, d.Get("received_prefix_count"), "the direct route is not received over BGP")
+	assert.Equal(t, 3, d.Get("advertised_prefix_count"))
+
+	body, _ := fake.request("POST /fabric/v4/connections/" + testRouteConnectionUuid + "/advertisedRoutes/search")
+	assert.JSONEq(t, `{"pagination": {"limit": 1}}`, body)
+}
@@ -1,11 +1,13 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func serviceTokenSchema() *schema.Resource {
@@ -94,7 +96,7 @@ func dataSourceMetalConnection() *schema.Resource {
 		speeds = append(speeds, allowedSpeed.Str)
 	}
 	return &schema.Resource{
-		Read: dataSourceMetalConnectionRead,
+		ReadContext: dataSourceMetalConnectionRead,
 
 		Schema: map[string]*schema.Schema{
 			"connection_id": {
@@ -202,11 +204,11 @@ func dataSourceMetalConnection() *schema.Resource {
 	}
 }
 
-func getConnectionPorts(cps []packngo.ConnectionPort) []map[string]interface{} {
+func getConnectionPorts(cps []metalConnectionPort) []map[string]interface{} {
 	ret := make([]map[string]interface{}, len(cps))
-	order := map[packngo.ConnectionPortRole]int{
-		packngo.ConnectionPortPrimary:   0,
-		packngo.ConnectionPortSecondary: 1,
+	order := map[string]int{
+		string(metalv1.INTERCONNECTIONPORTROLE_PRIMARY):   0,
+		string(metalv1.INTERCONNECTIONPORTROLE_SECONDARY): 1,
 	}
 
 	for _, p := range cps {
@@ -217,7 +219,7 @@ func getConnectionPorts(cps []packngo.ConnectionPort) []map[string]interface{} {
 		connPort := map[string]interface{}{
 			"name":                p.Name,
 			"id":                  p.ID,
-			"role":                string(p.Role),
+			"role":                p.Role,
 			"speed":               p.Speed,
 			"status":              p.Status,
 			"link_status":         p.LinkStatus,
@@ -229,13 +231,13 @@ func getConnectionPorts(cps []packngo.ConnectionPort) []map[string]interface{} {
 	return ret
 }
 
-func getConnectionVlans(conn *packngo.Connection) []int {
+func getConnectionVlans(conn *metalConnection) []int {
 	var ret []int
 
-	if conn.Type == packngo.ConnectionShared {
-		order := map[packngo.ConnectionPortRole]int{
-			packngo.ConnectionPortPrimary:   0,
-			packngo.ConnectionPortSecondary: 1,
+	if conn.Type == string(metalv1.INTERCONNECTIONTYPE_SHARED) {
+		order := map[string]int{
+			string(metalv1.INTERCONNECTIONPORTROLE_PRIMARY):   0,
+			string(metalv1.INTERCONNECTIONPORTROLE_SECONDARY): 1,
 		}
 
 		rawVlans := make([]int, len(conn.Ports))
@@ -253,13 +255,13 @@ func getConnectionVlans(conn *packngo.Connection) []int {
 	return ret
 }
 
-func dataSourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceMetalConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	connId := d.Get("connection_id").(string)
 	d.SetId(connId)
-	return resourceMetalConnectionRead(d, meta)
+	return resourceMetalConnectionRead(ctx, d, meta)
 }
 
-func getServiceTokens(tokens []packngo.FabricServiceToken) ([]map[string]interface{}, error) {
+func getServiceTokens(tokens []metalConnectionServiceToken) ([]map[string]interface{}, error) {
 	tokenList := []map[string]interface{}{}
 	for _, token := range tokens {
 		speed, err := speedUintToStr(token.MaxAllowedSpeed)
@@ -270,9 +272,9 @@ func getServiceTokens(tokens []packngo.FabricServiceToken) ([]map[string]interfa
 		rawToken := map[string]interface{}{
 			"id":                token.ID,
 			"max_allowed_speed": speed,
-			"role":              string(token.Role),
+			"role":              token.Role,
 			"state":             token.State,
-			"type":              string(token.ServiceTokenType),
+			"type":              token.ServiceTokenType,
 		}
 		if token.ExpiresAt != nil {
 			rawToken["expires_at"] = token.ExpiresAt.String()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"path"
 	"sort"
 	"strings"
@@ -207,12 +208,17 @@ func dataSourceMetalDevice() *schema.Resource {
 				Description: "The hostname to use for [Serial over SSH](https://deploy.equinix.com/developers/docs/metal/resilience-recovery/serial-over-ssh/) access to the device",
 				Computed:    true,
 			},
+			"last_modified_by": {
+				Type:        schema.TypeString,
+				Description: "The email, name or id of the user or API key which made the latest change to the device, according to its events",
+				Computed:    true,
+			},
 		},
 	}
 }
 
 func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	hostnameRaw, hostnameOK := d.GetOk("hostname")
 	projectIdRaw, projectIdOK := d.GetOk("project_id")
@@ -280,12 +286,7 @@ func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta
 	if device.HardwareReservation != nil {
 		d.Set("hardware_reservation_id", device.HardwareReservation.GetId())
 	}
-	networkType, err := getNetworkType(device)
-	if err != nil {
-		return diag.Errorf("[ERR] Error computing network type for device (%s): %s", d.Id(), err)
-	}
-
-	d.Set("network_type", networkType)
+	d.Set("network_type", deviceNetworkType(device))
 
 	d.Set("tags", device.Tags)
 
@@ -312,6 +313,12 @@ func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta
 	ports := getPorts(device.NetworkPorts)
 	d.Set("ports", ports)
 
+	lastModifiedBy, err := metalLastModifiedBy(deviceEventsPager(ctx, client, device.GetId()), "")
+	if err != nil {
+		log.Printf("[WARN] Could not read the events of device %s, last_modified_by is not set: %s", device.GetId(), err)
+	}
+	d.Set("last_modified_by", lastModifiedBy)
+
 	d.SetId(device.GetId())
 	return nil
 }
@@ -14,6 +14,8 @@ import (
 func dataSourceMetalDevices() *schema.Resource {
 	dsmd := dataSourceMetalDevice()
 	sch := dsmd.Schema
+	// reading the events of every device is too slow for a list
+	delete(sch, "last_modified_by")
 	for _, v := range sch {
 		if v.Optional {
 			v.Optional = false
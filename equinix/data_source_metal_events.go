@@ -0,0 +1,330 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/equinix/terraform-provider-equinix/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceMetalEvents() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		RecordSchema:               metalEventSchema(),
+		ResultAttributeName:        "events",
+		ResultAttributeDescription: "List of events that match specified filters, newest first",
+		FlattenRecord:              flattenMetalEvent,
+		GetRecords:                 getMetalEvents,
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"organization_id": {
+				Type:          schema.TypeString,
+				Description:   "The id of the organization to query for events",
+				Optional:      true,
+				ConflictsWith: []string{"project_id"},
+			},
+			"project_id": {
+				Type:          schema.TypeString,
+				Description:   "The id of the project to query for events",
+				Optional:      true,
+				ConflictsWith: []string{"organization_id"},
+			},
+			"resource_id": {
+				Type:        schema.TypeString,
+				Description: "Only events of the resource with this id are returned. Events of the devices are read from the device, events of other resources are looked up in the events of the organization or project, or in the events of the current user",
+				Optional:    true,
+			},
+			"created_after": {
+				Type:         schema.TypeString,
+				Description:  "Only events created after this RFC 3339 timestamp are returned",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"created_before": {
+				Type:         schema.TypeString,
+				Description:  "Only events created before this RFC 3339 timestamp are returned",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+		},
+	}
+	return datalist.NewResource(dataListConfig)
+}
+
+func metalEventSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "The id of the event",
+			Computed:    true,
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Description: "The type of the event, e.g. instance.updated",
+			Computed:    true,
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Description: "The state of the event",
+			Computed:    true,
+		},
+		"body": {
+			Type:        schema.TypeString,
+			Description: "The message of the event",
+			Computed:    true,
+		},
+		"interpolated": {
+			Type:        schema.TypeString,
+			Description: "The message of the event, with the names of the related resources",
+			Computed:    true,
+		},
+		"created_at": {
+			Type:        schema.TypeString,
+			Description: "The timestamp for when the event was created",
+			Computed:    true,
+		},
+		"ip": {
+			Type:        schema.TypeString,
+			Description: "The IP address the change was made from",
+			Computed:    true,
+		},
+		"resource_ids": {
+			Type:        schema.TypeList,
+			Description: "The ids of the resources the event relates to",
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"actor_id": {
+			Type:        schema.TypeString,
+			Description: "The id of the user or API key which made the change",
+			Computed:    true,
+		},
+		"actor_email": {
+			Type:        schema.TypeString,
+			Description: "The email of the user which made the change",
+			Computed:    true,
+		},
+		"actor_name": {
+			Type:        schema.TypeString,
+			Description: "The name of the user which made the change",
+			Computed:    true,
+		},
+	}
+}
+
+// metalEventPager reads one page of events. The Equinix Metal API lists
+// events newest first.
+type metalEventPager func(page int32) (*metalv1.EventList, *http.Response, error)
+
+func metalEventsPager(ctx context.Context, client *metalv1.APIClient, organizationID, projectID string) metalEventPager {
+	switch {
+	case projectID != "":
+		return func(page int32) (*metalv1.EventList, *http.Response, error) {
+			return client.EventsApi.FindProjectEvents(ctx, projectID).Page(page).Execute()
+		}
+	case organizationID != "":
+		return func(page int32) (*metalv1.EventList, *http.Response, error) {
+			return client.EventsApi.FindOrganizationEvents(ctx, organizationID).Page(page).Execute()
+		}
+	default:
+		return func(page int32) (*metalv1.EventList, *http.Response, error) {
+			return client.EventsApi.FindEvents(ctx).Page(page).Execute()
+		}
+	}
+}
+
+func deviceEventsPager(ctx context.Context, client *metalv1.APIClient, deviceID string) metalEventPager {
+	return func(page int32) (*metalv1.EventList, *http.Response, error) {
+		return client.EventsApi.FindDeviceEvents(ctx, deviceID).Page(page).Execute()
+	}
+}
+
+// listMetalEvents reads the events page by page and returns the events keep
+// includes. It stops reading at the first event keep returns next=false for.
+func listMetalEvents(pager metalEventPager, keep func(metalv1.Event) (include, next bool)) ([]metalv1.Event, error) {
+	var events []metalv1.Event
+	for page := int32(1); ; page++ {
+		list, resp, err := pager(page)
+		if err != nil {
+			return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		for _, e := range list.GetEvents() {
+			include, next := keep(e)
+			if include {
+				events = append(events, e)
+			}
+			if !next {
+				return events, nil
+			}
+		}
+		if list.Meta == nil || list.Meta.GetLastPage() <= page {
+			return events, nil
+		}
+	}
+}
+
+func getMetalEvents(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	client := meta.(*config.Config).Metalgo
+	ctx := context.Background()
+	organizationID := extra["organization_id"].(string)
+	projectID := extra["project_id"].(string)
+	resourceID := extra["resource_id"].(string)
+
+	var after, before time.Time
+	if v := extra["created_after"].(string); v != "" {
+		after, _ = time.Parse(time.RFC3339, v)
+	}
+	if v := extra["created_before"].(string); v != "" {
+		before, _ = time.Parse(time.RFC3339, v)
+	}
+
+	pager := metalEventsPager(ctx, client, organizationID, projectID)
+	relatedTo := resourceID
+	if resourceID != "" && organizationID == "" && projectID == "" {
+		// devices are the only resources with their own list of events
+		if _, _, err := client.DevicesApi.FindDeviceById(ctx, resourceID).Execute(); err == nil {
+			pager = deviceEventsPager(ctx, client, resourceID)
+			relatedTo = ""
+		}
+	}
+
+	events, err := listMetalEvents(pager, func(e metalv1.Event) (bool, bool) {
+		created := e.GetCreatedAt()
+		if !after.IsZero() && !created.After(after) {
+			// the older events are out of the time window
+			return false, false
+		}
+		if !before.IsZero() && !created.Before(before) {
+			return false, true
+		}
+		return metalEventRelatesTo(e, relatedTo), true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		records = append(records, e)
+	}
+	return records, nil
+}
+
+func flattenMetalEvent(rawEvent interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	event, ok := rawEvent.(metalv1.Event)
+	if !ok {
+		return nil, fmt.Errorf("expected event to be of type metalv1.Event, got %T", rawEvent)
+	}
+
+	resourceIDs := []string{}
+	for _, r := range event.GetRelationships() {
+		resourceIDs = append(resourceIDs, path.Base(r.GetHref()))
+	}
+	actor := metalEventActorOf(event)
+
+	createdAt := ""
+	if event.CreatedAt != nil {
+		createdAt = event.GetCreatedAt().Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"id":           event.GetId(),
+		"type":         event.GetType(),
+		"state":        event.GetState(),
+		"body":         event.GetBody(),
+		"interpolated": event.GetInterpolated(),
+		"created_at":   createdAt,
+		"ip":           event.GetIp(),
+		"resource_ids": resourceIDs,
+		"actor_id":     actor.ID,
+		"actor_email":  actor.Email,
+		"actor_name":   actor.Name,
+	}, nil
+}
+
+// metalEventActor is the user or API key which made the change an event
+// reports
+type metalEventActor struct {
+	ID    string
+	Email string
+	Name  string
+}
+
+// String returns the most readable name of the actor
+func (a metalEventActor) String() string {
+	switch {
+	case a.Email != "":
+		return a.Email
+	case a.Name != "":
+		return a.Name
+	}
+	return a.ID
+}
+
+func metalEventActorOf(e metalv1.Event) metalEventActor {
+	m := e.GetModifiedBy()
+	str := func(key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+	actor := metalEventActor{
+		ID:    str("id"),
+		Email: str("email"),
+		Name:  str("full_name"),
+	}
+	if actor.ID == "" && str("href") != "" {
+		actor.ID = path.Base(str("href"))
+	}
+	if actor.Name == "" {
+		actor.Name = str("name")
+	}
+	return actor
+}
+
+// metalEventRelatesTo reports whether the event relates to the resource, all
+// events relate to an empty resource id
+func metalEventRelatesTo(e metalv1.Event, resourceID string) bool {
+	if resourceID == "" {
+		return true
+	}
+	for _, r := range e.GetRelationships() {
+		if path.Base(r.GetHref()) == resourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// metalLastModifiedByMaxPages limits the events read to find the last actor
+// of a resource, a resource without recent events would otherwise read all
+// events of its project
+const metalLastModifiedByMaxPages = 5
+
+// metalLastModifiedBy returns the actor of the latest event related to the
+// resource which names one, and an empty string if there is no such event
+func metalLastModifiedBy(pager metalEventPager, relatedTo string) (string, error) {
+	limited := func(page int32) (*metalv1.EventList, *http.Response, error) {
+		if page > metalLastModifiedByMaxPages {
+			return &metalv1.EventList{}, nil, nil
+		}
+		return pager(page)
+	}
+	events, err := listMetalEvents(limited, func(e metalv1.Event) (bool, bool) {
+		if metalEventActorOf(e).String() == "" || !metalEventRelatesTo(e, relatedTo) {
+			return false, true
+		}
+		return true, false
+	})
+	if err != nil || len(events) == 0 {
+		return "", err
+	}
+	return metalEventActorOf(events[0]).String(), nil
+}
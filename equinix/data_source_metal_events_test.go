@@ -0,0 +1,97 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalProjectEvents = `{
+  "events": [
+    {"id": "e4", "type": "instance.updated", "state": "success", "body": "{{user}} updated the device", "interpolated": "alice updated the device", "created_at": "2023-06-04T10:00:00Z", "ip": "192.0.2.10", "relationships": [{"href": "/metal/v1/devices/d1"}], "modified_by": {"id": "u1", "email": "alice@example.com", "full_name": "Alice"}},
+    {"id": "e3", "type": "vlan.updated", "state": "success", "created_at": "2023-06-03T10:00:00Z", "relationships": [{"href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"}], "modified_by": {"href": "/metal/v1/users/u2", "full_name": "Bob"}},
+    {"id": "e2", "type": "vlan.created", "state": "success", "created_at": "2023-06-02T10:00:00Z", "relationships": [{"href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"}], "modified_by": {"id": "u1", "email": "alice@example.com"}},
+    {"id": "e1", "type": "instance.created", "state": "success", "created_at": "2023-06-01T10:00:00Z", "relationships": [{"href": "/metal/v1/devices/d1"}], "modified_by": {"id": "u1", "email": "alice@example.com"}}
+  ],
+  "meta": {"current_page": 1, "last_page": 1, "total": 4}
+}`
+
+func TestDataSourceMetalEventsRead_recorded(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/events": recordedMetalProjectEvents,
+	})
+
+	r := dataSourceMetalEvents()
+	d := r.TestResourceData()
+	d.Set("project_id", "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de")
+	d.Set("created_after", "2023-06-01T12:00:00Z")
+	d.Set("created_before", "2023-06-04T00:00:00Z")
+	diags := r.ReadContext(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	events := d.Get("events").([]interface{})
+	assert.Len(t, events, 2)
+	assert.Equal(t, map[string]interface{}{
+		"id":           "e3",
+		"type":         "vlan.updated",
+		"state":        "success",
+		"body":         "",
+		"interpolated": "",
+		"created_at":   "2023-06-03T10:00:00Z",
+		"ip":           "",
+		"resource_ids": []interface{}{"6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"},
+		"actor_id":     "u2",
+		"actor_email":  "",
+		"actor_name":   "Bob",
+	}, events[0])
+	assert.Equal(t, "e2", events[1].(map[string]interface{})["id"])
+}
+
+func TestDataSourceMetalEventsRead_resourceAndActor(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/events": recordedMetalProjectEvents,
+	})
+
+	r := dataSourceMetalEvents()
+	d := r.TestResourceData()
+	d.Set("project_id", "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de")
+	d.Set("resource_id", "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21")
+	d.Set("filter", []interface{}{map[string]interface{}{
+		"attribute": "actor_email",
+		"values":    []interface{}{"alice@example.com"},
+		"match_by":  "in",
+	}})
+	diags := r.ReadContext(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	events := d.Get("events").([]interface{})
+	assert.Len(t, events, 1)
+	assert.Equal(t, "e2", events[0].(map[string]interface{})["id"])
+}
+
+func TestDataSourceMetalVlanRead_lastModifiedBy(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": `{"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1001, "metro_code": "sv", "description": "recorded VLAN", "assigned_to": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"}}`,
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/events":  recordedMetalProjectEvents,
+	})
+
+	d := dataSourceMetalVlan().TestResourceData()
+	d.Set("vlan_id", "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21")
+	diags := dataSourceMetalVlanRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "Bob", d.Get("last_modified_by"))
+}
+
+func TestDataSourceMetalVlanRead_eventsNotReadable(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": `{"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1001, "metro_code": "sv", "assigned_to": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"}}`,
+	})
+
+	d := dataSourceMetalVlan().TestResourceData()
+	d.Set("vlan_id", "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21")
+	diags := dataSourceMetalVlanRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "the VLAN is read when its events are not: %v", diags)
+	assert.Equal(t, "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", d.Id())
+	assert.Equal(t, "", d.Get("last_modified_by"))
+}
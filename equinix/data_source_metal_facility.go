@@ -1,21 +1,39 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
+	"net/http"
 	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
-func getCapacityInput(capacitySpecs []interface{}, baseServerInfo packngo.ServerInfo) *packngo.CapacityInput {
-	ci := packngo.CapacityInput{Servers: []packngo.ServerInfo{}}
+// metalCapacityServer is a plan and quantity of servers of a capacity check.
+// metal-go models the quantity as a string, while the API reads and returns
+// it as a number, so capacity checks are sent with metalRequest
+type metalCapacityServer struct {
+	Facility  string `json:"facility,omitempty"`
+	Metro     string `json:"metro,omitempty"`
+	Plan      string `json:"plan,omitempty"`
+	Quantity  int    `json:"quantity,omitempty"`
+	Available bool   `json:"available,omitempty"`
+}
+
+type metalCapacityInput struct {
+	Servers []metalCapacityServer `json:"servers"`
+}
+
+func getCapacityInput(capacitySpecs []interface{}, baseServerInfo metalCapacityServer) *metalCapacityInput {
+	ci := metalCapacityInput{Servers: []metalCapacityServer{}}
 	for _, v := range capacitySpecs {
 		item := v.(map[string]interface{})
 		spec := baseServerInfo
@@ -26,6 +44,18 @@ func getCapacityInput(capacitySpecs []interface{}, baseServerInfo packngo.Server
 	return &ci
 }
 
+// checkMetalCapacity checks the capacity of the capacity specifications in
+// the location of baseServerInfo. path is "capacity" for facilities and
+// "capacity/metros" for metros
+func checkMetalCapacity(ctx context.Context, client *metalv1.APIClient, path string, capacitySpecs []interface{}, baseServerInfo metalCapacityServer) (*metalCapacityInput, error) {
+	res := &metalCapacityInput{}
+	resp, err := metalRequest(ctx, client, http.MethodPost, path, getCapacityInput(capacitySpecs, baseServerInfo), res)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return res, nil
+}
+
 func capacitySchema() *schema.Schema {
 	return &schema.Schema{
 		Type:        schema.TypeList,
@@ -50,7 +80,7 @@ func capacitySchema() *schema.Schema {
 
 func dataSourceMetalFacility() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalFacilityRead,
+		ReadContext: dataSourceMetalFacilityRead,
 		Schema: map[string]*schema.Schema{
 			"code": {
 				Type:        schema.TypeString,
@@ -86,59 +116,56 @@ func dataSourceMetalFacility() *schema.Resource {
 	}
 }
 
-func dataSourceMetalFacilityRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalFacilityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	code := d.Get("code").(string)
 
 	_, capacityOk := d.GetOk("capacity")
 	if capacityOk {
-		ci := getCapacityInput(
-			d.Get("capacity").([]interface{}),
-			packngo.ServerInfo{Facility: code},
-		)
-		res, _, err := client.CapacityService.Check(ci)
+		res, err := checkMetalCapacity(ctx, client, "capacity", d.Get("capacity").([]interface{}), metalCapacityServer{Facility: code})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		for _, s := range res.Servers {
 			if !s.Available {
-				return fmt.Errorf("not enough capacity in facility %s for %d device(s) of plan %s", s.Facility, s.Quantity, s.Plan)
+				return diag.Errorf("not enough capacity in facility %s for %d device(s) of plan %s", s.Facility, s.Quantity, s.Plan)
 			}
 		}
-		if err != nil {
-			return err
-		}
 	}
 
-	facilities, _, err := client.Facilities.List(nil)
+	facilities, resp, err := client.FacilitiesApi.FindFacilities(ctx).Execute()
 	if err != nil {
-		return fmt.Errorf("Error listing Facilities: %s", err)
+		return diag.Errorf("Error listing Facilities: %s", equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	dfRaw, dfOk := d.GetOk("features_required")
 
-	for _, f := range facilities {
-		if f.Code == code {
+	for _, f := range facilities.Facilities {
+		if f.GetCode() == code {
+			features := make([]string, 0, len(f.Features))
+			for _, feature := range f.Features {
+				features = append(features, string(feature))
+			}
 			if dfOk {
-				unsupported := converters.Difference(converters.IfArrToStringArr(dfRaw.(*schema.Set).List()), f.Features)
+				unsupported := converters.Difference(converters.IfArrToStringArr(dfRaw.(*schema.Set).List()), features)
 				if len(unsupported) > 0 {
-					return fmt.Errorf("facililty %s doesn't have feature(s) %v", f.Code, unsupported)
+					return diag.Errorf("facililty %s doesn't have feature(s) %v", f.GetCode(), unsupported)
 				}
 			}
-			d.SetId(f.ID)
-			return equinix_schema.SetMap(d, map[string]interface{}{
-				"code":     f.Code,
-				"name":     f.Name,
-				"features": f.Features,
+			d.SetId(f.GetId())
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"code":     f.GetCode(),
+				"name":     f.GetName(),
+				"features": features,
 				"metro": func(d *schema.ResourceData, k string) error {
 					if f.Metro != nil {
-						return d.Set(k, strings.ToLower(f.Metro.Code))
+						return d.Set(k, strings.ToLower(f.Metro.GetCode()))
 					}
 					return nil
 				},
-			})
+			}))
 		}
 	}
 
-	return fmt.Errorf("Facility %s was not found", code)
+	return diag.Errorf("Facility %s was not found", code)
 }
@@ -0,0 +1,148 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalFacilityList = `{"facilities": [
+  {
+    "id": "8e6470b3-b75e-47d1-bb93-45b225750975",
+    "name": "Amsterdam, NL",
+    "code": "ams1",
+    "features": ["baremetal", "global_ipv4", "backend_transfer", "layer_2", "ibx"],
+    "address": {"address": "Science Park 610", "city": "Amsterdam", "country": "NL", "zip_code": "1098 XH"},
+    "ip_ranges": ["2604:1380:2000::/36", "147.75.204.0/23"],
+    "metro": {"id": "d50fd052-34ec-4977-a173-ad6f9266995d", "code": "am", "name": "Amsterdam", "country": "NL"}
+  },
+  {
+    "id": "7b4f4e4a-2a5e-4a3d-9c63-6d3ec5d1b7a1",
+    "name": "Dallas 11",
+    "code": "da11",
+    "features": ["baremetal", "layer_2"],
+    "metro": {"id": "108b2cfb-246b-45e3-885a-bf3e82fce1a0", "code": "DA", "name": "Dallas", "country": "US"}
+  }
+]}`
+
+// packngoDataSourceMetalFacilityRead is the packngo implementation of
+// dataSourceMetalFacilityRead, kept to compare the states both write
+func packngoDataSourceMetalFacilityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	code := d.Get("code").(string)
+
+	if _, capacityOk := d.GetOk("capacity"); capacityOk {
+		ci := &packngo.CapacityInput{}
+		for _, v := range d.Get("capacity").([]interface{}) {
+			item := v.(map[string]interface{})
+			ci.Servers = append(ci.Servers, packngo.ServerInfo{Facility: code, Plan: item["plan"].(string), Quantity: item["quantity"].(int)})
+		}
+		res, _, err := client.CapacityService.Check(ci)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, s := range res.Servers {
+			if !s.Available {
+				return diag.Errorf("not enough capacity in facility %s for %d device(s) of plan %s", s.Facility, s.Quantity, s.Plan)
+			}
+		}
+	}
+
+	facilities, _, err := client.Facilities.List(nil)
+	if err != nil {
+		return diag.Errorf("Error listing Facilities: %s", err)
+	}
+
+	dfRaw, dfOk := d.GetOk("features_required")
+
+	for _, f := range facilities {
+		if f.Code == code {
+			if dfOk {
+				unsupported := converters.Difference(converters.IfArrToStringArr(dfRaw.(*schema.Set).List()), f.Features)
+				if len(unsupported) > 0 {
+					return diag.Errorf("facililty %s doesn't have feature(s) %v", f.Code, unsupported)
+				}
+			}
+			d.SetId(f.ID)
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"code":     f.Code,
+				"name":     f.Name,
+				"features": f.Features,
+				"metro": func(d *schema.ResourceData, k string) error {
+					if f.Metro != nil {
+						return d.Set(k, strings.ToLower(f.Metro.Code))
+					}
+					return nil
+				},
+			}))
+		}
+	}
+
+	return diag.FromErr(fmt.Errorf("Facility %s was not found", code))
+}
+
+func TestDataSourceMetalFacilityRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceMetalFacility(), "", map[string]interface{}{
+		"code":              "ams1",
+		"features_required": []interface{}{"baremetal", "layer_2"},
+		"capacity":          []interface{}{map[string]interface{}{"plan": "c3.small.x86", "quantity": 2}},
+	}, map[string]string{
+		"POST /metal/v1/capacity":  `{"servers": [{"facility": "ams1", "plan": "c3.small.x86", "quantity": 2, "available": true}]}`,
+		"GET /metal/v1/facilities": recordedMetalFacilityList,
+	}, dataSourceMetalFacilityRead, packngoDataSourceMetalFacilityRead)
+
+	assert.Equal(t, "8e6470b3-b75e-47d1-bb93-45b225750975", state["id"])
+	assert.Equal(t, "am", state["metro"])
+	assert.Equal(t, "5", state["features.#"])
+}
+
+func TestDataSourceMetalFacilityRead_noCapacity(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/capacity":  `{"servers": [{"facility": "ams1", "plan": "c3.small.x86", "quantity": 100, "available": false}]}`,
+		"GET /metal/v1/facilities": recordedMetalFacilityList,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalFacility().Schema, map[string]interface{}{
+		"code":     "ams1",
+		"capacity": []interface{}{map[string]interface{}{"plan": "c3.small.x86", "quantity": 100}},
+	})
+	diags := dataSourceMetalFacilityRead(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected a capacity error")
+	}
+	assert.Equal(t, "not enough capacity in facility ams1 for 100 device(s) of plan c3.small.x86", diags[0].Summary)
+
+	body, _ := fake.request("POST /metal/v1/capacity")
+	request := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid capacity request %q: %v", body, err)
+	}
+	assert.Equal(t, map[string]interface{}{"servers": []interface{}{
+		map[string]interface{}{"facility": "ams1", "plan": "c3.small.x86", "quantity": float64(100)},
+	}}, request, "the quantity is sent as a number")
+}
+
+func TestDataSourceMetalFacilityRead_missingFeature(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilityList,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalFacility().Schema, map[string]interface{}{
+		"code":              "da11",
+		"features_required": []interface{}{"global_ipv4"},
+	})
+	diags := dataSourceMetalFacilityRead(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Contains(t, diags[0].Summary, "doesn't have feature(s) [global_ipv4]")
+	}
+}
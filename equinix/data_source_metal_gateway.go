@@ -1,14 +1,16 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceMetalGateway() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalGatewayRead,
+		ReadContext: dataSourceMetalGatewayRead,
 
 		Schema: map[string]*schema.Schema{
 			"gateway_id": {
@@ -50,9 +52,9 @@ func dataSourceMetalGateway() *schema.Resource {
 	}
 }
 
-func dataSourceMetalGatewayRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceMetalGatewayRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	gatewayId, _ := d.Get("gateway_id").(string)
 
 	d.SetId(gatewayId)
-	return resourceMetalGatewayRead(d, meta)
+	return resourceMetalGatewayRead(ctx, d, meta)
 }
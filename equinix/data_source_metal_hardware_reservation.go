@@ -1,19 +1,22 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
+	"net/http"
 
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalHardwareReservation() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalHardwareReservationRead,
+		ReadContext: dataSourceMetalHardwareReservationRead,
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -68,17 +71,17 @@ func dataSourceMetalHardwareReservation() *schema.Resource {
 	}
 }
 
-func dataSourceMetalHardwareReservationRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalHardwareReservationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	hrIdRaw, hrIdOk := d.GetOk("id")
 	dIdRaw, dIdOk := d.GetOk("device_id")
 
 	if dIdOk == hrIdOk {
-		return fmt.Errorf("You must set one of id and device_id")
+		return diag.Errorf("You must set one of id and device_id")
 	}
 
 	var deviceId string
-	var hr *packngo.HardwareReservation
+	var hr *metalv1.HardwareReservation
 
 	if dIdOk {
 		deviceId = dIdRaw.(string)
@@ -86,39 +89,39 @@ func dataSourceMetalHardwareReservationRead(d *schema.ResourceData, meta interfa
 			"hardware_reservation.project",
 			"hardware_reservation.facility",
 		}
-		d, _, err := client.Devices.Get(deviceId, &packngo.GetOptions{Includes: includes})
+		d, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceId).Include(includes).Execute()
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 		if d.HardwareReservation == nil {
-			return fmt.Errorf("Device %s is not in a hardware reservation", deviceId)
+			return diag.Errorf("Device %s is not in a hardware reservation", deviceId)
 		}
 		hr = d.HardwareReservation
 
 	} else {
+		var resp *http.Response
 		var err error
-		hr, _, err = client.HardwareReservations.Get(
-			hrIdRaw.(string),
-			&packngo.GetOptions{Includes: []string{"project", "facility", "device"}})
+		hr, resp, err = client.HardwareReservationsApi.FindHardwareReservationById(ctx, hrIdRaw.(string)).
+			Include([]string{"project", "facility", "device"}).Execute()
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 		if hr.Device != nil {
-			deviceId = hr.Device.ID
+			deviceId = hr.Device.GetId()
 		}
 	}
 
 	m := map[string]interface{}{
-		"short_id":      hr.ShortID,
-		"project_id":    hr.Project.ID,
+		"short_id":      hr.GetShortId(),
+		"project_id":    hr.Project.GetId(),
 		"device_id":     deviceId,
-		"plan":          hr.Plan.Slug,
-		"facility":      hr.Facility.Code,
-		"provisionable": hr.Provisionable,
-		"spare":         hr.Spare,
-		"switch_uuid":   hr.SwitchUUID,
+		"plan":          hr.Plan.GetSlug(),
+		"facility":      hr.Facility.GetCode(),
+		"provisionable": hr.GetProvisionable(),
+		"spare":         hr.GetSpare(),
+		"switch_uuid":   hr.GetSwitchUuid(),
 	}
 
-	d.SetId(hr.ID)
-	return equinix_schema.SetMap(d, m)
+	d.SetId(hr.GetId())
+	return diag.FromErr(equinix_schema.SetMap(d, m))
 }
@@ -0,0 +1,103 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalHardwareReservation = `{
+  "id": "5f0c1a2b-7d3e-4f5a-9b6c-1d2e3f4a5b6c",
+  "short_id": "5f0c1a2b",
+  "provisionable": true,
+  "spare": false,
+  "switch_uuid": "7ab2c3d4",
+  "plan": {"id": "e69c0169-4726-46ea-98f1-939c9e8a3607", "slug": "c3.small.x86", "name": "c3.small.x86"},
+  "facility": {"id": "8e6470b3-b75e-47d1-bb93-45b225750975", "code": "ams1", "name": "Amsterdam, NL"},
+  "project": {"id": "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e", "name": "reservations"},
+  "device": {"id": "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a", "href": "/metal/v1/devices/9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a"}
+}`
+
+var recordedMetalHardwareReservationResponses = map[string]string{
+	"GET /metal/v1/hardware-reservations/5f0c1a2b-7d3e-4f5a-9b6c-1d2e3f4a5b6c": recordedMetalHardwareReservation,
+	"GET /metal/v1/devices/9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a":               `{"id": "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a", "hardware_reservation": ` + recordedMetalHardwareReservation + `}`,
+}
+
+// packngoDataSourceMetalHardwareReservationRead is the packngo implementation
+// of dataSourceMetalHardwareReservationRead, kept to compare the states both
+// write
+func packngoDataSourceMetalHardwareReservationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	var deviceId string
+	var hr *packngo.HardwareReservation
+
+	if dIdRaw, dIdOk := d.GetOk("device_id"); dIdOk {
+		deviceId = dIdRaw.(string)
+		includes := []string{"hardware_reservation.project", "hardware_reservation.facility"}
+		d, _, err := client.Devices.Get(deviceId, &packngo.GetOptions{Includes: includes})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		hr = d.HardwareReservation
+	} else {
+		var err error
+		hr, _, err = client.HardwareReservations.Get(d.Get("id").(string),
+			&packngo.GetOptions{Includes: []string{"project", "facility", "device"}})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if hr.Device != nil {
+			deviceId = hr.Device.ID
+		}
+	}
+
+	d.SetId(hr.ID)
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"short_id":      hr.ShortID,
+		"project_id":    hr.Project.ID,
+		"device_id":     deviceId,
+		"plan":          hr.Plan.Slug,
+		"facility":      hr.Facility.Code,
+		"provisionable": hr.Provisionable,
+		"spare":         hr.Spare,
+		"switch_uuid":   hr.SwitchUUID,
+	}))
+}
+
+func TestDataSourceMetalHardwareReservationRead_recorded(t *testing.T) {
+	for _, tc := range []struct {
+		id  string
+		raw map[string]interface{}
+	}{
+		{id: "5f0c1a2b-7d3e-4f5a-9b6c-1d2e3f4a5b6c"},
+		{raw: map[string]interface{}{"device_id": "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a"}},
+	} {
+		state := assertMetalReadParity(t, dataSourceMetalHardwareReservation(), tc.id, tc.raw, recordedMetalHardwareReservationResponses,
+			dataSourceMetalHardwareReservationRead, packngoDataSourceMetalHardwareReservationRead)
+
+		assert.Equal(t, "5f0c1a2b-7d3e-4f5a-9b6c-1d2e3f4a5b6c", state["id"])
+		assert.Equal(t, "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a", state["device_id"])
+		assert.Equal(t, "ams1", state["facility"])
+		assert.Equal(t, "c3.small.x86", state["plan"])
+	}
+}
+
+func TestDataSourceMetalHardwareReservationRead_deviceWithoutReservation(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/devices/9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a": `{"id": "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a"}`,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalHardwareReservation().Schema, map[string]interface{}{
+		"device_id": "9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a",
+	})
+	diags := dataSourceMetalHardwareReservationRead(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Equal(t, "Device 9d8c7b6a-5f4e-3d2c-1b0a-9f8e7d6c5b4a is not in a hardware reservation", diags[0].Summary)
+	}
+}
@@ -1,19 +1,21 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalIPBlockRanges() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalIPBlockRangesRead,
+		ReadContext: dataSourceMetalIPBlockRangesRead,
 
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -61,7 +63,7 @@ func dataSourceMetalIPBlockRanges() *schema.Resource {
 	}
 }
 
-func facilityMatch(ref string, facility *packngo.Facility) bool {
+func facilityMatch(ref string, facility *metalFacilityCode) bool {
 	if ref == "" {
 		return true
 	}
@@ -71,7 +73,7 @@ func facilityMatch(ref string, facility *packngo.Facility) bool {
 	return false
 }
 
-func metroMatch(ref string, metro *packngo.Metro) bool {
+func metroMatch(ref string, metro *metalCode) bool {
 	if ref == "" {
 		return true
 	}
@@ -81,7 +83,7 @@ func metroMatch(ref string, metro *packngo.Metro) bool {
 	return false
 }
 
-func metroOffacilityMatch(ref string, facility *packngo.Facility) bool {
+func metroOffacilityMatch(ref string, facility *metalFacilityCode) bool {
 	if ref == "" {
 		return true
 	}
@@ -91,12 +93,12 @@ func metroOffacilityMatch(ref string, facility *packngo.Facility) bool {
 	return false
 }
 
-func dataSourceMetalIPBlockRangesRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalIPBlockRangesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	projectID := d.Get("project_id").(string)
-	ips, _, err := client.ProjectIPs.List(projectID, nil)
+	ips, resp, err := listMetalIPReservations(ctx, client, projectID, "")
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	facility := d.Get("facility").(string)
@@ -0,0 +1,90 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// packngoDataSourceMetalIPBlockRangesRead is the packngo implementation of
+// dataSourceMetalIPBlockRangesRead, kept to compare the states both write
+func packngoDataSourceMetalIPBlockRangesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	projectID := d.Get("project_id").(string)
+	ips, _, err := client.ProjectIPs.List(projectID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	facility := d.Get("facility").(string)
+	metro := d.Get("metro").(string)
+
+	publicIPv4s := []string{}
+	globalIPv4s := []string{}
+	privateIPv4s := []string{}
+	theIPv6s := []string{}
+	var targetSlice *[]string
+
+	for _, ip := range ips {
+		targetSlice = nil
+		cnStr := fmt.Sprintf("%s/%d", ip.Network, ip.CIDR)
+		if ip.AddressFamily == 4 {
+			if ip.Public {
+				if ip.Global {
+					globalIPv4s = append(globalIPv4s, cnStr)
+				} else {
+					targetSlice = &publicIPv4s
+				}
+			} else {
+				targetSlice = &privateIPv4s
+			}
+		} else {
+			targetSlice = &theIPv6s
+		}
+		if targetSlice != nil {
+			block := packngoIPReservation(&ip)
+			if !(facilityMatch(facility, block.Facility) && metroMatch(metro, block.Metro)) {
+				if !metroOffacilityMatch(metro, block.Facility) {
+					continue
+				}
+			}
+			*targetSlice = append(*targetSlice, cnStr)
+		}
+	}
+
+	d.Set("public_ipv4", publicIPv4s)
+	d.Set("global_ipv4", globalIPv4s)
+	d.Set("private_ipv4", privateIPv4s)
+	d.Set("ipv6", theIPv6s)
+
+	id := projectID
+	if facility != "" {
+		id = id + "-" + facility
+	} else if metro != "" {
+		id = id + "-" + metro
+	}
+	d.SetId(id + "-IPs")
+	return nil
+}
+
+func TestDataSourceMetalIPBlockRangesRead_recorded(t *testing.T) {
+	all := assertMetalReadParity(t, dataSourceMetalIPBlockRanges(), "", map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+	}, recordedMetalIPBlocks, dataSourceMetalIPBlockRangesRead, packngoDataSourceMetalIPBlockRangesRead)
+	assert.Equal(t, "1", all["public_ipv4.#"])
+	assert.Equal(t, "1", all["private_ipv4.#"])
+	assert.Equal(t, "1", all["global_ipv4.#"])
+	assert.Equal(t, "1", all["ipv6.#"])
+
+	dallas := assertMetalReadParity(t, dataSourceMetalIPBlockRanges(), "", map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+		"metro":      "da",
+	}, recordedMetalIPBlocks, dataSourceMetalIPBlockRangesRead, packngoDataSourceMetalIPBlockRangesRead)
+	assert.Equal(t, testMetalIPProjectID+"-da-IPs", dallas["id"])
+	assert.Equal(t, "0", dallas["public_ipv4.#"])
+	assert.Equal(t, "2604:1380:4641:c500::/56", dallas["ipv6.0"])
+}
@@ -1,19 +1,20 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
 
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalMetro() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalMetroRead,
+		ReadContext: dataSourceMetalMetroRead,
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -40,46 +41,39 @@ func dataSourceMetalMetro() *schema.Resource {
 	}
 }
 
-func dataSourceMetalMetroRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalMetroRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	code := d.Get("code").(string)
 
 	_, capacityOk := d.GetOk("capacity")
 	if capacityOk {
-		ci := getCapacityInput(
-			d.Get("capacity").([]interface{}),
-			packngo.ServerInfo{Metro: code},
-		)
-		res, _, err := client.CapacityService.CheckMetros(ci)
+		res, err := checkMetalCapacity(ctx, client, "capacity/metros", d.Get("capacity").([]interface{}), metalCapacityServer{Metro: code})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		for _, s := range res.Servers {
 			if !s.Available {
-				return fmt.Errorf("not enough capacity in metro %s for %d device(s) of plan %s", s.Metro, s.Quantity, s.Plan)
+				return diag.Errorf("not enough capacity in metro %s for %d device(s) of plan %s", s.Metro, s.Quantity, s.Plan)
 			}
 		}
-		if err != nil {
-			return err
-		}
 	}
 
-	metros, _, err := client.Metros.List(nil)
+	metros, resp, err := client.MetrosApi.FindMetros(ctx).Execute()
 	if err != nil {
-		return fmt.Errorf("Error listing Metros: %s", err)
+		return diag.Errorf("Error listing Metros: %s", equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	for _, m := range metros {
-		if m.Code == code {
-			d.SetId(m.ID)
-			return equinix_schema.SetMap(d, map[string]interface{}{
-				"id":      m.ID,
-				"code":    m.Code,
-				"name":    m.Name,
-				"country": m.Country,
-			})
+	for _, m := range metros.Metros {
+		if m.GetCode() == code {
+			d.SetId(m.GetId())
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"id":      m.GetId(),
+				"code":    m.GetCode(),
+				"name":    m.GetName(),
+				"country": m.GetCountry(),
+			}))
 		}
 	}
 
-	return fmt.Errorf("Metro %s was not found", code)
+	return diag.Errorf("Metro %s was not found", code)
 }
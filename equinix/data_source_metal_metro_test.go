@@ -0,0 +1,87 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalMetros = `{"metros": [
+  {"id": "d50fd052-34ec-4977-a173-ad6f9266995d", "code": "am", "name": "Amsterdam", "country": "NL"},
+  {"id": "108b2cfb-246b-45e3-885a-bf3e82fce1a0", "code": "da", "name": "Dallas", "country": "US"}
+]}`
+
+// packngoDataSourceMetalMetroRead is the packngo implementation of
+// dataSourceMetalMetroRead, kept to compare the states both write
+func packngoDataSourceMetalMetroRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	code := d.Get("code").(string)
+
+	if _, capacityOk := d.GetOk("capacity"); capacityOk {
+		ci := &packngo.CapacityInput{}
+		for _, v := range d.Get("capacity").([]interface{}) {
+			item := v.(map[string]interface{})
+			ci.Servers = append(ci.Servers, packngo.ServerInfo{Metro: code, Plan: item["plan"].(string), Quantity: item["quantity"].(int)})
+		}
+		res, _, err := client.CapacityService.CheckMetros(ci)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, s := range res.Servers {
+			if !s.Available {
+				return diag.Errorf("not enough capacity in metro %s for %d device(s) of plan %s", s.Metro, s.Quantity, s.Plan)
+			}
+		}
+	}
+
+	metros, _, err := client.Metros.List(nil)
+	if err != nil {
+		return diag.Errorf("Error listing Metros: %s", err)
+	}
+
+	for _, m := range metros {
+		if m.Code == code {
+			d.SetId(m.ID)
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"id":      m.ID,
+				"code":    m.Code,
+				"name":    m.Name,
+				"country": m.Country,
+			}))
+		}
+	}
+
+	return diag.FromErr(fmt.Errorf("Metro %s was not found", code))
+}
+
+func TestDataSourceMetalMetroRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceMetalMetro(), "", map[string]interface{}{
+		"code":     "da",
+		"capacity": []interface{}{map[string]interface{}{"plan": "c3.small.x86"}},
+	}, map[string]string{
+		"POST /metal/v1/capacity/metros": `{"servers": [{"metro": "da", "plan": "c3.small.x86", "quantity": 1, "available": true}]}`,
+		"GET /metal/v1/locations/metros": recordedMetalMetros,
+	}, dataSourceMetalMetroRead, packngoDataSourceMetalMetroRead)
+
+	assert.Equal(t, "108b2cfb-246b-45e3-885a-bf3e82fce1a0", state["id"])
+	assert.Equal(t, "US", state["country"])
+}
+
+func TestDataSourceMetalMetroRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/locations/metros": recordedMetalMetros,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalMetro().Schema, map[string]interface{}{"code": "sv"})
+	diags := dataSourceMetalMetroRead(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Equal(t, "Metro sv was not found", diags[0].Summary)
+	}
+}
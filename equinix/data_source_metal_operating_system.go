@@ -1,18 +1,20 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
 	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceOperatingSystem() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalOperatingSystemRead,
+		ReadContext: dataSourceMetalOperatingSystemRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -43,8 +45,8 @@ func dataSourceOperatingSystem() *schema.Resource {
 	}
 }
 
-func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalOperatingSystemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	name, nameOK := d.GetOk("name")
 	distro, distroOK := d.GetOk("distro")
@@ -52,18 +54,19 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	provisionableOn, provisionableOnOK := d.GetOk("provisionable_on")
 
 	if !nameOK && !distroOK && !versionOK && !provisionableOnOK {
-		return fmt.Errorf("One of name, distro, version, or provisionable_on must be assigned")
+		return diag.Errorf("One of name, distro, version, or provisionable_on must be assigned")
 	}
 
-	oss, _, err := client.OperatingSystems.List()
+	osList, resp, err := client.OperatingSystemsApi.FindOperatingSystems(ctx).Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
+	oss := osList.OperatingSystems
 
 	if nameOK {
-		temp := []packngo.OS{}
+		temp := []metalv1.OperatingSystem{}
 		for _, os := range oss {
-			if strings.Contains(strings.ToLower(os.Name), strings.ToLower(name.(string))) {
+			if strings.Contains(strings.ToLower(os.GetName()), strings.ToLower(name.(string))) {
 				temp = append(temp, os)
 			}
 		}
@@ -71,9 +74,9 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	}
 
 	if distroOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
+		temp := []metalv1.OperatingSystem{}
 		for _, v := range oss {
-			if v.Distro == distro.(string) {
+			if v.GetDistro() == distro.(string) {
 				temp = append(temp, v)
 			}
 		}
@@ -81,9 +84,9 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	}
 
 	if versionOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
+		temp := []metalv1.OperatingSystem{}
 		for _, v := range oss {
-			if v.Version == version.(string) {
+			if v.GetVersion() == version.(string) {
 				temp = append(temp, v)
 			}
 		}
@@ -91,7 +94,7 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	}
 
 	if provisionableOnOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
+		temp := []metalv1.OperatingSystem{}
 		for _, v := range oss {
 			for _, po := range v.ProvisionableOn {
 				if po == provisionableOn.(string) {
@@ -103,16 +106,16 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	}
 
 	if len(oss) == 0 {
-		return fmt.Errorf("There are no operating systems that match the search criteria")
+		return diag.Errorf("There are no operating systems that match the search criteria")
 	}
 
 	if len(oss) > 1 {
-		return fmt.Errorf("There is more than one operating system that matches the search criteria")
+		return diag.Errorf("There is more than one operating system that matches the search criteria")
 	}
-	d.Set("name", oss[0].Name)
-	d.Set("distro", oss[0].Distro)
-	d.Set("version", oss[0].Version)
-	d.Set("slug", oss[0].Slug)
-	d.SetId(oss[0].Slug)
+	d.Set("name", oss[0].GetName())
+	d.Set("distro", oss[0].GetDistro())
+	d.Set("version", oss[0].GetVersion())
+	d.Set("slug", oss[0].GetSlug())
+	d.SetId(oss[0].GetSlug())
 	return nil
 }
@@ -0,0 +1,108 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalOperatingSystems = `{"operating_systems": [
+  {"id": "b6d2e6b8-4dcd-4e50-b3a5-5b4d3ad1f2a3", "slug": "ubuntu_20_04", "name": "Ubuntu 20.04 LTS", "distro": "ubuntu", "version": "20.04", "provisionable_on": ["c3.small.x86", "m3.large.x86"]},
+  {"id": "f0b0d3c7-0a2f-4a8a-9a4e-2c8c3e2f1d10", "slug": "ubuntu_22_04", "name": "Ubuntu 22.04 LTS", "distro": "ubuntu", "version": "22.04", "provisionable_on": ["c3.small.x86"]},
+  {"id": "2a9f4a6e-3b1c-4c8e-8f2d-7e6b5a4c3d21", "slug": "debian_11", "name": "Debian 11", "distro": "debian", "version": "11", "provisionable_on": ["c3.small.x86", "m3.large.x86"]}
+]}`
+
+// packngoDataSourceMetalOperatingSystemRead is the packngo implementation of
+// dataSourceMetalOperatingSystemRead, kept to compare the states both write
+func packngoDataSourceMetalOperatingSystemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	name, nameOK := d.GetOk("name")
+	distro, distroOK := d.GetOk("distro")
+	version, versionOK := d.GetOk("version")
+	provisionableOn, provisionableOnOK := d.GetOk("provisionable_on")
+
+	oss, _, err := client.OperatingSystems.List()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if nameOK {
+		temp := []packngo.OS{}
+		for _, os := range oss {
+			if strings.Contains(strings.ToLower(os.Name), strings.ToLower(name.(string))) {
+				temp = append(temp, os)
+			}
+		}
+		oss = temp
+	}
+	if distroOK && (len(oss) != 0) {
+		temp := []packngo.OS{}
+		for _, v := range oss {
+			if v.Distro == distro.(string) {
+				temp = append(temp, v)
+			}
+		}
+		oss = temp
+	}
+	if versionOK && (len(oss) != 0) {
+		temp := []packngo.OS{}
+		for _, v := range oss {
+			if v.Version == version.(string) {
+				temp = append(temp, v)
+			}
+		}
+		oss = temp
+	}
+	if provisionableOnOK && (len(oss) != 0) {
+		temp := []packngo.OS{}
+		for _, v := range oss {
+			for _, po := range v.ProvisionableOn {
+				if po == provisionableOn.(string) {
+					temp = append(temp, v)
+				}
+			}
+		}
+		oss = temp
+	}
+	if len(oss) != 1 {
+		return diag.FromErr(fmt.Errorf("%d operating systems match the search criteria", len(oss)))
+	}
+	d.Set("name", oss[0].Name)
+	d.Set("distro", oss[0].Distro)
+	d.Set("version", oss[0].Version)
+	d.Set("slug", oss[0].Slug)
+	d.SetId(oss[0].Slug)
+	return nil
+}
+
+func TestDataSourceMetalOperatingSystemRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceOperatingSystem(), "", map[string]interface{}{
+		"name":             "ubuntu",
+		"distro":           "ubuntu",
+		"provisionable_on": "m3.large.x86",
+	}, map[string]string{
+		"GET /metal/v1/operating-systems": recordedMetalOperatingSystems,
+	}, dataSourceMetalOperatingSystemRead, packngoDataSourceMetalOperatingSystemRead)
+
+	assert.Equal(t, "ubuntu_20_04", state["id"])
+	assert.Equal(t, "20.04", state["version"])
+}
+
+func TestDataSourceMetalOperatingSystemRead_ambiguous(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/operating-systems": recordedMetalOperatingSystems,
+	})
+
+	d := schema.TestResourceDataRaw(t, dataSourceOperatingSystem().Schema, map[string]interface{}{
+		"distro": "ubuntu",
+	})
+	diags := dataSourceMetalOperatingSystemRead(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Equal(t, "There is more than one operating system that matches the search criteria", diags[0].Summary)
+	}
+}
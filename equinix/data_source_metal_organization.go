@@ -1,20 +1,24 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"path"
 
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalOrganization() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalOrganizationRead,
+		ReadContext: dataSourceMetalOrganizationRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -95,10 +99,10 @@ func createOrganizationAddressDataSourceSchema() map[string]*schema.Schema {
 	}
 }
 
-func findOrgByName(os []packngo.Organization, name string) (*packngo.Organization, error) {
-	results := make([]packngo.Organization, 0)
+func findOrgByName(os []metalv1.Organization, name string) (*metalv1.Organization, error) {
+	results := make([]metalv1.Organization, 0)
 	for _, o := range os {
-		if o.Name == name {
+		if o.GetName() == name {
 			results = append(results, o)
 		}
 	}
@@ -111,52 +115,54 @@ func findOrgByName(os []packngo.Organization, name string) (*packngo.Organizatio
 	return nil, fmt.Errorf("too many organizations found with name %s (found %d, expected 1)", name, len(results))
 }
 
-func dataSourceMetalOrganizationRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalOrganizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	nameRaw, nameOK := d.GetOk("name")
 	orgIdRaw, orgIdOK := d.GetOk("organization_id")
 
 	if !orgIdOK && !nameOK {
-		return fmt.Errorf("you must supply organization_id or name")
+		return diag.Errorf("you must supply organization_id or name")
 	}
-	var org *packngo.Organization
+	var org *metalv1.Organization
 
 	if nameOK {
 		name := nameRaw.(string)
 
-		os, _, err := client.Organizations.List(&packngo.GetOptions{Includes: []string{"address"}})
+		// the paginated request does not return the response of a failed page
+		os, err := client.OrganizationsApi.FindOrganizations(ctx).Include([]string{"address"}).ExecuteWithPagination()
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
-		org, err = findOrgByName(os, name)
+		org, err = findOrgByName(os.Organizations, name)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
 		orgId := orgIdRaw.(string)
+		var resp *http.Response
 		var err error
 
-		org, _, err = client.Organizations.Get(orgId, &packngo.GetOptions{Includes: []string{"address"}})
+		org, resp, err = client.OrganizationsApi.FindOrganizationById(ctx, orgId).Include([]string{"address"}).Execute()
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 	projectIds := []string{}
 
 	for _, p := range org.Projects {
-		projectIds = append(projectIds, path.Base(p.URL))
+		projectIds = append(projectIds, path.Base(p.GetHref()))
 	}
 
-	d.SetId(org.ID)
-	return equinix_schema.SetMap(d, map[string]interface{}{
-		"organization_id": org.ID,
-		"name":            org.Name,
-		"description":     org.Description,
-		"website":         org.Website,
-		"twitter":         org.Twitter,
-		"logo":            org.Logo,
+	d.SetId(org.GetId())
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"organization_id": org.GetId(),
+		"name":            org.GetName(),
+		"description":     org.GetDescription(),
+		"website":         org.GetWebsite(),
+		"twitter":         org.GetTwitter(),
+		"logo":            org.GetLogo(),
 		"project_ids":     projectIds,
 		"address":         flattenMetalOrganizationAddress(org.Address),
-	})
+	}))
 }
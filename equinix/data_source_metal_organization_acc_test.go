@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
-	"github.com/packethost/packngo"
 )
 
 func TestAccDataSourceMetalOrganization_basic(t *testing.T) {
-	var org packngo.Organization
+	var org metalv1.Organization
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -0,0 +1,100 @@
+package equinix
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+// packngoDataSourceMetalOrganizationRead is the packngo implementation of
+// dataSourceMetalOrganizationRead, kept to compare the states both write
+func packngoDataSourceMetalOrganizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	var org *packngo.Organization
+
+	if name, nameOK := d.GetOk("name"); nameOK {
+		os, _, err := client.Organizations.List(&packngo.GetOptions{Includes: []string{"address"}})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for i := range os {
+			if os[i].Name == name.(string) {
+				org = &os[i]
+			}
+		}
+		if org == nil {
+			return diag.Errorf("no organization found with name %s", name)
+		}
+	} else {
+		var err error
+		org, _, err = client.Organizations.Get(d.Get("organization_id").(string), &packngo.GetOptions{Includes: []string{"address"}})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	projectIds := []string{}
+	for _, p := range org.Projects {
+		projectIds = append(projectIds, path.Base(p.URL))
+	}
+
+	d.SetId(org.ID)
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"organization_id": org.ID,
+		"name":            org.Name,
+		"description":     org.Description,
+		"website":         org.Website,
+		"twitter":         org.Twitter,
+		"logo":            org.Logo,
+		"project_ids":     projectIds,
+		"address": []interface{}{map[string]interface{}{
+			"address":  org.Address.Address,
+			"city":     *org.Address.City,
+			"country":  org.Address.Country,
+			"state":    *org.Address.State,
+			"zip_code": org.Address.ZipCode,
+		}},
+	}))
+}
+
+func TestDataSourceMetalOrganizationRead_recorded(t *testing.T) {
+	responses := map[string]string{
+		"GET /metal/v1/organizations/3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e": recordedMetalOrganization,
+		"GET /metal/v1/organizations": `{"organizations": [` + recordedMetalOrganization + `,
+			{"id": "6a5b4c3d-2e1f-4a9b-8c7d-6e5f4a3b2c1d", "name": "Personal", "address": {"address": "", "country": "", "zip_code": ""}}
+		], "meta": {"current_page": 1, "last_page": 1}}`,
+	}
+	for _, raw := range []map[string]interface{}{
+		{"organization_id": "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e"},
+		{"name": "Terraform Acceptance"},
+	} {
+		state := assertMetalReadParity(t, dataSourceMetalOrganization(), "", raw, responses,
+			dataSourceMetalOrganizationRead, packngoDataSourceMetalOrganizationRead)
+
+		assert.Equal(t, "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e", state["id"])
+		assert.Equal(t, "1", state["project_ids.#"])
+		assert.Equal(t, "CA", state["address.0.state"])
+	}
+}
+
+func TestDataSourceMetalOrganizationRead_pages(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{})
+	fake.queued["GET /metal/v1/organizations"] = []string{
+		`{"organizations": [{"id": "6a5b4c3d-2e1f-4a9b-8c7d-6e5f4a3b2c1d", "name": "Personal"}], "meta": {"current_page": 1, "last_page": 2}}`,
+		`{"organizations": [` + recordedMetalOrganization + `], "meta": {"current_page": 2, "last_page": 2}}`,
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalOrganization().Schema, map[string]interface{}{
+		"name": "Terraform Acceptance",
+	})
+	if diags := dataSourceMetalOrganizationRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Equal(t, "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e", d.Id(), "organizations are searched on every page")
+}
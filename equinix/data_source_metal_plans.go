@@ -1,15 +1,17 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
 	"github.com/equinix/terraform-provider-equinix/internal/datalist"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalPlans() *schema.Resource {
@@ -24,17 +26,44 @@ func dataSourceMetalPlans() *schema.Resource {
 	return datalist.NewResource(dataListConfig)
 }
 
+// metalPlan is a server plan as listed by the Equinix Metal API. metal-go
+// only decodes the links of the facilities and metros a plan is available in,
+// and the pricing keeps the precision of the previous packngo model.
+type metalPlan struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Slug            string   `json:"slug"`
+	Description     string   `json:"description"`
+	Line            string   `json:"line"`
+	Legacy          bool     `json:"legacy"`
+	Class           string   `json:"class"`
+	DeploymentTypes []string `json:"deployment_types"`
+	AvailableIn     []struct {
+		Code string `json:"code"`
+	} `json:"available_in"`
+	AvailableInMetros []struct {
+		Code string `json:"code"`
+	} `json:"available_in_metros"`
+	Pricing *struct {
+		Hour  float32 `json:"hour"`
+		Month float32 `json:"month"`
+	} `json:"pricing"`
+}
+
 func getPlans(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
-	client := meta.(*config.Config).Metal
-	opts := &packngo.ListOptions{
-		Includes: []string{"available_in", "available_in_metros"},
+	client := meta.(*config.Config).Metalgo
+	plans := struct {
+		Plans []metalPlan `json:"plans"`
+	}{}
+	resp, err := metalRequest(context.Background(), client, http.MethodGet, "plans?include=available_in,available_in_metros", nil, &plans)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
 	}
-	plans, _, err := client.Plans.List(opts)
 	plansIf := []interface{}{}
-	for _, p := range plans {
+	for _, p := range plans.Plans {
 		plansIf = append(plansIf, p)
 	}
-	return plansIf, err
+	return plansIf, nil
 }
 
 func planSchema() map[string]*schema.Schema {
@@ -95,9 +124,9 @@ func planSchema() map[string]*schema.Schema {
 }
 
 func flattenPlan(rawPlan interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
-	plan, ok := rawPlan.(packngo.Plan)
+	plan, ok := rawPlan.(metalPlan)
 	if !ok {
-		return nil, fmt.Errorf("unable to convert to packngo.Plan")
+		return nil, fmt.Errorf("unable to convert to metalPlan")
 	}
 
 	facs := []string{}
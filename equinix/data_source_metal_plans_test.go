@@ -0,0 +1,120 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/equinix/terraform-provider-equinix/internal/datalist"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalPlans = `{"plans": [
+  {
+    "id": "e69c0169-4726-46ea-98f1-939c9e8a3607",
+    "slug": "c3.small.x86",
+    "name": "c3.small.x86",
+    "description": "c3.small.x86 1x Intel Xeon E-2278G 8-Core Processor @ 3.40GHz",
+    "line": "baremetal",
+    "legacy": false,
+    "class": "c3.small.x86",
+    "deployment_types": ["on_demand", "spot_market"],
+    "pricing": {"hour": 0.5, "month": 365.35},
+    "available_in": [{"href": "/metal/v1/facilities/8e6470b3", "code": "ams1", "price": {"hour": 0.5}}],
+    "available_in_metros": [{"href": "/metal/v1/locations/metros/d50fd052", "code": "am", "price": {"hour": 0.5}}, {"href": "/metal/v1/locations/metros/108b2cfb", "code": "da", "price": {"hour": 0.5}}]
+  },
+  {
+    "id": "4a9a7a7e-1c8e-4d4a-9f0e-6c5d3b2a1e0f",
+    "slug": "baremetal_0",
+    "name": "t1.small.x86",
+    "line": "baremetal",
+    "legacy": true,
+    "class": "t1.small.x86",
+    "deployment_types": ["on_demand"],
+    "available_in": [],
+    "available_in_metros": []
+  }
+]}`
+
+// packngoGetPlans and packngoFlattenPlan are the packngo implementation of
+// getPlans and flattenPlan, kept to compare the states both write
+func packngoGetPlans(meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	client := packngoClient(meta)
+	opts := &packngo.ListOptions{
+		Includes: []string{"available_in", "available_in_metros"},
+	}
+	plans, _, err := client.Plans.List(opts)
+	plansIf := []interface{}{}
+	for _, p := range plans {
+		plansIf = append(plansIf, p)
+	}
+	return plansIf, err
+}
+
+func packngoFlattenPlan(rawPlan interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	plan, ok := rawPlan.(packngo.Plan)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert to packngo.Plan")
+	}
+	facs := []string{}
+	for _, f := range plan.AvailableIn {
+		facs = append(facs, f.Code)
+	}
+	metros := []string{}
+	for _, m := range plan.AvailableInMetros {
+		metros = append(metros, m.Code)
+	}
+	flattenedPlan := map[string]interface{}{
+		"id":                  plan.ID,
+		"name":                plan.Name,
+		"slug":                plan.Slug,
+		"description":         plan.Description,
+		"line":                plan.Line,
+		"legacy":              plan.Legacy,
+		"class":               plan.Class,
+		"deployment_types":    schema.NewSet(schema.HashString, converters.StringArrToIfArr(plan.DeploymentTypes)),
+		"available_in":        schema.NewSet(schema.HashString, converters.StringArrToIfArr(facs)),
+		"available_in_metros": schema.NewSet(schema.HashString, converters.StringArrToIfArr(metros)),
+	}
+	if plan.Pricing != nil {
+		flattenedPlan["pricing_hour"] = float64(plan.Pricing.Hour)
+		flattenedPlan["pricing_month"] = float64(plan.Pricing.Month)
+	}
+	return flattenedPlan, nil
+}
+
+// fixedDataListID replaces the unique ID that data list reads generate, for
+// the states of two reads to compare
+func fixedDataListID(read schema.ReadContextFunc) metalReadFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := read(ctx, d, meta)
+		d.SetId("datalist")
+		return diags
+	}
+}
+
+func TestDataSourceMetalPlansRead_recorded(t *testing.T) {
+	r := dataSourceMetalPlans()
+	packngoPlans := datalist.NewResource(&datalist.ResourceConfig{
+		RecordSchema:        planSchema(),
+		ResultAttributeName: "plans",
+		FlattenRecord:       packngoFlattenPlan,
+		GetRecords:          packngoGetPlans,
+	})
+	state := assertMetalReadParity(t, r, "", map[string]interface{}{
+		"sort": []interface{}{map[string]interface{}{"attribute": "name", "direction": "asc"}},
+	}, map[string]string{
+		"GET /metal/v1/plans": recordedMetalPlans,
+	}, fixedDataListID(r.ReadContext), fixedDataListID(packngoPlans.ReadContext))
+
+	assert.Equal(t, "2", state["plans.#"])
+	assert.Equal(t, "c3.small.x86", state["plans.0.slug"])
+	assert.Equal(t, "2", state["plans.0.available_in_metros.#"])
+	assert.Equal(t, "0.5", state["plans.0.pricing_hour"])
+	assert.Equal(t, "true", state["plans.1.legacy"])
+}
@@ -1,14 +1,15 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalPreCreatedIPBlock() *schema.Resource {
@@ -62,14 +63,14 @@ func dataSourceMetalPreCreatedIPBlock() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Read:   dataSourceMetalPreCreatedIPBlockRead,
-		Schema: s,
+		ReadContext: dataSourceMetalPreCreatedIPBlockRead,
+		Schema:      s,
 	}
 }
 
-func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceMetalPreCreatedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var types string
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	projectID := d.Get("project_id").(string)
 
 	ipv := d.Get("address_family").(int)
@@ -79,11 +80,11 @@ func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface
 	mval, mok := d.GetOk("metro")
 
 	if !public && global {
-		return fmt.Errorf("private (non-public) global IP address blocks are not supported in Equinix Metal")
+		return diag.Errorf("private (non-public) global IP address blocks are not supported in Equinix Metal")
 	}
 
 	if (fok || mok) && global {
-		return fmt.Errorf("you can't specify facility for global IP block - addresses from global blocks can be assigned to devices across several locations")
+		return diag.Errorf("you can't specify facility for global IP block - addresses from global blocks can be assigned to devices across several locations")
 	}
 
 	// Public and Address Family are required, prefilter types list based on
@@ -103,12 +104,9 @@ func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface
 		}
 	}
 
-	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
-	getOpts = getOpts.Filter("types", types)
-
-	ips, _, err := client.ProjectIPs.List(projectID, getOpts)
+	ips, resp, err := listMetalIPReservations(ctx, client, projectID, metalIPReservationQuery(types))
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	log.Printf("[DEBUG] filtering ips by (project: %s, facility: %s, metro: %s, family: %d, global: %t)", projectID, fval.(string), mval.(string), ipv, global)
@@ -121,7 +119,7 @@ func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface
 				continue
 			}
 			if ip.Public == public && ip.AddressFamily == ipv && facility == ip.Facility.Code {
-				return loadBlock(d, &ip)
+				return diag.FromErr(loadBlock(d, &ip))
 			}
 		}
 	} else if mok {
@@ -136,17 +134,17 @@ func dataSourceMetalPreCreatedIPBlockRead(d *schema.ResourceData, meta interface
 				ipMetro = ip.Facility.Metro
 			}
 			if ip.Public == public && ip.AddressFamily == ipv && metro == ipMetro.Code {
-				return loadBlock(d, &ip)
+				return diag.FromErr(loadBlock(d, &ip))
 			}
 		}
 	} else {
 		// lookup of blocks not specified with facility or metro
 		for _, ip := range ips {
 			if ip.Public == public && ip.AddressFamily == ipv && global == ip.Global {
-				return loadBlock(d, &ip)
+				return diag.FromErr(loadBlock(d, &ip))
 			}
 		}
 	}
 	log.Printf("[DEBUG] filter not matched in response ips: %v", ips)
-	return fmt.Errorf("could not find matching reserved block")
+	return diag.Errorf("could not find matching reserved block")
 }
@@ -0,0 +1,106 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+// packngoDataSourceMetalPreCreatedIPBlockRead is the packngo implementation
+// of dataSourceMetalPreCreatedIPBlockRead, kept to compare the states both
+// write
+func packngoDataSourceMetalPreCreatedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var types string
+	client := packngoClient(meta)
+	projectID := d.Get("project_id").(string)
+
+	ipv := d.Get("address_family").(int)
+	public := d.Get("public").(bool)
+	global := d.Get("global").(bool)
+	fval, fok := d.GetOk("facility")
+	mval, mok := d.GetOk("metro")
+
+	switch {
+	case global:
+		types = "global_ipv4"
+	case !public:
+		types = "private_ipv4,vrf"
+	case public:
+		switch ipv {
+		case 4:
+			types = "public_ipv4,global_ipv4"
+		case 6:
+			types = "public_ipv6"
+		}
+	}
+
+	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
+	getOpts = getOpts.Filter("types", types)
+
+	ips, _, err := client.ProjectIPs.List(projectID, getOpts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if fok {
+		for _, ip := range ips {
+			if ip.Facility == nil {
+				continue
+			}
+			if ip.Public == public && ip.AddressFamily == ipv && fval.(string) == ip.Facility.Code {
+				return diag.FromErr(packngoLoadBlock(d, &ip))
+			}
+		}
+	} else if mok {
+		for _, ip := range ips {
+			ipMetro := ip.Metro
+			if ip.Metro == nil {
+				if ip.Facility.Metro == nil {
+					continue
+				}
+				ipMetro = ip.Facility.Metro
+			}
+			if ip.Public == public && ip.AddressFamily == ipv && mval.(string) == ipMetro.Code {
+				return diag.FromErr(packngoLoadBlock(d, &ip))
+			}
+		}
+	} else {
+		for _, ip := range ips {
+			if ip.Public == public && ip.AddressFamily == ipv && global == ip.Global {
+				return diag.FromErr(packngoLoadBlock(d, &ip))
+			}
+		}
+	}
+	return diag.Errorf("could not find matching reserved block")
+}
+
+func TestDataSourceMetalPreCreatedIPBlockRead_recorded(t *testing.T) {
+	for name, tc := range map[string]struct {
+		raw  map[string]interface{}
+		want string
+	}{
+		"facility": {
+			raw:  map[string]interface{}{"facility": "ams1", "address_family": 4, "public": true},
+			want: "147.75.100.8/30",
+		},
+		"metro of facility": {
+			raw:  map[string]interface{}{"metro": "da", "address_family": 6, "public": true},
+			want: "2604:1380:4641:c500::/56",
+		},
+		"global": {
+			raw:  map[string]interface{}{"address_family": 4, "public": true, "global": true},
+			want: "147.75.40.3/32",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc.raw["project_id"] = testMetalIPProjectID
+			state := assertMetalReadParity(t, dataSourceMetalPreCreatedIPBlock(), "", tc.raw, recordedMetalIPBlocks,
+				dataSourceMetalPreCreatedIPBlockRead, packngoDataSourceMetalPreCreatedIPBlockRead)
+			assert.Equal(t, tc.want, state["cidr_notation"])
+		})
+	}
+}
@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
-	"github.com/packethost/packngo"
 )
 
 func TestAccDataSourceMetalProject_basic(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rn := acctest.RandStringFromCharSet(12, "abcdef0123456789")
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -0,0 +1,224 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceMetalProjectUsage() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataSourceMetalProjectUsageRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Description: "The UUID of the project",
+				Required:    true,
+			},
+			"start_date": {
+				Type:         schema.TypeString,
+				Description:  "Only usage created after this RFC 3339 timestamp is returned",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"end_date": {
+				Type:         schema.TypeString,
+				Description:  "Only usage created before this RFC 3339 timestamp is returned",
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"line_items": {
+				Type:        schema.TypeList,
+				Description: "Usage of the project, grouped by resource type, plan, metro, unit and price",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Description: "Type of the used resource, e.g. Instance or IP address",
+							Computed:    true,
+						},
+						"plan": {
+							Type:        schema.TypeString,
+							Description: "Plan of the used resource",
+							Computed:    true,
+						},
+						"metro": {
+							Type:        schema.TypeString,
+							Description: "Metro of the used resource, empty for global resources",
+							Computed:    true,
+						},
+						"unit": {
+							Type:        schema.TypeString,
+							Description: "Unit of the quantity, e.g. hour",
+							Computed:    true,
+						},
+						"price": {
+							Type:        schema.TypeFloat,
+							Description: "Price of one unit",
+							Computed:    true,
+						},
+						"quantity": {
+							Type:        schema.TypeFloat,
+							Description: "Used quantity, in units",
+							Computed:    true,
+						},
+						"total": {
+							Type:        schema.TypeFloat,
+							Description: "Total price of the line item",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"total": {
+				Type:        schema.TypeFloat,
+				Description: "Total price of the usage of the project",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// projectUsageLineItem is the usage of the resources of one type, plan and
+// metro, billed at the same unit price
+type projectUsageLineItem struct {
+	Type     string
+	Plan     string
+	Metro    string
+	Unit     string
+	Price    float64
+	Quantity float64
+	Total    float64
+}
+
+func dataSourceMetalProjectUsageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	projectID := d.Get("project_id").(string)
+	request := client.UsagesApi.FindProjectUsage(ctx, projectID)
+	if startDate, ok := d.GetOk("start_date"); ok {
+		request = request.CreatedAfter(startDate.(string))
+	}
+	if endDate, ok := d.GetOk("end_date"); ok {
+		request = request.CreatedBefore(endDate.(string))
+	}
+
+	usages, resp, err := request.Execute()
+	if err != nil {
+		return diag.Errorf("error reading usage of project %s: %s", projectID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	lineItems, err := groupProjectUsage(ctx, meta, usages.GetUsages())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	total := 0.0
+	items := make([]map[string]interface{}, 0, len(lineItems))
+	for _, item := range lineItems {
+		total += item.Total
+		items = append(items, map[string]interface{}{
+			"type":     item.Type,
+			"plan":     item.Plan,
+			"metro":    item.Metro,
+			"unit":     item.Unit,
+			"price":    item.Price,
+			"quantity": item.Quantity,
+			"total":    item.Total,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s-%s", projectID, d.Get("start_date").(string), d.Get("end_date").(string)))
+	if err := d.Set("line_items", items); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(d.Set("total", total))
+}
+
+// groupProjectUsage sums the usages of the same type, plan, metro, unit and
+// price. The line items are sorted by type, plan and metro.
+func groupProjectUsage(ctx context.Context, meta interface{}, usages []metalv1.ProjectUsage) ([]projectUsageLineItem, error) {
+	var lineItems []projectUsageLineItem
+	index := map[projectUsageLineItem]int{}
+
+	for _, u := range usages {
+		price, err := parseUsageNumber(u.GetPrice())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing price of usage %s: %w", u.GetName(), err)
+		}
+		quantity, err := parseUsageNumber(u.GetQuantity())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing quantity of usage %s: %w", u.GetName(), err)
+		}
+		total, err := parseUsageNumber(u.GetTotal())
+		if err != nil {
+			return nil, fmt.Errorf("error parsing total of usage %s: %w", u.GetName(), err)
+		}
+
+		key := projectUsageLineItem{
+			Type:  u.GetType(),
+			Plan:  u.GetPlan(),
+			Metro: projectUsageMetro(ctx, meta, u),
+			Unit:  u.GetUnit(),
+			Price: price,
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(lineItems)
+			index[key] = i
+			lineItems = append(lineItems, key)
+		}
+		lineItems[i].Quantity += quantity
+		lineItems[i].Total += total
+	}
+
+	sort.SliceStable(lineItems, func(i, j int) bool {
+		a, b := lineItems[i], lineItems[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Plan != b.Plan {
+			return a.Plan < b.Plan
+		}
+		return a.Metro < b.Metro
+	})
+	return lineItems, nil
+}
+
+// projectUsageMetro returns the metro of the usage. Usages only name the
+// facility of resources created in a facility, its metro is looked up.
+func projectUsageMetro(ctx context.Context, meta interface{}, u metalv1.ProjectUsage) string {
+	if metro, ok := u.AdditionalProperties["metro"].(string); ok && metro != "" {
+		return strings.ToLower(metro)
+	}
+	facility := u.GetFacility()
+	if facility == "" {
+		return ""
+	}
+	metro, err := facilityMetroCode(ctx, meta, facility)
+	if err != nil {
+		log.Printf("[WARN] Could not find the metro of facility %s, the usage of %s is reported without a metro: %s", facility, u.GetName(), err)
+		return ""
+	}
+	return metro
+}
+
+func parseUsageNumber(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
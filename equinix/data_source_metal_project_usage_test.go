@@ -0,0 +1,50 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalProjectUsage = `{
+  "usages": [
+    {"name": "node-1", "type": "Instance", "plan": "c3.small.x86", "plan_version": "c3.small.x86", "facility": "sv15", "unit": "hour", "price": "0.5", "quantity": "10", "total": "5.0"},
+    {"name": "node-2", "type": "Instance", "plan": "c3.small.x86", "plan_version": "c3.small.x86", "facility": "sv15", "unit": "hour", "price": "0.5", "quantity": "4", "total": "2.0"},
+    {"name": "node-3", "type": "Instance", "plan": "c3.small.x86", "plan_version": "c3.small.x86", "metro": "DA", "unit": "hour", "price": "0.5", "quantity": "2", "total": "1.0"},
+    {"name": "global-ip", "type": "IP Address", "plan": "global_ipv4", "unit": "hour", "price": "0.15", "quantity": "20", "total": "3.0"}
+  ]
+}`
+
+func TestDataSourceMetalProjectUsageRead_recorded(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/usages": recordedMetalProjectUsage,
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+
+	d := dataSourceMetalProjectUsage().TestResourceData()
+	d.Set("project_id", "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de")
+	d.Set("start_date", "2023-06-01T00:00:00Z")
+	diags := dataSourceMetalProjectUsageRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de-2023-06-01T00:00:00Z-", d.Id())
+	assert.Equal(t, 11.0, d.Get("total"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "IP Address", "plan": "global_ipv4", "metro": "", "unit": "hour", "price": 0.15, "quantity": 20.0, "total": 3.0},
+		map[string]interface{}{"type": "Instance", "plan": "c3.small.x86", "metro": "da", "unit": "hour", "price": 0.5, "quantity": 2.0, "total": 1.0},
+		map[string]interface{}{"type": "Instance", "plan": "c3.small.x86", "metro": "sv", "unit": "hour", "price": 0.5, "quantity": 14.0, "total": 7.0},
+	}, d.Get("line_items"))
+}
+
+func TestDataSourceMetalProjectUsageRead_invalidNumber(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/usages": `{"usages": [{"name": "node-1", "type": "Instance", "price": "n/a"}]}`,
+	})
+
+	d := dataSourceMetalProjectUsage().TestResourceData()
+	d.Set("project_id", "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de")
+	diags := dataSourceMetalProjectUsageRead(context.Background(), d, meta)
+	assert.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "error parsing price of usage node-1")
+}
@@ -1,19 +1,21 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalReservedIPBlock() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalReservedIPBlockRead,
+		ReadContext: dataSourceMetalReservedIPBlockRead,
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:          schema.TypeString,
@@ -120,53 +122,51 @@ func dataSourceMetalReservedIPBlock() *schema.Resource {
 	}
 }
 
-func dataSourceMetalReservedIPBlockRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalReservedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	blockId, blockIdOk := d.GetOk("id")
 	projectId, projectIdOk := d.GetOk("project_id")
 	address, addressOk := d.GetOk("ip_address")
-	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
-	getOpts = getOpts.Filter("types", "public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
+	query := metalIPReservationQuery("public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
 
 	if !(blockIdOk || (projectIdOk && addressOk)) {
-		return fmt.Errorf("you must specify either id or project_id and ip_address")
+		return diag.Errorf("you must specify either id or project_id and ip_address")
 	}
 	if blockIdOk {
-		block, _, err := client.ProjectIPs.Get(
-			blockId.(string), getOpts)
+		block, resp, err := getMetalIPReservation(ctx, client, blockId.(string), query)
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
-		return loadBlock(d, block)
+		return diag.FromErr(loadBlock(d, block))
 	}
 	// we search by project_id and ip_address
 	addressStr := address.(string)
 	lookupAddress := net.ParseIP(addressStr)
 	if lookupAddress == nil {
-		return fmt.Errorf("%s is not a valid ip_address", addressStr)
+		return diag.Errorf("%s is not a valid ip_address", addressStr)
 	}
 
-	blocks, _, err := client.ProjectIPs.List(projectId.(string), getOpts)
+	blocks, resp, err := listMetalIPReservations(ctx, client, projectId.(string), query)
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 	for _, b := range blocks {
 		cidr := fmt.Sprintf("%s/%d", b.Network, b.CIDR)
 		_, ipNet, err := net.ParseCIDR(cidr)
 		if err != nil {
-			return fmt.Errorf("CIDR expression of an Equinix Metal IP Block could not be parsed: %s. Please report this in a GitHub issue", cidr)
+			return diag.Errorf("CIDR expression of an Equinix Metal IP Block could not be parsed: %s. Please report this in a GitHub issue", cidr)
 		}
 
 		if ipNet.Contains(lookupAddress) {
 			d.Set("id", b.ID)
-			return loadBlock(d, &b)
+			return diag.FromErr(loadBlock(d, &b))
 		}
 	}
-	return fmt.Errorf("could not find matching reserved block, all blocks were \n%s", listOfCidrs(blocks))
+	return diag.Errorf("could not find matching reserved block, all blocks were \n%s", listOfCidrs(blocks))
 }
 
-func listOfCidrs(blocks []packngo.IPAddressReservation) string {
+func listOfCidrs(blocks []metalIPReservation) string {
 	cidrs := []string{}
 	for _, b := range blocks {
 		cidrs = append(cidrs, fmt.Sprintf("%s/%d", b.Network, b.CIDR))
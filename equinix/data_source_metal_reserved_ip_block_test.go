@@ -0,0 +1,78 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+// packngoDataSourceMetalReservedIPBlockRead is the packngo implementation of
+// dataSourceMetalReservedIPBlockRead, kept to compare the states both write
+func packngoDataSourceMetalReservedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	blockId, blockIdOk := d.GetOk("id")
+	projectId, projectIdOk := d.GetOk("project_id")
+	address, addressOk := d.GetOk("ip_address")
+	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
+	getOpts = getOpts.Filter("types", "public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
+
+	if !(blockIdOk || (projectIdOk && addressOk)) {
+		return diag.Errorf("you must specify either id or project_id and ip_address")
+	}
+	if blockIdOk {
+		block, _, err := client.ProjectIPs.Get(blockId.(string), getOpts)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		return diag.FromErr(packngoLoadBlock(d, block))
+	}
+	lookupAddress := net.ParseIP(address.(string))
+	blocks, _, err := client.ProjectIPs.List(projectId.(string), getOpts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, b := range blocks {
+		_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", b.Network, b.CIDR))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if ipNet.Contains(lookupAddress) {
+			d.Set("id", b.ID)
+			return diag.FromErr(packngoLoadBlock(d, &b))
+		}
+	}
+	return diag.Errorf("could not find matching reserved block")
+}
+
+func TestDataSourceMetalReservedIPBlockRead_recorded(t *testing.T) {
+	byID := assertMetalReadParity(t, dataSourceMetalReservedIPBlock(), testMetalPublicIPBlockID, nil, recordedMetalIPBlocks, dataSourceMetalReservedIPBlockRead, packngoDataSourceMetalReservedIPBlockRead)
+	assert.Equal(t, "147.75.100.8/30", byID["cidr_notation"])
+
+	byAddress := assertMetalReadParity(t, dataSourceMetalReservedIPBlock(), "", map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+		"ip_address": "147.75.100.10",
+	}, recordedMetalIPBlocks, dataSourceMetalReservedIPBlockRead, packngoDataSourceMetalReservedIPBlockRead)
+	assert.Equal(t, testMetalPublicIPBlockID, byAddress["id"])
+	assert.Equal(t, "ams1", byAddress["facility"])
+}
+
+func TestDataSourceMetalReservedIPBlockRead_noMatch(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, recordedMetalIPBlocks)
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalReservedIPBlock().Schema, map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+		"ip_address": "10.0.0.1",
+	})
+	diags := dataSourceMetalReservedIPBlockRead(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected an error for an address outside all blocks")
+	}
+	assert.Contains(t, diags[0].Summary, "147.75.100.8/30")
+}
@@ -1,19 +1,22 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceSpotMarketPrice() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalSpotMarketPriceRead,
+		ReadContext: dataSourceMetalSpotMarketPriceRead,
 		Schema: map[string]*schema.Schema{
 			"facility": {
 				Type:          schema.TypeString,
@@ -43,43 +46,51 @@ func dataSourceSpotMarketPrice() *schema.Resource {
 	}
 }
 
-func dataSourceMetalSpotMarketPriceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
-	sms := client.SpotMarket.(*packngo.SpotMarketServiceOp)
+// metalSpotMarketPrices are the spot market prices by facility or metro, then
+// by plan. metal-go models the prices with a field for each location.
+type metalSpotMarketPrices struct {
+	Prices map[string]map[string]struct {
+		Price float64 `json:"price"`
+	} `json:"spot_market_prices"`
+}
+
+func dataSourceMetalSpotMarketPriceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	facility := d.Get("facility").(string)
 	metro := d.Get("metro").(string)
 	plan := d.Get("plan").(string)
 
 	if facility != "" && metro != "" {
-		return fmt.Errorf("Parameters facility and metro cannot be used together")
+		return diag.Errorf("Parameters facility and metro cannot be used together")
 	}
 
 	filter := facility
-	fn := sms.PricesByFacility
+	path := "market/spot/prices"
 	filterType := "facility"
 
 	if metro != "" {
 		filter = metro
-		fn = sms.PricesByMetro
+		path = "market/spot/prices/metros"
 		filterType = "metro"
 	}
 
-	prices, _, err := fn()
+	prices := metalSpotMarketPrices{}
+	resp, err := metalRequest(ctx, client, http.MethodGet, path, nil, &prices)
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	match, ok := prices[filter]
+	match, ok := prices.Prices[filter]
 	if !ok {
-		return fmt.Errorf("Cannot find %s %s", filterType, filter)
+		return diag.Errorf("Cannot find %s %s", filterType, filter)
 	}
 
 	price, ok := match[plan]
 	if !ok {
-		return fmt.Errorf("Cannot find price for plan %s in %s %s", plan, filterType, filter)
+		return diag.Errorf("Cannot find price for plan %s in %s %s", plan, filterType, filter)
 	}
 
-	d.Set("price", price)
+	d.Set("price", price.Price)
 	d.SetId(fmt.Sprintf("%s-%s-%s", filterType, filter, plan))
 	return nil
 }
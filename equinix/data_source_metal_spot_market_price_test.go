@@ -0,0 +1,62 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+var recordedMetalSpotMarketPrices = map[string]string{
+	"GET /metal/v1/market/spot/prices":        `{"spot_market_prices": {"ams1": {"c3.small.x86": {"price": 0.15}, "m3.large.x86": {"price": 0.62}}}}`,
+	"GET /metal/v1/market/spot/prices/metros": `{"spot_market_prices": {"sv": {"c3.small.x86": {"price": 0.12}}, "am": {"c3.small.x86": {"price": 0.15}}}}`,
+}
+
+// packngoDataSourceMetalSpotMarketPriceRead is the packngo implementation of
+// dataSourceMetalSpotMarketPriceRead, kept to compare the states both write
+func packngoDataSourceMetalSpotMarketPriceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	sms := packngoClient(meta).SpotMarket.(*packngo.SpotMarketServiceOp)
+	filter, fn, filterType := d.Get("facility").(string), sms.PricesByFacility, "facility"
+	if metro := d.Get("metro").(string); metro != "" {
+		filter, fn, filterType = metro, sms.PricesByMetro, "metro"
+	}
+	prices, _, err := fn()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	price, ok := prices[filter][d.Get("plan").(string)]
+	if !ok {
+		return diag.Errorf("Cannot find price")
+	}
+	d.Set("price", price)
+	d.SetId(fmt.Sprintf("%s-%s-%s", filterType, filter, d.Get("plan").(string)))
+	return nil
+}
+
+func TestDataSourceMetalSpotMarketPriceRead_recorded(t *testing.T) {
+	for _, raw := range []map[string]interface{}{
+		{"facility": "ams1", "plan": "m3.large.x86"},
+		{"metro": "sv", "plan": "c3.small.x86"},
+	} {
+		state := assertMetalReadParity(t, dataSourceSpotMarketPrice(), "", raw, recordedMetalSpotMarketPrices,
+			dataSourceMetalSpotMarketPriceRead, packngoDataSourceMetalSpotMarketPriceRead)
+		assert.NotEqual(t, "0", state["price"])
+	}
+}
+
+func TestDataSourceMetalSpotMarketPriceRead_unknownPlan(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, recordedMetalSpotMarketPrices)
+
+	d := schema.TestResourceDataRaw(t, dataSourceSpotMarketPrice().Schema, map[string]interface{}{
+		"metro": "am",
+		"plan":  "m3.large.x86",
+	})
+	diags := dataSourceMetalSpotMarketPriceRead(context.Background(), d, meta)
+	if assert.True(t, diags.HasError()) {
+		assert.Equal(t, "Cannot find price for plan m3.large.x86 in metro am", diags[0].Summary)
+	}
+}
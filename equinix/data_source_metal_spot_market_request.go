@@ -13,7 +13,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalSpotMarketRequest() *schema.Resource {
@@ -85,12 +84,11 @@ func dataSourceMetalSpotMarketRequest() *schema.Resource {
 }
 
 func dataSourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	id := d.Get("request_id").(string)
 
-	smr, _, err := client.SpotMarketRequests.Get(id, &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+	smr, err := getSpotMarketRequest(ctx, client, id, "project", "devices", "facilities", "metro")
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
@@ -98,15 +96,8 @@ func dataSourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	deviceIDs := make([]string, len(smr.Devices))
-	for i, d := range smr.Devices {
-		deviceIDs[i] = d.ID
-	}
-
-	facs := smr.Facilities
 	facCodes := []string{}
-
-	for _, f := range facs {
+	for _, f := range smr.Facilities {
 		facCodes = append(facCodes, f.Code)
 	}
 	sort.Strings(facCodes) // avoid changes if we get the same facilities in a different order
@@ -114,7 +105,7 @@ func dataSourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.Resourc
 	d.SetId(id)
 
 	err = equinix_schema.SetMap(d, map[string]interface{}{
-		"device_ids": deviceIDs,
+		"device_ids": smr.DeviceIDs(),
 		"end_at": func(d *schema.ResourceData, k string) error {
 			if smr.EndAt != nil {
 				return d.Set(k, smr.EndAt.Format(time.RFC3339))
@@ -132,8 +123,7 @@ func dataSourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.Resourc
 		},
 		"max_bid_price": smr.MaxBidPrice,
 		"plan":          smr.Plan.Slug,
-		"project_id":    smr.Project.ID,
-		// TODO: created_at is not in packngo
+		"project_id":    smr.ProjectID(),
 	})
 
 	return diag.FromErr(err)
@@ -7,14 +7,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func TestAccDataSourceMetalSpotMarketRequest_basic(t *testing.T) {
 	projectName := fmt.Sprintf("ds-device-%s", acctest.RandString(10))
 	var (
-		facKey packngo.SpotMarketRequest
-		metKey packngo.SpotMarketRequest
+		facKey metalSpotMarketRequest
+		metKey metalSpotMarketRequest
 	)
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -0,0 +1,108 @@
+package equinix
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalSpotMarketRequest = `{
+  "id": "c2b4d6f8-0a1c-4e3f-8b5d-7a9c1e3f5b7d",
+  "devices_min": 1,
+  "devices_max": 2,
+  "max_bid_price": 0.45,
+  "end_at": "2026-10-20T10:00:00Z",
+  "facilities": [{"id": "f2", "code": "sv15"}, {"id": "f1", "code": "sv16"}],
+  "metro": {"id": "m1", "code": "SV"},
+  "plan": {"slug": "c3.small.x86"},
+  "project": {"id": "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e", "href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+  "devices": [{"id": "0d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a"}, {"id": "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9"}]
+}`
+
+// packngoDataSourceMetalSpotMarketRequestRead is the packngo implementation
+// of dataSourceMetalSpotMarketRequestRead, kept to compare the states both
+// write
+func packngoDataSourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	id := d.Get("request_id").(string)
+
+	smr, _, err := client.SpotMarketRequests.Get(id, &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	deviceIDs := make([]string, len(smr.Devices))
+	for i, d := range smr.Devices {
+		deviceIDs[i] = d.ID
+	}
+	facCodes := []string{}
+	for _, f := range smr.Facilities {
+		facCodes = append(facCodes, f.Code)
+	}
+	sort.Strings(facCodes)
+
+	d.SetId(id)
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"device_ids": deviceIDs,
+		"end_at": func(d *schema.ResourceData, k string) error {
+			if smr.EndAt != nil {
+				return d.Set(k, smr.EndAt.Format(time.RFC3339))
+			}
+			return nil
+		},
+		"devices_max": smr.DevicesMax,
+		"devices_min": smr.DevicesMin,
+		"facilities":  facCodes,
+		"metro": func(d *schema.ResourceData, k string) error {
+			if smr.Metro != nil {
+				return d.Set(k, strings.ToLower(smr.Metro.Code))
+			}
+			return nil
+		},
+		"max_bid_price": smr.MaxBidPrice,
+		"plan":          smr.Plan.Slug,
+		"project_id":    smr.Project.ID,
+	}))
+}
+
+func TestDataSourceMetalSpotMarketRequestRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceMetalSpotMarketRequest(), "", map[string]interface{}{
+		"request_id": "c2b4d6f8-0a1c-4e3f-8b5d-7a9c1e3f5b7d",
+	}, map[string]string{
+		"GET /metal/v1/spot-market-requests/c2b4d6f8-0a1c-4e3f-8b5d-7a9c1e3f5b7d": recordedMetalSpotMarketRequest,
+	}, dataSourceMetalSpotMarketRequestRead, packngoDataSourceMetalSpotMarketRequestRead)
+	assert.Equal(t, "2", state["device_ids.#"])
+	assert.Equal(t, "sv15", state["facilities.0"])
+	assert.Equal(t, "sv", state["metro"])
+	assert.Equal(t, "2026-10-20T10:00:00Z", state["end_at"])
+	assert.Equal(t, "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e", state["project_id"])
+}
+
+func TestDataSourceMetalSpotMarketRequestRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalSpotMarketRequest().Schema, map[string]interface{}{
+		"request_id": "gone",
+	})
+	d.SetId("gone")
+	if diags := dataSourceMetalSpotMarketRequestRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Empty(t, d.Id())
+}
@@ -1,7 +1,10 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net/http"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
@@ -10,15 +13,16 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceMetalVlan() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalVlanRead,
+		ReadContext: dataSourceMetalVlanRead,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -69,12 +73,17 @@ func dataSourceMetalVlan() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "List of device IDs to which this VLAN is assigned",
 			},
+			"last_modified_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The email, name or id of the user or API key which made the latest change to the VLAN, according to the events of its project",
+			},
 		},
 	}
 }
 
-func dataSourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalVlanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	projectRaw, projectOk := d.GetOk("project_id")
 	vxlanRaw, vxlanOk := d.GetOk("vxlan")
@@ -83,19 +92,17 @@ func dataSourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
 	facilityRaw, facilityOk := d.GetOk("facility")
 
 	if !(vlanIdOk || (vxlanOk || projectOk || metroOk || facilityOk)) {
-		return equinix_errors.FriendlyError(fmt.Errorf("You must set either vlan_id or a combination of vxlan, project_id, and, metro or facility"))
+		return diag.Errorf("You must set either vlan_id or a combination of vxlan, project_id, and, metro or facility")
 	}
 
-	var vlan *packngo.VirtualNetwork
+	var vlan *metalv1.VirtualNetwork
 
 	if vlanIdOk {
+		var resp *http.Response
 		var err error
-		vlan, _, err = client.ProjectVirtualNetworks.Get(
-			vlanIdRaw.(string),
-			&packngo.GetOptions{Includes: []string{"assigned_to"}},
-		)
+		vlan, resp, err = client.VLANsApi.GetVirtualNetwork(ctx, vlanIdRaw.(string)).Include([]string{"assigned_to"}).Execute()
 		if err != nil {
-			return equinix_errors.FriendlyError(err)
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 
 	} else {
@@ -103,57 +110,57 @@ func dataSourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
 		vxlan := vxlanRaw.(int)
 		metro := metroRaw.(string)
 		facility := facilityRaw.(string)
-		vlans, _, err := client.ProjectVirtualNetworks.List(
-			projectRaw.(string),
-			&packngo.GetOptions{Includes: []string{"assigned_to"}},
-		)
+		vlans, resp, err := client.VLANsApi.FindVirtualNetworks(ctx, projectID).Include([]string{"assigned_to"}).Execute()
 		if err != nil {
-			return equinix_errors.FriendlyError(err)
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 
 		vlan, err = matchingVlan(vlans.VirtualNetworks, vxlan, projectID, facility, metro)
 		if err != nil {
-			return equinix_errors.FriendlyError(err)
+			return diag.FromErr(err)
 		}
 	}
 
-	assignedDevices := []string{}
-	for _, d := range vlan.Instances {
-		assignedDevices = append(assignedDevices, d.ID)
+	// VLANs have no list of events, their events are in the events of the project
+	eventsPager := metalEventsPager(ctx, client, "", vlanProjectID(vlan))
+	lastModifiedBy, err := metalLastModifiedBy(eventsPager, vlan.GetId())
+	if err != nil {
+		log.Printf("[WARN] Could not read the events of VLAN %s, last_modified_by is not set: %s", vlan.GetId(), err)
 	}
 
-	d.SetId(vlan.ID)
-
-	return equinix_schema.SetMap(d, map[string]interface{}{
-		"vlan_id":     vlan.ID,
-		"project_id":  vlan.Project.ID,
-		"vxlan":       vlan.VXLAN,
-		"facility":    vlan.FacilityCode,
-		"metro":       vlan.MetroCode,
-		"description": vlan.Description,
-	})
+	d.SetId(vlan.GetId())
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"vlan_id":          vlan.GetId(),
+		"project_id":       vlanProjectID(vlan),
+		"vxlan":            int(vlan.GetVxlan()),
+		"facility":         vlanFacilityCode(vlan),
+		"metro":            vlan.GetMetroCode(),
+		"description":      vlan.GetDescription(),
+		"last_modified_by": lastModifiedBy,
+	}))
 }
 
-func matchingVlan(vlans []packngo.VirtualNetwork, vxlan int, projectID, facility, metro string) (*packngo.VirtualNetwork, error) {
-	matches := []packngo.VirtualNetwork{}
+func matchingVlan(vlans []metalv1.VirtualNetwork, vxlan int, projectID, facility, metro string) (*metalv1.VirtualNetwork, error) {
+	matches := []metalv1.VirtualNetwork{}
 	for _, v := range vlans {
-		if vxlan != 0 && v.VXLAN != vxlan {
+		if vxlan != 0 && int(v.GetVxlan()) != vxlan {
 			continue
 		}
-		if facility != "" && v.FacilityCode != facility {
+		if facility != "" && vlanFacilityCode(&v) != facility {
 			continue
 		}
-		if metro != "" && v.MetroCode != metro {
+		if metro != "" && v.GetMetroCode() != metro {
 			continue
 		}
 		matches = append(matches, v)
 	}
 	if len(matches) > 1 {
-		return nil, equinix_errors.FriendlyError(fmt.Errorf("Project %s has more than one matching VLAN", projectID))
+		return nil, fmt.Errorf("Project %s has more than one matching VLAN", projectID)
 	}
 
 	if len(matches) == 0 {
-		return nil, equinix_errors.FriendlyError(fmt.Errorf("Project %s does not have matching VLANs", projectID))
+		return nil, fmt.Errorf("Project %s does not have matching VLANs", projectID)
 	}
 	return &matches[0], nil
 }
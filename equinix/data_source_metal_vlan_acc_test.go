@@ -1,16 +1,17 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func TestAccDataSourceMetalVlan_byVxlanFacility(t *testing.T) {
@@ -223,9 +224,30 @@ data "equinix_metal_vlan" "dsvlan" {
 `, projSuffix, metro, desc)
 }
 
+// testMatchingVlan returns a VLAN as metal-go reads it, with the facility
+// code in the additional properties
+func testMatchingVlan(vxlan int32, facility, metro string) metalv1.VirtualNetwork {
+	vlan := metalv1.VirtualNetwork{AdditionalProperties: map[string]interface{}{}}
+	if vxlan != 0 {
+		vlan.Vxlan = metalv1.PtrInt32(vxlan)
+	}
+	if facility != "" {
+		vlan.AdditionalProperties["facility_code"] = facility
+	}
+	if metro != "" {
+		vlan.MetroCode = metalv1.PtrString(metro)
+	}
+	return vlan
+}
+
+func testMatchingVlanPtr(vxlan int32, facility, metro string) *metalv1.VirtualNetwork {
+	vlan := testMatchingVlan(vxlan, facility, metro)
+	return &vlan
+}
+
 func TestMetalVlan_matchingVlan(t *testing.T) {
 	type args struct {
-		vlans     []packngo.VirtualNetwork
+		vlans     []metalv1.VirtualNetwork
 		vxlan     int
 		projectID string
 		facility  string
@@ -234,52 +256,52 @@ func TestMetalVlan_matchingVlan(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    *packngo.VirtualNetwork
+		want    *metalv1.VirtualNetwork
 		wantErr bool
 	}{
 		{
 			name: "MatchingVLAN",
 			args: args{
-				vlans:     []packngo.VirtualNetwork{{VXLAN: 123}},
+				vlans:     []metalv1.VirtualNetwork{testMatchingVlan(123, "", "")},
 				vxlan:     123,
 				projectID: "",
 				facility:  "",
 				metro:     "",
 			},
-			want:    &packngo.VirtualNetwork{VXLAN: 123},
+			want:    testMatchingVlanPtr(123, "", ""),
 			wantErr: false,
 		},
 		{
 			name: "MatchingFac",
 			args: args{
-				vlans:    []packngo.VirtualNetwork{{FacilityCode: "fac"}},
+				vlans:    []metalv1.VirtualNetwork{testMatchingVlan(0, "fac", "")},
 				facility: "fac",
 			},
-			want:    &packngo.VirtualNetwork{FacilityCode: "fac"},
+			want:    testMatchingVlanPtr(0, "fac", ""),
 			wantErr: false,
 		},
 		{
 			name: "MatchingMet",
 			args: args{
-				vlans: []packngo.VirtualNetwork{{MetroCode: "met"}},
+				vlans: []metalv1.VirtualNetwork{testMatchingVlan(0, "", "met")},
 				metro: "met",
 			},
-			want:    &packngo.VirtualNetwork{MetroCode: "met"},
+			want:    testMatchingVlanPtr(0, "", "met"),
 			wantErr: false,
 		},
 		{
 			name: "SecondMatch",
 			args: args{
-				vlans: []packngo.VirtualNetwork{{FacilityCode: "fac"}, {MetroCode: "met"}},
+				vlans: []metalv1.VirtualNetwork{testMatchingVlan(0, "fac", ""), testMatchingVlan(0, "", "met")},
 				metro: "met",
 			},
-			want:    &packngo.VirtualNetwork{MetroCode: "met"},
+			want:    testMatchingVlanPtr(0, "", "met"),
 			wantErr: false,
 		},
 		{
 			name: "TwoMatches",
 			args: args{
-				vlans: []packngo.VirtualNetwork{{MetroCode: "met"}, {MetroCode: "met"}},
+				vlans: []metalv1.VirtualNetwork{testMatchingVlan(0, "", "met"), testMatchingVlan(0, "", "met")},
 				metro: "met",
 			},
 			want:    nil,
@@ -288,10 +310,10 @@ func TestMetalVlan_matchingVlan(t *testing.T) {
 		{
 			name: "ComplexMatch",
 			args: args{
-				vlans: []packngo.VirtualNetwork{{VXLAN: 987, FacilityCode: "fac", MetroCode: "skip"}, {VXLAN: 123, FacilityCode: "fac", MetroCode: "met"}, {VXLAN: 456, FacilityCode: "fac", MetroCode: "nope"}},
+				vlans: []metalv1.VirtualNetwork{testMatchingVlan(987, "fac", "skip"), testMatchingVlan(123, "fac", "met"), testMatchingVlan(456, "fac", "nope")},
 				metro: "met",
 			},
-			want:    &packngo.VirtualNetwork{VXLAN: 123, FacilityCode: "fac", MetroCode: "met"},
+			want:    testMatchingVlanPtr(123, "fac", "met"),
 			wantErr: false,
 		},
 		{
@@ -322,13 +344,13 @@ func TestMetalVlan_matchingVlan(t *testing.T) {
 }
 
 func testAccMetalDatasourceVlanCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_vlan" {
 			continue
 		}
-		if _, _, err := client.ProjectVirtualNetworks.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.VLANsApi.GetVirtualNetwork(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Data source VLAN still exists")
 		}
 	}
@@ -192,19 +192,19 @@ func createAccessPointLinkProtocolSch() map[string]*schema.Schema {
 			Type:        schema.TypeInt,
 			Optional:    true,
 			Computed:    true,
-			Description: "Vlan Tag information, vlanTag value specified for DOT1Q connections",
+			Description: "Vlan Tag information, vlanTag value specified for DOT1Q connections. Changing it on a port access point updates the connection in place",
 		},
 		"vlan_s_tag": {
 			Type:        schema.TypeInt,
 			Optional:    true,
 			Computed:    true,
-			Description: "Vlan Provider Tag information, vlanSTag value specified for QINQ connections",
+			Description: "Vlan Provider Tag information, vlanSTag value specified for QINQ connections. Changing it on a port access point updates the connection in place",
 		},
 		"vlan_c_tag": {
 			Type:        schema.TypeInt,
 			Optional:    true,
 			Computed:    true,
-			Description: "Vlan Customer Tag information, vlanCTag value specified for QINQ connections",
+			Description: "Vlan Customer Tag information, vlanCTag value specified for QINQ connections. Changing it on a port access point updates the connection in place",
 		},
 	}
 }
@@ -887,7 +887,7 @@ func createFabricConnectionResourceSchema() map[string]*schema.Schema {
 			Description: "Requester or Customer side connection configuration object of the multi-segment connection",
 			MaxItems:    1,
 			Elem:        createFabricConnectionSideRes(),
-			Set:         schema.HashResource(createConnectionSideAccessPointRes()),
+			Set:         connectionSideHash,
 		},
 		"z_side": {
 			Type:        schema.TypeSet,
@@ -895,7 +895,7 @@ func createFabricConnectionResourceSchema() map[string]*schema.Schema {
 			Description: "Destination or Provider side connection configuration object of the multi-segment connection",
 			MaxItems:    1,
 			Elem:        createFabricConnectionSideRes(),
-			Set:         schema.HashResource(createConnectionSideAccessPointRes()),
+			Set:         connectionSideHash,
 		},
 	}
 }
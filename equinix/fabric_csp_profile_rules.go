@@ -0,0 +1,280 @@
+package equinix
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// cspProfileRulesJSON holds the rules of the cloud service provider profiles.
+// Profiles are added by adding rules to the file, or to the file of the
+// fabric_csp_profile_rules_file provider attribute
+//
+//go:embed fabric_csp_profile_rules.json
+var cspProfileRulesJSON []byte
+
+// cspProfileRule describes what a cloud service provider expects from the
+// z_side access point of connections to its service profiles. Attributes are
+// the attributes of the access point, e.g. authentication_key.
+// AdditionalInfoKeys are keys of the additional_info of the connection which
+// are sent to the provider together, e.g. the AWS accessKey and secretKey
+type cspProfileRule struct {
+	Provider           string              `json:"provider"`
+	ProfileNames       []string            `json:"profile_names"`
+	Required           []string            `json:"required"`
+	Patterns           map[string]string   `json:"patterns"`
+	Allowed            map[string][]string `json:"allowed"`
+	Hints              map[string]string   `json:"hints"`
+	Bandwidths         []int               `json:"bandwidths"`
+	AdditionalInfoKeys []string            `json:"additional_info_keys"`
+
+	// patterns are the compiled Patterns
+	patterns map[string]*regexp.Regexp
+}
+
+var (
+	cspProfileRules     map[string]cspProfileRule
+	cspProfileRulesErr  error
+	cspProfileRulesOnce sync.Once
+
+	// cspProfileRulesFiles caches the rules of fabric_csp_profile_rules_file
+	// files by path
+	cspProfileRulesFiles sync.Map
+
+	// cspProfileNames caches the names of the service profiles by uuid, as
+	// every connection to the same profile is validated during a plan
+	cspProfileNames sync.Map
+)
+
+// parseCspProfileRules indexes the rules by profile name and compiles their
+// patterns
+func parseCspProfileRules(data []byte) (map[string]cspProfileRule, error) {
+	var rules []cspProfileRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid cloud service provider profile rules: %w", err)
+	}
+	indexed := map[string]cspProfileRule{}
+	for _, rule := range rules {
+		rule.patterns = make(map[string]*regexp.Regexp, len(rule.Patterns))
+		for attribute, pattern := range rule.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s pattern of the %s profile rules: %w", attribute, rule.Provider, err)
+			}
+			rule.patterns[attribute] = re
+		}
+		for _, name := range rule.ProfileNames {
+			if _, ok := indexed[name]; ok {
+				return nil, fmt.Errorf("service profile %q has more than one rule", name)
+			}
+			indexed[name] = rule
+		}
+	}
+	return indexed, nil
+}
+
+type cspProfileRulesFile struct {
+	rules map[string]cspProfileRule
+	err   error
+}
+
+// loadCspProfileRulesFile reads and parses a rules file of the
+// fabric_csp_profile_rules_file provider attribute
+func loadCspProfileRulesFile(path string) (map[string]cspProfileRule, error) {
+	if loaded, ok := cspProfileRulesFiles.Load(path); ok {
+		file := loaded.(cspProfileRulesFile)
+		return file.rules, file.err
+	}
+	var rules map[string]cspProfileRule
+	data, err := os.ReadFile(path)
+	if err == nil {
+		rules, err = parseCspProfileRules(data)
+	}
+	if err != nil {
+		err = fmt.Errorf("fabric_csp_profile_rules_file %s: %w", path, err)
+	}
+	cspProfileRulesFiles.Store(path, cspProfileRulesFile{rules: rules, err: err})
+	return rules, err
+}
+
+// getCspProfileRule returns the rule of a service profile. The rules of the
+// fabric_csp_profile_rules_file provider attribute take precedence over the
+// ones built into the provider
+func getCspProfileRule(meta interface{}, profileName string) (cspProfileRule, bool, error) {
+	if path := meta.(*config.Config).FabricCspProfileRulesFile; path != "" {
+		rules, err := loadCspProfileRulesFile(path)
+		if err != nil {
+			return cspProfileRule{}, false, err
+		}
+		if rule, ok := rules[profileName]; ok {
+			return rule, true, nil
+		}
+	}
+	cspProfileRulesOnce.Do(func() {
+		cspProfileRules, cspProfileRulesErr = parseCspProfileRules(cspProfileRulesJSON)
+	})
+	rule, ok := cspProfileRules[profileName]
+	return rule, ok, cspProfileRulesErr
+}
+
+// validate returns the problems of a connection side, bandwidth and
+// additional_info for the profile of the rule
+func (rule cspProfileRule) validate(side map[string]interface{}, bandwidth int, additionalInfo []interface{}) []string {
+	var problems []string
+	hint := func(attribute string) string {
+		if h, ok := rule.Hints[attribute]; ok {
+			return fmt.Sprintf(" (%s)", h)
+		}
+		return ""
+	}
+	for _, attribute := range rule.Required {
+		if connectionSideAttribute(side, "access_point."+attribute) == "" {
+			problems = append(problems, fmt.Sprintf("%s is required%s", attribute, hint(attribute)))
+		}
+	}
+
+	attributes := make([]string, 0, len(rule.patterns))
+	for attribute := range rule.patterns {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+	for _, attribute := range attributes {
+		value := connectionSideAttribute(side, "access_point."+attribute)
+		if value != "" && !rule.patterns[attribute].MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s %q is not valid%s", attribute, value, hint(attribute)))
+		}
+	}
+
+	attributes = attributes[:0]
+	for attribute := range rule.Allowed {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+	for _, attribute := range attributes {
+		value := connectionSideAttribute(side, "access_point."+attribute)
+		if value != "" && !containsFold(rule.Allowed[attribute], value) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s, got %q", attribute, strings.Join(rule.Allowed[attribute], ", "), value))
+		}
+	}
+
+	if len(rule.Bandwidths) > 0 && bandwidth > 0 && !containsInt(rule.Bandwidths, bandwidth) {
+		problems = append(problems, fmt.Sprintf("bandwidth %d Mbps is not offered, use one of %s", bandwidth, strings.Trim(fmt.Sprint(rule.Bandwidths), "[]")))
+	}
+	return append(problems, rule.validateAdditionalInfo(additionalInfo)...)
+}
+
+// validateAdditionalInfo checks that the AdditionalInfoKeys of the rule are
+// all given with a value, or none of them. The keys are case sensitive, a key
+// spelled differently is not sent to the provider
+func (rule cspProfileRule) validateAdditionalInfo(additionalInfo []interface{}) []string {
+	if len(rule.AdditionalInfoKeys) == 0 {
+		return nil
+	}
+	var problems []string
+	values := map[string]string{}
+	for _, item := range additionalInfo {
+		info, _ := item.(map[string]interface{})
+		key, _ := info["key"].(string)
+		value, _ := info["value"].(string)
+		for _, expected := range rule.AdditionalInfoKeys {
+			if key != expected && strings.EqualFold(key, expected) {
+				problems = append(problems, fmt.Sprintf("additional_info key %q must be spelled %q", key, expected))
+			}
+		}
+		values[key] = value
+	}
+
+	var missing []string
+	for _, key := range rule.AdditionalInfoKeys {
+		value, ok := values[key]
+		if !ok {
+			missing = append(missing, key)
+		} else if value == "" {
+			problems = append(problems, fmt.Sprintf("additional_info %s has no value", key))
+		}
+	}
+	if len(missing) > 0 && len(missing) < len(rule.AdditionalInfoKeys) {
+		problems = append(problems, fmt.Sprintf("additional_info %s must be given together, %s is missing", strings.Join(rule.AdditionalInfoKeys, " and "), strings.Join(missing, " and ")))
+	}
+	return problems
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fabricServiceProfileName looks the name of a service profile up, caching
+// it for the other connections of the plan
+func fabricServiceProfileName(ctx context.Context, meta interface{}, uuid string) (string, error) {
+	if name, ok := cspProfileNames.Load(uuid); ok {
+		return name.(string), nil
+	}
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	profile, _, err := client.ServiceProfilesApi.GetServiceProfileByUuid(ctx, uuid, nil)
+	if err != nil {
+		return "", err
+	}
+	cspProfileNames.Store(uuid, profile.Name)
+	return profile.Name, nil
+}
+
+// validateCspProfileRules checks the z_side access point of a connection to
+// a known cloud service provider profile, which the API only rejects once
+// the connection is created. Existing connections are only checked when
+// their z_side, bandwidth or additional_info change. The prefix locates the connection in the
+// configuration, e.g. secondary.0. for a leg of a connection pair
+func validateCspProfileRules(ctx context.Context, d *schema.ResourceDiff, meta interface{}, prefix string) error {
+	if d.Id() != "" && !d.HasChange(prefix+"z_side") && !d.HasChange(prefix+"bandwidth") && !d.HasChange(prefix+"additional_info") {
+		return nil
+	}
+	if !d.NewValueKnown(prefix+"z_side") || !d.NewValueKnown(prefix+"bandwidth") || !d.NewValueKnown(prefix+"additional_info") {
+		return nil
+	}
+	side := firstSetElem(d.Get(prefix + "z_side"))
+	uuid := connectionSideAttribute(side, "access_point.profile.uuid")
+	if uuid == "" || !strings.EqualFold(connectionSideAttribute(side, "access_point.type"), "SP") {
+		return nil
+	}
+
+	profileName, err := fabricServiceProfileName(ctx, meta, uuid)
+	if err != nil {
+		log.Printf("[WARN] Skipping the validation of the z_side access point, service profile %s could not be read: %v", uuid, err)
+		return nil
+	}
+	rule, ok, err := getCspProfileRule(meta, profileName)
+	if err != nil || !ok {
+		return err
+	}
+	additionalInfo, _ := d.Get(prefix + "additional_info").([]interface{})
+	if problems := rule.validate(side, d.Get(prefix+"bandwidth").(int), additionalInfo); len(problems) > 0 {
+		return fmt.Errorf("%sz_side.access_point of a connection to %s service profile %q: %s", prefix, rule.Provider, profileName, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package equinix
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAwsProfileUuid = "69ee618d-be52-468d-bc99-00566f2dd2b9"
+
+func TestCspProfileRulesParse(t *testing.T) {
+	rules, err := parseCspProfileRules(cspProfileRulesJSON)
+	assert.NoError(t, err)
+	for _, name := range []string{"AWS Direct Connect", "Azure ExpressRoute", "Google Cloud Partner Interconnect Zone 1", "Oracle Cloud Infrastructure -OCI- FastConnect"} {
+		assert.Contains(t, rules, name)
+	}
+
+	if assert.Contains(t, rules["AWS Direct Connect"].patterns, "authentication_key", "the patterns are compiled once") {
+		assert.True(t, rules["AWS Direct Connect"].patterns["authentication_key"].MatchString("357848976964"))
+	}
+
+	_, err = parseCspProfileRules([]byte(`[{"provider": "AWS", "profile_names": ["AWS"], "patterns": {"authentication_key": "("}}]`))
+	assert.ErrorContains(t, err, "invalid authentication_key pattern")
+
+	_, err = parseCspProfileRules([]byte(`[{"provider": "AWS", "profile_names": ["AWS"]}, {"provider": "AWS", "profile_names": ["AWS"]}]`))
+	assert.ErrorContains(t, err, "more than one rule")
+}
+
+func TestCspProfileRuleValidate(t *testing.T) {
+	rules, err := parseCspProfileRules(cspProfileRulesJSON)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const azureKey = "4d6e7f8a-1b2c-4d3e-9f0a-1b2c3d4e5f6a"
+	info := func(keyValues ...string) []interface{} {
+		var items []interface{}
+		for i := 0; i < len(keyValues); i += 2 {
+			items = append(items, map[string]interface{}{"key": keyValues[i], "value": keyValues[i+1]})
+		}
+		return items
+	}
+
+	tests := []struct {
+		name           string
+		profile        string
+		accessPoint    map[string]interface{}
+		bandwidth      int
+		additionalInfo []interface{}
+		want           []string
+	}{
+		{
+			name:        "aws invalid",
+			profile:     "AWS Direct Connect",
+			accessPoint: map[string]interface{}{"authentication_key": "3578-4897"},
+			bandwidth:   150,
+			want: []string{
+				"seller_region is required (the AWS region, e.g. us-west-1)",
+				`authentication_key "3578-4897" is not valid (the 12 digit AWS account ID)`,
+				"bandwidth 150 Mbps is not offered, use one of 50 100 200 300 400 500 1000 2000 5000 10000",
+			},
+		},
+		{
+			name:        "aws valid",
+			profile:     "AWS Direct Connect",
+			accessPoint: map[string]interface{}{"authentication_key": "357848976964", "seller_region": "us-west-1"},
+			bandwidth:   50,
+		},
+		{
+			name:           "aws secrets",
+			profile:        "AWS Direct Connect",
+			accessPoint:    map[string]interface{}{"authentication_key": "357848976964", "seller_region": "us-west-1"},
+			bandwidth:      50,
+			additionalInfo: info("accessKey", "AKIA", "secretKey", "secret"),
+		},
+		{
+			name:           "aws secret key missing",
+			profile:        "AWS Direct Connect",
+			accessPoint:    map[string]interface{}{"authentication_key": "357848976964", "seller_region": "us-west-1"},
+			bandwidth:      50,
+			additionalInfo: info("accessKey", "AKIA"),
+			want:           []string{"additional_info accessKey and secretKey must be given together, secretKey is missing"},
+		},
+		{
+			name:           "aws secret key misspelled and empty",
+			profile:        "AWS Direct Connect",
+			accessPoint:    map[string]interface{}{"authentication_key": "357848976964", "seller_region": "us-west-1"},
+			bandwidth:      50,
+			additionalInfo: info("accessKey", "", "secretkey", "secret"),
+			want: []string{
+				`additional_info key "secretkey" must be spelled "secretKey"`,
+				"additional_info accessKey has no value",
+				"additional_info accessKey and secretKey must be given together, secretKey is missing",
+			},
+		},
+		{
+			name:           "aws other additional info",
+			profile:        "AWS Direct Connect",
+			accessPoint:    map[string]interface{}{"authentication_key": "357848976964", "seller_region": "us-west-1"},
+			bandwidth:      50,
+			additionalInfo: info("ASN", "65000"),
+		},
+		{
+			name:        "azure peering type missing",
+			profile:     "Azure ExpressRoute",
+			accessPoint: map[string]interface{}{"authentication_key": azureKey},
+			bandwidth:   50,
+			want:        []string{"peering_type is required (PRIVATE or MICROSOFT)"},
+		},
+		{
+			name:        "azure peering type not allowed",
+			profile:     "Azure ExpressRoute",
+			accessPoint: map[string]interface{}{"authentication_key": azureKey, "peering_type": "PUBLIC"},
+			bandwidth:   50,
+			want:        []string{`peering_type must be one of PRIVATE, MICROSOFT, got "PUBLIC"`},
+		},
+		{
+			name:        "azure valid",
+			profile:     "Azure ExpressRoute",
+			accessPoint: map[string]interface{}{"authentication_key": azureKey, "peering_type": "private"},
+			bandwidth:   1000,
+		},
+		{
+			name:        "azure invalid key",
+			profile:     "Azure ExpressRoute",
+			accessPoint: map[string]interface{}{"authentication_key": "key", "peering_type": "PRIVATE"},
+			bandwidth:   50,
+			want:        []string{`authentication_key "key" is not valid (the ExpressRoute circuit service key)`},
+		},
+		{
+			name:        "gcp valid",
+			profile:     "Google Cloud Partner Interconnect Zone 1",
+			accessPoint: map[string]interface{}{"authentication_key": "9da09fe8-a33b-4457-ab7d-d91f83152276/us-west1/1"},
+			bandwidth:   50,
+		},
+		{
+			name:        "gcp invalid zone",
+			profile:     "Google Cloud Partner Interconnect Zone 2",
+			accessPoint: map[string]interface{}{"authentication_key": "9da09fe8-a33b-4457-ab7d-d91f83152276/us-west1/3"},
+			bandwidth:   50,
+			want:        []string{`authentication_key "9da09fe8-a33b-4457-ab7d-d91f83152276/us-west1/3" is not valid (the VLAN attachment pairing key, e.g. <key>/us-west1/1)`},
+		},
+		{
+			name:        "oracle any bandwidth",
+			profile:     "Oracle Cloud Infrastructure -OCI- FastConnect",
+			accessPoint: map[string]interface{}{"authentication_key": "ocid1.virtualcircuit.oc1.iad.aaaa", "seller_region": "us-ashburn-1"},
+			bandwidth:   150,
+		},
+		{
+			name:        "oracle invalid",
+			profile:     "Oracle Cloud Infrastructure -OCI- FastConnect",
+			accessPoint: map[string]interface{}{"authentication_key": "ocid1.instance.oc1"},
+			bandwidth:   50,
+			want: []string{
+				"seller_region is required (the OCI region, e.g. us-ashburn-1)",
+				`authentication_key "ocid1.instance.oc1" is not valid (the OCID of the FastConnect virtual circuit)`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := rules[tt.profile]
+			if !ok {
+				t.Fatalf("no rule for %q", tt.profile)
+			}
+			side := map[string]interface{}{"access_point": schema.NewSet(schema.HashResource(createConnectionSideAccessPointRes()), []interface{}{tt.accessPoint})}
+			assert.Equal(t, tt.want, rule.validate(side, tt.bandwidth, tt.additionalInfo))
+		})
+	}
+}
+
+func TestFabricConnectionCustomizeDiffCspRules(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/serviceProfiles/" + testAwsProfileUuid: `{"uuid": "` + testAwsProfileUuid + `", "name": "AWS Direct Connect", "type": "L2_PROFILE"}`,
+	})
+	config := testConnectionConfig(testConnectionPortA, 100, 100)
+	config["z_side"] = []interface{}{map[string]interface{}{
+		"access_point": []interface{}{map[string]interface{}{
+			"type":               "SP",
+			"authentication_key": "357848976964",
+			"profile":            []interface{}{map[string]interface{}{"type": "L2_PROFILE", "uuid": testAwsProfileUuid}},
+			"location":           []interface{}{map[string]interface{}{"metro_code": "SV"}},
+		}},
+	}}
+
+	_, err := resourceFabricConnection().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	assert.ErrorContains(t, err, `z_side.access_point of a connection to AWS service profile "AWS Direct Connect": seller_region is required`)
+
+	config["z_side"].([]interface{})[0].(map[string]interface{})["access_point"].([]interface{})[0].(map[string]interface{})["seller_region"] = "us-west-1"
+	_, err = resourceFabricConnection().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	assert.NoError(t, err)
+
+	config["additional_info"] = []interface{}{map[string]interface{}{"key": "accessKey", "value": "AKIA"}}
+	_, err = resourceFabricConnection().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	assert.ErrorContains(t, err, "additional_info accessKey and secretKey must be given together, secretKey is missing")
+}
+
+func testCspConnectionConfig(profile string, accessPoint map[string]interface{}) map[string]interface{} {
+	config := testConnectionConfig(testConnectionPortA, 100, 100)
+	accessPoint["type"] = "SP"
+	accessPoint["profile"] = []interface{}{map[string]interface{}{"type": "L2_PROFILE", "uuid": profile}}
+	accessPoint["location"] = []interface{}{map[string]interface{}{"metro_code": "SV"}}
+	config["z_side"] = []interface{}{map[string]interface{}{"access_point": []interface{}{accessPoint}}}
+	return config
+}
+
+func TestFabricConnectionCustomizeDiffCspRulesUnchanged(t *testing.T) {
+	const profile = "0b2e7a8c-4d5f-4e6a-9b7c-8d9e0f1a2b3c"
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/serviceProfiles/" + profile: `{"uuid": "` + profile + `", "name": "AWS Direct Connect", "type": "L2_PROFILE"}`,
+	})
+	config := testCspConnectionConfig(profile, map[string]interface{}{"authentication_key": "357848976964"})
+	state := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), config)
+	state.SetId(testConnectionUuid)
+
+	config["name"] = "port2aws"
+	_, err := resourceFabricConnection().Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	assert.NoError(t, err, "the unchanged z_side of an existing connection is not validated")
+	assert.Empty(t, fake.requestOrder())
+
+	config["bandwidth"] = 150
+	_, err = resourceFabricConnection().Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	assert.ErrorContains(t, err, "seller_region is required")
+}
+
+func TestFabricConnectionCustomizeDiffCspRulesFile(t *testing.T) {
+	const profile = "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/serviceProfiles/" + profile: `{"uuid": "` + profile + `", "name": "Partner Cloud", "type": "L2_PROFILE"}`,
+	})
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	rules := `[{"provider": "Partner", "profile_names": ["Partner Cloud"], "required": ["seller_region"], "bandwidths": [50, 100]}]`
+	if err := os.WriteFile(rulesFile, []byte(rules), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	meta.FabricCspProfileRulesFile = rulesFile
+
+	config := testCspConnectionConfig(profile, map[string]interface{}{})
+	_, err := resourceFabricConnection().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	assert.ErrorContains(t, err, `z_side.access_point of a connection to Partner service profile "Partner Cloud": seller_region is required`)
+
+	meta.FabricCspProfileRulesFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = resourceFabricConnection().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	assert.ErrorContains(t, err, "fabric_csp_profile_rules_file")
+}
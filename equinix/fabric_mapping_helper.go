@@ -1190,7 +1190,7 @@ func getRoutingProtocolPatchUpdateRequest(rp v4.RoutingProtocolData, d *schema.R
 	return changeOps, nil
 }
 
-func getUpdateRequests(conn v4.Connection, d *schema.ResourceData) ([][]v4.ConnectionChangeOperation, error) {
+func getUpdateRequests(conn v4.Connection, d fabricConnectionConfig) ([][]v4.ConnectionChangeOperation, error) {
 	var changeOps [][]v4.ConnectionChangeOperation
 	existingName := conn.Name
 	existingBandwidth := int(conn.Bandwidth)
@@ -1220,6 +1220,34 @@ func getUpdateRequests(conn v4.Connection, d *schema.ResourceData) ([][]v4.Conne
 		})
 	}
 
+	if d.HasChange("notifications") {
+		changeOps = append(changeOps, []v4.ConnectionChangeOperation{
+			{
+				Op:    "replace",
+				Path:  "/notifications",
+				Value: notificationToFabric(d.Get("notifications").([]interface{})),
+			},
+		})
+	}
+
+	updateRedundancy := redundancyToFabric(d.Get("redundancy").(*schema.Set).List())
+	if updateRedundancy.Group != "" && (conn.Redundancy == nil || conn.Redundancy.Group != updateRedundancy.Group) {
+		changeOps = append(changeOps, []v4.ConnectionChangeOperation{
+			{
+				Op:    "replace",
+				Path:  "/redundancy/group",
+				Value: updateRedundancy.Group,
+			},
+		})
+	}
+
+	if ops := getLinkProtocolUpdateRequest("/aSide", conn.ASide, d.Get("a_side")); len(ops) > 0 {
+		changeOps = append(changeOps, ops)
+	}
+	if ops := getLinkProtocolUpdateRequest("/zSide", conn.ZSide, d.Get("z_side")); len(ops) > 0 {
+		changeOps = append(changeOps, ops)
+	}
+
 	if *conn.Operation.ProviderStatus == v4.PENDING_APPROVAL_ProviderStatus && hasAWSSecrets {
 		changeOps = append(changeOps, []v4.ConnectionChangeOperation{
 			{
@@ -1237,6 +1265,70 @@ func getUpdateRequests(conn v4.Connection, d *schema.ResourceData) ([][]v4.Conne
 	return changeOps, nil
 }
 
+// connectionLinkProtocolTags maps the VLAN tags of a connection side link
+// protocol to their attribute names in the Fabric v4 patch paths
+var connectionLinkProtocolTags = []struct {
+	attribute string
+	path      string
+}{
+	{"vlan_tag", "vlanTag"},
+	{"vlan_s_tag", "vlanSTag"},
+	{"vlan_c_tag", "vlanCTag"},
+}
+
+// getLinkProtocolUpdateRequest returns the operations retagging the access
+// point of a connection side. The tags of a side are changed in a single
+// request, so that both tags of QINQ connections move together
+func getLinkProtocolUpdateRequest(sidePath string, side *v4.ConnectionSide, schemaSide interface{}) []v4.ConnectionChangeOperation {
+	linkProtocol := firstSetElem(connectionSideAccessPoint(schemaSide)["link_protocol"])
+	if linkProtocol == nil {
+		return nil
+	}
+	existing := v4.SimplifiedLinkProtocol{}
+	if side != nil && side.AccessPoint != nil && side.AccessPoint.LinkProtocol != nil {
+		existing = *side.AccessPoint.LinkProtocol
+	}
+	existingTags := map[string]int32{
+		"vlan_tag":   existing.VlanTag,
+		"vlan_s_tag": existing.VlanSTag,
+		"vlan_c_tag": existing.VlanCTag,
+	}
+
+	var changeOps []v4.ConnectionChangeOperation
+	for _, tag := range connectionLinkProtocolTags {
+		updateTag, _ := linkProtocol[tag.attribute].(int)
+		if updateTag != 0 && int32(updateTag) != existingTags[tag.attribute] {
+			changeOps = append(changeOps, v4.ConnectionChangeOperation{
+				Op:    "replace",
+				Path:  sidePath + "/accessPoint/linkProtocol/" + tag.path,
+				Value: updateTag,
+			})
+		}
+	}
+	return changeOps
+}
+
+// connectionSideAccessPoint returns the access point of a connection side
+// set, or nil when the side has none
+func connectionSideAccessPoint(side interface{}) map[string]interface{} {
+	return firstSetElem(firstSetElem(side)["access_point"])
+}
+
+// firstSetElem returns the first element of a set of resources, or nil when
+// the set is empty
+func firstSetElem(v interface{}) map[string]interface{} {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	for _, elem := range set.List() {
+		if m, ok := elem.(map[string]interface{}); ok {
+			return m
+		}
+	}
+	return nil
+}
+
 func getCloudRouterUpdateRequest(conn v4.CloudRouter, d *schema.ResourceData) (v4.CloudRouterChangeOperation, error) {
 	changeOps := v4.CloudRouterChangeOperation{}
 	existingName := conn.Name
@@ -1256,3 +1348,53 @@ func getCloudRouterUpdateRequest(conn v4.CloudRouter, d *schema.ResourceData) (v
 	}
 	return changeOps, nil
 }
+
+func networkProjectToTerra(project *v4.Project) *schema.Set {
+	if project == nil {
+		return nil
+	}
+	mappedProjects := []interface{}{
+		map[string]interface{}{"project_id": project.ProjectId},
+	}
+	return schema.NewSet(schema.HashResource(createGatewayProjectSchRes), mappedProjects)
+}
+
+func networkOperationToTerra(operation *v4.NetworkOperation) *schema.Set {
+	if operation == nil {
+		return nil
+	}
+	mappedOperation := map[string]interface{}{}
+	if operation.EquinixStatus != nil {
+		mappedOperation["equinix_status"] = string(*operation.EquinixStatus)
+	}
+	return schema.NewSet(schema.HashResource(createNetworkOperationRes), []interface{}{mappedOperation})
+}
+
+func networkChangeToTerra(change *v4.SimplifiedNetworkChange) *schema.Set {
+	if change == nil {
+		return nil
+	}
+	mappedChange := map[string]interface{}{
+		"uuid": change.Uuid,
+		"href": change.Href,
+	}
+	if change.Type_ != nil {
+		mappedChange["type"] = string(*change.Type_)
+	}
+	return schema.NewSet(schema.HashResource(createNetworkChangeRes), []interface{}{mappedChange})
+}
+
+func getNetworkUpdateRequest(network v4.Network, d *schema.ResourceData) ([]v4.NetworkChangeOperation, error) {
+	var changeOps []v4.NetworkChangeOperation
+	if updateNameVal := interface{}(d.Get("name").(string)); network.Name != updateNameVal {
+		changeOps = append(changeOps, v4.NetworkChangeOperation{Op: "replace", Path: "/name", Value: &updateNameVal})
+	}
+	if d.HasChange("notifications") {
+		updateNotificationsVal := interface{}(notificationToFabric(d.Get("notifications").([]interface{})))
+		changeOps = append(changeOps, v4.NetworkChangeOperation{Op: "replace", Path: "/notifications", Value: &updateNotificationsVal})
+	}
+	if len(changeOps) == 0 {
+		return changeOps, fmt.Errorf("nothing to update for the network %s", network.Name)
+	}
+	return changeOps, nil
+}
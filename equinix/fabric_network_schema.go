@@ -0,0 +1,138 @@
+package equinix
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var createNetworkChangeRes = &schema.Resource{
+	Schema: createNetworkChangeSch(),
+}
+
+func createNetworkChangeSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Uniquely identifies a change",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Type of change, e.g. NETWORK_CREATION, NETWORK_UPDATE, NETWORK_DELETION",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Network Change URI",
+		},
+	}
+}
+
+var createNetworkOperationRes = &schema.Resource{
+	Schema: createNetworkOperationSch(),
+}
+
+func createNetworkOperationSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"equinix_status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Network operation status, e.g. PROVISIONING, PROVISIONED, DEPROVISIONED",
+		},
+	}
+}
+
+func createFabricNetworkResourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Fabric Network URI information",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned network identifier",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Fabric Network name. An alpha-numeric 24 characters string which can include only hyphens and underscores",
+		},
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"EVPLAN", "EPLAN", "IPWAN"}, false),
+			Description:  "Supported Network types - EVPLAN, EPLAN, IPWAN",
+		},
+		"scope": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"REGIONAL", "GLOBAL"}, false),
+			Description:  "Fabric Network scope - REGIONAL or GLOBAL",
+		},
+		"location": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Fabric Network location. The region of a REGIONAL network",
+			Elem: &schema.Resource{
+				Schema: createLocationSch(),
+			},
+		},
+		"project": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Fabric Network project",
+			Elem: &schema.Resource{
+				Schema: createGatewayProjectSch(),
+			},
+		},
+		"notifications": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: "Preferences for notifications on Fabric Network configuration or status changes",
+			Elem: &schema.Resource{
+				Schema: createNotificationSch(),
+			},
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Fabric Network overall state - ACTIVE, INACTIVE or DELETED",
+		},
+		"connections_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of connections associated with this Fabric Network",
+		},
+		"operation": {
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Description: "Fabric Network operational data",
+			Elem:        createNetworkOperationRes,
+		},
+		"change": {
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Description: "Information on the last change of the Fabric Network",
+			Elem:        createNetworkChangeRes,
+		},
+		"change_log": {
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Description: "Captures Fabric Network lifecycle change information",
+			Elem: &schema.Resource{
+				Schema: createChangeLogSch(),
+			},
+		},
+	}
+}
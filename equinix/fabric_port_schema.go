@@ -0,0 +1,361 @@
+package equinix
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func createPortDemarcationPointSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"ibx": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "IBX of the demarcation point",
+		},
+		"cage_unique_space_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Cage unique space id",
+		},
+		"cabinet_unique_space_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Cabinet unique space id",
+		},
+		"patch_panel": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Patch panel",
+		},
+		"patch_panel_port_a": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Patch panel port A",
+		},
+		"patch_panel_port_b": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Patch panel port B",
+		},
+		"connector_type": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Connector type, e.g. SC, LC",
+		},
+	}
+}
+
+func createPortOrderSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"purchase_order_number": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Purchase order number",
+		},
+		"signatory": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"SELF", "DELEGATE", "SUPPORT"}, false),
+			Description:  "Who signs the Letter of Authorization - SELF, DELEGATE, SUPPORT",
+		},
+		"delegate_email": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Email of the delegate signing the Letter of Authorization",
+		},
+		"delegate_first_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "First name of the delegate signing the Letter of Authorization",
+		},
+		"delegate_last_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Last name of the delegate signing the Letter of Authorization",
+		},
+		"order_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Order Identification",
+		},
+		"order_number": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Order Reference Number",
+		},
+	}
+}
+
+func readPhysicalPortSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Equinix-assigned physical port identifier",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Physical port URI",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Physical port state",
+		},
+		"interface_speed": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Physical port speed in Mbps",
+		},
+		"interface_type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Physical port interface type",
+		},
+		"operational_status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Operational status of the physical port",
+		},
+	}
+}
+
+func createFabricPortResourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      "XF_PORT",
+			ValidateFunc: validation.StringInSlice([]string{"XF_PORT", "IX_PORT"}, false),
+			Description:  "Port type - XF_PORT, IX_PORT",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Port URI information",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned port identifier",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned port name",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Customer-provided port description",
+		},
+		"physical_ports_speed": {
+			Type:        schema.TypeInt,
+			Required:    true,
+			ForceNew:    true,
+			Description: "Speed of the physical ports in Mbps, e.g. 1000, 10000, 100000",
+		},
+		"physical_ports_type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "Type of the physical ports, e.g. 1000BASE_LX, 10GBASE_LR, 100GBASE_LR4",
+		},
+		"physical_ports_count": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Number of physical ports. Changing it adds or removes LAG members in place",
+		},
+		"connectivity_source_type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"COLO", "BMMR", "REMOTE"}, false),
+			Description:  "Port connectivity type - COLO, BMMR, REMOTE",
+		},
+		"bmmr_type": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Building meet-me room type of BMMR ports, e.g. SELF, EQUINIX",
+		},
+		"lag_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Order the port as a Link Aggregation Group, which allows more than one physical port",
+		},
+		"location": {
+			Type:        schema.TypeList,
+			Required:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Port location",
+			Elem: &schema.Resource{
+				Schema: createLocationSch(),
+			},
+		},
+		"account": {
+			Type:        schema.TypeList,
+			Required:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Customer account of the port",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"account_number": {
+						Type:        schema.TypeInt,
+						Required:    true,
+						Description: "Account Number",
+					},
+				},
+			},
+		},
+		"project": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Port project",
+			Elem: &schema.Resource{
+				Schema: createGatewayProjectSch(),
+			},
+		},
+		"encapsulation": {
+			Type:        schema.TypeList,
+			Required:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Port encapsulation",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"NULL", "DOT1Q", "QINQ", "UNTAGGED"}, false),
+						Description:  "Port encapsulation protocol type - NULL, DOT1Q, QINQ, UNTAGGED",
+					},
+					"tag_protocol_id": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Computed:    true,
+						Description: "Port encapsulation Tag Protocol Identifier, e.g. 0x8100",
+					},
+				},
+			},
+		},
+		"redundancy": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Port redundancy",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"priority": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"PRIMARY", "SECONDARY"}, false),
+						Description:  "Priority of the port in its redundancy group - PRIMARY, SECONDARY",
+					},
+					"group": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Computed:    true,
+						Description: "Redundancy group. For a SECONDARY port, the UUID of the PRIMARY port",
+					},
+				},
+			},
+		},
+		"demarcation_point": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Where the physical ports are delivered. LAG members added later are delivered to the same cage and cabinet",
+			Elem: &schema.Resource{
+				Schema: createPortDemarcationPointSch(),
+			},
+		},
+		"order": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Order information of the port",
+			Elem: &schema.Resource{
+				Schema: createPortOrderSch(),
+			},
+		},
+		"package_type": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "Port package type, e.g. STANDARD, UNLIMITED",
+		},
+		"notifications": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Users notified about the port order",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"NOTIFICATION", "TECHNICAL", "PEERING", "ESCALATION"}, false),
+						Description:  "Notification type - NOTIFICATION, TECHNICAL, PEERING, ESCALATION",
+					},
+					"registered_users": {
+						Type:        schema.TypeList,
+						Required:    true,
+						Description: "Usernames of the registered users to notify",
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Port state, e.g. PENDING, PROVISIONING, PROVISIONED",
+		},
+		"bandwidth": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Port bandwidth in Mbps",
+		},
+		"available_bandwidth": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Port available bandwidth in Mbps",
+		},
+		"used_bandwidth": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Port used bandwidth in Mbps",
+		},
+		"physical_ports": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Physical ports that implement the port, e.g. the members of a LAG",
+			Elem: &schema.Resource{
+				Schema: readPhysicalPortSch(),
+			},
+		},
+		"change_log": {
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Description: "Captures port lifecycle change information",
+			Elem: &schema.Resource{
+				Schema: createChangeLogSch(),
+			},
+		},
+	}
+}
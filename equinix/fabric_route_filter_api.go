@@ -0,0 +1,276 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// Route filters and route aggregations of Fabric Cloud Routers are not in
+// fabric-go yet, so their endpoints are called with the HTTP client of the
+// Fabric client and the models below.
+
+// fabricRoutePolicy is a route filter or a route aggregation
+type fabricRoutePolicy struct {
+	Href                 string             `json:"href,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Uuid                 string             `json:"uuid,omitempty"`
+	Name                 string             `json:"name,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	State                string             `json:"state,omitempty"`
+	NotMatchedRuleAction string             `json:"notMatchedRuleAction,omitempty"`
+	ConnectionsCount     int                `json:"connectionsCount,omitempty"`
+	RulesCount           int                `json:"rulesCount,omitempty"`
+	Change               *fabricRouteChange `json:"change,omitempty"`
+	Project              *v4.Project        `json:"project,omitempty"`
+}
+
+// fabricRoutePolicyRule is a rule of a route filter or route aggregation
+type fabricRoutePolicyRule struct {
+	Href        string             `json:"href,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Uuid        string             `json:"uuid,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description,omitempty"`
+	State       string             `json:"state,omitempty"`
+	Prefix      string             `json:"prefix,omitempty"`
+	PrefixMatch string             `json:"prefixMatch,omitempty"`
+	Action      string             `json:"action,omitempty"`
+	Change      *fabricRouteChange `json:"change,omitempty"`
+}
+
+// fabricRoutePolicyAttachment is a route filter or route aggregation
+// attached to a connection
+type fabricRoutePolicyAttachment struct {
+	Href             string `json:"href,omitempty"`
+	Type             string `json:"type,omitempty"`
+	Uuid             string `json:"uuid,omitempty"`
+	AttachmentStatus string `json:"attachmentStatus,omitempty"`
+	Direction        string `json:"direction,omitempty"`
+}
+
+type fabricRouteChange struct {
+	Uuid   string `json:"uuid,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Href   string `json:"href,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// fabricRoutePolicyKind holds what differs between route filters and route
+// aggregations
+type fabricRoutePolicyKind struct {
+	name        string
+	idAttribute string
+	path        string
+	rulesPath   string
+	types       []string
+	isFilter    bool
+}
+
+var fabricRouteFilters = fabricRoutePolicyKind{
+	name:        "route filter",
+	idAttribute: "route_filter_id",
+	path:        "routeFilters",
+	rulesPath:   "routeFilterRules",
+	types:       []string{"BGP_IPv4_PREFIX_FILTER", "BGP_IPv6_PREFIX_FILTER"},
+	isFilter:    true,
+}
+
+var fabricRouteAggregations = fabricRoutePolicyKind{
+	name:        "route aggregation",
+	idAttribute: "route_aggregation_id",
+	path:        "routeAggregations",
+	rulesPath:   "routeAggregationRules",
+	types:       []string{"BGP_IPv4_PREFIX_AGGREGATION"},
+}
+
+func (k fabricRoutePolicyKind) policyPath(uuid string) string {
+	return fmt.Sprintf("/fabric/v4/%s/%s", k.path, uuid)
+}
+
+func (k fabricRoutePolicyKind) ruleCollectionPath(policyUuid string) string {
+	return fmt.Sprintf("/fabric/v4/%s/%s/%s", k.path, policyUuid, k.rulesPath)
+}
+
+func (k fabricRoutePolicyKind) rulePath(policyUuid, ruleUuid string) string {
+	return k.ruleCollectionPath(policyUuid) + "/" + ruleUuid
+}
+
+func (k fabricRoutePolicyKind) attachmentPath(connectionUuid, policyUuid string) string {
+	return fmt.Sprintf("/fabric/v4/connections/%s/%s/%s", connectionUuid, k.path, policyUuid)
+}
+
+// fabricAPIError is the error of a Fabric request, formatted like
+// equinix_errors.FormatFabricError
+type fabricAPIError struct {
+	StatusCode int
+	Status     string
+	Errors     []v4.ModelError
+}
+
+func (e *fabricAPIError) Error() string {
+	errs := equinix_errors.Errors{e.Status}
+	for _, m := range e.Errors {
+		errs = append(errs, fmt.Sprintf("Code: %s", m.ErrorCode))
+		errs = append(errs, fmt.Sprintf("Message: %s", m.ErrorMessage))
+		errs = append(errs, fmt.Sprintf("Details: %s", m.Details))
+		if additionalInfo := equinix_errors.FormatFabricAdditionalInfo(m.AdditionalInfo); additionalInfo != "" {
+			errs = append(errs, fmt.Sprintf("AdditionalInfo: [%s]", additionalInfo))
+		}
+	}
+	return errs.Error()
+}
+
+func isFabricNotFound(err error) bool {
+	var apiErr *fabricAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// fabricRequest sends a request to the Fabric API and decodes the response
+// into out, if it is not nil
+func fabricRequest(ctx context.Context, meta interface{}, method, path string, body, out interface{}) error {
+	conf := meta.(*config.Config)
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(conf.BaseURL, "/")+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+conf.FabricAuthToken)
+	req.Header.Set("User-Agent", conf.FabricUserAgent())
+	req.Header.Set("X-SOURCE", "API")
+
+	httpClient := conf.FabricHTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &fabricAPIError{StatusCode: resp.StatusCode, Status: fmt.Sprintf("%s %s: %s", method, path, resp.Status)}
+		_ = json.Unmarshal(data, &apiErr.Errors)
+		return apiErr
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("error decoding response of %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// fabricRoutePollInterval is the delay and minimum interval of the waits for
+// route filters and route aggregations
+var fabricRoutePollInterval = 30 * time.Second
+
+// fabricRouteStateRefresh reads the state of a route filter, route
+// aggregation, rule or attachment. A deleted one is in state "404".
+func fabricRouteStateRefresh(ctx context.Context, meta interface{}, path string) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		var state struct {
+			State            string `json:"state"`
+			AttachmentStatus string `json:"attachmentStatus"`
+		}
+		if err := fabricRequest(ctx, meta, http.MethodGet, path, nil, &state); err != nil {
+			if isFabricNotFound(err) {
+				return state, strconv.Itoa(http.StatusNotFound), nil
+			}
+			return nil, "", err
+		}
+		if state.State != "" {
+			return state, state.State, nil
+		}
+		return state, state.AttachmentStatus, nil
+	}
+}
+
+func waitForFabricRouteState(ctx context.Context, meta interface{}, path string, pending, target []string, timeout time.Duration) error {
+	log.Printf("Waiting for %s to be %s", path, strings.Join(target, " or "))
+	stateConf := &retry.StateChangeConf{
+		Pending:    pending,
+		Target:     target,
+		Refresh:    fabricRouteStateRefresh(ctx, meta, path),
+		Timeout:    timeout,
+		Delay:      fabricRoutePollInterval,
+		MinTimeout: fabricRoutePollInterval,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func waitUntilFabricRouteIsProvisioned(ctx context.Context, meta interface{}, path string, timeout time.Duration) error {
+	return waitForFabricRouteState(ctx, meta, path,
+		[]string{string(v4.PROVISIONING_ConnectionState), string(v4.REPROVISIONING_ConnectionState)},
+		[]string{string(v4.PROVISIONED_ConnectionState)},
+		timeout)
+}
+
+func waitUntilFabricRouteIsDeprovisioned(ctx context.Context, meta interface{}, path string, timeout time.Duration) error {
+	return waitForFabricRouteState(ctx, meta, path,
+		[]string{string(v4.PROVISIONED_ConnectionState), string(v4.DEPROVISIONING_ConnectionState)},
+		[]string{string(v4.DEPROVISIONED_ConnectionState), strconv.Itoa(http.StatusNotFound)},
+		timeout)
+}
+
+// waitForFabricRouteChangeCompletion waits for the change of a route filter,
+// route aggregation or rule at path to complete
+func waitForFabricRouteChangeCompletion(ctx context.Context, meta interface{}, path string, change *fabricRouteChange, timeout time.Duration) error {
+	if change == nil || change.Uuid == "" {
+		return nil
+	}
+	log.Printf("Waiting for change %s of %s to complete", change.Uuid, path)
+	stateConf := &retry.StateChangeConf{
+		Target: []string{"COMPLETED"},
+		Refresh: func() (interface{}, string, error) {
+			dbChange := fabricRouteChange{}
+			if err := fabricRequest(ctx, meta, http.MethodGet, path+"/changes/"+change.Uuid, nil, &dbChange); err != nil {
+				return nil, "", err
+			}
+			if dbChange.Status == "FAILED" {
+				return nil, "", fmt.Errorf("change %s of %s failed", change.Uuid, path)
+			}
+			updatableState := ""
+			if dbChange.Status == "COMPLETED" {
+				updatableState = dbChange.Status
+			}
+			return dbChange, updatableState, nil
+		},
+		Timeout:    timeout,
+		Delay:      fabricRoutePollInterval,
+		MinTimeout: fabricRoutePollInterval,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
@@ -0,0 +1,188 @@
+package equinix
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var createRoutePolicyProjectRes = &schema.Resource{
+	Schema: createRoutePolicyProjectSch(),
+}
+
+func createRoutePolicyProjectSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"project_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Project Id",
+		},
+	}
+}
+
+func createFabricRoutePolicyResourceSchema(kind fabricRoutePolicyKind) map[string]*schema.Schema {
+	sch := map[string]*schema.Schema{
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URI information",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned identifier",
+		},
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice(kind.types, false),
+			Description:  "Type. One of " + strings.Join(kind.types, ", "),
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name. An alpha-numeric 24 characters string which can include only hyphens and underscores",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Customer-provided description",
+		},
+		"project": {
+			Type:        schema.TypeSet,
+			Required:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Project the " + kind.name + " belongs to",
+			Elem:        createRoutePolicyProjectRes,
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Provisioning state",
+		},
+		"connections_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of connections the " + kind.name + " is attached to",
+		},
+		"rules_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of rules",
+		},
+	}
+	if kind.isFilter {
+		sch["not_matched_rule_action"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Action for the prefixes which match no rule",
+		}
+	}
+	return sch
+}
+
+func createFabricRoutePolicyRuleResourceSchema(kind fabricRoutePolicyKind) map[string]*schema.Schema {
+	sch := map[string]*schema.Schema{
+		kind.idAttribute: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "UUID of the " + kind.name + " the rule belongs to",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Rule URI information",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned rule identifier",
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Rule type",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Rule name",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Customer-provided rule description",
+		},
+		"prefix": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsCIDR,
+			Description:  "IP prefix in CIDR notation, e.g. 192.168.0.0/24",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Provisioning state of the rule",
+		},
+	}
+	if kind.isFilter {
+		sch["prefix_match"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "orlonger",
+			ValidateFunc: validation.StringInSlice([]string{"exact", "orlonger"}, false),
+			Description:  "Whether the rule matches the prefix only (exact) or the prefix and longer prefixes in it (orlonger). Defaults to orlonger",
+		}
+		sch["action"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Action for the prefixes which match the rule",
+		}
+	}
+	return sch
+}
+
+func createFabricRoutePolicyAttachmentResourceSchema(kind fabricRoutePolicyKind) map[string]*schema.Schema {
+	sch := map[string]*schema.Schema{
+		"connection_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "UUID of the connection the " + kind.name + " is attached to",
+		},
+		kind.idAttribute: {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "UUID of the " + kind.name + " to attach",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URI information of the attached " + kind.name,
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Type of the attached " + kind.name,
+		},
+		"attachment_status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Status of the attachment",
+		},
+	}
+	if kind.isFilter {
+		sch["direction"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"INBOUND", "OUTBOUND"}, false),
+			Description:  "Direction the " + kind.name + " applies to. One of \"INBOUND\", \"OUTBOUND\"",
+		}
+	}
+	return sch
+}
@@ -0,0 +1,243 @@
+package equinix
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func createServiceTokenAccessPointSelectorSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "COLO",
+			ValidateFunc: validation.StringInSlice([]string{"COLO"}, false),
+			Description:  "Type of the access point - COLO",
+		},
+		"port": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Port the service token is bound to",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"uuid": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Equinix-assigned Port identifier",
+					},
+					"href": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Unique Resource Identifier",
+					},
+				},
+			},
+		},
+		"link_protocol": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "VLAN of the port the service token is bound to. Without it, connections can use any VLAN of the port",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice([]string{"UNTAGGED", "DOT1Q", "QINQ"}, false),
+						Description:  "Type of the link protocol - UNTAGGED, DOT1Q, QINQ",
+					},
+					"vlan_tag": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Vlan Tag information, vlanTag value specified for DOT1Q connections",
+					},
+					"vlan_s_tag": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Vlan Provider Tag information, vlanSTag value specified for QINQ connections",
+					},
+					"vlan_c_tag": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Vlan Customer Tag information, vlanCTag value specified for QINQ connections",
+					},
+				},
+			},
+		},
+	}
+}
+
+func createServiceTokenSideSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"access_point_selectors": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MinItems:    1,
+			Description: "Access points connections created with the service token can use",
+			Elem: &schema.Resource{
+				Schema: createServiceTokenAccessPointSelectorSch(),
+			},
+		},
+	}
+}
+
+func createServiceTokenConnectionSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{"EVPL_VC", "EPL_VC"}, false),
+			Description:  "Type of the connections created with the service token - EVPL_VC, EPL_VC",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned identifier of the connection created with the service token",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URI of the connection created with the service token",
+		},
+		"allow_remote_connection": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Authorization to create connections from remote metros",
+		},
+		"bandwidth_limit": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Bandwidth limit of the connections created with the service token in Mbps",
+		},
+		"supported_bandwidths": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Computed:    true,
+			Description: "Bandwidths in Mbps the connections created with the service token can use",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+		"a_side": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			ForceNew:     true,
+			MaxItems:     1,
+			ExactlyOneOf: []string{"service_token_connection.0.a_side", "service_token_connection.0.z_side"},
+			Description:  "A-side access points of an A-side service token, which lets the partner create connections from your port",
+			Elem: &schema.Resource{
+				Schema: createServiceTokenSideSch(),
+			},
+		},
+		"z_side": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Z-side access points of a Z-side service token, which lets the partner create connections to your port",
+			Elem: &schema.Resource{
+				Schema: createServiceTokenSideSch(),
+			},
+		},
+	}
+}
+
+func createFabricServiceTokenResourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      "VC_TOKEN",
+			ValidateFunc: validation.StringInSlice([]string{"VC_TOKEN"}, false),
+			Description:  "Service token type - VC_TOKEN",
+		},
+		"href": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Service token URI",
+		},
+		"uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned service token identifier, which is shared with the partner creating the connection",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Customer-provided service token name",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Customer-provided service token description",
+		},
+		"expiration_date_time": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validation.IsRFC3339Time,
+			DiffSuppressFunc: suppressEquivalentTime,
+			Description:      "Expiration date and time of the service token in RFC3339 format, e.g. 2024-12-31T23:59:59Z",
+		},
+		"service_token_connection": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Connection the service token authorizes",
+			Elem: &schema.Resource{
+				Schema: createServiceTokenConnectionSch(),
+			},
+		},
+		"notifications": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: "Preferences for notifications on service token configuration or status changes",
+			Elem: &schema.Resource{
+				Schema: createNotificationSch(),
+			},
+		},
+		"project": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			MaxItems:    1,
+			Description: "Project of the service token",
+			Elem: &schema.Resource{
+				Schema: createGatewayProjectSch(),
+			},
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Service token state - ACTIVE, INACTIVE, EXPIRED, DELETED",
+		},
+		"change_log": {
+			Type:        schema.TypeSet,
+			Computed:    true,
+			Description: "Captures service token lifecycle change information",
+			Elem: &schema.Resource{
+				Schema: createChangeLogSch(),
+			},
+		},
+	}
+}
+
+// suppressEquivalentTime ignores differences in the format of the same
+// RFC3339 time, e.g. the fractional seconds the API adds
+func suppressEquivalentTime(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
@@ -2,8 +2,8 @@ package equinix
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"path"
 	"sort"
@@ -17,7 +17,6 @@ import (
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 const (
@@ -71,16 +70,70 @@ func getNetworkInfo(ips []metalv1.IPAssignment) NetworkInfo {
 	return ni
 }
 
-func getNetworkType(device *metalv1.Device) (*string, error) {
-	pgDevice := packngo.Device{}
-	res, err := device.MarshalJSON()
-	if err == nil {
-		if err = json.Unmarshal(res, &pgDevice); err == nil {
-			networkType := pgDevice.GetNetworkType()
-			return &networkType, nil
+// deviceNetworkType returns the network type of the device, as shown in the
+// Equinix Metal console. It is composed of the plan, the bonding of the ports
+// and the management IPs of the device.
+func deviceNetworkType(device *metalv1.Device) string {
+	switch device.Plan.GetSlug() {
+	case "baremetal_0", "baremetal_1":
+		return string(metalv1.PORTNETWORKTYPE_LAYER3)
+	case "baremetal_1e":
+		return string(metalv1.PORTNETWORKTYPE_HYBRID)
+	}
+
+	if allPortsBonded(devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_BOND_PORT)) {
+		if allPortsBonded(devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_PORT)) {
+			if !deviceHasManagementIPs(device) {
+				return string(metalv1.PORTNETWORKTYPE_LAYER2_BONDED)
+			}
+			return string(metalv1.PORTNETWORKTYPE_LAYER3)
+		}
+		return string(metalv1.PORTNETWORKTYPE_HYBRID)
+	}
+	return string(metalv1.PORTNETWORKTYPE_LAYER2_INDIVIDUAL)
+}
+
+// devicePortsOfType returns the ports of the device with the type, by name
+func devicePortsOfType(device *metalv1.Device, portType metalv1.PortType) map[string]*metalv1.Port {
+	ports := map[string]*metalv1.Port{}
+	for _, port := range device.NetworkPorts {
+		if port.GetType() == portType {
+			p := port
+			ports[p.GetName()] = &p
+		}
+	}
+	return ports
+}
+
+func devicePortByName(device *metalv1.Device, name string) (*metalv1.Port, error) {
+	for _, port := range device.NetworkPorts {
+		if port.GetName() == name {
+			p := port
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("Port %s not found in device %s", name, device.GetId())
+}
+
+func allPortsBonded(ports map[string]*metalv1.Port) bool {
+	if len(ports) == 0 {
+		return false
+	}
+	for _, p := range ports {
+		if !p.Data.GetBonded() {
+			return false
+		}
+	}
+	return true
+}
+
+func deviceHasManagementIPs(device *metalv1.Device) bool {
+	for _, ip := range device.IpAddresses {
+		if ip.GetManagement() {
+			return true
 		}
 	}
-	return nil, err
+	return false
 }
 
 func getNetworkRank(family int, public bool) int {
@@ -112,11 +165,11 @@ func getPorts(ps []metalv1.Port) []map[string]interface{} {
 
 func hwReservationStateRefreshFunc(client *metalv1.APIClient, reservationId, instanceId string) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		r, _, err := client.HardwareReservationsApi.FindHardwareReservationById(context.TODO(), reservationId).Include([]string{"device"}).Execute()
+		r, resp, err := client.HardwareReservationsApi.FindHardwareReservationById(context.TODO(), reservationId).Include([]string{"device"}).Execute()
 		state := deprovisioning
 		switch {
 		case err != nil:
-			err = equinix_errors.FriendlyError(err)
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			state = errstate
 		case r != nil && r.GetProvisionable():
 			state = provisionable
@@ -0,0 +1,170 @@
+package equinix
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI serves recorded Equinix Metal or Fabric API responses, keyed by
+// method and path, and records the bodies of the requests it received.
+// Requests without a recorded response get a 404.
+type fakeAPI struct {
+	responses map[string]string
+	// statuses override the status of recorded responses, to fail requests
+	statuses map[string]int
+	// queued responses are served in turn before the recorded response, for
+	// resources whose state changes between requests
+	queued map[string][]string
+	// notFound is the body of the 404 responses, in the format of the API
+	notFound string
+
+	mu       sync.Mutex
+	requests map[string]string
+	headers  map[string]http.Header
+	// calls are the methods and paths of the requests, in order
+	calls []string
+}
+
+// newMetalFakeAPI serves recorded Equinix Metal API responses
+func newMetalFakeAPI(t *testing.T, responses map[string]string) (*fakeAPI, *config.Config) {
+	t.Helper()
+	return newFakeAPI(t, responses, `{"errors":["Not found"]}`)
+}
+
+// newFabricFakeAPI serves recorded Equinix Fabric API responses
+func newFabricFakeAPI(t *testing.T, responses map[string]string) (*fakeAPI, *config.Config) {
+	t.Helper()
+	return newFakeAPI(t, responses, `[{"errorCode":"EQ-3000000","errorMessage":"Not found"}]`)
+}
+
+// packngoClient returns a packngo client of the fake API, for the packngo
+// implementations the metal-go states are compared with
+func packngoClient(meta interface{}) *packngo.Client {
+	conf := meta.(*config.Config)
+	client, _ := packngo.NewClientWithBaseURL("packngo-test", conf.AuthToken, nil, conf.BaseURL+"/metal/v1/")
+	return client
+}
+
+// packngoFriendlyError converts a packngo error to the Metal error of the
+// provider, for the packngo implementations to match it
+func packngoFriendlyError(err error) error {
+	e, ok := err.(*packngo.ErrorResponse)
+	if !ok || e.Response == nil {
+		return err
+	}
+	errors := equinix_errors.Errors(e.Errors)
+	if len(errors) == 0 {
+		errors = equinix_errors.Errors{e.SingleError}
+	}
+	return &equinix_errors.ErrorResponse{StatusCode: e.Response.StatusCode, Errors: errors, IsAPIError: true}
+}
+
+func newFakeAPI(t *testing.T, responses map[string]string, notFound string) (*fakeAPI, *config.Config) {
+	t.Helper()
+	fake := &fakeAPI{responses: responses, statuses: map[string]int{}, queued: map[string][]string{}, notFound: notFound, requests: map[string]string{}, headers: map[string]http.Header{}}
+	mockAPI := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(mockAPI.Close)
+
+	meta := &config.Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock"}
+	if err := meta.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return fake, meta
+}
+
+func (f *fakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// metal-go requests have a double slash after the base path
+	key := r.Method + " " + strings.ReplaceAll(r.URL.Path, "//", "/")
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests[key] = string(body)
+	f.headers[key] = r.Header.Clone()
+	f.calls = append(f.calls, key)
+	status, failed := f.statuses[key]
+	response, ok := f.responses[key]
+	if queued := f.queued[key]; len(queued) > 0 {
+		response, ok = queued[0], true
+		f.queued[key] = queued[1:]
+	}
+	f.mu.Unlock()
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+	if failed {
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(f.notFound))
+		return
+	}
+	if response == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(response))
+}
+
+// request returns the body of the last request to the method and path
+func (f *fakeAPI) request(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, ok := f.requests[key]
+	return body, ok
+}
+
+// header returns the headers of the last request to the method and path
+func (f *fakeAPI) header(key string) http.Header {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.headers[key]
+}
+
+// requestOrder returns the methods and paths of the requests, in the order
+// they were received
+func (f *fakeAPI) requestOrder() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// metalReadFunc reads a resource into its state
+type metalReadFunc func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// assertMetalReadParity reads the resource from the same recorded responses
+// with the metal-go implementation and with the packngo implementation it
+// replaced, and checks that both write the same state. The reads start from
+// the raw configuration, which may be nil. It returns the state written by the
+// metal-go implementation.
+func assertMetalReadParity(t *testing.T, r *schema.Resource, id string, raw map[string]interface{}, responses map[string]string, read, packngoRead metalReadFunc) map[string]string {
+	t.Helper()
+	_, meta := newMetalFakeAPI(t, responses)
+
+	states := make([]map[string]string, 2)
+	for i, f := range []metalReadFunc{read, packngoRead} {
+		d := schema.TestResourceDataRaw(t, r.Schema, raw)
+		d.SetId(id)
+		if diags := f(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("unexpected read error: %v", diags)
+		}
+		states[i] = d.State().Attributes
+	}
+	assert.Equal(t, states[1], states[0], "the metal-go state differs from the packngo state")
+	return states[0]
+}
@@ -0,0 +1,344 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	spotMarketRequestPending   = "pending"
+	spotMarketRequestFulfilled = "fulfilled"
+)
+
+// spotMarketCandidate is one metro and plan combination of a bidding strategy
+type spotMarketCandidate struct {
+	Metro string
+	Plan  string
+}
+
+// spotMarketBid is a priced candidate, ready to be placed as a spot market request
+type spotMarketBid struct {
+	Metro       string
+	Plan        string
+	MarketPrice float64
+	Price       float64
+}
+
+type spotMarketBiddingStrategy struct {
+	MaxPrice       float64
+	MarginPercent  float64
+	AttemptTimeout time.Duration
+	Candidates     []spotMarketCandidate
+}
+
+func validateDurationString(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+	if _, err := time.ParseDuration(v); err != nil {
+		errors = append(errors, fmt.Errorf("expected %s to be a duration such as 5m, got %q: %w", k, v, err))
+	}
+	return
+}
+
+func expandSpotMarketBiddingStrategy(d *schema.ResourceData) (*spotMarketBiddingStrategy, error) {
+	raw := d.Get("bidding_strategy").([]interface{})
+	if len(raw) == 0 || raw[0] == nil {
+		return nil, nil
+	}
+	m := raw[0].(map[string]interface{})
+
+	timeout, err := time.ParseDuration(m["attempt_timeout"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bidding_strategy.attempt_timeout: %w", err)
+	}
+	strategy := &spotMarketBiddingStrategy{
+		MaxPrice:       m["max_price"].(float64),
+		MarginPercent:  m["margin_percent"].(float64),
+		AttemptTimeout: timeout,
+	}
+
+	defaultPlan := d.Get("instance_parameters.0.plan").(string)
+	for _, c := range m["candidate"].([]interface{}) {
+		candidate := c.(map[string]interface{})
+		plan := candidate["plan"].(string)
+		if plan == "" {
+			plan = defaultPlan
+		}
+		strategy.Candidates = append(strategy.Candidates, spotMarketCandidate{
+			Metro: strings.ToLower(candidate["metro"].(string)),
+			Plan:  plan,
+		})
+	}
+	return strategy, nil
+}
+
+// spotMarketPrices are the spot market prices per hour by metro and plan
+type spotMarketPrices map[string]map[string]float64
+
+// spotMarketRequestWaitDelay is the delay before a spot market request and its
+// devices are polled for the first time
+var spotMarketRequestWaitDelay = 3 * time.Second
+
+// metroSpotMarketPrices reads the spot market prices of every metro. metal-go
+// only models a few metros and plans, the others are additional properties,
+// so the report is read back from its JSON
+func metroSpotMarketPrices(ctx context.Context, client *metalv1.APIClient) (spotMarketPrices, error) {
+	list, resp, err := client.SpotMarketApi.FindMetroSpotMarketPrices(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error reading spot market prices: %w", equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	report, err := json.Marshal(list.GetSpotMarketPrices())
+	if err != nil {
+		return nil, err
+	}
+	var metros map[string]map[string]struct {
+		Price float64 `json:"price"`
+	}
+	if err := json.Unmarshal(report, &metros); err != nil {
+		return nil, fmt.Errorf("error reading spot market prices: %w", err)
+	}
+	prices := spotMarketPrices{}
+	for metro, plans := range metros {
+		prices[metro] = map[string]float64{}
+		for plan, price := range plans {
+			prices[metro][plan] = price.Price
+		}
+	}
+	return prices, nil
+}
+
+// spotMarketBids prices the candidates of the strategy in order. Candidates
+// without a market price, or with a market price above the cap, are skipped.
+// Bids are placed at the configured margin above the market price, but never
+// above the cap.
+func (s *spotMarketBiddingStrategy) spotMarketBids(prices spotMarketPrices) ([]spotMarketBid, error) {
+	bids := []spotMarketBid{}
+	for _, c := range s.Candidates {
+		market, ok := prices[c.Metro][c.Plan]
+		if !ok {
+			log.Printf("[DEBUG] No spot market price for plan %s in metro %s, skipping", c.Plan, c.Metro)
+			continue
+		}
+		if market > s.MaxPrice {
+			log.Printf("[DEBUG] Spot market price %.2f for plan %s in metro %s is above %.2f, skipping", market, c.Plan, c.Metro, s.MaxPrice)
+			continue
+		}
+		price := math.Min(market*(1+s.MarginPercent/100), s.MaxPrice)
+		bids = append(bids, spotMarketBid{
+			Metro:       c.Metro,
+			Plan:        c.Plan,
+			MarketPrice: market,
+			Price:       math.Floor(price*100+1e-9) / 100,
+		})
+	}
+	if len(bids) == 0 {
+		return nil, fmt.Errorf("none of the bidding_strategy candidates has a spot market price at or below %.2f", s.MaxPrice)
+	}
+	return bids, nil
+}
+
+// metalSpotMarketRequest is a spot market request as the API returns it.
+// metal-go models its facilities as a single link and has no plan or
+// devices, so spot market requests are read with metalRequest
+type metalSpotMarketRequest struct {
+	ID          string     `json:"id"`
+	DevicesMin  int        `json:"devices_min"`
+	DevicesMax  int        `json:"devices_max"`
+	MaxBidPrice float64    `json:"max_bid_price"`
+	EndAt       *time.Time `json:"end_at"`
+	Facilities  []struct {
+		ID   string `json:"id"`
+		Code string `json:"code"`
+	} `json:"facilities"`
+	Metro *struct {
+		Code string `json:"code"`
+	} `json:"metro"`
+	Project struct {
+		ID   string `json:"id"`
+		Href string `json:"href"`
+	} `json:"project"`
+	Plan struct {
+		Slug string `json:"slug"`
+	} `json:"plan"`
+	Devices []struct {
+		ID string `json:"id"`
+	} `json:"devices"`
+}
+
+// ProjectID returns the project of the request, which is only a link unless
+// the project is included
+func (smr *metalSpotMarketRequest) ProjectID() string {
+	if smr.Project.ID != "" {
+		return smr.Project.ID
+	}
+	return path.Base(smr.Project.Href)
+}
+
+func (smr *metalSpotMarketRequest) DeviceIDs() []string {
+	ids := make([]string, len(smr.Devices))
+	for i, device := range smr.Devices {
+		ids[i] = device.ID
+	}
+	return ids
+}
+
+func createSpotMarketRequest(ctx context.Context, client *metalv1.APIClient, projectID string, smrc metalv1.SpotMarketRequestCreateInput) (*metalSpotMarketRequest, error) {
+	smr := &metalSpotMarketRequest{}
+	resp, err := metalRequest(ctx, client, http.MethodPost, "projects/"+projectID+"/spot-market-requests", smrc, smr)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return smr, nil
+}
+
+func getSpotMarketRequest(ctx context.Context, client *metalv1.APIClient, id string, include ...string) (*metalSpotMarketRequest, error) {
+	smr := &metalSpotMarketRequest{}
+	resp, err := metalRequest(ctx, client, http.MethodGet, "spot-market-requests/"+id+"?include="+strings.Join(include, ","), nil, smr)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return smr, nil
+}
+
+func bidSpotMarketRequest(ctx context.Context, client *metalv1.APIClient, projectID string, smrc metalv1.SpotMarketRequestCreateInput, strategy *spotMarketBiddingStrategy) (*metalSpotMarketRequest, *spotMarketBid, error) {
+	prices, err := metroSpotMarketPrices(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	bids, err := strategy.spotMarketBids(prices)
+	if err != nil {
+		return nil, nil, err
+	}
+	return placeSpotMarketBids(ctx, client, projectID, smrc, bids, strategy.AttemptTimeout)
+}
+
+// placeSpotMarketBids places the bids in order, until one of them gets at
+// least devices_min devices within the attempt timeout. Requests which were
+// not fulfilled in time are removed before the next bid is placed.
+func placeSpotMarketBids(ctx context.Context, client *metalv1.APIClient, projectID string, smrc metalv1.SpotMarketRequestCreateInput, bids []spotMarketBid, attemptTimeout time.Duration) (*metalSpotMarketRequest, *spotMarketBid, error) {
+	var errs []string
+	for i := range bids {
+		bid := bids[i]
+		params := *smrc.InstanceParameters
+		params.Plan = metalv1.PtrString(bid.Plan)
+		smrc.InstanceParameters = &params
+		smrc.Metro = metalv1.PtrString(bid.Metro)
+		smrc.Facilities = nil
+		smrc.MaxBidPrice = metalv1.PtrFloat32(float32(bid.Price))
+
+		log.Printf("[DEBUG] Bidding %.2f for %d %s devices in metro %s", bid.Price, smrc.GetDevicesMin(), bid.Plan, bid.Metro)
+		smr, err := createSpotMarketRequest(ctx, client, projectID, smrc)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %s", bid.Metro, bid.Plan, err))
+			continue
+		}
+
+		stateConf := &retry.StateChangeConf{
+			Pending:    []string{spotMarketRequestPending},
+			Target:     []string{spotMarketRequestFulfilled},
+			Refresh:    spotMarketRequestDevicesMinRefreshFunc(ctx, client, smr.ID, int(smrc.GetDevicesMin())),
+			Timeout:    attemptTimeout,
+			MinTimeout: 5 * time.Second,
+			Delay:      spotMarketRequestWaitDelay,
+		}
+		if _, err = stateConf.WaitForStateContext(ctx); err == nil {
+			return smr, &bid, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s/%s: %s", bid.Metro, bid.Plan, err))
+
+		if err := deleteSpotMarketRequest(ctx, client, smr.ID); err != nil {
+			return nil, nil, fmt.Errorf("error removing unfulfilled spot market request %s: %s", smr.ID, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, fmt.Errorf("no spot market bid was fulfilled: %s", strings.Join(errs, "; "))
+}
+
+// deleteSpotMarketRequest removes a spot market request and terminates its
+// devices. Requests already removed are not an error
+func deleteSpotMarketRequest(ctx context.Context, client *metalv1.APIClient, id string) error {
+	resp, err := client.SpotMarketApi.DeleteSpotMarketRequest(ctx, id).ForceTermination(true).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err)
+}
+
+func getSpotMarketRequestWithDevices(ctx context.Context, client *metalv1.APIClient, id string) (*metalSpotMarketRequest, error) {
+	smr, err := getSpotMarketRequest(ctx, client, id, "devices")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch Spot market request with following error: %s", err)
+	}
+	return smr, nil
+}
+
+func spotMarketRequestDevicesMinRefreshFunc(ctx context.Context, client *metalv1.APIClient, id string, devicesMin int) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		smr, err := getSpotMarketRequestWithDevices(ctx, client, id)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(smr.Devices) >= devicesMin {
+			return smr, spotMarketRequestFulfilled, nil
+		}
+		return smr, spotMarketRequestPending, nil
+	}
+}
+
+// spotMarketRequestDevicesActiveRefreshFunc is done once the devices of a
+// spot market request are active
+func spotMarketRequestDevicesActiveRefreshFunc(ctx context.Context, client *metalv1.APIClient, id string) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		smr, err := getSpotMarketRequestWithDevices(ctx, client, id)
+		if err != nil {
+			return nil, "", err
+		}
+		deviceIDs := smr.DeviceIDs()
+		for _, deviceID := range deviceIDs {
+			device, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceID).Execute()
+			if err != nil {
+				return nil, "", fmt.Errorf("Failed to fetch Device with following error: %s", equinix_errors.FriendlyErrorForMetalGo(err, resp))
+			}
+			if device.GetState() != metalv1.DEVICESTATE_ACTIVE {
+				return nil, "not_done", nil
+			}
+		}
+		if len(deviceIDs) == 0 {
+			return nil, "not_done", nil
+		}
+		return smr, "done", nil
+	}
+}
+
+func waitForSpotMarketRequestDevices(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending:        []string{"not_done"},
+		Target:         []string{"done"},
+		Refresh:        spotMarketRequestDevicesActiveRefreshFunc(ctx, client, id),
+		Timeout:        timeout - time.Second*10, // reduce 30s to avoid context deadline
+		MinTimeout:     5 * time.Second,
+		Delay:          spotMarketRequestWaitDelay,
+		NotFoundChecks: 600, // Setting high number, to support long timeouts
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
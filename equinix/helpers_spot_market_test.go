@@ -0,0 +1,187 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_spotMarketBids(t *testing.T) {
+	prices := spotMarketPrices{
+		"sv": {"c3.small.x86": 0.40, "m3.large.x86": 1.20},
+		"da": {"c3.small.x86": 0.25},
+		"ny": {"c3.small.x86": 0.90},
+	}
+
+	strategy := &spotMarketBiddingStrategy{
+		MaxPrice:      1.00,
+		MarginPercent: 10,
+		Candidates: []spotMarketCandidate{
+			{Metro: "ny", Plan: "c3.small.x86"}, // bid capped at max price
+			{Metro: "sv", Plan: "m3.large.x86"}, // market above max price, skipped
+			{Metro: "am", Plan: "c3.small.x86"}, // no price, skipped
+			{Metro: "da", Plan: "c3.small.x86"},
+			{Metro: "sv", Plan: "c3.small.x86"},
+		},
+	}
+
+	bids, err := strategy.spotMarketBids(prices)
+	assert.NoError(t, err)
+	assert.Equal(t, []spotMarketBid{
+		{Metro: "ny", Plan: "c3.small.x86", MarketPrice: 0.90, Price: 0.99},
+		{Metro: "da", Plan: "c3.small.x86", MarketPrice: 0.25, Price: 0.27},
+		{Metro: "sv", Plan: "c3.small.x86", MarketPrice: 0.40, Price: 0.44},
+	}, bids)
+
+	strategy.MaxPrice = 0.10
+	_, err = strategy.spotMarketBids(prices)
+	assert.Error(t, err)
+}
+
+func Test_metroSpotMarketPrices(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/market/spot/prices/metros": `{"spot_market_prices": {
+			"sv": {"c3.small.x86": {"price": 0.4}},
+			"da": {"c3.small.x86": {"price": 0.25}, "n3.xlarge.x86": {"price": 1.1}}
+		}}`,
+	})
+
+	prices, err := metroSpotMarketPrices(context.Background(), meta.Metalgo)
+	assert.NoError(t, err)
+	assert.Equal(t, spotMarketPrices{
+		"sv": {"c3.small.x86": 0.4},
+		"da": {"c3.small.x86": 0.25, "n3.xlarge.x86": 1.1},
+	}, prices)
+}
+
+func TestMetalSpotMarketRequestUpdate_rebidFailure(t *testing.T) {
+	defer func(delay time.Duration) { spotMarketRequestWaitDelay = delay }(spotMarketRequestWaitDelay)
+	spotMarketRequestWaitDelay = 0
+
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/market/spot/prices/metros":              `{"spot_market_prices": {"sv": {"c3.small.x86": {"price": 0.4}}}}`,
+		"POST /metal/v1/projects/project/spot-market-requests": `{"id": "new"}`,
+		"GET /metal/v1/spot-market-requests/new":               `{"id": "new", "devices": [{"id": "device"}]}`,
+		// The device of the new request fails to provision
+		"DELETE /metal/v1/spot-market-requests/new": "",
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMetalSpotMarketRequest().Schema, map[string]interface{}{
+		"project_id":       "project",
+		"devices_min":      1,
+		"devices_max":      1,
+		"wait_for_devices": true,
+		"instance_parameters": []interface{}{map[string]interface{}{
+			"hostname":         "spot",
+			"billing_cycle":    "hourly",
+			"plan":             "c3.small.x86",
+			"operating_system": "ubuntu_22_04",
+		}},
+		"bidding_strategy": []interface{}{map[string]interface{}{
+			"max_price":       1.0,
+			"attempt_timeout": "1m",
+			"candidate": []interface{}{map[string]interface{}{
+				"metro": "sv",
+				"plan":  "c3.small.x86",
+			}},
+		}},
+	})
+	d.SetId("old")
+
+	diags := resourceMetalSpotMarketRequestUpdate(context.Background(), d, meta)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, "old", d.Id(), "the state keeps the previous request")
+	assert.Contains(t, fake.requestOrder(), "DELETE /metal/v1/spot-market-requests/new")
+	assert.NotContains(t, fake.requestOrder(), "DELETE /metal/v1/spot-market-requests/old")
+}
+
+func TestMetalSpotMarketRequestRead_bid(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/spot-market-requests/smr": `{
+			"id": "smr",
+			"devices_min": 1,
+			"devices_max": 2,
+			"max_bid_price": 0.44,
+			"facilities": [{"id": "f", "code": "sv15"}],
+			"metro": {"code": "sv"},
+			"plan": {"slug": "c3.small.x86"},
+			"project": {"href": "/metal/v1/projects/project"}
+		}`,
+		"GET /metal/v1/market/spot/prices/metros": `{"spot_market_prices": {"sv": {"c3.small.x86": {"price": 0.4}}}}`,
+	})
+
+	// An imported request only has its ID
+	d := resourceMetalSpotMarketRequest().TestResourceData()
+	d.SetId("smr")
+
+	diags := resourceMetalSpotMarketRequestRead(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, "project", d.Get("project_id"))
+	assert.Equal(t, 2, d.Get("devices_max"))
+	assert.Equal(t, 0.44, d.Get("max_bid_price"))
+	assert.Equal(t, []interface{}{"sv15"}, d.Get("facilities"))
+	assert.Equal(t, "sv", d.Get("bid_metro"))
+	assert.Equal(t, "c3.small.x86", d.Get("bid_plan"))
+	assert.Equal(t, 0.4, d.Get("bid_market_price"))
+
+	// The market price of the bid is kept once known
+	delete(fake.responses, "GET /metal/v1/market/spot/prices/metros")
+	d.Set("bid_market_price", 0.35)
+	diags = resourceMetalSpotMarketRequestRead(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, 0.35, d.Get("bid_market_price"))
+}
+
+func TestMetalSpotMarketRequestCreate_maxBidPrice(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/projects/project/spot-market-requests": `{"id": "smr"}`,
+		"GET /metal/v1/spot-market-requests/smr":               `{"id": "smr", "devices_min": 1, "devices_max": 1, "max_bid_price": 0.5, "metro": {"code": "sv"}, "plan": {"slug": "c3.small.x86"}, "project": {"href": "/metal/v1/projects/project"}}`,
+		"GET /metal/v1/market/spot/prices/metros":              `{"spot_market_prices": {}}`,
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMetalSpotMarketRequest().Schema, map[string]interface{}{
+		"project_id":    "project",
+		"devices_min":   1,
+		"devices_max":   1,
+		"max_bid_price": 0.5,
+		"metro":         "sv",
+		"instance_parameters": []interface{}{map[string]interface{}{
+			"hostname":         "spot",
+			"billing_cycle":    "hourly",
+			"plan":             "c3.small.x86",
+			"operating_system": "ubuntu_22_04",
+			"customdata":       `{"key": "value"}`,
+		}},
+	})
+
+	diags := resourceMetalSpotMarketRequestCreate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, "smr", d.Id())
+	body, _ := fake.request("POST /metal/v1/projects/project/spot-market-requests")
+	assert.JSONEq(t, `{
+		"devices_min": 1,
+		"devices_max": 1,
+		"max_bid_price": 0.5,
+		"metro": "sv",
+		"instance_parameters": {
+			"always_pxe": false,
+			"billing_cycle": "hourly",
+			"customdata": "{\"key\": \"value\"}",
+			"hostname": "spot",
+			"locked": false,
+			"operating_system": "ubuntu_22_04",
+			"plan": "c3.small.x86",
+			"userdata": ""
+		}
+	}`, body)
+	assert.Equal(t, "sv", d.Get("bid_metro"))
+}
@@ -0,0 +1,103 @@
+package equinix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+// Some Equinix Metal responses do not match the metal-go models, for example
+// spot market requests list their facilities where metal-go expects a single
+// link. Those endpoints are called with the HTTP client, the server and the
+// headers of the metal-go client, and the models of the resource.
+
+// metalRequest sends a request to the Equinix Metal API and decodes the JSON
+// response into out. path is relative to the Metal API base path, such as
+// "spot-market-requests/{id}". The response is returned with the error of a
+// failed request, to be passed to equinix_errors.FriendlyErrorForMetalGo
+func metalRequest(ctx context.Context, client *metalv1.APIClient, method, path string, body, out interface{}) (*http.Response, error) {
+	cfg := client.GetConfig()
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	url := strings.TrimSuffix(cfg.Servers[0].URL, "/") + "/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range cfg.DefaultHeader {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", cfg.UserAgent)
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, fmt.Errorf("%s %s: %s %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp, fmt.Errorf("error decoding %s %s response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// metalListRequest requests every page of a list in turn and passes each page
+// to add, which decodes its items. Like the paginated metal-go requests, it
+// follows the page numbers of the list meta until the last page.
+func metalListRequest(ctx context.Context, client *metalv1.APIClient, path string, add func(page json.RawMessage) error) (*http.Response, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	for pageNumber := 1; ; {
+		var page json.RawMessage
+		resp, err := metalRequest(ctx, client, http.MethodGet, fmt.Sprintf("%s%spage=%d", path, separator, pageNumber), nil, &page)
+		if err != nil {
+			return resp, err
+		}
+		if err := add(page); err != nil {
+			return resp, err
+		}
+		meta := struct {
+			Meta struct {
+				CurrentPage int `json:"current_page"`
+				LastPage    int `json:"last_page"`
+			} `json:"meta"`
+		}{}
+		if err := json.Unmarshal(page, &meta); err != nil {
+			return resp, fmt.Errorf("error decoding the meta of %s: %w", path, err)
+		}
+		if meta.Meta.LastPage <= meta.Meta.CurrentPage {
+			return resp, nil
+		}
+		pageNumber = meta.Meta.CurrentPage + 1
+	}
+}
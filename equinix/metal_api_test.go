@@ -0,0 +1,56 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetalClientForSDK_ownUserAgent(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+	userAgent := meta.Metalgo.GetConfig().UserAgent
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, nil)
+	client := meta.NewMetalClientForSDK(d)
+	assert.Equal(t, userAgent, client.GetConfig().UserAgent, "resources without a module use the provider User-Agent")
+	assert.Same(t, meta.Metalgo.GetConfig().HTTPClient, client.GetConfig().HTTPClient, "the clients share the retrying HTTP client")
+
+	client.GetConfig().UserAgent = "module " + userAgent
+	assert.Equal(t, userAgent, meta.Metalgo.GetConfig().UserAgent)
+	assert.Equal(t, userAgent, meta.NewMetalClientForSDK(d).GetConfig().UserAgent, "the User-Agent of a client is not shared")
+}
+
+func TestMetalListRequest_pages(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{})
+	fake.queued["GET /metal/v1/organizations/org/invitations"] = []string{
+		`{"invitations": [{"id": "a"}, {"id": "b"}], "meta": {"current_page": 1, "last_page": 2}}`,
+		`{"invitations": [{"id": "c"}], "meta": {"current_page": 2, "last_page": 2}}`,
+	}
+
+	ids := []string{}
+	_, err := metalListRequest(context.Background(), meta.Metalgo, "organizations/org/invitations?include=user", func(page json.RawMessage) error {
+		list := struct {
+			Invitations []struct {
+				ID string `json:"id"`
+			} `json:"invitations"`
+		}{}
+		if err := json.Unmarshal(page, &list); err != nil {
+			return err
+		}
+		for _, inv := range list.Invitations {
+			ids = append(ids, inv.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.Equal(t, []string{
+		"GET /metal/v1/organizations/org/invitations",
+		"GET /metal/v1/organizations/org/invitations",
+	}, fake.requestOrder())
+}
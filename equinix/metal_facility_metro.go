@@ -0,0 +1,161 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// facilityMetros caches the metro codes of facility codes, facilities don't
+// move between metros
+var facilityMetros sync.Map
+
+// facilityMetroCode returns the code of the metro the facility is in
+func facilityMetroCode(ctx context.Context, meta interface{}, facility string) (string, error) {
+	facility = strings.ToLower(facility)
+	if metro, ok := facilityMetros.Load(facility); ok {
+		return metro.(string), nil
+	}
+
+	client := meta.(*config.Config).Metalgo
+	facilities, resp, err := client.FacilitiesApi.FindFacilities(ctx).Execute()
+	if err != nil {
+		return "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	for _, f := range facilities.GetFacilities() {
+		if f.Metro != nil && f.Metro.GetCode() != "" {
+			facilityMetros.Store(strings.ToLower(f.GetCode()), strings.ToLower(f.Metro.GetCode()))
+		}
+	}
+	if metro, ok := facilityMetros.Load(facility); ok {
+		return metro.(string), nil
+	}
+	return "", fmt.Errorf("facility %s was not found", facility)
+}
+
+// facilityMetroAttributes names the placement attributes of a Metal resource
+// which was created in a facility or in a metro
+type facilityMetroAttributes struct {
+	// Facility is a facility code, or a list of facility codes if
+	// DeployedFacility is set
+	Facility string
+	Metro    string
+	// DeployedFacility is the computed facility the resource was placed in
+	DeployedFacility string
+}
+
+func (a facilityMetroAttributes) isList() bool {
+	return a.DeployedFacility != ""
+}
+
+// currentFacility returns the facility the resource is in, from its state
+func (a facilityMetroAttributes) currentFacility(d *schema.ResourceDiff) string {
+	if a.isList() {
+		old, _ := d.GetChange(a.DeployedFacility)
+		return old.(string)
+	}
+	old, _ := d.GetChange(a.Facility)
+	return old.(string)
+}
+
+// newFacilities returns the facilities in the configuration
+func (a facilityMetroAttributes) newFacilities(d *schema.ResourceDiff) []string {
+	if a.isList() {
+		return converters.IfArrToStringArr(d.Get(a.Facility).([]interface{}))
+	}
+	if facility := d.Get(a.Facility).(string); facility != "" {
+		return []string{facility}
+	}
+	return nil
+}
+
+func (a facilityMetroAttributes) forceNew(d *schema.ResourceDiff) error {
+	for _, key := range []string{a.Facility, a.Metro} {
+		if d.HasChange(key) {
+			if err := d.ForceNew(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// customizeDiff replaces the resource when its facility or metro changes,
+// unless the configuration moves between the facility of the resource and the
+// metro of that facility. Such changes only update the state.
+func (a facilityMetroAttributes) customizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChanges(a.Facility, a.Metro) {
+		return nil
+	}
+	if !d.NewValueKnown(a.Facility) || !d.NewValueKnown(a.Metro) {
+		return a.forceNew(d)
+	}
+
+	currentFacility := a.currentFacility(d)
+	if facilities := a.newFacilities(d); len(facilities) > 0 {
+		// one of the configured facilities must be the one the resource is in
+		if !slices.ContainsFunc(facilities, func(f string) bool {
+			return strings.EqualFold(f, currentFacility) || (a.isList() && f == "any")
+		}) {
+			return a.forceNew(d)
+		}
+	}
+
+	newMetro := d.Get(a.Metro).(string)
+	if newMetro == "" {
+		return nil
+	}
+	oldMetro, _ := d.GetChange(a.Metro)
+	currentMetro := oldMetro.(string)
+	if currentMetro == "" && currentFacility != "" {
+		metro, err := facilityMetroCode(ctx, meta, currentFacility)
+		if err != nil {
+			log.Printf("[WARN] Could not find the metro of facility %s, changing %s replaces the resource: %s", currentFacility, a.Metro, err)
+			return a.forceNew(d)
+		}
+		currentMetro = metro
+	}
+	if !strings.EqualFold(newMetro, currentMetro) {
+		return a.forceNew(d)
+	}
+	return nil
+}
+
+// stateUpgrade sets the metro of resources which were created in a facility
+// and have no metro in their state yet. The state is kept as it is when the
+// metro can't be found.
+func (a facilityMetroAttributes) stateUpgrade(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	if metro, _ := rawState[a.Metro].(string); metro != "" {
+		return rawState, nil
+	}
+
+	facility, _ := rawState[a.Facility].(string)
+	if a.isList() {
+		facility, _ = rawState[a.DeployedFacility].(string)
+	}
+	if facility == "" || meta == nil {
+		return rawState, nil
+	}
+
+	metro, err := facilityMetroCode(ctx, meta, facility)
+	if err != nil {
+		log.Printf("[WARN] Could not find the metro of facility %s, %s is not set in the state: %s", facility, a.Metro, err)
+		return rawState, nil
+	}
+	rawState[a.Metro] = metro
+	return rawState, nil
+}
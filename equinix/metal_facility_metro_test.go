@@ -0,0 +1,230 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalFacilities = `{
+  "facilities": [
+    {"id": "4d8b4b8e-6a4c-4b4e-9f7e-1a2b3c4d5e6f", "code": "sv15", "name": "Silicon Valley, CA", "metro": {"id": "2991b022-b8c4-497e-8db7-5a407c3a209b", "code": "sv", "name": "Silicon Valley", "country": "US"}},
+    {"id": "5e9c5c9f-7b5d-4c5f-8a8f-2b3c4d5e6f7a", "code": "da11", "name": "Dallas, TX", "metro": {"id": "108b2cfb-246b-45e3-885a-bf3e82fce1a0", "code": "da", "name": "Dallas", "country": "US"}}
+  ]
+}`
+
+func testPlacementDiff(t *testing.T, r *schema.Resource, state map[string]string, config map[string]interface{}) *terraform.InstanceDiff {
+	t.Helper()
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+	diff, err := r.Diff(context.Background(), &terraform.InstanceState{ID: "id", Attributes: state}, terraform.NewResourceConfigRaw(config), meta)
+	assert.NoError(t, err)
+	return diff
+}
+
+func TestFacilityMetroCustomizeDiff_vlan(t *testing.T) {
+	state := map[string]string{
+		"id":         "id",
+		"project_id": "project",
+		"facility":   "sv15",
+		"metro":      "",
+		"vxlan":      "1001",
+	}
+
+	diff := testPlacementDiff(t, resourceMetalVlan(), state, map[string]interface{}{
+		"project_id": "project",
+		"metro":      "sv",
+	})
+	assert.False(t, diff.RequiresNew(), "moving from a facility to its metro keeps the VLAN")
+
+	diff = testPlacementDiff(t, resourceMetalVlan(), state, map[string]interface{}{
+		"project_id": "project",
+		"metro":      "da",
+	})
+	assert.True(t, diff.RequiresNew(), "moving to another metro replaces the VLAN")
+
+	diff = testPlacementDiff(t, resourceMetalVlan(), state, map[string]interface{}{
+		"project_id": "project",
+		"facility":   "da11",
+	})
+	assert.True(t, diff.RequiresNew(), "moving to another facility replaces the VLAN")
+}
+
+func TestFacilityMetroCustomizeDiff_device(t *testing.T) {
+	state := map[string]string{
+		"id":                "id",
+		"project_id":        "project",
+		"plan":              "c3.small.x86",
+		"operating_system":  "ubuntu_22_04",
+		"facilities.#":      "1",
+		"facilities.0":      "any",
+		"deployed_facility": "sv15",
+		"metro":             "sv",
+	}
+
+	diff := testPlacementDiff(t, resourceMetalDevice(), state, map[string]interface{}{
+		"project_id":       "project",
+		"plan":             "c3.small.x86",
+		"operating_system": "ubuntu_22_04",
+		"metro":            "sv",
+	})
+	assert.False(t, diff.RequiresNew(), "moving from facilities to the metro of the device keeps it")
+
+	diff = testPlacementDiff(t, resourceMetalDevice(), state, map[string]interface{}{
+		"project_id":       "project",
+		"plan":             "c3.small.x86",
+		"operating_system": "ubuntu_22_04",
+		"metro":            "da",
+	})
+	assert.True(t, diff.RequiresNew(), "moving to another metro replaces the device")
+}
+
+func TestFacilityMetroStateUpgrade(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+	ctx := context.Background()
+
+	state, err := metalVlanPlacement.stateUpgrade(ctx, map[string]interface{}{"facility": "da11", "metro": ""}, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "da", state["metro"])
+
+	state, err = metalVlanPlacement.stateUpgrade(ctx, map[string]interface{}{"facility": "da11", "metro": "sv"}, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "sv", state["metro"], "metros in the state are kept")
+
+	state, err = metalDevicePlacement.stateUpgrade(ctx, map[string]interface{}{"facilities": []interface{}{"any"}, "deployed_facility": "sv15"}, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "sv", state["metro"])
+
+	state, err = metalVlanPlacement.stateUpgrade(ctx, map[string]interface{}{"facility": "unknown1"}, meta)
+	assert.NoError(t, err, "unknown facilities don't fail the upgrade")
+	assert.Nil(t, state["metro"])
+}
+
+// upgradeMetalState upgrades a raw state the way Terraform does, decoding it
+// with the type of its schema version, and returns the upgraded attributes
+func upgradeMetalState(t *testing.T, meta *config.Config, typeName string, r *schema.Resource, version int64, raw *tfprotov5.RawState) map[string]string {
+	t.Helper()
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{typeName: r}}
+	p.SetMeta(meta)
+
+	resp, err := schema.NewGRPCProviderServer(p).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  version,
+		RawState: raw,
+	})
+	if err != nil {
+		t.Fatalf("error upgrading %s state: %s", typeName, err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("error upgrading %s state: %s: %s", typeName, d.Summary, d.Detail)
+	}
+	val, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding upgraded %s state: %s", typeName, err)
+	}
+	state, err := r.ShimInstanceStateFromValue(val)
+	if err != nil {
+		t.Fatalf("error decoding upgraded %s state: %s", typeName, err)
+	}
+	return state.Attributes
+}
+
+func TestMetalConnection_StateUpgradeV0(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+
+	state := upgradeMetalState(t, meta, "equinix_metal_connection", resourceMetalConnection(), 0, &tfprotov5.RawState{JSON: []byte(`{
+		"id": "conn", "name": "conn", "facility": "da11", "metro": null, "redundancy": "primary", "type": "dedicated",
+		"speed": "10Gbps", "vlans": [1000],
+		"ports": [{"id": "port", "name": "primary", "role": "primary", "speed": 10000000000, "status": "active", "link_status": "up", "virtual_circuit_ids": ["vc"]}]
+	}`)})
+	assert.Equal(t, "da", state["metro"])
+	assert.Equal(t, "da11", state["facility"])
+	assert.Equal(t, "10Gbps", state["speed"])
+	assert.Equal(t, "1000", state["vlans.0"])
+	assert.Equal(t, "vc", state["ports.0.virtual_circuit_ids.0"])
+}
+
+func TestMetalReservedIPBlock_StateUpgradeV0(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+
+	// States written by Terraform 0.11 are flatmaps, read with the type of
+	// version 0
+	state := upgradeMetalState(t, meta, "equinix_metal_reserved_ip_block", resourceMetalReservedIPBlock(), 0, &tfprotov5.RawState{Flatmap: map[string]string{
+		"id":              "block",
+		"project_id":      "project",
+		"facility":        "sv15",
+		"type":            "public_ipv4",
+		"quantity":        "4",
+		"cidr":            "30",
+		"global":          "false",
+		"tags.#":          "1",
+		"tags.1316447040": "edge",
+		"timeouts.%":      "0",
+		"custom_data":     "{}",
+		"wait_for_state":  "created",
+		"address_family":  "4",
+		"cidr_notation":   "147.75.0.0/30",
+		"management":      "false",
+		"manageable":      "true",
+		"public":          "true",
+		"network":         "147.75.0.0",
+		"netmask":         "255.255.255.252",
+		"gateway":         "147.75.0.1",
+		"address":         "147.75.0.0",
+		"description":     "",
+		"metro":           "",
+		"vrf_id":          "",
+	}})
+	assert.Equal(t, "sv", state["metro"])
+	assert.Equal(t, "4", state["quantity"])
+	assert.Equal(t, "1", state["tags.#"])
+}
+
+func TestMetalVlan_StateUpgradeV0(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+
+	state := upgradeMetalState(t, meta, "equinix_metal_vlan", resourceMetalVlan(), 0, &tfprotov5.RawState{JSON: []byte(`{
+		"id": "vlan", "project_id": "project", "facility": "sv15", "metro": null, "vxlan": 1000, "description": "edge"
+	}`)})
+	assert.Equal(t, "sv", state["metro"])
+	assert.Equal(t, "1000", state["vxlan"])
+}
+
+func TestMetalDevice_StateUpgradeV1(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/facilities": recordedMetalFacilities,
+	})
+
+	state := upgradeMetalState(t, meta, "equinix_metal_device", resourceMetalDevice(), 1, &tfprotov5.RawState{JSON: []byte(`{
+		"id": "device", "project_id": "project", "plan": "c3.small.x86", "operating_system": "ubuntu_22_04",
+		"facilities": ["any"], "deployed_facility": "sv15", "metro": null,
+		"storage": [{"disk": [{"device": "/dev/sda", "wipe_table": true, "partition": [{"label": "ROOT", "number": 1, "size": "0"}]}], "raid": null, "filesystem": null}]
+	}`)})
+	assert.Equal(t, "sv", state["metro"])
+	assert.Equal(t, "/dev/sda", state["storage.0.disk.0.device"])
+	assert.Equal(t, "ROOT", state["storage.0.disk.0.partition.0.label"])
+
+	// Version 0 states go through both upgrades
+	state = upgradeMetalState(t, meta, "equinix_metal_device", resourceMetalDevice(), 0, &tfprotov5.RawState{JSON: []byte(`{
+		"id": "device", "project_id": "project", "plan": "c3.small.x86", "operating_system": "ubuntu_22_04",
+		"facilities": ["sv15"], "deployed_facility": "sv15",
+		"storage": "{\"disks\": [{\"device\": \"/dev/sda\", \"wipeTable\": true}]}"
+	}`)})
+	assert.Equal(t, "sv", state["metro"])
+	assert.Equal(t, "/dev/sda", state["storage.0.disk.0.device"])
+}
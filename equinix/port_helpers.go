@@ -2,41 +2,46 @@ package equinix
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"golang.org/x/exp/slices"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
 )
 
+// portIncludes are the port fields included in port responses, the VLANs
+// are needed to tell the attached and the native VLANs
+var portIncludes = []string{
+	"native_virtual_network",
+	"virtual_networks",
+}
+
 type ClientPortResource struct {
-	Client   *packngo.Client
-	Port     *packngo.Port
+	Client   *metalv1.APIClient
+	Port     *metalv1.Port
 	Resource *schema.ResourceData
 }
 
-func getClientPortResource(d *schema.ResourceData, meta interface{}) (*ClientPortResource, *packngo.Response, error) {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func getClientPortResource(ctx context.Context, d *schema.ResourceData, meta interface{}) (*ClientPortResource, *http.Response, error) {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	port_id := d.Get("port_id").(string)
 
-	getOpts := &packngo.GetOptions{Includes: []string{
-		"native_virtual_network",
-		"virtual_networks",
-	}}
-	port, resp, err := client.Ports.Get(port_id, getOpts)
+	port, resp, err := client.PortsApi.FindPortById(ctx, port_id).Include(portIncludes).Execute()
 	if err != nil {
-		return nil, resp, err
+		return nil, resp, equinix_errors.FriendlyErrorForMetalGo(err, resp)
 	}
 
 	cpr := &ClientPortResource{
@@ -47,7 +52,7 @@ func getClientPortResource(d *schema.ResourceData, meta interface{}) (*ClientPor
 	return cpr, resp, nil
 }
 
-func getPortByResourceData(d *schema.ResourceData, client *packngo.Client) (*packngo.Port, error) {
+func getPortByResourceData(ctx context.Context, d *schema.ResourceData, client *metalv1.APIClient) (*metalv1.Port, error) {
 	portId, portIdOk := d.GetOk("port_id")
 	resourceId := d.Id()
 
@@ -68,30 +73,22 @@ func getPortByResourceData(d *schema.ResourceData, client *packngo.Client) (*pac
 		}
 	}
 
-	var port *packngo.Port
-
-	getOpts := &packngo.GetOptions{Includes: []string{
-		"native_virtual_network",
-		"virtual_networks",
-	}}
 	if portIdOk {
-		var err error
-		port, _, err = client.Ports.Get(portId.(string), getOpts)
+		port, resp, err := client.PortsApi.FindPortById(ctx, portId.(string)).Include(portIncludes).Execute()
 		if err != nil {
-			return nil, err
-		}
-	} else {
-		if !(deviceIdOk && portNameOk) {
-			return nil, fmt.Errorf("If you don't use port_id, you must supply both device_id and name")
+			return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		}
-		device, _, err := client.Devices.Get(deviceId.(string), getOpts)
-		if err != nil {
-			return nil, err
-		}
-		return device.GetPortByName(portName.(string))
+		return port, nil
 	}
 
-	return port, nil
+	if !(deviceIdOk && portNameOk) {
+		return nil, fmt.Errorf("If you don't use port_id, you must supply both device_id and name")
+	}
+	device, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceId.(string)).Include(portIncludes).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return devicePortByName(device, portName.(string))
 }
 
 func getSpecifiedNative(d *schema.ResourceData) string {
@@ -103,18 +100,33 @@ func getSpecifiedNative(d *schema.ResourceData) string {
 	return specifiedNative
 }
 
-func getCurrentNative(p *packngo.Port) string {
+func getCurrentNative(p *metalv1.Port) string {
 	currentNative := ""
 	if p.NativeVirtualNetwork != nil {
-		currentNative = p.NativeVirtualNetwork.ID
+		currentNative = p.NativeVirtualNetwork.GetId()
 	}
 	return currentNative
 }
 
-func attachedVlanIds(p *packngo.Port) []string {
+// portVirtualNetworks returns the VLANs attached to the port. metal-go models
+// them as links, the fields of the included VLANs are only kept in the
+// additional properties of the links.
+func portVirtualNetworks(p *metalv1.Port) []metalv1.VirtualNetwork {
+	vlans := make([]metalv1.VirtualNetwork, 0, len(p.VirtualNetworks))
+	for _, link := range p.VirtualNetworks {
+		vlan := metalv1.VirtualNetwork{}
+		if raw, err := link.MarshalJSON(); err == nil {
+			_ = json.Unmarshal(raw, &vlan)
+		}
+		vlans = append(vlans, vlan)
+	}
+	return vlans
+}
+
+func attachedVlanIds(p *metalv1.Port) []string {
 	attached := []string{}
-	for _, v := range p.AttachedVirtualNetworks {
-		attached = append(attached, v.ID)
+	for _, v := range portVirtualNetworks(p) {
+		attached = append(attached, v.GetId())
 	}
 	return attached
 }
@@ -133,8 +145,15 @@ func specifiedVlanIds(d *schema.ResourceData) []string {
 	return []string{}
 }
 
-func batchVlans(ctx context.Context, start time.Time, removeOnly bool) func(*ClientPortResource) error {
-	return func(cpr *ClientPortResource) error {
+func vlanAssignment(vlan string, state metalv1.PortVlanAssignmentBatchVlanAssignmentsInnerState) metalv1.PortVlanAssignmentBatchCreateInputVlanAssignmentsInner {
+	return metalv1.PortVlanAssignmentBatchCreateInputVlanAssignmentsInner{
+		Vlan:  metalv1.PtrString(vlan),
+		State: state.Ptr(),
+	}
+}
+
+func batchVlans(start time.Time, removeOnly bool) func(context.Context, *ClientPortResource) error {
+	return func(ctx context.Context, cpr *ClientPortResource) error {
 		var vlansToAssign []string
 		var currentNative string
 		vlansToRemove := converters.Difference(
@@ -149,38 +168,34 @@ func batchVlans(ctx context.Context, start time.Time, removeOnly bool) func(*Cli
 				attachedVlanIds(cpr.Port),
 			)
 		}
-		vacr := &packngo.VLANAssignmentBatchCreateRequest{}
+		input := metalv1.PortVlanAssignmentBatchCreateInput{}
 		for _, v := range vlansToRemove {
-			vacr.VLANAssignments = append(vacr.VLANAssignments, packngo.VLANAssignmentCreateRequest{
-				VLAN:  v,
-				State: packngo.VLANAssignmentUnassigned,
-			})
+			input.VlanAssignments = append(input.VlanAssignments,
+				vlanAssignment(v, metalv1.PORTVLANASSIGNMENTBATCHVLANASSIGNMENTSINNERSTATE_UNASSIGNED))
 		}
 
 		for _, v := range vlansToAssign {
-			native := currentNative == v
-			vacr.VLANAssignments = append(vacr.VLANAssignments, packngo.VLANAssignmentCreateRequest{
-				VLAN:   v,
-				State:  packngo.VLANAssignmentAssigned,
-				Native: &native,
-			})
+			assignment := vlanAssignment(v, metalv1.PORTVLANASSIGNMENTBATCHVLANASSIGNMENTSINNERSTATE_ASSIGNED)
+			assignment.Native = metalv1.PtrBool(currentNative == v)
+			input.VlanAssignments = append(input.VlanAssignments, assignment)
 		}
-		return createAndWaitForBatch(ctx, start, cpr, vacr)
+		return createAndWaitForBatch(ctx, start, cpr, input)
 	}
 }
 
-func createAndWaitForBatch(ctx context.Context, start time.Time, cpr *ClientPortResource, vacr *packngo.VLANAssignmentBatchCreateRequest) error {
-	if len(vacr.VLANAssignments) == 0 {
+func createAndWaitForBatch(ctx context.Context, start time.Time, cpr *ClientPortResource, input metalv1.PortVlanAssignmentBatchCreateInput) error {
+	if len(input.VlanAssignments) == 0 {
 		return nil
 	}
 
-	portID := cpr.Port.ID
+	portID := cpr.Port.GetId()
 	c := cpr.Client
 
-	b, _, err := c.VLANAssignments.CreateBatch(portID, vacr, nil)
+	b, resp, err := c.PortsApi.CreatePortVlanAssignmentBatch(ctx, portID).PortVlanAssignmentBatchCreateInput(input).Execute()
 	if err != nil {
-		return fmt.Errorf("vlan assignment batch could not be created: %w", err)
+		return fmt.Errorf("vlan assignment batch could not be created: %w", equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
+	batchID := b.GetId()
 
 	deadline, _ := ctx.Deadline()
 	// originally set timeout in ctx by TF
@@ -188,145 +203,132 @@ func createAndWaitForBatch(ctx context.Context, start time.Time, cpr *ClientPort
 
 	stateChangeConf := &retry.StateChangeConf{
 		Delay:      5 * time.Second,
-		Pending:    []string{string(packngo.VLANAssignmentBatchQueued), string(packngo.VLANAssignmentBatchInProgress)},
-		Target:     []string{string(packngo.VLANAssignmentBatchCompleted)},
+		Pending:    []string{string(metalv1.PORTVLANASSIGNMENTBATCHSTATE_QUEUED), string(metalv1.PORTVLANASSIGNMENTBATCHSTATE_IN_PROGRESS)},
+		Target:     []string{string(metalv1.PORTVLANASSIGNMENTBATCHSTATE_COMPLETED)},
 		MinTimeout: 5 * time.Second,
 		Timeout:    ctxTimeout - time.Since(start) - 30*time.Second,
 		Refresh: func() (result interface{}, state string, err error) {
-			b, _, err := c.VLANAssignments.GetBatch(portID, b.ID, nil)
-			switch b.State {
-			case packngo.VLANAssignmentBatchFailed:
-				return b, string(packngo.VLANAssignmentBatchFailed),
-					fmt.Errorf("vlan assignment batch %s provisioning failed: %s", b.ID, strings.Join(b.ErrorMessages, "; "))
-			case packngo.VLANAssignmentBatchCompleted:
-				return b, string(packngo.VLANAssignmentBatchCompleted), nil
+			b, resp, err := c.PortsApi.FindPortVlanAssignmentBatchByPortIdAndBatchId(ctx, portID, batchID).Execute()
+			if err != nil {
+				return nil, "", fmt.Errorf("vlan assignment batch %s could not be polled: %w", batchID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+			}
+			switch b.GetState() {
+			case metalv1.PORTVLANASSIGNMENTBATCHSTATE_FAILED:
+				return b, string(metalv1.PORTVLANASSIGNMENTBATCHSTATE_FAILED),
+					fmt.Errorf("vlan assignment batch %s provisioning failed: %s", batchID, strings.Join(b.ErrorMessages, "; "))
 			default:
-				if err != nil {
-					return b, "", fmt.Errorf("vlan assignment batch %s could not be polled: %w", b.ID, err)
-				}
-				return b, string(b.State), err
+				return b, string(b.GetState()), nil
 			}
 		},
 	}
 	if _, err = stateChangeConf.WaitForStateContext(ctx); err != nil {
-		return errors.Wrapf(err, "vlan assignment batch %s is not complete after timeout", b.ID)
+		return errors.Wrapf(err, "vlan assignment batch %s is not complete after timeout", batchID)
 	}
 	return nil
 }
 
-func updateNativeVlan(cpr *ClientPortResource) error {
+func updateNativeVlan(ctx context.Context, cpr *ClientPortResource) error {
 	currentNative := getCurrentNative(cpr.Port)
 	specifiedNative := getSpecifiedNative(cpr.Resource)
 
 	if currentNative != specifiedNative {
-		var port *packngo.Port
+		var port *metalv1.Port
+		var resp *http.Response
 		var err error
 		if specifiedNative == "" && currentNative != "" {
-			port, _, err = cpr.Client.Ports.UnassignNative(cpr.Port.ID)
+			port, resp, err = cpr.Client.PortsApi.DeleteNativeVlan(ctx, cpr.Port.GetId()).Include(portIncludes).Execute()
 		} else {
-			port, _, err = cpr.Client.Ports.AssignNative(cpr.Port.ID, specifiedNative)
+			port, resp, err = cpr.Client.PortsApi.AssignNativeVlan(ctx, cpr.Port.GetId()).Vnid(specifiedNative).Include(portIncludes).Execute()
 		}
 		if err != nil {
-			return err
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		}
 		*(cpr.Port) = *port
 	}
 	return nil
 }
 
-func processBondAction(cpr *ClientPortResource, actionIsBond bool) error {
+func processBondAction(ctx context.Context, cpr *ClientPortResource, actionIsBond bool) error {
 	wantsBondedRaw, wantsBondedOk := cpr.Resource.GetOkExists("bonded")
 	wantsBonded := wantsBondedRaw.(bool)
 	// only act if the necessary action is the one specified in doBond
 	if wantsBondedOk && (wantsBonded == actionIsBond) {
 		// act if the current Bond state of the port is different than the spcified
-		if wantsBonded != cpr.Port.Data.Bonded {
-			action := cpr.Client.DevicePorts.Disbond
+		if wantsBonded != cpr.Port.Data.GetBonded() {
+			var resp *http.Response
+			var err error
 			if wantsBonded {
-				action = cpr.Client.DevicePorts.Bond
+				_, resp, err = cpr.Client.PortsApi.BondPort(ctx, cpr.Port.GetId()).BulkEnable(false).Execute()
+			} else {
+				_, resp, err = cpr.Client.PortsApi.DisbondPort(ctx, cpr.Port.GetId()).BulkDisable(false).Execute()
 			}
-
-			port, _, err := action(cpr.Port, false)
 			if err != nil {
-				return err
+				return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			}
-			getOpts := &packngo.GetOptions{Includes: []string{
-				"native_virtual_network",
-				"virtual_networks",
-			}}
-			port, _, err = cpr.Client.Ports.Get(port.ID, getOpts)
-			if err != nil {
-				return err
-			}
-
-			*(cpr.Port) = *port
+			return refreshPort(ctx, cpr)
 		}
 	}
 	return nil
 }
 
-func makeBond(cpr *ClientPortResource) error {
-	return processBondAction(cpr, true)
+func makeBond(ctx context.Context, cpr *ClientPortResource) error {
+	return processBondAction(ctx, cpr, true)
 }
 
-func makeDisbond(cpr *ClientPortResource) error {
-	return processBondAction(cpr, false)
+func makeDisbond(ctx context.Context, cpr *ClientPortResource) error {
+	return processBondAction(ctx, cpr, false)
 }
 
-func convertToL2(cpr *ClientPortResource) error {
+func convertToL2(ctx context.Context, cpr *ClientPortResource) error {
 	l2, l2Ok := cpr.Resource.GetOkExists("layer2")
-	isLayer2 := slices.Contains(l2Types, cpr.Port.NetworkType)
+	isLayer2 := slices.Contains(l2Types, string(cpr.Port.GetNetworkType()))
 
 	if l2Ok && l2.(bool) && !isLayer2 {
-		port, _, err := cpr.Client.Ports.ConvertToLayerTwo(cpr.Port.ID)
+		port, resp, err := cpr.Client.PortsApi.ConvertLayer2(ctx, cpr.Port.GetId()).
+			PortAssignInput(metalv1.PortAssignInput{}).
+			Include(portIncludes).
+			Execute()
 		if err != nil {
-			return err
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		}
 		*(cpr.Port) = *port
 	}
 	return nil
 }
 
-func convertToL3(cpr *ClientPortResource) error {
+func convertToL3(ctx context.Context, cpr *ClientPortResource) error {
 	l2, l2Ok := cpr.Resource.GetOkExists("layer2")
-	isLayer2 := slices.Contains(l2Types, cpr.Port.NetworkType)
+	isLayer2 := slices.Contains(l2Types, string(cpr.Port.GetNetworkType()))
 
 	if l2Ok && !l2.(bool) && isLayer2 {
-		ips := []packngo.AddressRequest{
-			{AddressFamily: 4, Public: true},
-			{AddressFamily: 4, Public: false},
-			{AddressFamily: 6, Public: true},
+		ips := []metalv1.PortConvertLayer3InputRequestIpsInner{
+			{AddressFamily: metalv1.PtrInt32(4), Public: metalv1.PtrBool(true)},
+			{AddressFamily: metalv1.PtrInt32(4), Public: metalv1.PtrBool(false)},
+			{AddressFamily: metalv1.PtrInt32(6), Public: metalv1.PtrBool(true)},
 		}
-		port, _, err := cpr.Client.Ports.ConvertToLayerThree(cpr.Port.ID, ips)
+		port, resp, err := cpr.Client.PortsApi.ConvertLayer3(ctx, cpr.Port.GetId()).
+			PortConvertLayer3Input(metalv1.PortConvertLayer3Input{RequestIps: ips}).
+			Include(portIncludes).
+			Execute()
 		if err != nil {
-			return err
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		}
 		*(cpr.Port) = *port
 	}
 	return nil
 }
 
-func portSanityChecks(cpr *ClientPortResource) error {
-	isBondPort := cpr.Port.Type == "NetworkBondPort"
-
-	// Constraint: Only bond ports have layer2 mode
-	l2Raw, l2Ok := cpr.Resource.GetOkExists("layer2")
-	if !isBondPort && l2Ok {
-		return fmt.Errorf("layer2 flag can be set only for bond ports")
-	}
-
-	l2 := l2Raw.(bool)
-
-	bonded := cpr.Resource.Get("bonded").(bool)
-
-	// Constraint: L3 unbonded is not really allowed for Bond port
-	if isBondPort && !l2 && !bonded {
-		return fmt.Errorf("bond port in Layer3 can't be unbonded")
-	}
+// portSanityChecks repeats the checks of resourceMetalPortCustomizeDiff, for
+// ports which were not known when planning
+func portSanityChecks(_ context.Context, cpr *ClientPortResource) error {
+	_, err := planPortTransition(cpr.Port, portTransitionTargetFromResourceData(cpr.Resource))
+	return err
+}
 
-	// Constraint: native vlan ..
-	// - must be one of assigned vlans
-	// - there must be more than one vlan assigned to the port
+// nativeVlanSanityCheck checks the constraints of the native vlan, it ..
+// - must be one of assigned vlans
+// - there must be more than one vlan assigned to the port
+func nativeVlanSanityCheck(_ context.Context, cpr *ClientPortResource) error {
 	nativeVlanRaw, nativeVlanOk := cpr.Resource.GetOk("native_vlan_id")
 	if nativeVlanOk {
 		nativeVlan := nativeVlanRaw.(string)
@@ -342,18 +344,28 @@ func portSanityChecks(cpr *ClientPortResource) error {
 	return nil
 }
 
-func portProperlyDestroyed(port *packngo.Port) error {
+// refreshPort reloads the port, e.g. after a vlan assignment batch completed
+func refreshPort(ctx context.Context, cpr *ClientPortResource) error {
+	port, resp, err := cpr.Client.PortsApi.FindPortById(ctx, cpr.Port.GetId()).Include(portIncludes).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	*(cpr.Port) = *port
+	return nil
+}
+
+func portProperlyDestroyed(port *metalv1.Port) error {
 	var errs []string
-	if !port.Data.Bonded {
-		errs = append(errs, fmt.Sprintf("port %s wasn't bonded after equinix_metal_port destroy;", port.ID))
+	if !port.Data.GetBonded() {
+		errs = append(errs, fmt.Sprintf("port %s wasn't bonded after equinix_metal_port destroy;", port.GetId()))
 	}
-	if port.Type == "NetworkBondPort" && port.NetworkType != "layer3" {
+	if port.GetType() == metalv1.PORTTYPE_NETWORK_BOND_PORT && port.GetNetworkType() != metalv1.PORTNETWORKTYPE_LAYER3 {
 		errs = append(errs, "bond port should be in layer3 type after destroy;")
 	}
 	if port.NativeVirtualNetwork != nil {
 		errs = append(errs, "port should not have native VLAN assigned after destroy;")
 	}
-	if len(port.AttachedVirtualNetworks) != 0 {
+	if len(port.VirtualNetworks) != 0 {
 		errs = append(errs, "port should not have VLANs attached after destroy")
 	}
 	if len(errs) > 0 {
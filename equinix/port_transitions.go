@@ -0,0 +1,317 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// portTransitionTarget is the configuration an equinix_metal_port is moved to
+type portTransitionTarget struct {
+	Bonded bool
+	// Layer2 is nil when the layer2 flag is not set
+	Layer2 *bool
+	// Vlans are the vlan_ids, or the vxlan_ids if UseVxlans is set. They
+	// are nil if they are not known yet.
+	Vlans     []string
+	UseVxlans bool
+	// KeepVlans is set when neither vlan_ids nor vxlan_ids are configured or
+	// changed. The attached VLANs are left alone, so that VLANs attached with
+	// equinix_metal_port_vlan_attachment or equinix_metal_port_vlans stay.
+	KeepVlans bool
+	// Native is nil if native_vlan_id is not known yet
+	Native *string
+}
+
+// portVlansConfigured tells whether vlan_ids or vxlan_ids are set in the
+// configuration. Both are computed, so their values alone can not tell.
+func portVlansConfigured(config cty.Value) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	return !config.GetAttr("vlan_ids").IsNull() || !config.GetAttr("vxlan_ids").IsNull()
+}
+
+func portTransitionTargetFromResourceData(d *schema.ResourceData) portTransitionTarget {
+	t := portTransitionTarget{
+		Bonded:    d.Get("bonded").(bool),
+		Vlans:     specifiedVlanIds(d),
+		KeepVlans: !portVlansConfigured(d.GetRawConfig()),
+	}
+	if l2, ok := d.GetOkExists("layer2"); ok {
+		layer2 := l2.(bool)
+		t.Layer2 = &layer2
+	}
+	_, t.UseVxlans = d.GetOk("vxlan_ids")
+	if _, ok := d.GetOk("vlan_ids"); ok {
+		t.UseVxlans = false
+	}
+	native := getSpecifiedNative(d)
+	t.Native = &native
+	return t
+}
+
+func portTransitionTargetFromResourceDiff(d *schema.ResourceDiff) portTransitionTarget {
+	t := portTransitionTarget{
+		Bonded: d.Get("bonded").(bool),
+	}
+	if l2 := d.GetRawConfig().GetAttr("layer2"); !l2.IsNull() && l2.IsKnown() {
+		layer2 := l2.True()
+		t.Layer2 = &layer2
+	}
+
+	vlans := d.GetRawConfig().GetAttr("vlan_ids")
+	vxlans := d.GetRawConfig().GetAttr("vxlan_ids")
+	switch {
+	case vlans.IsNull() && vxlans.IsNull(),
+		d.Id() != "" && !d.HasChange("vlan_ids") && !d.HasChange("vxlan_ids"):
+		t.KeepVlans = true
+	case !vlans.IsWhollyKnown() || !vxlans.IsWhollyKnown():
+		// VLANs created in the same plan are not known yet
+	case !vxlans.IsNull() && vxlans.LengthInt() > 0:
+		t.UseVxlans = true
+		t.Vlans = converters.IfArrToIntStringArr(d.Get("vxlan_ids").(*schema.Set).List())
+	default:
+		t.Vlans = converters.IfArrToStringArr(d.Get("vlan_ids").(*schema.Set).List())
+	}
+
+	if d.NewValueKnown("native_vlan_id") {
+		native := d.Get("native_vlan_id").(string)
+		t.Native = &native
+	}
+	return t
+}
+
+// planPortTransition checks that the port can be moved to the target and
+// returns the steps resourceMetalPortUpdate runs to get there, in order.
+func planPortTransition(port *metalv1.Port, t portTransitionTarget) ([]string, error) {
+	if t.KeepVlans {
+		t.Vlans, t.UseVxlans = attachedVlanIds(port), false
+	}
+	portName := port.GetName()
+	bonded := port.Data.GetBonded()
+	isBondPort := port.GetType() == metalv1.PORTTYPE_NETWORK_BOND_PORT
+	isLayer2 := slices.Contains(l2Types, string(port.GetNetworkType()))
+
+	// Constraint: Only bond ports have layer2 mode
+	if !isBondPort && t.Layer2 != nil {
+		return nil, fmt.Errorf("layer2 flag can be set only for bond ports, %s is a %s", portName, port.GetType())
+	}
+	wantsLayer2 := t.Layer2 != nil && *t.Layer2
+
+	// Constraint: L3 unbonded is not really allowed for Bond port
+	if isBondPort && !wantsLayer2 && !t.Bonded {
+		return nil, fmt.Errorf("bond port %s in Layer3 can't be unbonded, set layer2 to true for the layer2-individual network type", portName)
+	}
+
+	if bonded && !t.Bonded && !port.GetDisbondOperationSupported() {
+		return nil, fmt.Errorf("port %s can't be removed from its bond, the device plan does not support disbonding", portName)
+	}
+
+	// Constraint: native vlan ..
+	// - must be one of assigned vlans
+	// - there must be more than one vlan assigned to the port
+	if t.Native != nil && *t.Native != "" && t.Vlans != nil {
+		if !slices.Contains(t.Vlans, *t.Native) {
+			return nil, fmt.Errorf("the native VLAN %s is not (being) assigned to port %s", *t.Native, portName)
+		}
+		if len(t.Vlans) < 2 {
+			return nil, fmt.Errorf("native VLAN can only be set if more than one VLAN are assigned to port %s", portName)
+		}
+	}
+
+	var steps []string
+	var toRemove, toAssign []string
+	if t.Vlans != nil {
+		attached := attachedVlanIds(port)
+		if t.UseVxlans {
+			attached = []string{}
+			for _, v := range portVirtualNetworks(port) {
+				attached = append(attached, strconv.Itoa(int(v.GetVxlan())))
+			}
+		}
+		toRemove = converters.Difference(attached, t.Vlans)
+		toAssign = converters.Difference(t.Vlans, attached)
+		sort.Strings(toRemove)
+		sort.Strings(toAssign)
+	}
+
+	if len(toRemove) > 0 {
+		steps = append(steps, fmt.Sprintf("detach VLANs %s from %s", strings.Join(toRemove, ", "), portName))
+	}
+	if bonded && !t.Bonded {
+		steps = append(steps, fmt.Sprintf("disbond %s", portName))
+	}
+	if wantsLayer2 && !isLayer2 {
+		steps = append(steps, fmt.Sprintf("convert %s to layer2", portName))
+	}
+	if !bonded && t.Bonded {
+		steps = append(steps, fmt.Sprintf("bond %s", portName))
+	}
+	if t.Layer2 != nil && !wantsLayer2 && isLayer2 {
+		steps = append(steps, fmt.Sprintf("convert %s to layer3", portName))
+	}
+	if t.Vlans == nil {
+		steps = append(steps, fmt.Sprintf("attach and detach VLANs of %s (known after apply)", portName))
+	} else if len(toAssign) > 0 {
+		steps = append(steps, fmt.Sprintf("attach VLANs %s to %s", strings.Join(toAssign, ", "), portName))
+	}
+	if t.Native != nil && *t.Native != getCurrentNative(port) {
+		if *t.Native == "" {
+			steps = append(steps, fmt.Sprintf("unassign native VLAN of %s", portName))
+		} else {
+			steps = append(steps, fmt.Sprintf("assign native VLAN %s to %s", *t.Native, portName))
+		}
+	}
+	return steps, nil
+}
+
+// portTransitionAttributes are the attributes which move a port to another
+// configuration
+var portTransitionAttributes = []string{"bonded", "layer2", "native_vlan_id", "vlan_ids", "vxlan_ids"}
+
+// resourceMetalPortCustomizeDiff rejects port transitions which can not be
+// applied and plans the steps of the transition in transition_steps. Ports
+// whose configuration did not change are not planned, so that the plan stays
+// empty.
+func resourceMetalPortCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges(portTransitionAttributes...) {
+		return nil
+	}
+	if !d.NewValueKnown("port_id") {
+		// ports of devices created in the same plan are not known yet
+		d.SetNewComputed("transition_steps")
+		return nil
+	}
+
+	client := meta.(*config.Config).Metalgo
+	port, resp, err := client.PortsApi.FindPortById(ctx, d.Get("port_id").(string)).Include(portIncludes).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] Port (%s) not found, its changes can not be planned", d.Get("port_id").(string))
+			d.SetNewComputed("transition_steps")
+			return nil
+		}
+		return fmt.Errorf("error reading port %s to plan its changes: %w", d.Get("port_id").(string), err)
+	}
+
+	steps, err := planPortTransition(port, portTransitionTargetFromResourceDiff(d))
+	if err != nil {
+		return err
+	}
+	if len(steps) > 0 {
+		return d.SetNew("transition_steps", steps)
+	}
+	return nil
+}
+
+// planDeviceNetworkTypeTransition checks that the device can be converted to
+// the network type and returns the steps of the conversion, following
+// convertDeviceNetworkType.
+func planDeviceNetworkTypeTransition(device *metalv1.Device, targetType string) ([]string, error) {
+	// "hybrid-bonded" is an alias for "layer3" with VLAN(s) connected
+	if targetType == "hybrid-bonded" {
+		targetType = string(metalv1.PORTNETWORKTYPE_LAYER3)
+	}
+	currentType := deviceNetworkType(device)
+	if currentType == targetType {
+		return nil, nil
+	}
+
+	bondPorts := portNames(devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_BOND_PORT))
+	ethPorts := portNames(devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_PORT))
+
+	var steps []string
+	switch metalv1.PortNetworkType(targetType) {
+	case metalv1.PORTNETWORKTYPE_LAYER3:
+		steps = append(steps,
+			fmt.Sprintf("bond %s", strings.Join(bondPorts, ", ")),
+			"convert bond0 to layer3",
+			fmt.Sprintf("bond %s", strings.Join(ethPorts, ", ")))
+	case metalv1.PORTNETWORKTYPE_HYBRID:
+		var oddPorts []string
+		for _, name := range []string{"eth1", "eth3"} {
+			port, err := devicePortByName(device, name)
+			if err != nil {
+				continue
+			}
+			if port.Data.GetBonded() && !port.GetDisbondOperationSupported() {
+				return nil, fmt.Errorf("device %s can't be converted to %s, port %s does not support disbonding", device.GetId(), targetType, name)
+			}
+			oddPorts = append(oddPorts, name)
+		}
+		steps = append(steps,
+			fmt.Sprintf("bond %s", strings.Join(bondPorts, ", ")),
+			"convert bond0 to layer3",
+			fmt.Sprintf("disbond %s", strings.Join(oddPorts, ", ")))
+	case metalv1.PORTNETWORKTYPE_LAYER2_INDIVIDUAL:
+		for _, name := range bondPorts {
+			port, _ := devicePortByName(device, name)
+			if port.Data.GetBonded() && !port.GetDisbondOperationSupported() {
+				return nil, fmt.Errorf("device %s can't be converted to %s, port %s does not support disbonding", device.GetId(), targetType, name)
+			}
+		}
+		steps = append(steps,
+			"convert bond0 to layer2",
+			fmt.Sprintf("disbond %s", strings.Join(bondPorts, ", ")))
+	case metalv1.PORTNETWORKTYPE_LAYER2_BONDED:
+		steps = append(steps,
+			fmt.Sprintf("convert %s to layer2", strings.Join(bondPorts, ", ")),
+			fmt.Sprintf("bond %s", strings.Join(ethPorts, ", ")))
+	}
+	return append([]string{fmt.Sprintf("convert device from %s to %s", currentType, targetType)}, steps...), nil
+}
+
+func portNames(ports map[string]*metalv1.Port) []string {
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resourceMetalDeviceNetworkTypeCustomizeDiff rejects network type changes
+// which can not be applied and plans the steps in transition_steps.
+func resourceMetalDeviceNetworkTypeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("device_id") || !d.NewValueKnown("type") {
+		d.SetNewComputed("transition_steps")
+		return nil
+	}
+
+	client := meta.(*config.Config).Metalgo
+	device, resp, err := client.DevicesApi.FindDeviceById(ctx, d.Get("device_id").(string)).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] Device (%s) not found, its network type change can not be planned", d.Get("device_id").(string))
+			d.SetNewComputed("transition_steps")
+			return nil
+		}
+		return fmt.Errorf("error reading device %s to plan its network type change: %w", d.Get("device_id").(string), err)
+	}
+
+	steps, err := planDeviceNetworkTypeTransition(device, d.Get("type").(string))
+	if err != nil {
+		return err
+	}
+	if len(steps) > 0 {
+		return d.SetNew("transition_steps", steps)
+	}
+	return nil
+}
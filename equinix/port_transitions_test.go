@@ -0,0 +1,223 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_planPortTransition(t *testing.T) {
+	yes, no := true, false
+	vlan1, vlan2, empty := "vlan-1", "vlan-2", ""
+
+	bond0 := func(networkType string, bonded, disbondSupported bool, vlans ...string) *metalv1.Port {
+		p := &metalv1.Port{
+			Name:                      metalv1.PtrString("bond0"),
+			Type:                      metalv1.PORTTYPE_NETWORK_BOND_PORT.Ptr(),
+			NetworkType:               metalv1.PortNetworkType(networkType).Ptr(),
+			Data:                      &metalv1.PortData{Bonded: metalv1.PtrBool(bonded)},
+			DisbondOperationSupported: metalv1.PtrBool(disbondSupported),
+		}
+		for _, v := range vlans {
+			p.VirtualNetworks = append(p.VirtualNetworks, metalv1.Href{
+				Href:                 "/metal/v1/virtual-networks/" + v,
+				AdditionalProperties: map[string]interface{}{"id": v},
+			})
+		}
+		return p
+	}
+	eth1 := &metalv1.Port{
+		Name:                      metalv1.PtrString("eth1"),
+		Type:                      metalv1.PORTTYPE_NETWORK_PORT.Ptr(),
+		Data:                      &metalv1.PortData{Bonded: metalv1.PtrBool(true)},
+		DisbondOperationSupported: metalv1.PtrBool(true),
+	}
+
+	tests := []struct {
+		name      string
+		port      *metalv1.Port
+		target    portTransitionTarget
+		wantSteps []string
+		wantErr   string
+	}{
+		{
+			name:   "layer3 to layer2-individual with native VLAN",
+			port:   bond0("layer3", true, true),
+			target: portTransitionTarget{Bonded: false, Layer2: &yes, Vlans: []string{vlan1, vlan2}, Native: &vlan1},
+			wantSteps: []string{
+				"disbond bond0",
+				"convert bond0 to layer2",
+				"attach VLANs vlan-1, vlan-2 to bond0",
+				"assign native VLAN vlan-1 to bond0",
+			},
+		},
+		{
+			name:   "layer2-bonded back to layer3",
+			port:   bond0("layer2-bonded", true, true, vlan1),
+			target: portTransitionTarget{Bonded: true, Layer2: &no, Vlans: []string{}, Native: &empty},
+			wantSteps: []string{
+				"detach VLANs vlan-1 from bond0",
+				"convert bond0 to layer3",
+			},
+		},
+		{
+			name:      "unknown VLANs",
+			port:      bond0("layer2-bonded", true, true),
+			target:    portTransitionTarget{Bonded: true, Layer2: &yes},
+			wantSteps: []string{"attach and detach VLANs of bond0 (known after apply)"},
+		},
+		{
+			name:   "unmanaged VLANs",
+			port:   bond0("layer2-bonded", true, true, vlan1, vlan2),
+			target: portTransitionTarget{Bonded: true, Layer2: &yes, KeepVlans: true, Native: &vlan2},
+			wantSteps: []string{
+				"assign native VLAN vlan-2 to bond0",
+			},
+		},
+		{
+			name:   "no changes",
+			port:   bond0("layer3", true, true),
+			target: portTransitionTarget{Bonded: true, Vlans: []string{}, Native: &empty},
+		},
+		{
+			name:    "disbond not supported",
+			port:    bond0("layer3", true, false),
+			target:  portTransitionTarget{Bonded: false, Layer2: &yes},
+			wantErr: "does not support disbonding",
+		},
+		{
+			name:    "layer2 on eth port",
+			port:    eth1,
+			target:  portTransitionTarget{Bonded: true, Layer2: &yes},
+			wantErr: "only for bond ports",
+		},
+		{
+			name:    "unbonded layer3 bond port",
+			port:    bond0("layer3", true, true),
+			target:  portTransitionTarget{Bonded: false, Layer2: &no},
+			wantErr: "can't be unbonded",
+		},
+		{
+			name:    "native VLAN not attached",
+			port:    bond0("layer2-individual", false, true),
+			target:  portTransitionTarget{Bonded: false, Layer2: &yes, Vlans: []string{vlan1, "vlan-3"}, Native: &vlan2},
+			wantErr: "is not (being) assigned",
+		},
+		{
+			name:    "native VLAN as only VLAN",
+			port:    bond0("layer2-individual", false, true),
+			target:  portTransitionTarget{Bonded: false, Layer2: &yes, Vlans: []string{vlan1}, Native: &vlan1},
+			wantErr: "more than one VLAN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := planPortTransition(tt.port, tt.target)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSteps, steps)
+		})
+	}
+}
+
+func TestMetalPortCustomizeDiff_outOfBandVlans(t *testing.T) {
+	// vlan-1 was attached with equinix_metal_port_vlan_attachment
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/ports/7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a": `{
+			"id": "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a",
+			"name": "bond0",
+			"type": "NetworkBondPort",
+			"network_type": "hybrid-bonded",
+			"data": {"bonded": true},
+			"disbond_operation_supported": true,
+			"virtual_networks": [{"id": "vlan-1", "vxlan": 1000, "href": "/metal/v1/virtual-networks/vlan-1"}]
+		}`,
+	})
+	d := resourceMetalPort().TestResourceData()
+	d.SetId("7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a")
+	d.Set("port_id", "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a")
+	if diags := resourceMetalPortRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+
+	diff, err := testMetalPortDiff(t, d, meta, map[string]cty.Value{
+		"port_id": cty.StringVal("7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a"),
+		"bonded":  cty.True,
+		"layer2":  cty.False,
+	})
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty(), "the port keeps the VLANs it does not manage: %v", diff)
+
+	diff, err = testMetalPortDiff(t, d, meta, map[string]cty.Value{
+		"port_id":  cty.StringVal("7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a"),
+		"bonded":   cty.True,
+		"layer2":   cty.False,
+		"vlan_ids": cty.SetVal([]cty.Value{cty.StringVal("vlan-2")}),
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, diff) {
+		assert.Equal(t, "detach VLANs vlan-1 from bond0", diff.Attributes["transition_steps.0"].New)
+		assert.Equal(t, "attach VLANs vlan-2 to bond0", diff.Attributes["transition_steps.1"].New)
+	}
+}
+
+// testMetalPortDiff plans the port configuration against the state of d. The
+// raw configuration the port CustomizeDiff reads is passed like Terraform does.
+func testMetalPortDiff(t *testing.T, d *schema.ResourceData, meta interface{}, attrs map[string]cty.Value) (*terraform.InstanceDiff, error) {
+	t.Helper()
+	block := resourceMetalPort().CoreConfigSchema()
+	config, err := block.CoerceValue(cty.ObjectVal(attrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := d.State()
+	state.RawConfig = config
+	return resourceMetalPort().Diff(context.Background(), state, terraform.NewResourceConfigShimmed(config, block), meta)
+}
+
+func Test_planDeviceNetworkTypeTransition(t *testing.T) {
+	port := func(name string, portType metalv1.PortType, disbondSupported bool) metalv1.Port {
+		return metalv1.Port{
+			Name:                      metalv1.PtrString(name),
+			Type:                      portType.Ptr(),
+			Data:                      &metalv1.PortData{Bonded: metalv1.PtrBool(true)},
+			DisbondOperationSupported: metalv1.PtrBool(disbondSupported),
+		}
+	}
+	device := func(eth1DisbondSupported bool) *metalv1.Device {
+		return &metalv1.Device{
+			Id: metalv1.PtrString("device"),
+			NetworkPorts: []metalv1.Port{
+				port("bond0", metalv1.PORTTYPE_NETWORK_BOND_PORT, true),
+				port("eth0", metalv1.PORTTYPE_NETWORK_PORT, true),
+				port("eth1", metalv1.PORTTYPE_NETWORK_PORT, eth1DisbondSupported),
+			},
+			IpAddresses: []metalv1.IPAssignment{{Management: metalv1.PtrBool(true)}},
+		}
+	}
+
+	steps, err := planDeviceNetworkTypeTransition(device(true), "hybrid")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"convert device from layer3 to hybrid",
+		"bond bond0",
+		"convert bond0 to layer3",
+		"disbond eth1",
+	}, steps)
+
+	steps, err = planDeviceNetworkTypeTransition(device(true), "hybrid-bonded")
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+
+	_, err = planDeviceNetworkTypeTransition(device(false), "hybrid")
+	assert.ErrorContains(t, err, "port eth1 does not support disbonding")
+}
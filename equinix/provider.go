@@ -74,81 +74,107 @@ func Provider() *schema.Provider {
 				Optional: true,
 				Default:  30,
 			},
+			"fabric_csp_profile_rules_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(config.FabricCspProfileRulesFileEnvVar, ""),
+				Description: "Path to a JSON file of rules checking the connections to cloud service provider profiles at plan time. They are added to the rules built into the provider, and replace them for the same profile names",
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"equinix_ecx_port":                   dataSourceECXPort(),
-			"equinix_ecx_l2_sellerprofile":       dataSourceECXL2SellerProfile(),
-			"equinix_ecx_l2_sellerprofiles":      dataSourceECXL2SellerProfiles(),
-			"equinix_fabric_routing_protocol":    dataSourceRoutingProtocol(),
-			"equinix_fabric_connection":          dataSourceFabricConnection(),
-			"equinix_fabric_cloud_router":        dataSourceCloudRouter(),
-			"equinix_fabric_port":                dataSourceFabricPort(),
-			"equinix_fabric_ports":               dataSourceFabricGetPortsByName(),
-			"equinix_fabric_service_profile":     dataSourceFabricServiceProfileReadByUuid(),
-			"equinix_fabric_service_profiles":    dataSourceFabricSearchServiceProfilesByName(),
-			"equinix_network_account":            dataSourceNetworkAccount(),
-			"equinix_network_device":             dataSourceNetworkDevice(),
-			"equinix_network_device_type":        dataSourceNetworkDeviceType(),
-			"equinix_network_device_software":    dataSourceNetworkDeviceSoftware(),
-			"equinix_network_device_platform":    dataSourceNetworkDevicePlatform(),
-			"equinix_metal_hardware_reservation": dataSourceMetalHardwareReservation(),
-			"equinix_metal_metro":                dataSourceMetalMetro(),
-			"equinix_metal_facility":             dataSourceMetalFacility(),
-			"equinix_metal_connection":           dataSourceMetalConnection(),
-			"equinix_metal_gateway":              dataSourceMetalGateway(),
-			"equinix_metal_ip_block_ranges":      dataSourceMetalIPBlockRanges(),
-			"equinix_metal_precreated_ip_block":  dataSourceMetalPreCreatedIPBlock(),
-			"equinix_metal_operating_system":     dataSourceOperatingSystem(),
-			"equinix_metal_organization":         dataSourceMetalOrganization(),
-			"equinix_metal_spot_market_price":    dataSourceSpotMarketPrice(),
-			"equinix_metal_device":               dataSourceMetalDevice(),
-			"equinix_metal_devices":              dataSourceMetalDevices(),
-			"equinix_metal_device_bgp_neighbors": dataSourceMetalDeviceBGPNeighbors(),
-			"equinix_metal_plans":                dataSourceMetalPlans(),
-			"equinix_metal_port":                 dataSourceMetalPort(),
-			"equinix_metal_project":              dataSourceMetalProject(),
-			"equinix_metal_project_ssh_key":      metal_project_ssh_key.DataSource(),
-			"equinix_metal_reserved_ip_block":    dataSourceMetalReservedIPBlock(),
-			"equinix_metal_spot_market_request":  dataSourceMetalSpotMarketRequest(),
-			"equinix_metal_virtual_circuit":      dataSourceMetalVirtualCircuit(),
-			"equinix_metal_vlan":                 dataSourceMetalVlan(),
-			"equinix_metal_vrf":                  dataSourceMetalVRF(),
+			"equinix_ecx_port":                       dataSourceECXPort(),
+			"equinix_ecx_l2_sellerprofile":           dataSourceECXL2SellerProfile(),
+			"equinix_ecx_l2_sellerprofiles":          dataSourceECXL2SellerProfiles(),
+			"equinix_fabric_routing_protocol":        dataSourceRoutingProtocol(),
+			"equinix_fabric_routing_protocol_status": dataSourceFabricRoutingProtocolStatus(),
+			"equinix_fabric_connection":              dataSourceFabricConnection(),
+			"equinix_fabric_network_connections":     dataSourceFabricNetworkConnections(),
+			"equinix_fabric_cloud_router":            dataSourceCloudRouter(),
+			"equinix_fabric_cloud_router_routes":     dataSourceFabricCloudRouterRoutes(),
+			"equinix_fabric_port":                    dataSourceFabricPort(),
+			"equinix_fabric_ports":                   dataSourceFabricGetPortsByName(),
+			"equinix_fabric_price":                   dataSourceFabricPrice(),
+			"equinix_fabric_service_profile":         dataSourceFabricServiceProfileReadByUuid(),
+			"equinix_fabric_service_profiles":        dataSourceFabricSearchServiceProfilesByName(),
+			"equinix_network_account":                dataSourceNetworkAccount(),
+			"equinix_network_device":                 dataSourceNetworkDevice(),
+			"equinix_network_device_type":            dataSourceNetworkDeviceType(),
+			"equinix_network_device_software":        dataSourceNetworkDeviceSoftware(),
+			"equinix_network_device_platform":        dataSourceNetworkDevicePlatform(),
+			"equinix_metal_hardware_reservation":     dataSourceMetalHardwareReservation(),
+			"equinix_metal_metro":                    dataSourceMetalMetro(),
+			"equinix_metal_facility":                 dataSourceMetalFacility(),
+			"equinix_metal_connection":               dataSourceMetalConnection(),
+			"equinix_metal_gateway":                  dataSourceMetalGateway(),
+			"equinix_metal_ip_block_ranges":          dataSourceMetalIPBlockRanges(),
+			"equinix_metal_precreated_ip_block":      dataSourceMetalPreCreatedIPBlock(),
+			"equinix_metal_operating_system":         dataSourceOperatingSystem(),
+			"equinix_metal_organization":             dataSourceMetalOrganization(),
+			"equinix_metal_spot_market_price":        dataSourceSpotMarketPrice(),
+			"equinix_metal_device":                   dataSourceMetalDevice(),
+			"equinix_metal_devices":                  dataSourceMetalDevices(),
+			"equinix_metal_events":                   dataSourceMetalEvents(),
+			"equinix_metal_device_bgp_neighbors":     dataSourceMetalDeviceBGPNeighbors(),
+			"equinix_metal_plans":                    dataSourceMetalPlans(),
+			"equinix_metal_port":                     dataSourceMetalPort(),
+			"equinix_metal_project":                  dataSourceMetalProject(),
+			"equinix_metal_project_ssh_key":          metal_project_ssh_key.DataSource(),
+			"equinix_metal_project_usage":            dataSourceMetalProjectUsage(),
+			"equinix_metal_reserved_ip_block":        dataSourceMetalReservedIPBlock(),
+			"equinix_metal_spot_market_request":      dataSourceMetalSpotMarketRequest(),
+			"equinix_metal_virtual_circuit":          dataSourceMetalVirtualCircuit(),
+			"equinix_metal_vlan":                     dataSourceMetalVlan(),
+			"equinix_metal_vrf":                      dataSourceMetalVRF(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"equinix_ecx_l2_connection":          resourceECXL2Connection(),
-			"equinix_ecx_l2_connection_accepter": resourceECXL2ConnectionAccepter(),
-			"equinix_ecx_l2_serviceprofile":      resourceECXL2ServiceProfile(),
-			"equinix_fabric_cloud_router":        resourceCloudRouter(),
-			"equinix_fabric_connection":          resourceFabricConnection(),
-			"equinix_fabric_routing_protocol":    resourceFabricRoutingProtocol(),
-			"equinix_fabric_service_profile":     resourceFabricServiceProfile(),
-			"equinix_network_device":             resourceNetworkDevice(),
-			"equinix_network_ssh_user":           resourceNetworkSSHUser(),
-			"equinix_network_bgp":                resourceNetworkBGP(),
-			"equinix_network_ssh_key":            resourceNetworkSSHKey(),
-			"equinix_network_acl_template":       resourceNetworkACLTemplate(),
-			"equinix_network_device_link":        resourceNetworkDeviceLink(),
-			"equinix_network_file":               resourceNetworkFile(),
-			"equinix_metal_user_api_key":         resourceMetalUserAPIKey(),
-			"equinix_metal_project_api_key":      resourceMetalProjectAPIKey(),
-			"equinix_metal_connection":           resourceMetalConnection(),
-			"equinix_metal_device":               resourceMetalDevice(),
-			"equinix_metal_device_network_type":  resourceMetalDeviceNetworkType(),
-			"equinix_metal_ssh_key":              metal_ssh_key.Resource(),
-			"equinix_metal_organization_member":  resourceMetalOrganizationMember(),
-			"equinix_metal_port":                 resourceMetalPort(),
-			"equinix_metal_project_ssh_key":      metal_project_ssh_key.Resource(),
-			"equinix_metal_project":              resourceMetalProject(),
-			"equinix_metal_organization":         resourceMetalOrganization(),
-			"equinix_metal_reserved_ip_block":    resourceMetalReservedIPBlock(),
-			"equinix_metal_ip_attachment":        resourceMetalIPAttachment(),
-			"equinix_metal_spot_market_request":  resourceMetalSpotMarketRequest(),
-			"equinix_metal_vlan":                 resourceMetalVlan(),
-			"equinix_metal_virtual_circuit":      resourceMetalVirtualCircuit(),
-			"equinix_metal_vrf":                  resourceMetalVRF(),
-			"equinix_metal_bgp_session":          resourceMetalBGPSession(),
-			"equinix_metal_port_vlan_attachment": resourceMetalPortVlanAttachment(),
-			"equinix_metal_gateway":              resourceMetalGateway(),
+			"equinix_ecx_l2_connection":                   resourceECXL2Connection(),
+			"equinix_ecx_l2_connection_accepter":          resourceECXL2ConnectionAccepter(),
+			"equinix_ecx_l2_serviceprofile":               resourceECXL2ServiceProfile(),
+			"equinix_fabric_cloud_router":                 resourceCloudRouter(),
+			"equinix_fabric_connection":                   resourceFabricConnection(),
+			"equinix_fabric_connection_pair":              resourceFabricConnectionPair(),
+			"equinix_fabric_connection_route_aggregation": resourceFabricConnectionRouteAggregation(),
+			"equinix_fabric_connection_route_filter":      resourceFabricConnectionRouteFilter(),
+			"equinix_fabric_network":                      resourceFabricNetwork(),
+			"equinix_fabric_port":                         resourceFabricPort(),
+			"equinix_fabric_route_aggregation":            resourceFabricRouteAggregation(),
+			"equinix_fabric_route_aggregation_rule":       resourceFabricRouteAggregationRule(),
+			"equinix_fabric_route_filter":                 resourceFabricRouteFilter(),
+			"equinix_fabric_route_filter_rule":            resourceFabricRouteFilterRule(),
+			"equinix_fabric_routing_protocol":             resourceFabricRoutingProtocol(),
+			"equinix_fabric_service_profile":              resourceFabricServiceProfile(),
+			"equinix_fabric_service_token":                resourceFabricServiceToken(),
+			"equinix_network_device":                      resourceNetworkDevice(),
+			"equinix_network_ssh_user":                    resourceNetworkSSHUser(),
+			"equinix_network_bgp":                         resourceNetworkBGP(),
+			"equinix_network_ssh_key":                     resourceNetworkSSHKey(),
+			"equinix_network_acl_template":                resourceNetworkACLTemplate(),
+			"equinix_network_device_link":                 resourceNetworkDeviceLink(),
+			"equinix_network_file":                        resourceNetworkFile(),
+			"equinix_metal_user_api_key":                  resourceMetalUserAPIKey(),
+			"equinix_metal_project_api_key":               resourceMetalProjectAPIKey(),
+			"equinix_metal_connection":                    resourceMetalConnection(),
+			"equinix_metal_device":                        resourceMetalDevice(),
+			"equinix_metal_device_network_type":           resourceMetalDeviceNetworkType(),
+			"equinix_metal_ssh_key":                       metal_ssh_key.Resource(),
+			"equinix_metal_organization_member":           resourceMetalOrganizationMember(),
+			"equinix_metal_organization_members":          resourceMetalOrganizationMembers(),
+			"equinix_metal_port":                          resourceMetalPort(),
+			"equinix_metal_port_vlans":                    resourceMetalPortVlans(),
+			"equinix_metal_project_ssh_key":               metal_project_ssh_key.Resource(),
+			"equinix_metal_project":                       resourceMetalProject(),
+			"equinix_metal_organization":                  resourceMetalOrganization(),
+			"equinix_metal_reserved_ip_block":             resourceMetalReservedIPBlock(),
+			"equinix_metal_floating_ip":                   resourceMetalFloatingIP(),
+			"equinix_metal_ip_attachment":                 resourceMetalIPAttachment(),
+			"equinix_metal_spot_market_request":           resourceMetalSpotMarketRequest(),
+			"equinix_metal_vlan":                          resourceMetalVlan(),
+			"equinix_metal_virtual_circuit":               resourceMetalVirtualCircuit(),
+			"equinix_metal_vrf":                           resourceMetalVRF(),
+			"equinix_metal_bgp_session":                   resourceMetalBGPSession(),
+			"equinix_metal_port_vlan_attachment":          resourceMetalPortVlanAttachment(),
+			"equinix_metal_gateway":                       resourceMetalGateway(),
+			"equinix_metal_hardware_reservation":          resourceMetalHardwareReservation(),
 		},
 		ProviderMetaSchema: map[string]*schema.Schema{
 			"module_name": {
@@ -182,6 +208,8 @@ func configureProvider(ctx context.Context, d *schema.ResourceData, p *schema.Pr
 		PageSize:       d.Get("response_max_page_size").(int),
 		MaxRetries:     d.Get("max_retries").(int),
 		MaxRetryWait:   time.Duration(mrws) * time.Second,
+
+		FabricCspProfileRulesFile: d.Get("fabric_csp_profile_rules_file").(string),
 	}
 	meta := providerMeta{}
 
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/hashcode"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
@@ -31,17 +33,245 @@ func resourceFabricConnection() *schema.Resource {
 		UpdateContext: resourceFabricConnectionUpdate,
 		DeleteContext: resourceFabricConnectionDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceFabricConnectionImport,
 		},
-		Schema: createFabricConnectionResourceSchema(),
+		Schema:        createFabricConnectionResourceSchema(),
+		CustomizeDiff: resourceFabricConnectionCustomizeDiff,
 
 		Description: "Fabric V4 API compatible resource allows creation and management of Equinix Fabric connection\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
 	}
 }
 
+// resourceFabricConnectionImport imports a connection by its UUID, which is
+// the same UUID the equinix_ecx_l2_connection resource was tracking. The ID of
+// an imported redundant ECX connection is '(primaryID):(secondaryID)', while
+// each Fabric connection is a resource of its own
+func resourceFabricConnectionImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ids := strings.Split(d.Id(), ":")
+	if len(ids) > 1 {
+		return nil, fmt.Errorf("%q is the ID of a redundant equinix_ecx_l2_connection, import the primary connection %s and the secondary connection %s into two equinix_fabric_connection resources", d.Id(), ids[0], ids[1])
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// connectionSideIdentityAttributes are the attributes identifying a connection
+// side, which Fabric can not change in place
+var connectionSideIdentityAttributes = []string{
+	"access_point.type",
+	"access_point.port.uuid",
+	"access_point.profile.uuid",
+	"access_point.router.uuid",
+	"access_point.network.uuid",
+	"access_point.virtual_device.uuid",
+	"access_point.virtual_device.type",
+	"access_point.interface.uuid",
+	"access_point.interface.type",
+	"access_point.link_protocol.type",
+	"access_point.seller_region",
+	"access_point.peering_type",
+	"access_point.authentication_key",
+}
+
+// connectionSideProfileAttributes identify a service profile access point in
+// addition to connectionSideIdentityAttributes. The metro of the other access
+// points is the one of their port, router, network or device, which Fabric
+// fills in on read
+var connectionSideProfileAttributes = []string{
+	"access_point.location.metro_code",
+}
+
+// connectionSideVlanAttributes are the VLAN tags of a port access point,
+// which resourceFabricConnectionUpdate changes in place
+var connectionSideVlanAttributes = []string{
+	"access_point.link_protocol.vlan_tag",
+	"access_point.link_protocol.vlan_s_tag",
+	"access_point.link_protocol.vlan_c_tag",
+}
+
+// fabricConnectionConfig is the configuration of a connection, either of an
+// equinix_fabric_connection or of a leg of an equinix_fabric_connection_pair.
+// It is implemented by schema.ResourceData and schema.ResourceDiff
+type fabricConnectionConfig interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	HasChange(key string) bool
+}
+
+// resourceFabricConnectionCustomizeDiff validates connections to cloud service
+// provider profiles, and replaces the connection only when an attribute
+// Fabric can not patch changes. Changes to the name, bandwidth, notifications,
+// redundancy group and VLAN tags are applied in place
+func resourceFabricConnectionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateCspProfileRules(ctx, d, meta, ""); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	for _, key := range connectionReplacedAttributes(d) {
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectionReplacedAttributes returns the attributes of a connection that
+// Fabric can not patch and that changed, as paths for ResourceDiff.ForceNew
+func connectionReplacedAttributes(d fabricConnectionConfig) []string {
+	var replaced []string
+	if d.HasChange("type") {
+		replaced = append(replaced, "type")
+	}
+	for _, key := range []string{"a_side", "z_side"} {
+		if !d.HasChange(key) {
+			continue
+		}
+		old, new := d.GetChange(key)
+		if connectionSideReplaced(old, new) {
+			attribute := "access_point"
+			if connectionSideAttribute(firstSetElem(new), "service_token.uuid") != "" {
+				attribute = "service_token"
+			}
+			replaced = append(replaced, setElemAttribute(d, key, attribute))
+		}
+	}
+	for _, elem := range []struct{ key, attribute string }{{"project", "project_id"}, {"redundancy", "priority"}} {
+		if !d.HasChange(elem.key) {
+			continue
+		}
+		old, new := d.GetChange(elem.key)
+		updateVal, _ := firstSetElem(new)[elem.attribute].(string)
+		if existingVal, _ := firstSetElem(old)[elem.attribute].(string); updateVal != "" && !strings.EqualFold(existingVal, updateVal) {
+			replaced = append(replaced, setElemAttribute(d, elem.key, elem.attribute))
+		}
+	}
+	return replaced
+}
+
+// setElemAttribute returns the path of an attribute of the new element of a
+// set. ForceNew on the set itself only applies to changes of its size, not to
+// an element replaced by another one
+func setElemAttribute(d fabricConnectionConfig, key, attribute string) string {
+	set := d.Get(key).(*schema.Set)
+	for _, elem := range set.List() {
+		return fmt.Sprintf("%s.%d.%s", key, set.F(elem), attribute)
+	}
+	return key
+}
+
+// connectionSideReplaced reports whether a connection side changes one of
+// connectionSideIdentityAttributes or connectionSideProfileAttributes, or
+// moves to another service token. Attributes left unset in the configuration
+// are not compared
+func connectionSideReplaced(old, new interface{}) bool {
+	oldSide, newSide := firstSetElem(old), firstSetElem(new)
+	attributes := append([]string{"service_token.uuid"}, connectionSideIdentityAttributes...)
+	for _, attribute := range append(attributes, connectionSideProfileAttributes...) {
+		updateVal := strings.ToUpper(connectionSideAttribute(newSide, attribute))
+		if updateVal != "" && updateVal != strings.ToUpper(connectionSideAttribute(oldSide, attribute)) {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionSideAttribute returns the attribute of a connection side at the
+// given dotted path, e.g. access_point.port.uuid, as a string
+func connectionSideAttribute(side map[string]interface{}, path string) string {
+	parts := strings.Split(path, ".")
+	elem := side
+	for _, part := range parts[:len(parts)-1] {
+		elem = firstSetElem(elem[part])
+	}
+	switch v := elem[parts[len(parts)-1]].(type) {
+	case string:
+		return v
+	case int:
+		if v != 0 {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// connectionSideHash hashes a connection side on the attributes identifying
+// it in the configuration. Attributes Fabric fills in on read, like the
+// location of a port or the access point behind a service token, are left
+// out so that they do not show as changes
+func connectionSideHash(v interface{}) int {
+	side, _ := v.(map[string]interface{})
+	if token := connectionSideAttribute(side, "service_token.uuid"); token != "" {
+		return hashcode.String(token)
+	}
+	attributes := append([]string{}, connectionSideIdentityAttributes...)
+	if connectionSideAttribute(side, "access_point.profile.uuid") != "" {
+		attributes = append(attributes, connectionSideProfileAttributes...)
+	}
+	if connectionSideAttribute(side, "access_point.port.uuid") != "" {
+		// Fabric assigns the VLAN tags of the other access points
+		attributes = append(attributes, connectionSideVlanAttributes...)
+	}
+	var buf bytes.Buffer
+	for _, attribute := range attributes {
+		buf.WriteString(fmt.Sprintf("%s-", strings.ToUpper(connectionSideAttribute(side, attribute))))
+	}
+	return hashcode.String(buf.String())
+}
+
 func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	uuid, err := createFabricConnection(ctx, meta, d)
+	if uuid != "" {
+		d.SetId(uuid)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceFabricConnectionRead(ctx, d, meta)
+}
+
+// createFabricConnection orders a connection and waits for it to be created,
+// returning its UUID as soon as it is ordered
+func createFabricConnection(ctx context.Context, meta interface{}, d fabricConnectionConfig) (string, error) {
 	client := meta.(*config.Config).FabricClient
 	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	createRequest, err := fabricConnectionPostRequest(d)
+	if err != nil {
+		return "", err
+	}
+
+	conn, _, err := client.ConnectionsApi.CreateConnection(ctx, createRequest)
+	if err != nil {
+		return "", equinix_errors.FormatFabricError(err)
+	}
+
+	if err = waitUntilConnectionIsCreated(conn.Uuid, meta, ctx); err != nil {
+		return conn.Uuid, fmt.Errorf("error waiting for connection (%s) to be created: %s", conn.Uuid, err)
+	}
+
+	awsSecrets, hasAWSSecrets := additionalInfoContainsAWSSecrets(d.Get("additional_info").([]interface{}))
+	if hasAWSSecrets {
+		patchChangeOperation := []v4.ConnectionChangeOperation{
+			{
+				Op:    "add",
+				Path:  "",
+				Value: map[string]interface{}{"additionalInfo": awsSecrets},
+			},
+		}
+
+		_, _, patchErr := client.ConnectionsApi.UpdateConnectionByUuid(ctx, patchChangeOperation, conn.Uuid)
+		if patchErr != nil {
+			return conn.Uuid, equinix_errors.FormatFabricError(patchErr)
+		}
+
+		if _, statusChangeErr := waitForConnectionProviderStatusChange(conn.Uuid, meta, ctx); statusChangeErr != nil {
+			return conn.Uuid, fmt.Errorf("error waiting for AWS Approval for connection %s: %v", conn.Uuid, statusChangeErr)
+		}
+	}
+	return conn.Uuid, nil
+}
+
+func fabricConnectionPostRequest(d fabricConnectionConfig) (v4.ConnectionPostRequest, error) {
 	conType := v4.ConnectionType(d.Get("type").(string))
 	schemaNotifications := d.Get("notifications").([]interface{})
 	notifications := notificationToFabric(schemaNotifications)
@@ -68,7 +298,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 		if len(serviceTokenRequest) != 0 {
 			mappedServiceToken, err := serviceTokenToFabric(serviceTokenRequest)
 			if err != nil {
-				return diag.FromErr(err)
+				return v4.ConnectionPostRequest{}, err
 			}
 			connectionASide = v4.ConnectionSide{ServiceToken: &mappedServiceToken}
 		}
@@ -92,7 +322,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 		if len(serviceTokenRequest) != 0 {
 			mappedServiceToken, err := serviceTokenToFabric(serviceTokenRequest)
 			if err != nil {
-				return diag.FromErr(err)
+				return v4.ConnectionPostRequest{}, err
 			}
 			connectionZSide = v4.ConnectionSide{ServiceToken: &mappedServiceToken}
 		}
@@ -102,7 +332,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	createRequest := v4.ConnectionPostRequest{
+	return v4.ConnectionPostRequest{
 		Name:           d.Get("name").(string),
 		Type_:          &conType,
 		Order:          &order,
@@ -113,39 +343,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 		ASide:          &connectionASide,
 		ZSide:          &connectionZSide,
 		Project:        &project,
-	}
-
-	conn, _, err := client.ConnectionsApi.CreateConnection(ctx, createRequest)
-	if err != nil {
-		return diag.FromErr(equinix_errors.FormatFabricError(err))
-	}
-	d.SetId(conn.Uuid)
-
-	if err = waitUntilConnectionIsCreated(d.Id(), meta, ctx); err != nil {
-		return diag.Errorf("error waiting for connection (%s) to be created: %s", d.Id(), err)
-	}
-
-	awsSecrets, hasAWSSecrets := additionalInfoContainsAWSSecrets(additionalInfoTerraConfig)
-	if hasAWSSecrets {
-		patchChangeOperation := []v4.ConnectionChangeOperation{
-			{
-				Op:    "add",
-				Path:  "",
-				Value: map[string]interface{}{"additionalInfo": awsSecrets},
-			},
-		}
-
-		_, _, patchErr := client.ConnectionsApi.UpdateConnectionByUuid(ctx, patchChangeOperation, conn.Uuid)
-		if patchErr != nil {
-			return diag.FromErr(equinix_errors.FormatFabricError(patchErr))
-		}
-
-		if _, statusChangeErr := waitForConnectionProviderStatusChange(d.Id(), meta, ctx); statusChangeErr != nil {
-			return diag.Errorf("error waiting for AWS Approval for connection %s: %v", d.Id(), statusChangeErr)
-		}
-	}
-
-	return resourceFabricConnectionRead(ctx, d, meta)
+	}, nil
 }
 
 func additionalInfoContainsAWSSecrets(info []interface{}) ([]interface{}, bool) {
@@ -181,7 +379,16 @@ func resourceFabricConnectionRead(ctx context.Context, d *schema.ResourceData, m
 
 func setFabricMap(d *schema.ResourceData, conn v4.Connection) diag.Diagnostics {
 	diags := diag.Diagnostics{}
-	err := equinix_schema.SetMap(d, map[string]interface{}{
+	err := equinix_schema.SetMap(d, connectionMap(conn))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+// connectionMap maps a connection to the attributes of its schema
+func connectionMap(conn v4.Connection) map[string]interface{} {
+	return map[string]interface{}{
 		"name":      conn.Name,
 		"bandwidth": conn.Bandwidth,
 		"href":      conn.Href,
@@ -202,15 +409,10 @@ func setFabricMap(d *schema.ResourceData, conn v4.Connection) diag.Diagnostics {
 		"z_side":          connectionSideToTerra(conn.ZSide),
 		"additional_info": additionalInfoToTerra(conn.AdditionalInfo),
 		"project":         projectToTerra(conn.Project),
-	})
-	if err != nil {
-		return diag.FromErr(err)
 	}
-	return diags
 }
 
 func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*config.Config).FabricClient
 	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
 	dbConn, err := verifyConnectionCreated(d.Id(), meta, ctx)
 	if err != nil {
@@ -220,16 +422,26 @@ func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.Errorf("either timed out or errored out while fetching connection for uuid %s: error -> %v", d.Id(), err)
 	}
 
+	updatedConn, diags := updateFabricConnection(ctx, meta, dbConn, d)
+	d.SetId(updatedConn.Uuid)
+	return append(diags, setFabricMap(d, updatedConn)...)
+}
+
+// updateFabricConnection patches the changes of a connection one request at
+// a time, waiting for each of them to complete
+func updateFabricConnection(ctx context.Context, meta interface{}, dbConn v4.Connection, d fabricConnectionConfig) (v4.Connection, diag.Diagnostics) {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
 	diags := diag.Diagnostics{}
 	updateRequests, err := getUpdateRequests(dbConn, d)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{Severity: 1, Summary: err.Error()})
-		return diags
+		return dbConn, diags
 	}
 	updatedConn := dbConn
 
 	for _, update := range updateRequests {
-		_, _, err := client.ConnectionsApi.UpdateConnectionByUuid(ctx, update, d.Id())
+		_, _, err := client.ConnectionsApi.UpdateConnectionByUuid(ctx, update, dbConn.Uuid)
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{Severity: 0, Summary: fmt.Sprintf("connection property update request error: %v [update payload: %v] (other updates will be successful if the payload is not shown)", equinix_errors.FormatFabricError(err), update)})
 			continue
@@ -237,14 +449,14 @@ func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 
 		var waitFunction func(uuid string, meta interface{}, ctx context.Context) (v4.Connection, error)
 		if update[0].Op == "replace" {
-			// Update type is either name or bandwidth
+			// Update type is name, bandwidth, notifications, redundancy group or VLAN tags
 			waitFunction = waitForConnectionUpdateCompletion
 		} else if update[0].Op == "add" {
 			// Update type is aws secret additionalInfo
 			waitFunction = waitForConnectionProviderStatusChange
 		}
 
-		conn, err := waitFunction(d.Id(), meta, ctx)
+		conn, err := waitFunction(dbConn.Uuid, meta, ctx)
 
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{Severity: 0, Summary: fmt.Sprintf("connection property update completion timeout error: %v [update payload: %v] (other updates will be successful if the payload is not shown)", err, update)})
@@ -252,11 +464,13 @@ func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 			updatedConn = conn
 		}
 	}
-
-	d.SetId(updatedConn.Uuid)
-	return append(diags, setFabricMap(d, updatedConn)...)
+	return updatedConn, diags
 }
 
+// fabricConnectionPollInterval is how often connections are polled while
+// they are created, updated and deleted
+var fabricConnectionPollInterval = 30 * time.Second
+
 func waitForConnectionUpdateCompletion(uuid string, meta interface{}, ctx context.Context) (v4.Connection, error) {
 	log.Printf("[DEBUG] Waiting for connection update to complete, uuid %s", uuid)
 	stateConf := &retry.StateChangeConf{
@@ -274,8 +488,8 @@ func waitForConnectionUpdateCompletion(uuid string, meta interface{}, ctx contex
 			return dbConn, updatableState, nil
 		},
 		Timeout:    3 * time.Minute,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+		Delay:      fabricConnectionPollInterval,
+		MinTimeout: fabricConnectionPollInterval,
 	}
 
 	inter, err := stateConf.WaitForStateContext(ctx)
@@ -307,8 +521,8 @@ func waitUntilConnectionIsCreated(uuid string, meta interface{}, ctx context.Con
 			return dbConn, string(*dbConn.State), nil
 		},
 		Timeout:    5 * time.Minute,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+		Delay:      fabricConnectionPollInterval,
+		MinTimeout: fabricConnectionPollInterval,
 	}
 
 	_, err := stateConf.WaitForStateContext(ctx)
@@ -335,8 +549,8 @@ func waitForConnectionProviderStatusChange(uuid string, meta interface{}, ctx co
 			return dbConn, string(*dbConn.Operation.ProviderStatus), nil
 		},
 		Timeout:    5 * time.Minute,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+		Delay:      fabricConnectionPollInterval,
+		MinTimeout: fabricConnectionPollInterval,
 	}
 
 	inter, err := stateConf.WaitForStateContext(ctx)
@@ -365,8 +579,8 @@ func verifyConnectionCreated(uuid string, meta interface{}, ctx context.Context)
 			return dbConn, string(*dbConn.State), nil
 		},
 		Timeout:    5 * time.Minute,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+		Delay:      fabricConnectionPollInterval,
+		MinTimeout: fabricConnectionPollInterval,
 	}
 
 	inter, err := stateConf.WaitForStateContext(ctx)
@@ -379,26 +593,34 @@ func verifyConnectionCreated(uuid string, meta interface{}, ctx context.Context)
 }
 
 func resourceFabricConnectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	diags := diag.Diagnostics{}
+	if err := deleteFabricConnection(ctx, meta, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.Diagnostics{}
+}
+
+// deleteFabricConnection deletes a connection and waits for it to be
+// deprovisioned. A connection already deleted is not an error
+func deleteFabricConnection(ctx context.Context, meta interface{}, uuid string) error {
 	client := meta.(*config.Config).FabricClient
 	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
-	_, _, err := client.ConnectionsApi.DeleteConnectionByUuid(ctx, d.Id())
+	_, _, err := client.ConnectionsApi.DeleteConnectionByUuid(ctx, uuid)
 	if err != nil {
 		errors, ok := err.(v4.GenericSwaggerError).Model().([]v4.ModelError)
 		if ok {
 			// EQ-3142509 = Connection already deleted
 			if equinix_errors.HasModelErrorCode(errors, "EQ-3142509") {
-				return diags
+				return nil
 			}
 		}
-		return diag.FromErr(equinix_errors.FormatFabricError(err))
+		return equinix_errors.FormatFabricError(err)
 	}
 
-	err = waitUntilConnectionDeprovisioned(d.Id(), meta, ctx)
+	err = waitUntilConnectionDeprovisioned(uuid, meta, ctx)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("API call failed while waiting for resource deletion. Error %v", err))
+		return fmt.Errorf("API call failed while waiting for resource deletion. Error %v", err)
 	}
-	return diags
+	return nil
 }
 
 func waitUntilConnectionDeprovisioned(uuid string, meta interface{}, ctx context.Context) error {
@@ -419,8 +641,8 @@ func waitUntilConnectionDeprovisioned(uuid string, meta interface{}, ctx context
 			return dbConn, string(*dbConn.State), nil
 		},
 		Timeout:    6 * time.Minute,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+		Delay:      fabricConnectionPollInterval,
+		MinTimeout: fabricConnectionPollInterval,
 	}
 
 	_, err := stateConf.WaitForStateContext(ctx)
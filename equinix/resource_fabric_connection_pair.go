@@ -0,0 +1,371 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// connectionPairLegs are the connections of a pair, in the order they are
+// created and updated
+var connectionPairLegs = []string{"primary", "secondary"}
+
+func createFabricConnectionPairLegSch() map[string]*schema.Schema {
+	sch := createFabricConnectionResourceSchema()
+	// The pair assigns the redundancy of its connections
+	delete(sch, "redundancy")
+	return sch
+}
+
+func createFabricConnectionPairSch() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"primary": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Primary connection of the pair, created first. Changing an attribute Fabric can not update replaces the primary connection within the pair",
+			Elem: &schema.Resource{
+				Schema: createFabricConnectionPairLegSch(),
+			},
+		},
+		"secondary": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MaxItems:    1,
+			Description: "Secondary connection of the pair, created in the redundancy group of the primary connection. Changing an attribute Fabric can not update replaces the secondary connection only",
+			Elem: &schema.Resource{
+				Schema: createFabricConnectionPairLegSch(),
+			},
+		},
+		"redundancy_group": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Redundancy group identifier shared by the primary and secondary connections",
+		},
+		"primary_uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned identifier of the primary connection",
+		},
+		"secondary_uuid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Equinix-assigned identifier of the secondary connection",
+		},
+	}
+}
+
+func resourceFabricConnectionPair() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(12 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(12 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext:   resourceFabricConnectionPairRead,
+		CreateContext: resourceFabricConnectionPairCreate,
+		UpdateContext: resourceFabricConnectionPairUpdate,
+		DeleteContext: resourceFabricConnectionPairDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFabricConnectionPairImport,
+		},
+		Schema:        createFabricConnectionPairSch(),
+		CustomizeDiff: resourceFabricConnectionPairCustomizeDiff,
+
+		Description: "Fabric V4 API compatible resource allows creation and management of a redundant pair of Equinix Fabric connections sharing a redundancy group\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+// connectionPairLeg is the configuration of the primary or secondary
+// connection of a pair, with the redundancy the pair assigns to it
+type connectionPairLeg struct {
+	d          fabricConnectionConfig
+	prefix     string
+	redundancy map[string]interface{}
+}
+
+func newConnectionPairLeg(d fabricConnectionConfig, leg, group string) connectionPairLeg {
+	return connectionPairLeg{
+		d:      d,
+		prefix: leg + ".0.",
+		redundancy: map[string]interface{}{
+			"priority": strings.ToUpper(leg),
+			"group":    group,
+		},
+	}
+}
+
+func (l connectionPairLeg) Get(key string) interface{} {
+	if key == "redundancy" {
+		return schema.NewSet(schema.HashResource(createRedundancyRes), []interface{}{l.redundancy})
+	}
+	return l.d.Get(l.prefix + key)
+}
+
+func (l connectionPairLeg) GetChange(key string) (interface{}, interface{}) {
+	if key == "redundancy" {
+		return l.Get(key), l.Get(key)
+	}
+	return l.d.GetChange(l.prefix + key)
+}
+
+func (l connectionPairLeg) HasChange(key string) bool {
+	if key == "redundancy" {
+		return false
+	}
+	return l.d.HasChange(l.prefix + key)
+}
+
+// connectionPairId joins the UUIDs of the connections of a pair, as the
+// import ID of a redundant equinix_ecx_l2_connection
+func connectionPairId(primaryUuid, secondaryUuid string) string {
+	if secondaryUuid == "" {
+		return primaryUuid
+	}
+	return primaryUuid + ":" + secondaryUuid
+}
+
+func parseConnectionPairId(id string) (string, string) {
+	ids := strings.SplitN(id, ":", 2)
+	if len(ids) < 2 {
+		return ids[0], ""
+	}
+	return ids[0], ids[1]
+}
+
+// connectionPairUuids returns the UUIDs of the connections of the pair. A
+// pair is identified by its redundancy group, which does not change when its
+// connections are replaced. Pairs imported, or created before, are identified
+// by the UUIDs of their connections until they are read
+func connectionPairUuids(d *schema.ResourceData) (string, string) {
+	primaryUuid, secondaryUuid := d.Get("primary_uuid").(string), d.Get("secondary_uuid").(string)
+	if primaryUuid == "" && strings.Contains(d.Id(), ":") {
+		return parseConnectionPairId(d.Id())
+	}
+	return primaryUuid, secondaryUuid
+}
+
+func resourceFabricConnectionPairImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	primaryUuid, secondaryUuid := parseConnectionPairId(d.Id())
+	if primaryUuid == "" || secondaryUuid == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected '(primaryID):(secondaryID)'", d.Id())
+	}
+	if err := equinix_schema.SetMap(d, map[string]interface{}{
+		"primary_uuid":   primaryUuid,
+		"secondary_uuid": secondaryUuid,
+	}); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceFabricConnectionPairCustomizeDiff validates the connections of the
+// pair like resourceFabricConnectionCustomizeDiff. A connection changing an
+// attribute Fabric can not patch is replaced within the pair on update, which
+// the plan shows as a new primary_uuid or secondary_uuid
+func resourceFabricConnectionPairCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, leg := range connectionPairLegs {
+		if err := validateCspProfileRules(ctx, d, meta, leg+".0."); err != nil {
+			return err
+		}
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	for _, leg := range connectionPairLegs {
+		replaced := connectionReplacedAttributes(newConnectionPairLeg(d, leg, ""))
+		if len(replaced) == 0 && d.Get(leg+"_uuid").(string) != "" {
+			continue
+		}
+		if len(replaced) > 0 {
+			log.Printf("[WARN] The %s connection of the pair %s will be deleted and created again in its redundancy group, Fabric can not update %s", leg, d.Id(), strings.Join(replaced, ", "))
+		}
+		if err := d.SetNewComputed(leg + "_uuid"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceFabricConnectionPairCreate creates the primary connection, then the
+// secondary connection in its redundancy group, which identifies the pair.
+// Until the group is known, the pair is identified by its primary connection
+func resourceFabricConnectionPairCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	primaryUuid, err := createFabricConnection(ctx, meta, newConnectionPairLeg(d, "primary", ""))
+	if primaryUuid != "" {
+		d.SetId(primaryUuid)
+		d.Set("primary_uuid", primaryUuid)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	group, err := fabricConnectionRedundancyGroup(ctx, meta, primaryUuid)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(group)
+	d.Set("redundancy_group", group)
+	secondaryUuid, err := createFabricConnection(ctx, meta, newConnectionPairLeg(d, "secondary", group))
+	d.Set("secondary_uuid", secondaryUuid)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceFabricConnectionPairRead(ctx, d, meta)
+}
+
+func fabricConnectionRedundancyGroup(ctx context.Context, meta interface{}, uuid string) (string, error) {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	conn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid, nil)
+	if err != nil {
+		return "", equinix_errors.FormatFabricError(err)
+	}
+	if conn.Redundancy == nil || conn.Redundancy.Group == "" {
+		return "", fmt.Errorf("connection %s has no redundancy group", uuid)
+	}
+	return conn.Redundancy.Group, nil
+}
+
+func resourceFabricConnectionPairRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	primaryUuid, secondaryUuid := connectionPairUuids(d)
+	pair := map[string]interface{}{}
+	for leg, uuid := range map[string]string{"primary": primaryUuid, "secondary": secondaryUuid} {
+		if uuid == "" {
+			// The secondary connection of a pair that failed to be created
+			continue
+		}
+		conn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid, nil)
+		if err != nil {
+			log.Printf("[WARN] Connection %s not found , error %s", uuid, err)
+			if !strings.Contains(err.Error(), "500") {
+				d.SetId("")
+			}
+			return diag.FromErr(equinix_errors.FormatFabricError(err))
+		}
+		legMap := connectionMap(conn)
+		delete(legMap, "redundancy")
+		pair[leg] = []interface{}{legMap}
+		pair[leg+"_uuid"] = conn.Uuid
+		if leg == "primary" && conn.Redundancy != nil {
+			pair["redundancy_group"] = conn.Redundancy.Group
+		}
+	}
+	if err := equinix_schema.SetMap(d, pair); err != nil {
+		return diag.FromErr(err)
+	}
+	if group, ok := pair["redundancy_group"].(string); ok && group != "" {
+		d.SetId(group)
+	}
+	return nil
+}
+
+// resourceFabricConnectionPairUpdate updates one connection at a time, so
+// that the other one carries the traffic. The secondary connection is only
+// updated once the update of the primary one completed. A connection planned
+// to be replaced is created again in the redundancy group first, and the
+// connection it replaces is deleted once the new one is created
+func resourceFabricConnectionPairUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	uuids := map[string]string{}
+	for _, leg := range connectionPairLegs {
+		// The UUID of a connection planned to be replaced is unknown
+		old, _ := d.GetChange(leg + "_uuid")
+		uuids[leg] = old.(string)
+	}
+	group := d.Get("redundancy_group").(string)
+	diags := diag.Diagnostics{}
+
+	for _, leg := range connectionPairLegs {
+		if !d.HasChange(leg) && uuids[leg] != "" {
+			continue
+		}
+		legConfig := newConnectionPairLeg(d, leg, group)
+		if uuids[leg] != "" && !d.HasChange(leg+"_uuid") {
+			legDiags := updateFabricConnectionPairLeg(ctx, meta, uuids[leg], legConfig)
+			diags = append(diags, legDiags...)
+			if legDiags.HasError() {
+				return diags
+			}
+			continue
+		}
+
+		legDiags := replaceFabricConnectionPairLeg(ctx, d, meta, leg, uuids[leg], legConfig)
+		diags = append(diags, legDiags...)
+		if legDiags.HasError() {
+			return diags
+		}
+	}
+	return append(diags, resourceFabricConnectionPairRead(ctx, d, meta)...)
+}
+
+// replaceFabricConnectionPairLeg creates the connection of a leg of the pair,
+// then deletes the connection it replaces, if any. The replaced connection is
+// kept when the new one fails to be created, and a connection missing from
+// the pair is created again by the next apply
+func replaceFabricConnectionPairLeg(ctx context.Context, d *schema.ResourceData, meta interface{}, leg, oldUuid string, legConfig connectionPairLeg) diag.Diagnostics {
+	group := d.Get("redundancy_group").(string)
+	uuid, err := createFabricConnection(ctx, meta, legConfig)
+	if err != nil {
+		if oldUuid == "" {
+			d.Set(leg+"_uuid", uuid)
+			return diag.FromErr(err)
+		}
+		d.Set(leg+"_uuid", oldUuid)
+		if uuid != "" {
+			err = fmt.Errorf("%w. The replacement %s connection %s was ordered but not created, the connection %s it replaces is kept", err, leg, uuid, oldUuid)
+		}
+		return diag.FromErr(err)
+	}
+	d.Set(leg+"_uuid", uuid)
+	if oldUuid == "" {
+		return nil
+	}
+
+	if err := deleteFabricConnection(ctx, meta, oldUuid); err != nil {
+		return diag.Errorf("the %s connection of the pair was created again as %s, but the connection %s it replaces could not be deleted: %s", leg, uuid, oldUuid, err)
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The %s connection of the pair was replaced", leg),
+		Detail:   fmt.Sprintf("Fabric can not update the %s connection in place. It was created again as %s in redundancy group %s, and the connection %s it replaces was deleted.", leg, uuid, group, oldUuid),
+	}}
+}
+
+func updateFabricConnectionPairLeg(ctx context.Context, meta interface{}, uuid string, leg connectionPairLeg) diag.Diagnostics {
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	dbConn, err := verifyConnectionCreated(uuid, meta, ctx)
+	if err != nil {
+		return diag.Errorf("either timed out or errored out while fetching connection for uuid %s: error -> %v", uuid, err)
+	}
+	_, diags := updateFabricConnection(ctx, meta, dbConn, leg)
+	return diags
+}
+
+// resourceFabricConnectionPairDelete deletes the secondary connection before
+// the primary one
+func resourceFabricConnectionPairDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	primaryUuid, secondaryUuid := connectionPairUuids(d)
+	for _, uuid := range []string{secondaryUuid, primaryUuid} {
+		if uuid == "" {
+			continue
+		}
+		if err := deleteFabricConnection(ctx, meta, uuid); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return diag.Diagnostics{}
+}
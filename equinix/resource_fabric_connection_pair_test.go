@@ -0,0 +1,290 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSecondaryConnectionUuid = "0b3e8a53-6f1c-4e2d-9a7b-8c9d0e1f2a3b"
+	testRedundancyGroup         = "7c8d0f3e-1a2b-4c5d-8e9f-0a1b2c3d4e5f"
+)
+
+const recordedSecondaryConnection = `{
+  "uuid": "` + testSecondaryConnectionUuid + `",
+  "name": "tf-aws-sec",
+  "type": "EVPL_VC",
+  "bandwidth": 200,
+  "state": "ACTIVE",
+  "redundancy": {"group": "` + testRedundancyGroup + `", "priority": "SECONDARY"},
+  "aSide": {"accessPoint": {
+    "type": "COLO",
+    "port": {"uuid": "` + testConnectionPortZ + `"},
+    "linkProtocol": {"type": "QINQ", "vlanSTag": 778, "vlanCTag": 1000}
+  }},
+  "zSide": {"accessPoint": {
+    "type": "SP",
+    "authenticationKey": "357848976964",
+    "sellerRegion": "us-west-1",
+    "profile": {"type": "L2_PROFILE", "uuid": "` + testAwsProfileUuid + `"},
+    "location": {"metroCode": "SV"}
+  }}
+}`
+
+func testConnectionPairLegConfig(name, port string, vlanSTag int) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"name":      name,
+		"type":      "EVPL_VC",
+		"bandwidth": 200,
+		"a_side": []interface{}{map[string]interface{}{
+			"access_point": []interface{}{map[string]interface{}{
+				"type": "COLO",
+				"port": []interface{}{map[string]interface{}{"uuid": port}},
+				"link_protocol": []interface{}{map[string]interface{}{
+					"type":       "QINQ",
+					"vlan_s_tag": vlanSTag,
+					"vlan_c_tag": 1000,
+				}},
+			}},
+		}},
+		"z_side": []interface{}{map[string]interface{}{
+			"access_point": []interface{}{map[string]interface{}{
+				"type":               "SP",
+				"authentication_key": "357848976964",
+				"seller_region":      "us-west-1",
+				"profile":            []interface{}{map[string]interface{}{"type": "L2_PROFILE", "uuid": testAwsProfileUuid}},
+				"location":           []interface{}{map[string]interface{}{"metro_code": "SV"}},
+			}},
+		}},
+	}}
+}
+
+func testConnectionPairConfig(primaryPort, secondaryPort string) map[string]interface{} {
+	return map[string]interface{}{
+		"primary":   testConnectionPairLegConfig("tf-aws", primaryPort, 777),
+		"secondary": testConnectionPairLegConfig("tf-aws-sec", secondaryPort, 778),
+	}
+}
+
+func testConnectionPairRead(t *testing.T) (*schema.ResourceData, interface{}) {
+	t.Helper()
+	_, d, meta := testConnectionPairReadFake(t, map[string]string{})
+	return d, meta
+}
+
+// testConnectionPairReadFake reads the recorded pair, identified by the UUIDs
+// of its connections, with a fake API serving the recorded connections first
+// and then the responses
+func testConnectionPairReadFake(t *testing.T, responses map[string]string) (*fakeAPI, *schema.ResourceData, interface{}) {
+	t.Helper()
+	fake, meta := newFabricFakeAPI(t, responses)
+	for uuid, conn := range map[string]string{testConnectionUuid: recordedEcxConnection, testSecondaryConnectionUuid: recordedSecondaryConnection} {
+		key := "GET /fabric/v4/connections/" + uuid
+		if _, ok := responses[key]; ok {
+			fake.queued[key] = []string{conn}
+		} else {
+			responses[key] = conn
+		}
+	}
+	d := resourceFabricConnectionPair().TestResourceData()
+	d.SetId(connectionPairId(testConnectionUuid, testSecondaryConnectionUuid))
+	if diags := resourceFabricConnectionPairRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	return fake, d, meta
+}
+
+func TestFabricConnectionPairLegPostRequest(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricConnectionPairSch(), testConnectionPairConfig(testConnectionPortA, testConnectionPortZ))
+
+	request, err := fabricConnectionPostRequest(newConnectionPairLeg(d, "secondary", testRedundancyGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "tf-aws-sec", request.Name)
+	assert.Equal(t, int32(200), request.Bandwidth)
+	if assert.NotNil(t, request.Redundancy) {
+		assert.Equal(t, v4.SECONDARY_ConnectionPriority, *request.Redundancy.Priority)
+		assert.Equal(t, testRedundancyGroup, request.Redundancy.Group)
+	}
+	if assert.NotNil(t, request.ASide.AccessPoint) {
+		assert.Equal(t, testConnectionPortZ, request.ASide.AccessPoint.Port.Uuid)
+		assert.Equal(t, int32(778), request.ASide.AccessPoint.LinkProtocol.VlanSTag)
+	}
+
+	request, err = fabricConnectionPostRequest(newConnectionPairLeg(d, "primary", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, "tf-aws", request.Name)
+	assert.Equal(t, v4.PRIMARY_ConnectionPriority, *request.Redundancy.Priority)
+	assert.Empty(t, request.Redundancy.Group, "Fabric assigns the group of the primary connection")
+}
+
+func TestFabricConnectionPairRead(t *testing.T) {
+	d, _ := testConnectionPairRead(t)
+
+	assert.Equal(t, testRedundancyGroup, d.Id(), "the pair is identified by its redundancy group")
+	assert.Equal(t, testRedundancyGroup, d.Get("redundancy_group"))
+	assert.Equal(t, testConnectionUuid, d.Get("primary_uuid"))
+	assert.Equal(t, "tf-aws", d.Get("primary.0.name"))
+	assert.Equal(t, testSecondaryConnectionUuid, d.Get("secondary_uuid"))
+	assert.Equal(t, "tf-aws-sec", d.Get("secondary.0.name"))
+	secondarySide := firstSetElem(d.Get("secondary.0.a_side"))
+	assert.Equal(t, testConnectionPortZ, connectionSideAttribute(secondarySide, "access_point.port.uuid"))
+	assert.Equal(t, "778", connectionSideAttribute(secondarySide, "access_point.link_protocol.vlan_s_tag"))
+}
+
+func TestFabricConnectionPairCustomizeDiff(t *testing.T) {
+	d, meta := testConnectionPairRead(t)
+	diff := func(config map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+		diff, err := resourceFabricConnectionPair().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(config), meta)
+		assert.NoError(t, err)
+		return diff
+	}
+
+	replacedLegs := func(pairDiff *terraform.InstanceDiff) []string {
+		t.Helper()
+		data, err := schema.InternalMap(createFabricConnectionPairSch()).Data(d.State(), pairDiff)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var legs []string
+		for _, leg := range connectionPairLegs {
+			if data.HasChange(leg + "_uuid") {
+				legs = append(legs, leg)
+			}
+		}
+		return legs
+	}
+
+	config := testConnectionPairConfig(testConnectionPortA, testConnectionPortZ)
+	config["secondary"].([]interface{})[0].(map[string]interface{})["bandwidth"] = 500
+	if pairDiff := diff(config); assert.NotNil(t, pairDiff) {
+		assert.False(t, pairDiff.RequiresNew(), "the bandwidth is updated in place")
+		assert.Empty(t, replacedLegs(pairDiff))
+	}
+
+	if pairDiff := diff(testConnectionPairConfig(testConnectionPortA, testConnectionPortA)); assert.NotNil(t, pairDiff) {
+		assert.False(t, pairDiff.RequiresNew(), "moving the secondary connection replaces it within the pair")
+		assert.True(t, pairDiff.Attributes["secondary_uuid"].NewComputed)
+		assert.Equal(t, []string{"secondary"}, replacedLegs(pairDiff))
+	}
+
+	if pairDiff := diff(testConnectionPairConfig(testConnectionPortZ, testConnectionPortZ)); assert.NotNil(t, pairDiff) {
+		assert.False(t, pairDiff.RequiresNew(), "moving the primary connection replaces it within the pair")
+		assert.True(t, pairDiff.Attributes["primary_uuid"].NewComputed)
+		assert.Equal(t, []string{"primary"}, replacedLegs(pairDiff))
+	}
+}
+
+func TestFabricConnectionPairImport(t *testing.T) {
+	d := resourceFabricConnectionPair().TestResourceData()
+	d.SetId(testConnectionUuid)
+	_, err := resourceFabricConnectionPairImport(context.Background(), d, nil)
+	assert.ErrorContains(t, err, "expected '(primaryID):(secondaryID)'")
+
+	d.SetId(connectionPairId(testConnectionUuid, testSecondaryConnectionUuid))
+	imported, err := resourceFabricConnectionPairImport(context.Background(), d, nil)
+	assert.NoError(t, err)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, testConnectionUuid, d.Get("primary_uuid"))
+	assert.Equal(t, testSecondaryConnectionUuid, d.Get("secondary_uuid"))
+}
+
+const testReplacementConnectionUuid = "5a6b7c8d-9e0f-4a1b-8c2d-3e4f5a6b7c8d"
+
+// testConnectionPairReplaceSecondary returns the data to update the recorded
+// pair, moving its secondary connection to the port of the primary one
+func testConnectionPairReplaceSecondary(t *testing.T, responses map[string]string) (*fakeAPI, *schema.ResourceData, interface{}) {
+	t.Helper()
+	fake, state, meta := testConnectionPairReadFake(t, responses)
+	config := testConnectionPairConfig(testConnectionPortA, testConnectionPortA)
+	for _, leg := range connectionPairLegs {
+		// the recorded notifications and order are not in the configuration
+		legConfig := config[leg].([]interface{})[0].(map[string]interface{})
+		legConfig["notifications"] = state.Get(leg + ".0.notifications")
+		legConfig["order"] = state.Get(leg + ".0.order").(*schema.Set).List()
+	}
+	diff, err := resourceFabricConnectionPair().Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, err := schema.InternalMap(createFabricConnectionPairSch()).Data(state.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fake, d, meta
+}
+
+func TestFabricConnectionPairUpdateReplacesLeg(t *testing.T) {
+	fastFabricConnectionPolling(t)
+	replacement := strings.NewReplacer(testSecondaryConnectionUuid, testReplacementConnectionUuid, testConnectionPortZ, testConnectionPortA).Replace(recordedSecondaryConnection)
+	deleteSecondary := "DELETE /fabric/v4/connections/" + testSecondaryConnectionUuid
+	fake, d, meta := testConnectionPairReplaceSecondary(t, map[string]string{
+		"POST /fabric/v4/connections":                                 replacement,
+		"GET /fabric/v4/connections/" + testReplacementConnectionUuid: replacement,
+		deleteSecondary: strings.Replace(recordedSecondaryConnection, `"ACTIVE"`, `"DEPROVISIONING"`, 1),
+		"GET /fabric/v4/connections/" + testSecondaryConnectionUuid: strings.Replace(recordedSecondaryConnection, `"ACTIVE"`, `"DEPROVISIONED"`, 1),
+	})
+
+	diags := resourceFabricConnectionPairUpdate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Contains(t, diags[0].Detail, testReplacementConnectionUuid)
+	}
+
+	calls := fake.requestOrder()
+	create, remove := indexOf(calls, "POST /fabric/v4/connections"), indexOf(calls, deleteSecondary)
+	if create < 0 || remove < 0 {
+		t.Fatalf("the secondary connection was not replaced: %v", calls)
+	}
+	assert.Less(t, create, remove, "the replacement is created before the connection it replaces is deleted")
+
+	body, _ := fake.request("POST /fabric/v4/connections")
+	request := v4.ConnectionPostRequest{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid create request %q: %v", body, err)
+	}
+	if assert.NotNil(t, request.Redundancy) {
+		assert.Equal(t, v4.SECONDARY_ConnectionPriority, *request.Redundancy.Priority)
+		assert.Equal(t, testRedundancyGroup, request.Redundancy.Group)
+	}
+
+	assert.Equal(t, testRedundancyGroup, d.Id(), "the ID of the pair does not change")
+	assert.Equal(t, testReplacementConnectionUuid, d.Get("secondary_uuid"))
+	assert.Equal(t, testConnectionUuid, d.Get("primary_uuid"))
+	assert.NotContains(t, calls, "DELETE /fabric/v4/connections/"+testConnectionUuid)
+}
+
+func TestFabricConnectionPairUpdateKeepsLegWhenReplacementFails(t *testing.T) {
+	fastFabricConnectionPolling(t)
+	fake, d, meta := testConnectionPairReplaceSecondary(t, map[string]string{
+		"POST /fabric/v4/connections": `[{"errorCode":"EQ-3142102","errorMessage":"Connection already exists"}]`,
+	})
+	fake.statuses["POST /fabric/v4/connections"] = http.StatusBadRequest
+
+	diags := resourceFabricConnectionPairUpdate(context.Background(), d, meta)
+	assert.True(t, diags.HasError())
+	assert.NotContains(t, fake.requestOrder(), "DELETE /fabric/v4/connections/"+testSecondaryConnectionUuid)
+	assert.Equal(t, testSecondaryConnectionUuid, d.Get("secondary_uuid"), "the connection the replacement failed for is kept")
+	assert.Equal(t, testRedundancyGroup, d.Id())
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
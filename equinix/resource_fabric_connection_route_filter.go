@@ -0,0 +1,143 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFabricConnectionRouteFilter() *schema.Resource {
+	r := resourceFabricConnectionRoutePolicy(fabricRouteFilters)
+	r.Description = "Fabric V4 API compatible resource allows attachment of Equinix Fabric Cloud Router route filters to connections in inbound or outbound direction\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
+
+// resourceFabricConnectionRoutePolicy returns the resource of a route filter
+// or route aggregation attached to a connection. Its id is
+// <connection-uuid>/<policy-uuid>.
+func resourceFabricConnectionRoutePolicy(kind fabricRoutePolicyKind) *schema.Resource {
+	r := &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Update: schema.DefaultTimeout(6 * time.Minute),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricConnectionRoutePolicyRead(ctx, d, meta, kind)
+		},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricConnectionRoutePolicyAttach(ctx, d, meta, kind, d.Timeout(schema.TimeoutCreate))
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricConnectionRoutePolicyDelete(ctx, d, meta, kind)
+		},
+		Importer: &schema.ResourceImporter{
+			// Custom state context function, to parse import argument as <connection-uuid>/<policy-uuid>
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				connectionUuid, policyUuid, ok := strings.Cut(d.Id(), "/")
+				if !ok || connectionUuid == "" || policyUuid == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%s), expected <connection_id>/<%s>", d.Id(), kind.idAttribute)
+				}
+				_ = d.Set("connection_id", connectionUuid)
+				_ = d.Set(kind.idAttribute, policyUuid)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: createFabricRoutePolicyAttachmentResourceSchema(kind),
+	}
+	if kind.isFilter {
+		// the direction of an attached route filter is changed by attaching it again
+		r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricConnectionRoutePolicyAttach(ctx, d, meta, kind, d.Timeout(schema.TimeoutUpdate))
+		}
+	}
+	return r
+}
+
+func connectionRoutePolicyPath(d *schema.ResourceData, kind fabricRoutePolicyKind) string {
+	return kind.attachmentPath(d.Get("connection_id").(string), d.Get(kind.idAttribute).(string))
+}
+
+func waitUntilRoutePolicyIsAttached(ctx context.Context, meta interface{}, path string, timeout time.Duration) error {
+	return waitForFabricRouteState(ctx, meta, path,
+		[]string{"ATTACHING"},
+		[]string{"ATTACHED", "PENDING_BGP_CONFIGURATION"},
+		timeout)
+}
+
+func waitUntilRoutePolicyIsDetached(ctx context.Context, meta interface{}, path string, timeout time.Duration) error {
+	return waitForFabricRouteState(ctx, meta, path,
+		[]string{"ATTACHED", "DETACHING", "PENDING_BGP_CONFIGURATION"},
+		[]string{"DETACHED", strconv.Itoa(http.StatusNotFound)},
+		timeout)
+}
+
+func resourceFabricConnectionRoutePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	attachment := fabricRoutePolicyAttachment{}
+	if err := fabricRequest(ctx, meta, http.MethodGet, connectionRoutePolicyPath(d, kind), nil, &attachment); err != nil {
+		if isFabricNotFound(err) {
+			log.Printf("[WARN] Fabric %s %s is not attached, removing from state", kind.name, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if attachment.AttachmentStatus == "DETACHED" {
+		log.Printf("[WARN] Fabric %s %s is detached, removing from state", kind.name, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	m := map[string]interface{}{
+		"href":              attachment.Href,
+		"type":              attachment.Type,
+		"attachment_status": attachment.AttachmentStatus,
+	}
+	if kind.isFilter {
+		m["direction"] = attachment.Direction
+	}
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceFabricConnectionRoutePolicyAttach(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind, timeout time.Duration) diag.Diagnostics {
+	var attachRequest interface{}
+	if kind.isFilter {
+		attachRequest = fabricRoutePolicyAttachment{Direction: d.Get("direction").(string)}
+	}
+	if err := fabricRequest(ctx, meta, http.MethodPut, connectionRoutePolicyPath(d, kind), attachRequest, nil); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("connection_id").(string), d.Get(kind.idAttribute).(string)))
+
+	if err := waitUntilRoutePolicyIsAttached(ctx, meta, connectionRoutePolicyPath(d, kind), timeout); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be attached: %s", kind.name, d.Id(), err)
+	}
+	return resourceFabricConnectionRoutePolicyRead(ctx, d, meta, kind)
+}
+
+func resourceFabricConnectionRoutePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	if err := fabricRequest(ctx, meta, http.MethodDelete, connectionRoutePolicyPath(d, kind), nil, nil); err != nil {
+		if isFabricNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := waitUntilRoutePolicyIsDetached(ctx, meta, connectionRoutePolicyPath(d, kind), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be detached: %s", kind.name, d.Id(), err)
+	}
+	return nil
+}
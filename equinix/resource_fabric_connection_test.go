@@ -0,0 +1,269 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testConnectionUuid  = "f8b3a6a4-2d3e-4d6b-9a1c-5e7f8a9b0c1d"
+	testConnectionPortA = "c4d9350e-77c5-7c5d-1ce0-306a5c00a600"
+	testConnectionPortZ = "c4d9350e-783c-83cd-1ce0-306a5c00a600"
+)
+
+func fastFabricConnectionPolling(t *testing.T) {
+	interval := fabricConnectionPollInterval
+	fabricConnectionPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { fabricConnectionPollInterval = interval })
+}
+
+func testConnectionSide(port string, vlanTag int) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"access_point": []interface{}{map[string]interface{}{
+			"type": "COLO",
+			"port": []interface{}{map[string]interface{}{"uuid": port}},
+			"link_protocol": []interface{}{map[string]interface{}{
+				"type":     "DOT1Q",
+				"vlan_tag": vlanTag,
+			}},
+		}},
+	}}
+}
+
+func testConnectionConfig(aSidePort string, aSideVlan, zSideVlan int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      "port2port",
+		"type":      "EVPL_VC",
+		"bandwidth": 50,
+		"notifications": []interface{}{map[string]interface{}{
+			"type":   "ALL",
+			"emails": []interface{}{"test@equinix.com"},
+		}},
+		"a_side": testConnectionSide(aSidePort, aSideVlan),
+		"z_side": testConnectionSide(testConnectionPortZ, zSideVlan),
+	}
+}
+
+func testConnectionDiff(t *testing.T, config map[string]interface{}) *terraform.InstanceDiff {
+	t.Helper()
+	state := map[string]string{
+		"id":                       testConnectionUuid,
+		"name":                     "port2port",
+		"type":                     "EVPL_VC",
+		"bandwidth":                "50",
+		"notifications.#":          "1",
+		"notifications.0.type":     "ALL",
+		"notifications.0.emails.#": "1",
+		"notifications.0.emails.0": "test@equinix.com",
+	}
+	for side, port := range map[string]string{"a_side": testConnectionPortA, "z_side": testConnectionPortZ} {
+		state[side+".#"] = "1"
+		state[side+".1.access_point.#"] = "1"
+		state[side+".1.access_point.1.type"] = "COLO"
+		state[side+".1.access_point.1.port.#"] = "1"
+		state[side+".1.access_point.1.port.1.uuid"] = port
+		state[side+".1.access_point.1.link_protocol.#"] = "1"
+		state[side+".1.access_point.1.link_protocol.1.type"] = "DOT1Q"
+		state[side+".1.access_point.1.link_protocol.1.vlan_tag"] = "100"
+	}
+	diff, err := resourceFabricConnection().Diff(context.Background(), &terraform.InstanceState{ID: testConnectionUuid, Attributes: state}, terraform.NewResourceConfigRaw(config), nil)
+	assert.NoError(t, err)
+	return diff
+}
+
+func TestFabricConnectionCustomizeDiff(t *testing.T) {
+	diff := testConnectionDiff(t, testConnectionConfig(testConnectionPortA, 200, 100))
+	if assert.NotNil(t, diff) {
+		assert.False(t, diff.RequiresNew(), "retagging the VLAN keeps the connection")
+	}
+
+	diff = testConnectionDiff(t, testConnectionConfig(testConnectionPortZ, 100, 100))
+	if assert.NotNil(t, diff) {
+		assert.True(t, diff.RequiresNew(), "moving to another port replaces the connection")
+	}
+
+	config := testConnectionConfig(testConnectionPortA, 100, 100)
+	config["type"] = "EPL_VC"
+	diff = testConnectionDiff(t, config)
+	if assert.NotNil(t, diff) {
+		assert.True(t, diff.RequiresNew(), "changing the connection type replaces the connection")
+	}
+}
+
+func TestFabricConnectionCustomizeDiffServiceProfile(t *testing.T) {
+	const profile = "bfb74121-7e2c-4f74-99b3-69cdafb03b41"
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/serviceProfiles/" + profile: `{"uuid": "` + profile + `", "name": "Partner Profile", "type": "L2_PROFILE"}`,
+	})
+	config := func(sellerRegion, metroCode string) map[string]interface{} {
+		accessPoint := map[string]interface{}{
+			"type":          "SP",
+			"profile":       []interface{}{map[string]interface{}{"uuid": profile}},
+			"seller_region": sellerRegion,
+		}
+		if metroCode != "" {
+			accessPoint["location"] = []interface{}{map[string]interface{}{"metro_code": metroCode}}
+		}
+		return map[string]interface{}{
+			"name":      "port2aws",
+			"type":      "EVPL_VC",
+			"bandwidth": 50,
+			"a_side":    testConnectionSide(testConnectionPortA, 100),
+			"z_side":    []interface{}{map[string]interface{}{"access_point": []interface{}{accessPoint}}},
+		}
+	}
+	state := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), config("us-west-1", "SV"))
+	state.SetId(testConnectionUuid)
+	diff := func(config map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+		diff, err := resourceFabricConnection().Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+		assert.NoError(t, err)
+		return diff
+	}
+
+	if d := diff(config("us-west-1", "SV")); d != nil {
+		assert.False(t, d.RequiresNew(), "unchanged access point")
+	}
+	if d := diff(config("us-east-1", "SV")); assert.NotNil(t, d) {
+		assert.True(t, d.RequiresNew(), "changing the seller region replaces the connection")
+	}
+	if d := diff(config("us-west-1", "DC")); assert.NotNil(t, d) {
+		assert.True(t, d.RequiresNew(), "changing the metro replaces the connection")
+	}
+}
+
+func TestFabricConnectionSideReplaced(t *testing.T) {
+	sides := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), map[string]interface{}{
+		"a_side": testConnectionSide(testConnectionPortA, 100),
+		"z_side": testConnectionSide(testConnectionPortA, 200),
+	})
+	moved := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), map[string]interface{}{
+		"a_side": testConnectionSide(testConnectionPortZ, 100),
+	})
+
+	assert.Equal(t, testConnectionPortA, connectionSideAttribute(firstSetElem(sides.Get("a_side")), "access_point.port.uuid"))
+	assert.Empty(t, connectionSideAttribute(firstSetElem(sides.Get("a_side")), "service_token.uuid"))
+	assert.False(t, connectionSideReplaced(sides.Get("a_side"), sides.Get("z_side")), "VLAN tags are patched in place")
+	assert.True(t, connectionSideReplaced(sides.Get("a_side"), moved.Get("a_side")))
+}
+
+func TestFabricConnectionUpdateRequests(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), testConnectionConfig(testConnectionPortA, 200, 100))
+	d.SetId(testConnectionUuid)
+	d.Set("redundancy", []interface{}{map[string]interface{}{"priority": "SECONDARY", "group": "primary-group"}})
+
+	provisioned := v4.PROVISIONED_ProviderStatus
+	dot1q := v4.DOT1_Q_LinkProtocolType
+	conn := v4.Connection{
+		Uuid:      testConnectionUuid,
+		Name:      "port2port",
+		Bandwidth: 50,
+		Operation: &v4.ConnectionOperation{ProviderStatus: &provisioned},
+		ASide:     &v4.ConnectionSide{AccessPoint: &v4.AccessPoint{LinkProtocol: &v4.SimplifiedLinkProtocol{Type_: &dot1q, VlanTag: 100}}},
+		ZSide:     &v4.ConnectionSide{AccessPoint: &v4.AccessPoint{LinkProtocol: &v4.SimplifiedLinkProtocol{Type_: &dot1q, VlanTag: 100}}},
+	}
+
+	updates, err := getUpdateRequests(conn, d)
+	assert.NoError(t, err)
+	paths := map[string]interface{}{}
+	for _, update := range updates {
+		for _, op := range update {
+			assert.Equal(t, "replace", op.Op)
+			paths[op.Path] = op.Value
+		}
+	}
+	assert.Equal(t, 200, paths["/aSide/accessPoint/linkProtocol/vlanTag"])
+	assert.NotContains(t, paths, "/zSide/accessPoint/linkProtocol/vlanTag", "unchanged VLAN tags are not patched")
+	assert.Equal(t, "primary-group", paths["/redundancy/group"])
+	assert.Contains(t, paths, "/notifications")
+	assert.NotContains(t, paths, "/name")
+}
+
+func TestFabricConnectionSideHashIgnoresComputed(t *testing.T) {
+	colo := v4.COLO_AccessPointType
+	dot1q := v4.DOT1_Q_LinkProtocolType
+	side := &v4.ConnectionSide{AccessPoint: &v4.AccessPoint{
+		Type_:        &colo,
+		Port:         &v4.SimplifiedPort{Uuid: testConnectionPortA, Name: "ops-user100-CX-SV1-NL-Dot1q-STD-10G-PRI"},
+		Location:     &v4.SimplifiedLocation{MetroCode: "SV"},
+		LinkProtocol: &v4.SimplifiedLinkProtocol{Type_: &dot1q, VlanTag: 100},
+	}}
+
+	d := schema.TestResourceDataRaw(t, createFabricConnectionResourceSchema(), testConnectionConfig(testConnectionPortA, 100, 100))
+	config := d.Get("a_side").(*schema.Set)
+	if err := d.Set("a_side", connectionSideToTerra(side)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	read := d.Get("a_side").(*schema.Set)
+	assert.Equal(t, 1, read.Len())
+	assert.True(t, config.HashEqual(read), "values filled in by Fabric do not change the side")
+	assert.Equal(t, "SV", connectionSideAttribute(firstSetElem(read), "access_point.location.metro_code"))
+}
+
+// recordedEcxConnection is a connection ordered with equinix_ecx_l2_connection,
+// read through the Fabric v4 API
+const recordedEcxConnection = `{
+  "uuid": "` + testConnectionUuid + `",
+  "name": "tf-aws",
+  "type": "EVPL_VC",
+  "bandwidth": 200,
+  "state": "ACTIVE",
+  "redundancy": {"group": "7c8d0f3e-1a2b-4c5d-8e9f-0a1b2c3d4e5f", "priority": "PRIMARY"},
+  "order": {"purchaseOrderNumber": "1-323929"},
+  "notifications": [{"type": "ALL", "emails": ["marry@equinix.com", "john@equinix.com"]}],
+  "aSide": {"accessPoint": {
+    "type": "COLO",
+    "port": {"uuid": "` + testConnectionPortA + `"},
+    "linkProtocol": {"type": "QINQ", "vlanSTag": 777, "vlanCTag": 1000}
+  }},
+  "zSide": {"accessPoint": {
+    "type": "SP",
+    "authenticationKey": "357848976964",
+    "sellerRegion": "us-west-1",
+    "profile": {"type": "L2_PROFILE", "uuid": "` + testAwsProfileUuid + `"},
+    "location": {"metroCode": "SV"}
+  }}
+}`
+
+func TestFabricConnectionImport(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/connections/" + testConnectionUuid: recordedEcxConnection,
+	})
+
+	d := resourceFabricConnection().TestResourceData()
+	d.SetId(testConnectionUuid + ":" + testConnectionPortZ)
+	_, err := resourceFabricConnectionImport(context.Background(), d, meta)
+	assert.ErrorContains(t, err, "import the primary connection "+testConnectionUuid+" and the secondary connection "+testConnectionPortZ)
+
+	d.SetId(testConnectionUuid)
+	imported, err := resourceFabricConnectionImport(context.Background(), d, meta)
+	if err != nil || len(imported) != 1 {
+		t.Fatalf("unexpected import result: %v, %v", imported, err)
+	}
+	assert.Empty(t, resourceFabricConnectionRead(context.Background(), imported[0], meta))
+
+	assert.Equal(t, "tf-aws", d.Get("name"))
+	assert.Equal(t, 200, d.Get("bandwidth"))
+	assert.Equal(t, "1-323929", firstSetElem(d.Get("order"))["purchase_order_number"])
+	assert.Equal(t, []interface{}{"marry@equinix.com", "john@equinix.com"}, d.Get("notifications.0.emails"))
+	redundancy := firstSetElem(d.Get("redundancy"))
+	assert.Equal(t, "PRIMARY", redundancy["priority"])
+
+	aSide := firstSetElem(d.Get("a_side"))
+	assert.Equal(t, testConnectionPortA, connectionSideAttribute(aSide, "access_point.port.uuid"))
+	assert.Equal(t, "QINQ", connectionSideAttribute(aSide, "access_point.link_protocol.type"))
+	assert.Equal(t, "777", connectionSideAttribute(aSide, "access_point.link_protocol.vlan_s_tag"))
+	assert.Equal(t, "1000", connectionSideAttribute(aSide, "access_point.link_protocol.vlan_c_tag"))
+
+	zSide := firstSetElem(d.Get("z_side"))
+	assert.Equal(t, testAwsProfileUuid, connectionSideAttribute(zSide, "access_point.profile.uuid"))
+	assert.Equal(t, "357848976964", connectionSideAttribute(zSide, "access_point.authentication_key"))
+	assert.Equal(t, "us-west-1", connectionSideAttribute(zSide, "access_point.seller_region"))
+	assert.Equal(t, "SV", connectionSideAttribute(zSide, "access_point.location.metro_code"))
+}
@@ -0,0 +1,268 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFabricNetwork() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Update: schema.DefaultTimeout(6 * time.Minute),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext:   resourceFabricNetworkRead,
+		CreateContext: resourceFabricNetworkCreate,
+		UpdateContext: resourceFabricNetworkUpdate,
+		DeleteContext: resourceFabricNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: createFabricNetworkResourceSchema(),
+
+		Description: "Fabric V4 API compatible resource allows creation and management of Equinix Fabric Network. EVPLAN and EPLAN networks are attached to connections of type EVPLAN_VC and EPLAN_VC\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func resourceFabricNetworkCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	networkType := v4.NetworkType(d.Get("type").(string))
+	scope := v4.NetworkScope(d.Get("scope").(string))
+	createRequest := v4.NetworkPostRequest{
+		Name:          d.Get("name").(string),
+		Type_:         &networkType,
+		Scope:         &scope,
+		Notifications: notificationToFabric(d.Get("notifications").([]interface{})),
+	}
+	if schemaLocation := d.Get("location").(*schema.Set).List(); len(schemaLocation) != 0 {
+		location := locationToFabric(schemaLocation)
+		createRequest.Location = &location
+	}
+	if schemaProject := d.Get("project").(*schema.Set).List(); len(schemaProject) != 0 {
+		project := projectToFabric(schemaProject)
+		createRequest.Project = &project
+	}
+
+	network, _, err := client.NetworksApi.CreateNetwork(ctx, createRequest)
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	d.SetId(network.Uuid)
+
+	if _, err = waitUntilFabricNetworkIsProvisioned(d.Id(), meta, ctx, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error waiting for Fabric Network (%s) to be created: %s", d.Id(), err)
+	}
+
+	return resourceFabricNetworkRead(ctx, d, meta)
+}
+
+func resourceFabricNetworkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	network, _, err := client.NetworksApi.GetNetworkByUuid(ctx, d.Id())
+	if err != nil {
+		log.Printf("[WARN] Fabric Network %s not found , error %s", d.Id(), err)
+		if !strings.Contains(err.Error(), "500") {
+			d.SetId("")
+		}
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	if network.State != nil && *network.State == v4.DELETED_NetworkState {
+		log.Printf("[WARN] Fabric Network %s is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	d.SetId(network.Uuid)
+	return setFabricNetworkMap(d, network)
+}
+
+func setFabricNetworkMap(d *schema.ResourceData, network v4.Network) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	m := map[string]interface{}{
+		"name":              network.Name,
+		"href":              network.Href,
+		"uuid":              network.Uuid,
+		"notifications":     notificationToTerra(network.Notifications),
+		"project":           networkProjectToTerra(network.Project),
+		"connections_count": int(network.ConnectionsCount),
+		"operation":         networkOperationToTerra(network.Operation),
+		"change":            networkChangeToTerra(network.Change),
+		"change_log":        changeLogToTerra(network.ChangeLog),
+	}
+	if network.Type_ != nil {
+		m["type"] = string(*network.Type_)
+	}
+	if network.Scope != nil {
+		m["scope"] = string(*network.Scope)
+	}
+	if network.State != nil {
+		m["state"] = string(*network.State)
+	}
+	if network.Location != nil {
+		m["location"] = locationToTerra(network.Location)
+	}
+	err := equinix_schema.SetMap(d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
+func resourceFabricNetworkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	dbNetwork, err := waitUntilFabricNetworkIsProvisioned(d.Id(), meta, ctx, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return diag.Errorf("either timed out or errored out while fetching Fabric Network for uuid %s and error %v", d.Id(), err)
+	}
+	updates, err := getNetworkUpdateRequest(dbNetwork, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	network, _, err := client.NetworksApi.UpdateNetworkByUuid(ctx, updates, d.Id())
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	if network.Change != nil {
+		if _, err = waitForFabricNetworkUpdateCompletion(network.Change.Uuid, d.Id(), meta, ctx, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(fmt.Errorf("errored while waiting for successful Fabric Network update, error %v", err))
+		}
+	}
+
+	return resourceFabricNetworkRead(ctx, d, meta)
+}
+
+func resourceFabricNetworkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	_, _, err := client.NetworksApi.DeleteNetworkByUuid(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	err = waitUntilFabricNetworkIsDeprovisioned(d.Id(), meta, ctx, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("API call failed while waiting for resource deletion. Error %v", err))
+	}
+	return diags
+}
+
+// fabricNetworkStatus returns the Equinix status of the network operation,
+// which is PROVISIONED for networks without connections too
+func fabricNetworkStatus(network v4.Network) string {
+	if network.Operation == nil || network.Operation.EquinixStatus == nil {
+		return ""
+	}
+	return string(*network.Operation.EquinixStatus)
+}
+
+func waitUntilFabricNetworkIsProvisioned(uuid string, meta interface{}, ctx context.Context, timeout time.Duration) (v4.Network, error) {
+	log.Printf("Waiting for Fabric Network to be provisioned, uuid %s", uuid)
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{
+			string(v4.PROVISIONING_NetworkEquinixStatus),
+		},
+		Target: []string{
+			string(v4.PROVISIONED_NetworkEquinixStatus),
+		},
+		Refresh: func() (interface{}, string, error) {
+			client := meta.(*config.Config).FabricClient
+			dbNetwork, _, err := client.NetworksApi.GetNetworkByUuid(ctx, uuid)
+			if err != nil {
+				return "", "", equinix_errors.FormatFabricError(err)
+			}
+			return dbNetwork, fabricNetworkStatus(dbNetwork), nil
+		},
+		Timeout:    timeout,
+		Delay:      30 * time.Second,
+		MinTimeout: 30 * time.Second,
+	}
+
+	inter, err := stateConf.WaitForStateContext(ctx)
+	dbNetwork := v4.Network{}
+
+	if err == nil {
+		dbNetwork = inter.(v4.Network)
+	}
+	return dbNetwork, err
+}
+
+func waitForFabricNetworkUpdateCompletion(changeUuid string, uuid string, meta interface{}, ctx context.Context, timeout time.Duration) (v4.NetworkChange, error) {
+	log.Printf("Waiting for Fabric Network update to complete, uuid %s", uuid)
+	stateConf := &retry.StateChangeConf{
+		Target: []string{string(v4.COMPLETED_NetworkChangeStatus)},
+		Refresh: func() (interface{}, string, error) {
+			client := meta.(*config.Config).FabricClient
+			dbChange, _, err := client.NetworksApi.GetNetworkChangeByUuid(ctx, uuid, changeUuid)
+			if err != nil {
+				return "", "", equinix_errors.FormatFabricError(err)
+			}
+			updatableState := ""
+			if dbChange.Status != nil {
+				switch *dbChange.Status {
+				case v4.COMPLETED_NetworkChangeStatus:
+					updatableState = string(*dbChange.Status)
+				case v4.FAILED_NetworkChangeStatus, v4.REJECTED_NetworkChangeStatus:
+					return "", "", fmt.Errorf("change %s of Fabric Network %s is %s", changeUuid, uuid, *dbChange.Status)
+				}
+			}
+			return dbChange, updatableState, nil
+		},
+		Timeout:    timeout,
+		Delay:      30 * time.Second,
+		MinTimeout: 30 * time.Second,
+	}
+
+	inter, err := stateConf.WaitForStateContext(ctx)
+	dbChange := v4.NetworkChange{}
+
+	if err == nil {
+		dbChange = inter.(v4.NetworkChange)
+	}
+	return dbChange, err
+}
+
+func waitUntilFabricNetworkIsDeprovisioned(uuid string, meta interface{}, ctx context.Context, timeout time.Duration) error {
+	log.Printf("Waiting for Fabric Network to be deprovisioned, uuid %s", uuid)
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{
+			string(v4.PROVISIONED_NetworkEquinixStatus),
+			string(v4.DEPROVISIONING_NetworkEquinixStatus),
+		},
+		Target: []string{
+			string(v4.DEPROVISIONED_NetworkEquinixStatus),
+		},
+		Refresh: func() (interface{}, string, error) {
+			client := meta.(*config.Config).FabricClient
+			dbNetwork, _, err := client.NetworksApi.GetNetworkByUuid(ctx, uuid)
+			if err != nil {
+				return "", "", equinix_errors.FormatFabricError(err)
+			}
+			return dbNetwork, fabricNetworkStatus(dbNetwork), nil
+		},
+		Timeout:    timeout,
+		Delay:      30 * time.Second,
+		MinTimeout: 30 * time.Second,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
@@ -0,0 +1,106 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const testNetworkUuid = "3f2e1d0c-9b8a-4765-8432-10fedcba9876"
+
+func TestFabricNetworkRead(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/networks/" + testNetworkUuid: `{
+  "uuid": "` + testNetworkUuid + `",
+  "name": "evplan-network",
+  "type": "EVPLAN",
+  "scope": "REGIONAL",
+  "state": "ACTIVE",
+  "connectionsCount": 2,
+  "location": {"region": "EMEA"},
+  "project": {"projectId": "776847000642406"},
+  "notifications": [{"type": "ALL", "emails": ["test@equinix.com"]}],
+  "operation": {"equinixStatus": "PROVISIONED"}
+}`,
+	})
+
+	d := resourceFabricNetwork().TestResourceData()
+	d.SetId(testNetworkUuid)
+	diags := resourceFabricNetworkRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, "evplan-network", d.Get("name"))
+	assert.Equal(t, "EVPLAN", d.Get("type"))
+	assert.Equal(t, "REGIONAL", d.Get("scope"))
+	assert.Equal(t, 2, d.Get("connections_count"))
+	assert.Equal(t, "EMEA", firstSetElem(d.Get("location"))["region"])
+	assert.Equal(t, "776847000642406", firstSetElem(d.Get("project"))["project_id"])
+	assert.Equal(t, "test@equinix.com", d.Get("notifications.0.emails.0"))
+	assert.Equal(t, "PROVISIONED", firstSetElem(d.Get("operation"))["equinix_status"])
+}
+
+func TestFabricNetworkReadDeleted(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/networks/" + testNetworkUuid: `{"uuid": "` + testNetworkUuid + `", "state": "DELETED"}`,
+	})
+
+	d := resourceFabricNetwork().TestResourceData()
+	d.SetId(testNetworkUuid)
+	diags := resourceFabricNetworkRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestFabricNetworkUpdateRequest(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricNetworkResourceSchema(), map[string]interface{}{
+		"name": "renamed-network",
+		"notifications": []interface{}{
+			map[string]interface{}{"type": "ALL", "emails": []interface{}{"test@equinix.com"}},
+		},
+	})
+	network := v4.Network{Name: "evplan-network"}
+
+	updates, err := getNetworkUpdateRequest(network, d)
+	assert.NoError(t, err)
+	assert.Len(t, updates, 2, "notifications are set in the plan")
+	assert.Equal(t, "/name", updates[0].Path)
+	assert.Equal(t, "renamed-network", *updates[0].Value)
+	assert.Equal(t, "/notifications", updates[1].Path)
+
+	network.Name = "renamed-network"
+	d = schema.TestResourceDataRaw(t, createFabricNetworkResourceSchema(), map[string]interface{}{
+		"name": "renamed-network",
+	})
+	_, err = getNetworkUpdateRequest(network, d)
+	assert.Error(t, err)
+}
+
+func TestDataSourceFabricNetworkConnectionsRead(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/networks/" + testNetworkUuid + "/connections": `{
+  "pagination": {"total": 1},
+  "data": [{
+    "uuid": "` + testRouteConnectionUuid + `",
+    "name": "evplan-vc",
+    "type": "EVPLAN_VC",
+    "state": "ACTIVE",
+    "bandwidth": 50,
+    "href": "https://api.equinix.com/fabric/v4/connections/` + testRouteConnectionUuid + `"
+  }]
+}`,
+	})
+
+	d := dataSourceFabricNetworkConnections().TestResourceData()
+	d.Set("network_uuid", testNetworkUuid)
+	diags := dataSourceFabricNetworkConnectionsRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, testNetworkUuid, d.Id())
+	assert.Equal(t, 1, d.Get("connections.#"))
+	assert.Equal(t, testRouteConnectionUuid, d.Get("connections.0.uuid"))
+	assert.Equal(t, "EVPLAN_VC", d.Get("connections.0.type"))
+	assert.Equal(t, 50, d.Get("connections.0.bandwidth"))
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
@@ -16,10 +20,11 @@ import (
 	"github.com/antihax/optional"
 	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func resourceFabricPortRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+func readFabricPortByUuid(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*config.Config).FabricClient
 	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
 	port, _, err := client.PortsApi.GetPortByUuid(ctx, d.Id())
@@ -123,3 +128,519 @@ func portNameQueryParamToFabric(portNameParam []interface{}) v4.PortsApiGetPorts
 	}
 	return mappedPn
 }
+
+// fabricPortPollInterval is how often port orders and LAG member changes are
+// polled
+var fabricPortPollInterval = 30 * time.Second
+
+func resourceFabricPort() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Hour),
+			Update: schema.DefaultTimeout(6 * time.Hour),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext:   resourceFabricPortRead,
+		CreateContext: resourceFabricPortCreate,
+		UpdateContext: resourceFabricPortUpdate,
+		DeleteContext: resourceFabricPortDelete,
+		CustomizeDiff: resourceFabricPortCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: createFabricPortResourceSchema(),
+
+		Description: "Fabric V4 API compatible resource allows ordering and management of Equinix Fabric Ports, including Link Aggregation Group (LAG) ports whose members can be added and removed in place\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+// resourceFabricPortCustomizeDiff rejects more than one physical port on
+// ports without LAG, which the API only refuses once the order is placed
+func resourceFabricPortCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if count := d.Get("physical_ports_count").(int); count > 1 && !d.Get("lag_enabled").(bool) {
+		return fmt.Errorf("physical_ports_count is %d, but ports with more than one physical port must set lag_enabled", count)
+	}
+	return nil
+}
+
+func resourceFabricPortCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	port, _, err := client.PortsApi.CreatePort(ctx, portRequest(d))
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	d.SetId(port.Uuid)
+
+	if err = waitUntilFabricPortIsProvisioned(ctx, meta, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return fabricPortOrderWaitDiags(ctx, d, meta, err)
+	}
+	return resourceFabricPortRead(ctx, d, meta)
+}
+
+func resourceFabricPortRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	port, resp, err := client.PortsApi.GetPortByUuid(ctx, d.Id())
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Printf("[WARN] Fabric Port %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	if port.State != nil && *port.State == v4.DEPROVISIONED_PortState {
+		log.Printf("[WARN] Fabric Port %s is deprovisioned, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	return setFabricPortResourceMap(d, port)
+}
+
+func resourceFabricPortUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if updates := getPortUpdateRequest(d); len(updates) != 0 {
+		if err := fabricRequest(ctx, meta, http.MethodPatch, "/fabric/v4/ports/"+d.Id(), updates, nil); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if !d.HasChange("physical_ports_count") {
+		return resourceFabricPortRead(ctx, d, meta)
+	}
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	port, _, err := client.PortsApi.GetPortByUuid(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	want := d.Get("physical_ports_count").(int)
+	add, remove := lagMemberChanges(port, want)
+	if add > 0 {
+		newMembers := v4.BulkPhysicalPort{}
+		for i := 0; i < add; i++ {
+			newMembers.Data = append(newMembers.Data, lagMemberRequest(d))
+		}
+		if _, _, err := client.PortsApi.AddToLag(ctx, newMembers, d.Id()); err != nil {
+			return diag.FromErr(equinix_errors.FormatFabricError(err))
+		}
+	}
+	for _, member := range remove {
+		if err := removeFabricPortLagMember(ctx, meta, d.Id(), member.Id); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := waitForFabricPortLagMembers(ctx, meta, d.Id(), want, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return fabricPortOrderWaitDiags(ctx, d, meta, err)
+	}
+	return resourceFabricPortRead(ctx, d, meta)
+}
+
+func resourceFabricPortDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := fabricRequest(ctx, meta, http.MethodDelete, "/fabric/v4/ports/"+d.Id(), nil, nil); err != nil {
+		if isFabricNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := waitUntilFabricPortIsDeprovisioned(ctx, meta, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error waiting for Fabric Port (%s) to be deleted: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// removeFabricPortLagMember removes a physical port from a LAG port. fabric-go
+// has no method for it, so it is sent as
+//
+//	DELETE /fabric/v4/ports/{portId}/physicalPorts/{physicalPortId}
+//
+// without a body, where physicalPortId is the numeric id of the physical port
+// in the port's physicalPorts. The response, the port with the physical port
+// DEPROVISIONING, is ignored: the caller polls the port until the member is
+// gone. A 404 means the physical port was already removed
+func removeFabricPortLagMember(ctx context.Context, meta interface{}, uuid string, physicalPortId int32) error {
+	path := fmt.Sprintf("/fabric/v4/ports/%s/physicalPorts/%d", uuid, physicalPortId)
+	if err := fabricRequest(ctx, meta, http.MethodDelete, path, nil, nil); err != nil && !isFabricNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// fabricPortOrderWaitDiags keeps a port whose order is still in progress when
+// the timeout is reached, as physical ports can take days to be delivered.
+// Destroying it would cancel the order. The timeout ends either the wait or
+// the context of the operation, so the port is read with a new context
+func fabricPortOrderWaitDiags(ctx context.Context, d *schema.ResourceData, meta interface{}, err error) diag.Diagnostics {
+	var timeoutErr *retry.TimeoutError
+	if !errors.As(err, &timeoutErr) && !errors.Is(err, context.DeadlineExceeded) {
+		return diag.Errorf("error waiting for Fabric Port (%s) order: %s", d.Id(), err)
+	}
+	readCtx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+	diags := resourceFabricPortRead(readCtx, d, meta)
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Fabric Port %s order is still in progress", d.Id()),
+		Detail:   fmt.Sprintf("The port order was placed, but the port was not provisioned before the timeout: %s. Its state is refreshed on the next plan.", err),
+	})
+}
+
+func portRequest(d *schema.ResourceData) v4.Port {
+	portType := v4.PortType(d.Get("type").(string))
+	account := accountToCloudRouter(d.Get("account").([]interface{}))
+	location := locationToFabric(d.Get("location").([]interface{}))
+	port := v4.Port{
+		Type_:                  &portType,
+		Description:            d.Get("description").(string),
+		PhysicalPortsSpeed:     int32(d.Get("physical_ports_speed").(int)),
+		PhysicalPortsType:      d.Get("physical_ports_type").(string),
+		PhysicalPortsCount:     int32(d.Get("physical_ports_count").(int)),
+		ConnectivitySourceType: d.Get("connectivity_source_type").(string),
+		BmmrType:               d.Get("bmmr_type").(string),
+		LagEnabled:             d.Get("lag_enabled").(bool),
+		Account:                &account,
+		Location:               &location,
+		Settings:               &v4.PortSettings{PackageType: d.Get("package_type").(string)},
+		Order:                  portOrderToFabric(d.Get("order").([]interface{})),
+		DemarcationPoint:       portDemarcationPointToFabric(d.Get("demarcation_point").([]interface{})),
+	}
+	if schemaProject := d.Get("project").(*schema.Set).List(); len(schemaProject) != 0 {
+		project := projectToFabric(schemaProject)
+		port.Project = &project
+	}
+	for _, e := range d.Get("encapsulation").([]interface{}) {
+		eMap := e.(map[string]interface{})
+		port.Encapsulation = &v4.PortEncapsulation{Type_: eMap["type"].(string), TagProtocolId: eMap["tag_protocol_id"].(string)}
+	}
+	for _, r := range d.Get("redundancy").([]interface{}) {
+		rMap := r.(map[string]interface{})
+		priority := v4.PortPriority(rMap["priority"].(string))
+		port.Redundancy = &v4.PortRedundancy{Enabled: true, Group: rMap["group"].(string), Priority: &priority}
+	}
+	port.Notifications = portNotificationsToFabric(d.Get("notifications").([]interface{}))
+	return port
+}
+
+func portNotificationsToFabric(schemaNotifications []interface{}) []v4.PortNotification {
+	var notifications []v4.PortNotification
+	for _, n := range schemaNotifications {
+		nMap := n.(map[string]interface{})
+		notifications = append(notifications, v4.PortNotification{
+			Type_:           nMap["type"].(string),
+			RegisteredUsers: converters.IfArrToStringArr(nMap["registered_users"].([]interface{})),
+		})
+	}
+	return notifications
+}
+
+// fabricPortChangeOperation is an operation of a port update, which fabric-go
+// has no API for
+type fabricPortChangeOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func getPortUpdateRequest(d *schema.ResourceData) []fabricPortChangeOperation {
+	var changeOps []fabricPortChangeOperation
+	if d.HasChange("description") {
+		changeOps = append(changeOps, fabricPortChangeOperation{Op: "replace", Path: "/description", Value: d.Get("description").(string)})
+	}
+	if d.HasChange("notifications") {
+		notifications := portNotificationsToFabric(d.Get("notifications").([]interface{}))
+		if notifications == nil {
+			notifications = []v4.PortNotification{}
+		}
+		changeOps = append(changeOps, fabricPortChangeOperation{Op: "replace", Path: "/notifications", Value: notifications})
+	}
+	return changeOps
+}
+
+// lagMemberRequest returns a physical port delivered to the demarcation point
+// of the port. The patch panel ports of each member are assigned by Equinix
+func lagMemberRequest(d *schema.ResourceData) v4.PhysicalPort {
+	memberType := v4.XF_PHYSICAL_PORT_PhysicalPortType
+	member := v4.PhysicalPort{
+		Type_: &memberType,
+		Order: portOrderToFabric(d.Get("order").([]interface{})),
+	}
+	if demarcationPoint := portDemarcationPointToFabric(d.Get("demarcation_point").([]interface{})); demarcationPoint != nil {
+		member.DemarcationPoint = &v4.PortDemarcationPoint{
+			Ibx:                  demarcationPoint.Ibx,
+			CageUniqueSpaceId:    demarcationPoint.CageUniqueSpaceId,
+			CabinetUniqueSpaceId: demarcationPoint.CabinetUniqueSpaceId,
+			ConnectorType:        demarcationPoint.ConnectorType,
+		}
+	}
+	return member
+}
+
+func portOrderToFabric(schemaOrder []interface{}) *v4.PortOrder {
+	if len(schemaOrder) == 0 || schemaOrder[0] == nil {
+		return nil
+	}
+	oMap := schemaOrder[0].(map[string]interface{})
+	order := &v4.PortOrder{}
+	if number := oMap["purchase_order_number"].(string); number != "" {
+		order.PurchaseOrder = &v4.PortOrderPurchaseOrder{Number: number}
+	}
+	if signatory := oMap["signatory"].(string); signatory != "" {
+		order.Signature = &v4.PortOrderSignature{Signatory: signatory}
+		if email := oMap["delegate_email"].(string); email != "" {
+			order.Signature.Delegate = &v4.PortOrderSignatureDelegate{
+				Email:     email,
+				FirstName: oMap["delegate_first_name"].(string),
+				LastName:  oMap["delegate_last_name"].(string),
+			}
+		}
+	}
+	return order
+}
+
+func portDemarcationPointToFabric(schemaDemarcationPoint []interface{}) *v4.PortDemarcationPoint {
+	if len(schemaDemarcationPoint) == 0 || schemaDemarcationPoint[0] == nil {
+		return nil
+	}
+	dpMap := schemaDemarcationPoint[0].(map[string]interface{})
+	return &v4.PortDemarcationPoint{
+		Ibx:                  dpMap["ibx"].(string),
+		CageUniqueSpaceId:    dpMap["cage_unique_space_id"].(string),
+		CabinetUniqueSpaceId: dpMap["cabinet_unique_space_id"].(string),
+		PatchPanel:           dpMap["patch_panel"].(string),
+		PatchPanelPortA:      dpMap["patch_panel_port_a"].(string),
+		PatchPanelPortB:      dpMap["patch_panel_port_b"].(string),
+		ConnectorType:        dpMap["connector_type"].(string),
+	}
+}
+
+func setFabricPortResourceMap(d *schema.ResourceData, port v4.Port) diag.Diagnostics {
+	m := map[string]interface{}{
+		"href":                     port.Href,
+		"uuid":                     port.Uuid,
+		"name":                     port.Name,
+		"description":              port.Description,
+		"physical_ports_speed":     int(port.PhysicalPortsSpeed),
+		"connectivity_source_type": port.ConnectivitySourceType,
+		"lag_enabled":              port.LagEnabled,
+		"bandwidth":                int(port.Bandwidth),
+		"available_bandwidth":      int(port.AvailableBandwidth),
+		"used_bandwidth":           int(port.UsedBandwidth),
+		"project":                  networkProjectToTerra(port.Project),
+		"physical_ports":           physicalPortsToTerra(port.PhysicalPorts),
+		"change_log":               changeLogToTerra(port.Changelog),
+	}
+	if port.State != nil && (*port.State == v4.PROVISIONED_PortState || *port.State == v4.ACTIVE_PortState) {
+		m["physical_ports_count"] = len(activeLagMembers(port))
+	} else if port.PhysicalPortsCount != 0 {
+		// The physical ports of an order in progress are not listed yet
+		m["physical_ports_count"] = int(port.PhysicalPortsCount)
+	}
+	if port.PhysicalPortsType != "" {
+		m["physical_ports_type"] = port.PhysicalPortsType
+	}
+	if port.BmmrType != "" {
+		m["bmmr_type"] = port.BmmrType
+	}
+	if port.Type_ != nil {
+		m["type"] = string(*port.Type_)
+	}
+	if port.State != nil {
+		m["state"] = string(*port.State)
+	}
+	if port.Account != nil {
+		m["account"] = []interface{}{map[string]interface{}{"account_number": int(port.Account.AccountNumber)}}
+	}
+	if port.Location != nil {
+		m["location"] = []interface{}{map[string]interface{}{
+			"region":     port.Location.Region,
+			"metro_name": port.Location.MetroName,
+			"metro_code": port.Location.MetroCode,
+			"ibx":        port.Location.Ibx,
+		}}
+	}
+	if port.Encapsulation != nil {
+		m["encapsulation"] = []interface{}{map[string]interface{}{
+			"type":            port.Encapsulation.Type_,
+			"tag_protocol_id": port.Encapsulation.TagProtocolId,
+		}}
+	}
+	if port.Redundancy != nil && port.Redundancy.Priority != nil {
+		m["redundancy"] = []interface{}{map[string]interface{}{
+			"priority": string(*port.Redundancy.Priority),
+			"group":    port.Redundancy.Group,
+		}}
+	}
+	if port.Settings != nil {
+		m["package_type"] = port.Settings.PackageType
+	}
+	if port.Order != nil {
+		order := d.Get("order").([]interface{})
+		mappedOrder := map[string]interface{}{}
+		if len(order) != 0 && order[0] != nil {
+			// Signature details are not returned by the API
+			mappedOrder = order[0].(map[string]interface{})
+		}
+		mappedOrder["order_id"] = port.Order.OrderId
+		mappedOrder["order_number"] = port.Order.OrderNumber
+		if port.Order.PurchaseOrder != nil {
+			mappedOrder["purchase_order_number"] = port.Order.PurchaseOrder.Number
+		}
+		m["order"] = []interface{}{mappedOrder}
+	}
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func physicalPortsToTerra(physicalPorts []v4.PhysicalPort) []interface{} {
+	mappedPhysicalPorts := make([]interface{}, len(physicalPorts))
+	for i, physicalPort := range physicalPorts {
+		mappedPhysicalPort := map[string]interface{}{
+			"id":              int(physicalPort.Id),
+			"href":            physicalPort.Href,
+			"interface_speed": int(physicalPort.InterfaceSpeed),
+			"interface_type":  physicalPort.InterfaceType,
+		}
+		if physicalPort.State != nil {
+			mappedPhysicalPort["state"] = string(*physicalPort.State)
+		}
+		if physicalPort.Operation != nil {
+			mappedPhysicalPort["operational_status"] = physicalPort.Operation.OperationalStatus
+		}
+		mappedPhysicalPorts[i] = mappedPhysicalPort
+	}
+	return mappedPhysicalPorts
+}
+
+// lagMemberChanges returns how many physical ports to add to the port, or
+// which to remove, to have want physical ports. The members ordered last are
+// removed first
+func lagMemberChanges(port v4.Port, want int) (add int, remove []v4.PhysicalPort) {
+	members := activeLagMembers(port)
+	if want >= len(members) {
+		return want - len(members), nil
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Id > members[j].Id })
+	return 0, members[:len(members)-want]
+}
+
+// activeLagMembers returns the physical ports of the port which are not
+// being removed
+func activeLagMembers(port v4.Port) []v4.PhysicalPort {
+	var members []v4.PhysicalPort
+	for _, physicalPort := range port.PhysicalPorts {
+		if physicalPort.State != nil && (*physicalPort.State == v4.DEPROVISIONING_PortState || *physicalPort.State == v4.DEPROVISIONED_PortState) {
+			continue
+		}
+		members = append(members, physicalPort)
+	}
+	return members
+}
+
+func waitUntilFabricPortIsProvisioned(ctx context.Context, meta interface{}, uuid string, timeout time.Duration) error {
+	log.Printf("Waiting for Fabric Port to be provisioned, uuid %s", uuid)
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{
+			string(v4.PENDING_PortState),
+			string(v4.PROVISIONING_PortState),
+		},
+		Target: []string{
+			string(v4.PROVISIONED_PortState),
+			string(v4.ACTIVE_PortState),
+		},
+		Refresh: func() (interface{}, string, error) {
+			client := meta.(*config.Config).FabricClient
+			port, _, err := client.PortsApi.GetPortByUuid(ctx, uuid)
+			if err != nil {
+				return "", "", equinix_errors.FormatFabricError(err)
+			}
+			if port.State == nil {
+				// orders are only given a state once they are accepted
+				return port, string(v4.PENDING_PortState), nil
+			}
+			return port, string(*port.State), nil
+		},
+		Timeout:    timeout,
+		Delay:      fabricPortPollInterval,
+		MinTimeout: fabricPortPollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func waitForFabricPortLagMembers(ctx context.Context, meta interface{}, uuid string, count int, timeout time.Duration) error {
+	log.Printf("Waiting for Fabric Port %s to have %d provisioned physical ports", uuid, count)
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"changing"},
+		Target:  []string{"done"},
+		Refresh: func() (interface{}, string, error) {
+			client := meta.(*config.Config).FabricClient
+			port, _, err := client.PortsApi.GetPortByUuid(ctx, uuid)
+			if err != nil {
+				return "", "", equinix_errors.FormatFabricError(err)
+			}
+			members := activeLagMembers(port)
+			if len(members) != count {
+				return port, "changing", nil
+			}
+			for _, member := range members {
+				if member.State == nil {
+					continue
+				}
+				switch *member.State {
+				case v4.FAILED_PortState:
+					return "", "", fmt.Errorf("physical port %d of Fabric Port %s failed", member.Id, uuid)
+				case v4.PENDING_PortState, v4.PROVISIONING_PortState:
+					return port, "changing", nil
+				}
+			}
+			return port, "done", nil
+		},
+		Timeout:    timeout,
+		Delay:      fabricPortPollInterval,
+		MinTimeout: fabricPortPollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func waitUntilFabricPortIsDeprovisioned(ctx context.Context, meta interface{}, uuid string, timeout time.Duration) error {
+	log.Printf("Waiting for Fabric Port to be deprovisioned, uuid %s", uuid)
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{
+			string(v4.PENDING_PortState),
+			string(v4.PROVISIONING_PortState),
+			string(v4.PROVISIONED_PortState),
+			string(v4.ACTIVE_PortState),
+			string(v4.DEPROVISIONING_PortState),
+		},
+		Target: []string{
+			string(v4.DEPROVISIONED_PortState),
+		},
+		Refresh: func() (interface{}, string, error) {
+			port := v4.Port{}
+			if err := fabricRequest(ctx, meta, http.MethodGet, "/fabric/v4/ports/"+uuid, nil, &port); err != nil {
+				if isFabricNotFound(err) {
+					return port, string(v4.DEPROVISIONED_PortState), nil
+				}
+				return "", "", err
+			}
+			if port.State == nil {
+				return port, "", nil
+			}
+			return port, string(*port.State), nil
+		},
+		Timeout:    timeout,
+		Delay:      fabricPortPollInterval,
+		MinTimeout: fabricPortPollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
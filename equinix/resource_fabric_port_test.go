@@ -0,0 +1,353 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const testFabricPortUuid = "c4d9350e-783c-83cd-1ce0-306a5c00a600"
+
+const recordedFabricPort = `{
+  "type": "XF_PORT",
+  "uuid": "` + testFabricPortUuid + `",
+  "name": "ops-user100-CX-SV1-NL-Dot1q-STD-10G-PRI-LAG",
+  "state": "PROVISIONED",
+  "physicalPortsSpeed": 10000,
+  "physicalPortsType": "10GBASE_LR",
+  "connectivitySourceType": "COLO",
+  "lagEnabled": true,
+  "account": {"accountNumber": 203612},
+  "location": {"metroCode": "SV", "ibx": "SV1"},
+  "encapsulation": {"type": "DOT1Q", "tagProtocolId": "0x8100"},
+  "redundancy": {"enabled": true, "priority": "PRIMARY"},
+  "order": {"orderId": "1-1234567", "orderNumber": "1-7654321", "purchaseOrder": {"number": "PO-1"}},
+  "physicalPorts": [
+    {"type": "XF_PHYSICAL_PORT", "id": 1, "state": "PROVISIONED"},
+    {"type": "XF_PHYSICAL_PORT", "id": 2, "state": "PROVISIONED"}
+  ]
+}`
+
+func fastFabricPortPolling(t *testing.T) {
+	interval := fabricPortPollInterval
+	fabricPortPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { fabricPortPollInterval = interval })
+}
+
+func testFabricPortConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                     "XF_PORT",
+		"physical_ports_speed":     10000,
+		"physical_ports_type":      "10GBASE_LR",
+		"physical_ports_count":     2,
+		"connectivity_source_type": "COLO",
+		"lag_enabled":              true,
+		"location":                 []interface{}{map[string]interface{}{"metro_code": "SV"}},
+		"account":                  []interface{}{map[string]interface{}{"account_number": 203612}},
+		"encapsulation":            []interface{}{map[string]interface{}{"type": "DOT1Q"}},
+		"redundancy":               []interface{}{map[string]interface{}{"priority": "PRIMARY"}},
+		"demarcation_point": []interface{}{map[string]interface{}{
+			"ibx":                  "SV1",
+			"cage_unique_space_id": "SV1:01:002345",
+			"patch_panel_port_a":   "1",
+		}},
+		"order": []interface{}{map[string]interface{}{
+			"purchase_order_number": "PO-1",
+			"signatory":             "DELEGATE",
+			"delegate_email":        "delegate@equinix.com",
+		}},
+	}
+}
+
+func TestFabricPortCreate(t *testing.T) {
+	fastFabricPortPolling(t)
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/ports":                      recordedFabricPort,
+		"GET /fabric/v4/ports/" + testFabricPortUuid: recordedFabricPort,
+	})
+
+	d := schema.TestResourceDataRaw(t, createFabricPortResourceSchema(), testFabricPortConfig())
+	diags := resourceFabricPortCreate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	body, _ := fake.request("POST /fabric/v4/ports")
+	request := v4.Port{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid create request %q: %v", body, err)
+	}
+	assert.Equal(t, int32(2), request.PhysicalPortsCount)
+	assert.True(t, request.LagEnabled)
+	assert.Equal(t, "SV1:01:002345", request.DemarcationPoint.CageUniqueSpaceId)
+	assert.Equal(t, "delegate@equinix.com", request.Order.Signature.Delegate.Email)
+	assert.Equal(t, v4.PRIMARY_PortPriority, *request.Redundancy.Priority)
+
+	assert.Equal(t, testFabricPortUuid, d.Id())
+	assert.Equal(t, "PROVISIONED", d.Get("state"))
+	assert.Equal(t, 2, d.Get("physical_ports_count"))
+	assert.Equal(t, "1-7654321", d.Get("order.0.order_number"))
+	assert.Equal(t, "DELEGATE", d.Get("order.0.signatory"), "signature details are kept from the configuration")
+	assert.Equal(t, "0x8100", d.Get("encapsulation.0.tag_protocol_id"))
+}
+
+func TestFabricPortOrderTimeoutKeepsPort(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/ports/" + testFabricPortUuid: recordedFabricPort,
+	})
+
+	d := schema.TestResourceDataRaw(t, createFabricPortResourceSchema(), testFabricPortConfig())
+	d.SetId(testFabricPortUuid)
+	diags := fabricPortOrderWaitDiags(context.Background(), d, meta, &retry.TimeoutError{LastState: "PENDING"})
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, testFabricPortUuid, d.Id())
+}
+
+func TestFabricPortLagMemberChanges(t *testing.T) {
+	provisioned, deprovisioning := v4.PROVISIONED_PortState, v4.DEPROVISIONING_PortState
+	port := v4.Port{PhysicalPorts: []v4.PhysicalPort{
+		{Id: 1, State: &provisioned},
+		{Id: 3, State: &provisioned},
+		{Id: 2, State: &provisioned},
+		{Id: 4, State: &deprovisioning},
+	}}
+
+	add, remove := lagMemberChanges(port, 5)
+	assert.Equal(t, 2, add, "the member being removed is not counted")
+	assert.Empty(t, remove)
+
+	add, remove = lagMemberChanges(port, 1)
+	assert.Equal(t, 0, add)
+	if assert.Len(t, remove, 2) {
+		assert.Equal(t, int32(3), remove[0].Id, "the members ordered last are removed first")
+		assert.Equal(t, int32(2), remove[1].Id)
+	}
+}
+
+func TestFabricPortLagMemberRequest(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricPortResourceSchema(), testFabricPortConfig())
+	member := lagMemberRequest(d)
+	assert.Equal(t, "SV1:01:002345", member.DemarcationPoint.CageUniqueSpaceId)
+	assert.Empty(t, member.DemarcationPoint.PatchPanelPortA, "patch panel ports are assigned per member")
+	assert.Equal(t, "PO-1", member.Order.PurchaseOrder.Number)
+}
+
+func TestFabricPortReadPendingOrder(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/ports/" + testFabricPortUuid: `{
+  "type": "XF_PORT",
+  "uuid": "` + testFabricPortUuid + `",
+  "state": "PENDING",
+  "physicalPortsCount": 2,
+  "lagEnabled": true
+}`,
+	})
+
+	d := schema.TestResourceDataRaw(t, createFabricPortResourceSchema(), testFabricPortConfig())
+	d.SetId(testFabricPortUuid)
+	diags := resourceFabricPortRead(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, 2, d.Get("physical_ports_count"), "the ordered physical ports are kept until they are delivered")
+}
+
+func TestFabricPortUpdateDescription(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"PATCH /fabric/v4/ports/" + testFabricPortUuid: recordedFabricPort,
+		"GET /fabric/v4/ports/" + testFabricPortUuid:   recordedFabricPort,
+	})
+
+	r := resourceFabricPort()
+	config := testFabricPortConfig()
+	state := schema.TestResourceDataRaw(t, r.Schema, config)
+	state.SetId(testFabricPortUuid)
+	if diags := resourceFabricPortRead(context.Background(), state, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	config["description"] = "primary port"
+	config["notifications"] = []interface{}{map[string]interface{}{
+		"type":             "TECHNICAL",
+		"registered_users": []interface{}{"user1"},
+	}}
+	diff, err := r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("description and notifications must be updated in place: %v", diff)
+	}
+
+	d, err := schema.InternalMap(r.Schema).Data(state.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	diags := resourceFabricPortUpdate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	body, _ := fake.request("PATCH /fabric/v4/ports/" + testFabricPortUuid)
+	var updates []fabricPortChangeOperation
+	if err := json.Unmarshal([]byte(body), &updates); err != nil {
+		t.Fatalf("invalid update request %q: %v", body, err)
+	}
+	if assert.Len(t, updates, 2) {
+		assert.Equal(t, "/description", updates[0].Path)
+		assert.Equal(t, "primary port", updates[0].Value)
+		assert.Equal(t, "/notifications", updates[1].Path)
+	}
+	assert.NotContains(t, fake.requestOrder(), "POST /fabric/v4/ports/"+testFabricPortUuid+"/physicalPorts/bulk", "the LAG members are unchanged")
+}
+
+// testFabricPortWithMembers is the recorded port with a physical port in each
+// of the states
+func testFabricPortWithMembers(t *testing.T, states ...string) string {
+	port := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(recordedFabricPort), &port); err != nil {
+		t.Fatal(err)
+	}
+	var members []interface{}
+	for i, state := range states {
+		members = append(members, map[string]interface{}{"type": "XF_PHYSICAL_PORT", "id": i + 1, "state": state})
+	}
+	port["physicalPorts"] = members
+	b, err := json.Marshal(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// testFabricPortUpdateData reads the port with the first queued response, and
+// returns the data to update it to count physical ports
+func testFabricPortUpdateData(t *testing.T, meta interface{}, count int) *schema.ResourceData {
+	r := resourceFabricPort()
+	config := testFabricPortConfig()
+	state := schema.TestResourceDataRaw(t, r.Schema, config)
+	state.SetId(testFabricPortUuid)
+	if diags := resourceFabricPortRead(context.Background(), state, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	config["physical_ports_count"] = count
+	diff, err := r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("physical_ports_count must be updated in place: %v", diff)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return d
+}
+
+func TestFabricPortUpdateAddLagMember(t *testing.T) {
+	fastFabricPortPolling(t)
+	getPort := "GET /fabric/v4/ports/" + testFabricPortUuid
+	addMembers := "POST /fabric/v4/ports/" + testFabricPortUuid + "/physicalPorts/bulk"
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		addMembers: `{"data":[]}`,
+		getPort:    testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED", "PROVISIONED"),
+	})
+	fake.queued[getPort] = []string{
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED"),
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED"),
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED", "PENDING"),
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED", "PROVISIONING"),
+	}
+
+	d := testFabricPortUpdateData(t, meta, 3)
+	diags := resourceFabricPortUpdate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	body, ok := fake.request(addMembers)
+	if !ok {
+		t.Fatalf("no physical ports were added: %v", fake.requestOrder())
+	}
+	request := v4.BulkPhysicalPort{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid add request %q: %v", body, err)
+	}
+	if assert.Len(t, request.Data, 1) {
+		assert.Equal(t, "SV1:01:002345", request.Data[0].DemarcationPoint.CageUniqueSpaceId)
+	}
+	assert.Empty(t, fake.queued[getPort], "the update waits for the new member to be provisioned")
+	assert.Equal(t, 3, d.Get("physical_ports_count"))
+}
+
+func TestFabricPortUpdateRemoveLagMember(t *testing.T) {
+	fastFabricPortPolling(t)
+	getPort := "GET /fabric/v4/ports/" + testFabricPortUuid
+	removeMember := "DELETE /fabric/v4/ports/" + testFabricPortUuid + "/physicalPorts/2"
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		removeMember: testFabricPortWithMembers(t, "PROVISIONED", "DEPROVISIONING"),
+		getPort:      testFabricPortWithMembers(t, "PROVISIONED", "DEPROVISIONED"),
+	})
+	fake.queued[getPort] = []string{
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED"),
+		testFabricPortWithMembers(t, "PROVISIONED", "PROVISIONED"),
+		testFabricPortWithMembers(t, "PROVISIONED", "DEPROVISIONING"),
+	}
+
+	d := testFabricPortUpdateData(t, meta, 1)
+	diags := resourceFabricPortUpdate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	calls := fake.requestOrder()
+	assert.Contains(t, calls, removeMember, "the member ordered last is removed")
+	assert.NotContains(t, calls, "DELETE /fabric/v4/ports/"+testFabricPortUuid+"/physicalPorts/1")
+	body, _ := fake.request(removeMember)
+	assert.Empty(t, body, "the request has no body")
+	assert.Empty(t, fake.queued[getPort], "the update waits for the member to be deprovisioned")
+	assert.Equal(t, 1, d.Get("physical_ports_count"))
+}
+
+func TestFabricPortCreateOrderTimeout(t *testing.T) {
+	fastFabricPortPolling(t)
+	getPort := "GET /fabric/v4/ports/" + testFabricPortUuid
+	pending := `{"type": "XF_PORT", "uuid": "` + testFabricPortUuid + `", "state": "PENDING", "physicalPortsCount": 2, "lagEnabled": true}`
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/ports": `{"type": "XF_PORT", "uuid": "` + testFabricPortUuid + `"}`,
+		getPort:                 pending,
+	})
+	// the order has no state until it is accepted
+	fake.queued[getPort] = []string{`{"type": "XF_PORT", "uuid": "` + testFabricPortUuid + `"}`}
+
+	r := resourceFabricPort()
+	config := testFabricPortConfig()
+	config["timeouts"] = map[string]interface{}{"create": "100ms"}
+	diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, diags := r.Apply(context.Background(), nil, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("a port whose order is in progress must not fail: %v", diags)
+	}
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Contains(t, diags[0].Summary, "order is still in progress")
+	}
+	if assert.NotNil(t, state) {
+		assert.Equal(t, testFabricPortUuid, state.ID, "the port is kept, destroying it would cancel the order")
+		assert.Equal(t, "PENDING", state.Attributes["state"])
+	}
+	assert.Empty(t, fake.queued[getPort])
+}
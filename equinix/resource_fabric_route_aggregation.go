@@ -0,0 +1,23 @@
+package equinix
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFabricRouteAggregation() *schema.Resource {
+	r := resourceFabricRoutePolicy(fabricRouteAggregations)
+	r.Description = "Fabric V4 API compatible resource allows creation and management of Equinix Fabric Cloud Router route aggregations. Route aggregations advertise summary prefixes instead of the prefixes in them\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
+
+func resourceFabricRouteAggregationRule() *schema.Resource {
+	r := resourceFabricRoutePolicyRule(fabricRouteAggregations)
+	r.Description = "Fabric V4 API compatible resource allows creation and management of the rules of Equinix Fabric Cloud Router route aggregations. Each rule is a prefix which is advertised instead of the prefixes in it\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
+
+func resourceFabricConnectionRouteAggregation() *schema.Resource {
+	r := resourceFabricConnectionRoutePolicy(fabricRouteAggregations)
+	r.Description = "Fabric V4 API compatible resource allows attachment of Equinix Fabric Cloud Router route aggregations to connections. Route aggregations apply to the prefixes advertised on the connection\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
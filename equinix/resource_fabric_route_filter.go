@@ -0,0 +1,144 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFabricRouteFilter() *schema.Resource {
+	r := resourceFabricRoutePolicy(fabricRouteFilters)
+	r.Description = "Fabric V4 API compatible resource allows creation and management of Equinix Fabric Cloud Router route filters. Route filters decide which BGP prefixes a connection accepts or advertises\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
+
+// resourceFabricRoutePolicy returns the resource of a route filter or route
+// aggregation, which only differ in their types and rules
+func resourceFabricRoutePolicy(kind fabricRoutePolicyKind) *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyRead(ctx, d, meta, kind)
+		},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyCreate(ctx, d, meta, kind)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyUpdate(ctx, d, meta, kind)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyDelete(ctx, d, meta, kind)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: createFabricRoutePolicyResourceSchema(kind),
+	}
+}
+
+func routePolicyRequest(d *schema.ResourceData) fabricRoutePolicy {
+	project := projectToFabric(d.Get("project").(*schema.Set).List())
+	return fabricRoutePolicy{
+		Type:        d.Get("type").(string),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Project:     &project,
+	}
+}
+
+func resourceFabricRoutePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	policy := fabricRoutePolicy{}
+	if err := fabricRequest(ctx, meta, http.MethodGet, kind.policyPath(d.Id()), nil, &policy); err != nil {
+		if isFabricNotFound(err) {
+			log.Printf("[WARN] Fabric %s %s not found, removing from state", kind.name, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if policy.State == string(v4.DEPROVISIONED_ConnectionState) {
+		log.Printf("[WARN] Fabric %s %s is deprovisioned, removing from state", kind.name, d.Id())
+		d.SetId("")
+		return nil
+	}
+	return setFabricRoutePolicyMap(d, policy, kind)
+}
+
+func resourceFabricRoutePolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	policy := fabricRoutePolicy{}
+	if err := fabricRequest(ctx, meta, http.MethodPost, "/fabric/v4/"+kind.path, routePolicyRequest(d), &policy); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(policy.Uuid)
+
+	if err := waitUntilFabricRouteIsProvisioned(ctx, meta, kind.policyPath(d.Id()), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be created: %s", kind.name, d.Id(), err)
+	}
+	return resourceFabricRoutePolicyRead(ctx, d, meta, kind)
+}
+
+func resourceFabricRoutePolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	policy := fabricRoutePolicy{}
+	if err := fabricRequest(ctx, meta, http.MethodPut, kind.policyPath(d.Id()), routePolicyRequest(d), &policy); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForFabricRouteChangeCompletion(ctx, meta, kind.policyPath(d.Id()), policy.Change, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be updated: %s", kind.name, d.Id(), err)
+	}
+	if err := waitUntilFabricRouteIsProvisioned(ctx, meta, kind.policyPath(d.Id()), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be updated: %s", kind.name, d.Id(), err)
+	}
+	return resourceFabricRoutePolicyRead(ctx, d, meta, kind)
+}
+
+func resourceFabricRoutePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	if err := fabricRequest(ctx, meta, http.MethodDelete, kind.policyPath(d.Id()), nil, nil); err != nil {
+		if isFabricNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := waitUntilFabricRouteIsDeprovisioned(ctx, meta, kind.policyPath(d.Id()), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error waiting for %s (%s) to be deleted: %s", kind.name, d.Id(), err)
+	}
+	return nil
+}
+
+func setFabricRoutePolicyMap(d *schema.ResourceData, policy fabricRoutePolicy, kind fabricRoutePolicyKind) diag.Diagnostics {
+	m := map[string]interface{}{
+		"href":              policy.Href,
+		"uuid":              policy.Uuid,
+		"type":              policy.Type,
+		"name":              policy.Name,
+		"description":       policy.Description,
+		"state":             policy.State,
+		"connections_count": policy.ConnectionsCount,
+		"rules_count":       policy.RulesCount,
+	}
+	if policy.Project != nil {
+		m["project"] = schema.NewSet(schema.HashResource(createRoutePolicyProjectRes), []interface{}{
+			map[string]interface{}{"project_id": policy.Project.ProjectId},
+		})
+	}
+	if kind.isFilter {
+		m["not_matched_rule_action"] = policy.NotMatchedRuleAction
+	}
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
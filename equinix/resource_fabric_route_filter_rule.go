@@ -0,0 +1,155 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceFabricRouteFilterRule() *schema.Resource {
+	r := resourceFabricRoutePolicyRule(fabricRouteFilters)
+	r.Description = "Fabric V4 API compatible resource allows creation and management of the rules of Equinix Fabric Cloud Router route filters. Each rule permits a prefix, and optionally the longer prefixes in it\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)"
+	return r
+}
+
+// resourceFabricRoutePolicyRule returns the resource of a rule of a route
+// filter or route aggregation
+func resourceFabricRoutePolicyRule(kind fabricRoutePolicyKind) *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyRuleRead(ctx, d, meta, kind)
+		},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyRuleCreate(ctx, d, meta, kind)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyRuleUpdate(ctx, d, meta, kind)
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return resourceFabricRoutePolicyRuleDelete(ctx, d, meta, kind)
+		},
+		Importer: &schema.ResourceImporter{
+			// Custom state context function, to parse import argument as <policy-uuid>/<rule-uuid>
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				policyUuid, uuid, ok := strings.Cut(d.Id(), "/")
+				if !ok || policyUuid == "" || uuid == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%s), expected <%s>/<rule-uuid>", d.Id(), kind.idAttribute)
+				}
+				_ = d.Set(kind.idAttribute, policyUuid)
+				d.SetId(uuid)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: createFabricRoutePolicyRuleResourceSchema(kind),
+	}
+}
+
+func routePolicyRuleRequest(d *schema.ResourceData, kind fabricRoutePolicyKind) fabricRoutePolicyRule {
+	rule := fabricRoutePolicyRule{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Prefix:      d.Get("prefix").(string),
+	}
+	if kind.isFilter {
+		rule.PrefixMatch = d.Get("prefix_match").(string)
+	}
+	return rule
+}
+
+func resourceFabricRoutePolicyRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	rule := fabricRoutePolicyRule{}
+	if err := fabricRequest(ctx, meta, http.MethodGet, kind.rulePath(d.Get(kind.idAttribute).(string), d.Id()), nil, &rule); err != nil {
+		if isFabricNotFound(err) {
+			log.Printf("[WARN] Fabric %s rule %s not found, removing from state", kind.name, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if rule.State == string(v4.DEPROVISIONED_ConnectionState) {
+		log.Printf("[WARN] Fabric %s rule %s is deprovisioned, removing from state", kind.name, d.Id())
+		d.SetId("")
+		return nil
+	}
+	return setFabricRoutePolicyRuleMap(d, rule, kind)
+}
+
+func resourceFabricRoutePolicyRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	policyUuid := d.Get(kind.idAttribute).(string)
+	rule := fabricRoutePolicyRule{}
+	if err := fabricRequest(ctx, meta, http.MethodPost, kind.ruleCollectionPath(policyUuid), routePolicyRuleRequest(d, kind), &rule); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(rule.Uuid)
+
+	if err := waitUntilFabricRouteIsProvisioned(ctx, meta, kind.rulePath(policyUuid, d.Id()), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error waiting for %s rule (%s) to be created: %s", kind.name, d.Id(), err)
+	}
+	return resourceFabricRoutePolicyRuleRead(ctx, d, meta, kind)
+}
+
+func resourceFabricRoutePolicyRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	rulePath := kind.rulePath(d.Get(kind.idAttribute).(string), d.Id())
+	rule := fabricRoutePolicyRule{}
+	if err := fabricRequest(ctx, meta, http.MethodPut, rulePath, routePolicyRuleRequest(d, kind), &rule); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForFabricRouteChangeCompletion(ctx, meta, rulePath, rule.Change, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error waiting for %s rule (%s) to be updated: %s", kind.name, d.Id(), err)
+	}
+	if err := waitUntilFabricRouteIsProvisioned(ctx, meta, rulePath, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error waiting for %s rule (%s) to be updated: %s", kind.name, d.Id(), err)
+	}
+	return resourceFabricRoutePolicyRuleRead(ctx, d, meta, kind)
+}
+
+func resourceFabricRoutePolicyRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}, kind fabricRoutePolicyKind) diag.Diagnostics {
+	rulePath := kind.rulePath(d.Get(kind.idAttribute).(string), d.Id())
+	if err := fabricRequest(ctx, meta, http.MethodDelete, rulePath, nil, nil); err != nil {
+		if isFabricNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if err := waitUntilFabricRouteIsDeprovisioned(ctx, meta, rulePath, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error waiting for %s rule (%s) to be deleted: %s", kind.name, d.Id(), err)
+	}
+	return nil
+}
+
+func setFabricRoutePolicyRuleMap(d *schema.ResourceData, rule fabricRoutePolicyRule, kind fabricRoutePolicyKind) diag.Diagnostics {
+	m := map[string]interface{}{
+		"href":        rule.Href,
+		"uuid":        rule.Uuid,
+		"type":        rule.Type,
+		"name":        rule.Name,
+		"description": rule.Description,
+		"prefix":      rule.Prefix,
+		"state":       rule.State,
+	}
+	if kind.isFilter {
+		m["prefix_match"] = rule.PrefixMatch
+		m["action"] = rule.Action
+	}
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRouteFilterUuid     = "9b5d8a4c-2e1f-4a3b-8c7d-6e5f4a3b2c10"
+	testRouteFilterRuleUuid = "9b5d8a4c-2e1f-4a3b-8c7d-6e5f4a3b2c11"
+	testRouteConnectionUuid = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+)
+
+func fastFabricRoutePolling(t *testing.T) {
+	interval := fabricRoutePollInterval
+	fabricRoutePollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { fabricRoutePollInterval = interval })
+}
+
+func TestResourceFabricRouteFilterRuleCreate(t *testing.T) {
+	fastFabricRoutePolling(t)
+	rulePath := "/fabric/v4/routeFilters/" + testRouteFilterUuid + "/routeFilterRules"
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST " + rulePath: `{"uuid": "` + testRouteFilterRuleUuid + `", "state": "PROVISIONING", "prefix": "10.0.0.0/8"}`,
+		"GET " + rulePath + "/" + testRouteFilterRuleUuid: `{
+  "href": "https://api.equinix.com` + rulePath + "/" + testRouteFilterRuleUuid + `",
+  "uuid": "` + testRouteFilterRuleUuid + `",
+  "type": "BGP_IPv4_PREFIX_FILTER_RULE",
+  "name": "private",
+  "state": "PROVISIONED",
+  "prefix": "10.0.0.0/8",
+  "prefixMatch": "orlonger",
+  "action": "PERMIT"
+}`,
+	})
+
+	d := resourceFabricRouteFilterRule().TestResourceData()
+	d.Set("route_filter_id", testRouteFilterUuid)
+	d.Set("name", "private")
+	d.Set("prefix", "10.0.0.0/8")
+	d.Set("prefix_match", "orlonger")
+	diags := resourceFabricRoutePolicyRuleCreate(context.Background(), d, meta, fabricRouteFilters)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	body, _ := fake.request("POST " + rulePath)
+	assert.JSONEq(t, `{"name":"private","prefix":"10.0.0.0/8","prefixMatch":"orlonger"}`, body)
+	userAgent := fake.header("POST " + rulePath).Get("User-Agent")
+	assert.Equal(t, meta.FabricUserAgent(), userAgent)
+	assert.Contains(t, userAgent, "terraform-provider-equinix/")
+	assert.Equal(t, testRouteFilterRuleUuid, d.Id())
+	assert.Equal(t, "PERMIT", d.Get("action"))
+	assert.Equal(t, "PROVISIONED", d.Get("state"))
+}
+
+func TestResourceFabricConnectionRouteFilterAttach(t *testing.T) {
+	fastFabricRoutePolling(t)
+	attachmentPath := "/fabric/v4/connections/" + testRouteConnectionUuid + "/routeFilters/" + testRouteFilterUuid
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"PUT " + attachmentPath: `{"uuid": "` + testRouteFilterUuid + `", "attachmentStatus": "ATTACHING", "direction": "INBOUND"}`,
+		"GET " + attachmentPath: `{"uuid": "` + testRouteFilterUuid + `", "type": "BGP_IPv4_PREFIX_FILTER", "attachmentStatus": "PENDING_BGP_CONFIGURATION", "direction": "INBOUND"}`,
+	})
+
+	d := resourceFabricConnectionRouteFilter().TestResourceData()
+	d.Set("connection_id", testRouteConnectionUuid)
+	d.Set("route_filter_id", testRouteFilterUuid)
+	d.Set("direction", "INBOUND")
+	diags := resourceFabricConnectionRoutePolicyAttach(context.Background(), d, meta, fabricRouteFilters, time.Minute)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	body, _ := fake.request("PUT " + attachmentPath)
+	assert.JSONEq(t, `{"direction":"INBOUND"}`, body)
+	assert.Equal(t, testRouteConnectionUuid+"/"+testRouteFilterUuid, d.Id())
+	assert.Equal(t, "PENDING_BGP_CONFIGURATION", d.Get("attachment_status"))
+}
+
+func TestResourceFabricRouteFilterRead_notFound(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{})
+
+	d := resourceFabricRouteFilter().TestResourceData()
+	d.SetId(testRouteFilterUuid)
+	diags := resourceFabricRoutePolicyRead(context.Background(), d, meta, fabricRouteFilters)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "", d.Id(), "a deleted route filter is removed from state")
+}
+
+func TestFabricAPIError(t *testing.T) {
+	err := &fabricAPIError{
+		StatusCode: 400,
+		Status:     "POST /fabric/v4/routeFilters: 400 Bad Request",
+		Errors:     []v4.ModelError{{ErrorCode: "EQ-3044001", ErrorMessage: "Invalid argument value passed", Details: "name"}},
+	}
+	assert.Equal(t, "POST /fabric/v4/routeFilters: 400 Bad Request; Code: EQ-3044001; Message: Invalid argument value passed; Details: name", err.Error())
+	assert.False(t, isFabricNotFound(err))
+	assert.True(t, isFabricNotFound(fmt.Errorf("wrapped: %w", &fabricAPIError{StatusCode: 404})))
+}
@@ -0,0 +1,386 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// fabric-go models the link protocol of access point selectors as
+// v4.LinkProtocol, an empty struct, so it can neither send nor read the VLAN a
+// service token is bound to. fabricServiceTokenRequest and
+// fabricServiceTokenSide carry the link protocol for the raw requests that
+// stand in for ServiceTokensApi: tokens are always read with one, and created
+// with one when a selector has a link protocol
+type fabricServiceTokenRequest struct {
+	v4.ServiceToken
+	Connection *fabricServiceTokenConnection `json:"connection,omitempty"`
+}
+
+type fabricServiceTokenConnection struct {
+	v4.ServiceTokenConnection
+	ASide *fabricServiceTokenSide `json:"aSide,omitempty"`
+	ZSide *fabricServiceTokenSide `json:"zSide,omitempty"`
+}
+
+type fabricServiceTokenSide struct {
+	AccessPointSelectors []fabricAccessPointSelector `json:"accessPointSelectors"`
+}
+
+type fabricAccessPointSelector struct {
+	Type         string                       `json:"type,omitempty"`
+	Port         *v4.SimplifiedMetadataEntity `json:"port,omitempty"`
+	LinkProtocol *v4.SimplifiedLinkProtocol   `json:"linkProtocol,omitempty"`
+}
+
+// fabricGo returns the request as a v4.ServiceToken, without link protocols
+func (r fabricServiceTokenRequest) fabricGo() v4.ServiceToken {
+	serviceToken := r.ServiceToken
+	if r.Connection != nil {
+		conn := r.Connection.ServiceTokenConnection
+		conn.ASide = r.Connection.ASide.fabricGo()
+		conn.ZSide = r.Connection.ZSide.fabricGo()
+		serviceToken.Connection = &conn
+	}
+	return serviceToken
+}
+
+func (s *fabricServiceTokenSide) fabricGo() *v4.ServiceTokenSide {
+	if s == nil {
+		return nil
+	}
+	side := &v4.ServiceTokenSide{}
+	for _, selector := range s.AccessPointSelectors {
+		side.AccessPointSelectors = append(side.AccessPointSelectors, v4.AccessPointSelector{
+			Type_: selector.Type,
+			Port:  selector.Port,
+		})
+	}
+	return side
+}
+
+func (r fabricServiceTokenRequest) hasLinkProtocol() bool {
+	if r.Connection == nil {
+		return false
+	}
+	for _, side := range []*fabricServiceTokenSide{r.Connection.ASide, r.Connection.ZSide} {
+		if side == nil {
+			continue
+		}
+		for _, selector := range side.AccessPointSelectors {
+			if selector.LinkProtocol != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func serviceTokenPath(uuid string) string {
+	return "/fabric/v4/serviceTokens/" + uuid
+}
+
+func resourceFabricServiceToken() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Minute),
+			Update: schema.DefaultTimeout(6 * time.Minute),
+			Delete: schema.DefaultTimeout(6 * time.Minute),
+			Read:   schema.DefaultTimeout(6 * time.Minute),
+		},
+		ReadContext:   resourceFabricServiceTokenRead,
+		CreateContext: resourceFabricServiceTokenCreate,
+		UpdateContext: resourceFabricServiceTokenUpdate,
+		DeleteContext: resourceFabricServiceTokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: createFabricServiceTokenResourceSchema(),
+
+		Description: "Fabric V4 API compatible resource allows creation and management of Equinix Fabric Service Tokens. A service token lets a partner create a connection to or from your port without access to your account\n\n~> **Note** Equinix Fabric v4 resources and datasources are currently in Beta. The interfaces related to `equinix_fabric_` resources and datasources may change ahead of general availability. Please, do not hesitate to report any problems that you experience by opening a new [issue](https://github.com/equinix/terraform-provider-equinix/issues/new?template=bug.md)",
+	}
+}
+
+func resourceFabricServiceTokenCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	request := serviceTokenRequest(d)
+	if request.hasLinkProtocol() {
+		// The link protocol is lost in v4.ServiceToken, see fabricServiceTokenRequest
+		serviceToken := fabricServiceTokenRequest{}
+		if err := fabricRequest(ctx, meta, http.MethodPost, "/fabric/v4/serviceTokens", request, &serviceToken); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(serviceToken.Uuid)
+		return resourceFabricServiceTokenRead(ctx, d, meta)
+	}
+
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	serviceToken, _, err := client.ServiceTokensApi.CreateServiceToken(ctx, request.fabricGo())
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	d.SetId(serviceToken.Uuid)
+	return resourceFabricServiceTokenRead(ctx, d, meta)
+}
+
+func resourceFabricServiceTokenRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// A single raw request, as ServiceTokensApi would drop the link protocols
+	// of the selectors, see fabricServiceTokenRequest
+	serviceToken := fabricServiceTokenRequest{}
+	if err := fabricRequest(ctx, meta, http.MethodGet, serviceTokenPath(d.Id()), nil, &serviceToken); err != nil {
+		if isFabricNotFound(err) {
+			log.Printf("[WARN] Fabric Service Token %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if serviceToken.State != nil && *serviceToken.State == v4.DELETED_ServiceTokenState {
+		log.Printf("[WARN] Fabric Service Token %s is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	return setFabricServiceTokenMap(d, serviceToken.ServiceToken, serviceToken.Connection)
+}
+
+func resourceFabricServiceTokenUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	updates := getServiceTokenUpdateRequest(d)
+	if len(updates) == 0 {
+		return resourceFabricServiceTokenRead(ctx, d, meta)
+	}
+	if _, _, err := client.ServiceTokensApi.UpdateServiceTokenByUuid(ctx, updates, d.Id()); err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+	return resourceFabricServiceTokenRead(ctx, d, meta)
+}
+
+func resourceFabricServiceTokenDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).FabricClient
+	ctx = context.WithValue(ctx, v4.ContextAccessToken, meta.(*config.Config).FabricAuthToken)
+	if resp, err := client.ServiceTokensApi.DeleteServiceTokenByUuid(ctx, d.Id()); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	if err := waitUntilFabricServiceTokenIsDeleted(ctx, meta, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error waiting for Fabric Service Token (%s) to be deleted: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func serviceTokenRequest(d *schema.ResourceData) fabricServiceTokenRequest {
+	tokenType := v4.ServiceTokenType(d.Get("type").(string))
+	// expiration_date_time is validated as RFC3339
+	expiration, _ := time.Parse(time.RFC3339, d.Get("expiration_date_time").(string))
+	request := fabricServiceTokenRequest{
+		ServiceToken: v4.ServiceToken{
+			Type_:              &tokenType,
+			Name:               d.Get("name").(string),
+			Description:        d.Get("description").(string),
+			ExpirationDateTime: expiration,
+			Notifications:      notificationToFabric(d.Get("notifications").([]interface{})),
+		},
+	}
+	if schemaProject := d.Get("project").(*schema.Set).List(); len(schemaProject) != 0 {
+		project := projectToFabric(schemaProject)
+		request.Project = &project
+	}
+	for _, c := range d.Get("service_token_connection").([]interface{}) {
+		connMap := c.(map[string]interface{})
+		request.Connection = &fabricServiceTokenConnection{
+			ServiceTokenConnection: v4.ServiceTokenConnection{
+				Type_:                 connMap["type"].(string),
+				AllowRemoteConnection: connMap["allow_remote_connection"].(bool),
+				BandwidthLimit:        int32(connMap["bandwidth_limit"].(int)),
+				SupportedBandwidths:   serviceTokenBandwidthsToFabric(connMap["supported_bandwidths"].([]interface{})),
+			},
+			ASide: serviceTokenSideToFabric(connMap["a_side"].([]interface{})),
+			ZSide: serviceTokenSideToFabric(connMap["z_side"].([]interface{})),
+		}
+	}
+	return request
+}
+
+func serviceTokenBandwidthsToFabric(schemaBandwidths []interface{}) []int32 {
+	var bandwidths []int32
+	for _, b := range schemaBandwidths {
+		bandwidths = append(bandwidths, int32(b.(int)))
+	}
+	return bandwidths
+}
+
+func serviceTokenSideToFabric(schemaSide []interface{}) *fabricServiceTokenSide {
+	if len(schemaSide) == 0 || schemaSide[0] == nil {
+		return nil
+	}
+	side := &fabricServiceTokenSide{}
+	for _, s := range schemaSide[0].(map[string]interface{})["access_point_selectors"].([]interface{}) {
+		selectorMap := s.(map[string]interface{})
+		selector := fabricAccessPointSelector{Type: selectorMap["type"].(string)}
+		for _, p := range selectorMap["port"].([]interface{}) {
+			selector.Port = &v4.SimplifiedMetadataEntity{Uuid: p.(map[string]interface{})["uuid"].(string)}
+		}
+		if lp := selectorMap["link_protocol"].([]interface{}); len(lp) != 0 {
+			linkProtocol := linkProtocolToFabric(lp)
+			selector.LinkProtocol = &linkProtocol
+		}
+		side.AccessPointSelectors = append(side.AccessPointSelectors, selector)
+	}
+	return side
+}
+
+func setFabricServiceTokenMap(d *schema.ResourceData, serviceToken v4.ServiceToken, conn *fabricServiceTokenConnection) diag.Diagnostics {
+	var tokenType, state, expiration string
+	if serviceToken.Type_ != nil {
+		tokenType = string(*serviceToken.Type_)
+	}
+	if serviceToken.State != nil {
+		state = string(*serviceToken.State)
+	}
+	if !serviceToken.ExpirationDateTime.IsZero() {
+		expiration = serviceToken.ExpirationDateTime.Format(time.RFC3339)
+	}
+	m := map[string]interface{}{
+		"type":                     tokenType,
+		"href":                     serviceToken.Href,
+		"uuid":                     serviceToken.Uuid,
+		"name":                     serviceToken.Name,
+		"description":              serviceToken.Description,
+		"expiration_date_time":     expiration,
+		"state":                    state,
+		"notifications":            notificationToTerra(serviceToken.Notifications),
+		"project":                  networkProjectToTerra(serviceToken.Project),
+		"change_log":               changeLogToTerra(serviceToken.Changelog),
+		"service_token_connection": serviceTokenConnectionToTerra(conn),
+	}
+	if err := equinix_schema.SetMap(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func serviceTokenConnectionToTerra(conn *fabricServiceTokenConnection) []interface{} {
+	if conn == nil {
+		return nil
+	}
+	supportedBandwidths := make([]interface{}, len(conn.SupportedBandwidths))
+	for i, b := range conn.SupportedBandwidths {
+		supportedBandwidths[i] = int(b)
+	}
+	return []interface{}{map[string]interface{}{
+		"type":                    conn.Type_,
+		"uuid":                    conn.Uuid,
+		"href":                    conn.Href,
+		"allow_remote_connection": conn.AllowRemoteConnection,
+		"bandwidth_limit":         int(conn.BandwidthLimit),
+		"supported_bandwidths":    supportedBandwidths,
+		"a_side":                  serviceTokenSideToTerra(conn.ASide),
+		"z_side":                  serviceTokenSideToTerra(conn.ZSide),
+	}}
+}
+
+func serviceTokenSideToTerra(side *fabricServiceTokenSide) []interface{} {
+	if side == nil {
+		return nil
+	}
+	selectors := make([]interface{}, len(side.AccessPointSelectors))
+	for i, selector := range side.AccessPointSelectors {
+		mappedSelector := map[string]interface{}{
+			"type": selector.Type,
+		}
+		if selector.Port != nil {
+			mappedSelector["port"] = []interface{}{map[string]interface{}{
+				"uuid": selector.Port.Uuid,
+				"href": selector.Port.Href,
+			}}
+		}
+		if lp := selector.LinkProtocol; lp != nil && lp.Type_ != nil {
+			mappedSelector["link_protocol"] = []interface{}{map[string]interface{}{
+				"type":       string(*lp.Type_),
+				"vlan_tag":   int(lp.VlanTag),
+				"vlan_s_tag": int(lp.VlanSTag),
+				"vlan_c_tag": int(lp.VlanCTag),
+			}}
+		}
+		selectors[i] = mappedSelector
+	}
+	return []interface{}{map[string]interface{}{"access_point_selectors": selectors}}
+}
+
+func getServiceTokenUpdateRequest(d *schema.ResourceData) []v4.ServiceTokenChangeOperation {
+	var changeOps []v4.ServiceTokenChangeOperation
+	replace := func(path string, value interface{}) {
+		changeOps = append(changeOps, v4.ServiceTokenChangeOperation{Op: "replace", Path: path, Value: &value})
+	}
+	if d.HasChange("name") {
+		replace("/name", d.Get("name").(string))
+	}
+	if d.HasChange("description") {
+		replace("/description", d.Get("description").(string))
+	}
+	if d.HasChange("expiration_date_time") {
+		replace("/expirationDateTime", d.Get("expiration_date_time").(string))
+	}
+	if d.HasChange("notifications") {
+		replace("/notifications", notificationToFabric(d.Get("notifications").([]interface{})))
+	}
+	if d.HasChange("service_token_connection.0.bandwidth_limit") {
+		replace("/connection/bandwidthLimit", d.Get("service_token_connection.0.bandwidth_limit").(int))
+	}
+	if d.HasChange("service_token_connection.0.supported_bandwidths") {
+		replace("/connection/supportedBandwidths", serviceTokenBandwidthsToFabric(d.Get("service_token_connection.0.supported_bandwidths").([]interface{})))
+	}
+	return changeOps
+}
+
+func waitUntilFabricServiceTokenIsDeleted(ctx context.Context, meta interface{}, uuid string, timeout time.Duration) error {
+	log.Printf("Waiting for Fabric Service Token to be deleted, uuid %s", uuid)
+	client := meta.(*config.Config).FabricClient
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{
+			string(v4.ACTIVE_ServiceTokenState),
+			string(v4.INACTIVE_ServiceTokenState),
+			string(v4.EXPIRED_ServiceTokenState),
+		},
+		Target: []string{
+			string(v4.DELETED_ServiceTokenState),
+		},
+		Refresh: func() (interface{}, string, error) {
+			serviceToken, resp, err := client.ServiceTokensApi.GetServiceTokenByUuid(ctx, uuid)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					return serviceToken, string(v4.DELETED_ServiceTokenState), nil
+				}
+				return nil, "", equinix_errors.FormatFabricError(err)
+			}
+			state := ""
+			if serviceToken.State != nil {
+				state = string(*serviceToken.State)
+			}
+			return serviceToken, state, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 30 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("service token %s: %w", uuid, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	v4 "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testServiceTokenUuid = "9d8c7b6a-5f4e-4d3c-8b2a-19f8e7d6c5b4"
+	testServiceTokenPort = "c4d9350e-77c5-7c5d-1ce0-306a5c00a600"
+)
+
+const recordedServiceToken = `{
+  "type": "VC_TOKEN",
+  "uuid": "` + testServiceTokenUuid + `",
+  "href": "https://api.equinix.com/fabric/v4/serviceTokens/` + testServiceTokenUuid + `",
+  "name": "partner-token",
+  "expirationDateTime": "2030-01-31T10:30:00.000Z",
+  "state": "INACTIVE",
+  "connection": {
+    "type": "EVPL_VC",
+    "bandwidthLimit": 100,
+    "supportedBandwidths": [50, 100],
+    "zSide": {
+      "accessPointSelectors": [{
+        "type": "COLO",
+        "port": {"uuid": "` + testServiceTokenPort + `"},
+        "linkProtocol": {"type": "DOT1Q", "vlanTag": 1001}
+      }]
+    }
+  },
+  "notifications": [{"type": "NOTIFICATIONS", "emails": ["test@equinix.com"]}]
+}`
+
+func testServiceTokenConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"name":                 "partner-token",
+		"expiration_date_time": "2030-01-31T10:30:00Z",
+		"service_token_connection": []interface{}{map[string]interface{}{
+			"type":                 "EVPL_VC",
+			"bandwidth_limit":      100,
+			"supported_bandwidths": []interface{}{50, 100},
+			"z_side": []interface{}{map[string]interface{}{
+				"access_point_selectors": []interface{}{map[string]interface{}{
+					"type": "COLO",
+					"port": []interface{}{map[string]interface{}{"uuid": testServiceTokenPort}},
+					"link_protocol": []interface{}{map[string]interface{}{
+						"type":     "DOT1Q",
+						"vlan_tag": 1001,
+					}},
+				}},
+			}},
+		}},
+		"notifications": []interface{}{map[string]interface{}{
+			"type":   "NOTIFICATIONS",
+			"emails": []interface{}{"test@equinix.com"},
+		}},
+	}
+}
+
+func TestFabricServiceTokenCreate(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/serviceTokens":                        recordedServiceToken,
+		"GET /fabric/v4/serviceTokens/" + testServiceTokenUuid: recordedServiceToken,
+	})
+
+	d := schema.TestResourceDataRaw(t, createFabricServiceTokenResourceSchema(), testServiceTokenConfig())
+	diags := resourceFabricServiceTokenCreate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	body, _ := fake.request("POST /fabric/v4/serviceTokens")
+	request := fabricServiceTokenRequest{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid create request %q: %v", body, err)
+	}
+	assert.Equal(t, v4.VC_TOKEN_ServiceTokenType, *request.Type_)
+	assert.Equal(t, "2030-01-31T10:30:00Z", request.ExpirationDateTime.Format(time.RFC3339))
+	assert.Nil(t, request.Connection.ASide)
+	selector := request.Connection.ZSide.AccessPointSelectors[0]
+	assert.Equal(t, testServiceTokenPort, selector.Port.Uuid)
+	assert.Equal(t, int32(1001), selector.LinkProtocol.VlanTag, "the token is bound to the VLAN")
+
+	assert.Equal(t, testServiceTokenUuid, d.Id())
+	assert.Equal(t, testServiceTokenUuid, d.Get("uuid"))
+	assert.Equal(t, "INACTIVE", d.Get("state"))
+	assert.Equal(t, 1001, d.Get("service_token_connection.0.z_side.0.access_point_selectors.0.link_protocol.0.vlan_tag"))
+	assert.Equal(t, 100, d.Get("service_token_connection.0.bandwidth_limit"))
+	assert.Equal(t, "2030-01-31T10:30:00Z", d.Get("expiration_date_time"))
+	assert.Equal(t, []string{
+		"POST /fabric/v4/serviceTokens",
+		"GET /fabric/v4/serviceTokens/" + testServiceTokenUuid,
+	}, fake.requestOrder(), "the token is read with a single request")
+}
+
+func TestFabricServiceTokenCreateWithoutLinkProtocol(t *testing.T) {
+	fake, meta := newFabricFakeAPI(t, map[string]string{
+		"POST /fabric/v4/serviceTokens":                        recordedServiceToken,
+		"GET /fabric/v4/serviceTokens/" + testServiceTokenUuid: recordedServiceToken,
+	})
+
+	config := testServiceTokenConfig()
+	selectors := config["service_token_connection"].([]interface{})[0].(map[string]interface{})["z_side"].([]interface{})[0].(map[string]interface{})["access_point_selectors"].([]interface{})
+	delete(selectors[0].(map[string]interface{}), "link_protocol")
+	d := schema.TestResourceDataRaw(t, createFabricServiceTokenResourceSchema(), config)
+	diags := resourceFabricServiceTokenCreate(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	body, _ := fake.request("POST /fabric/v4/serviceTokens")
+	assert.NotContains(t, body, "linkProtocol")
+	assert.Contains(t, body, testServiceTokenPort)
+	assert.Equal(t, meta.FabricUserAgent(), fake.header("POST /fabric/v4/serviceTokens").Get("User-Agent"))
+	assert.Equal(t, testServiceTokenUuid, d.Id())
+}
+
+func TestFabricServiceTokenReadDeleted(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{
+		"GET /fabric/v4/serviceTokens/" + testServiceTokenUuid: `{"uuid": "` + testServiceTokenUuid + `", "state": "DELETED"}`,
+	})
+
+	d := resourceFabricServiceToken().TestResourceData()
+	d.SetId(testServiceTokenUuid)
+	diags := resourceFabricServiceTokenRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestFabricServiceTokenReadNotFound(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{})
+
+	d := resourceFabricServiceToken().TestResourceData()
+	d.SetId(testServiceTokenUuid)
+	diags := resourceFabricServiceTokenRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestFabricServiceTokenUpdateRequest(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, createFabricServiceTokenResourceSchema(), testServiceTokenConfig())
+	d.SetId(testServiceTokenUuid)
+
+	updates := getServiceTokenUpdateRequest(d)
+	var paths []string
+	for _, update := range updates {
+		paths = append(paths, update.Path)
+	}
+	assert.Contains(t, paths, "/expirationDateTime")
+	assert.Contains(t, paths, "/connection/bandwidthLimit")
+	assert.NotContains(t, paths, "/description")
+}
+
+func TestFabricServiceTokenDeleteNotFound(t *testing.T) {
+	_, meta := newFabricFakeAPI(t, map[string]string{})
+
+	d := resourceFabricServiceToken().TestResourceData()
+	d.SetId(testServiceTokenUuid)
+	diags := resourceFabricServiceTokenDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+}
+
+func TestSuppressEquivalentTime(t *testing.T) {
+	assert.True(t, suppressEquivalentTime("", "2030-01-31T10:30:00.000Z", "2030-01-31T10:30:00Z", nil))
+	assert.False(t, suppressEquivalentTime("", "2030-01-31T10:30:00Z", "2030-02-01T10:30:00Z", nil))
+	assert.False(t, suppressEquivalentTime("", "", "2030-02-01T10:30:00Z", nil))
+}
@@ -1,24 +1,37 @@
 package equinix
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"path"
+	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
+const bgpSessionStatusUp = "up"
+
 func resourceMetalBGPSession() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalBGPSessionCreate,
-		Read:   resourceMetalBGPSessionRead,
-		Delete: resourceMetalBGPSessionDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
+		CreateContext: resourceMetalBGPSessionCreate,
+		ReadContext:   resourceMetalBGPSessionRead,
+		UpdateContext: resourceMetalBGPSessionUpdate,
+		DeleteContext: resourceMetalBGPSessionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -37,10 +50,15 @@ func resourceMetalBGPSession() *schema.Resource {
 			},
 			"default_route": {
 				Type:        schema.TypeBool,
-				Description: "Boolean flag to set the default route policy. False by default",
+				Description: "Boolean flag to set the default route policy. False by default. Changing it updates the session in place",
+				Optional:    true,
+				Default:     false,
+			},
+			"wait_for_up": {
+				Type:        schema.TypeBool,
+				Description: "Wait until the session status is up when creating or updating it. The BGP daemon on the device must be configured for the session to come up",
 				Optional:    true,
 				Default:     false,
-				ForceNew:    true,
 			},
 
 			"status": {
@@ -48,64 +66,155 @@ func resourceMetalBGPSession() *schema.Resource {
 				Description: "Status of the session - up or down",
 				Computed:    true,
 			},
+			"learned_routes": {
+				Type:        schema.TypeList,
+				Description: "Routes learned by Equinix Metal from the device over this session",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"routes_in": {
+				Type:        schema.TypeList,
+				Description: "Routes the device is allowed to announce over this session, as listed by the BGP neighbor of its address family",
+				Computed:    true,
+				Elem:        bgpRouteSchema(),
+			},
+			"routes_out": {
+				Type:        schema.TypeList,
+				Description: "Routes announced to the device over this session, as listed by the BGP neighbor of its address family",
+				Computed:    true,
+				Elem:        bgpRouteSchema(),
+			},
 		},
 	}
 }
 
-func resourceMetalBGPSessionCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalBGPSessionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	dID := d.Get("device_id").(string)
 	addressFamily := d.Get("address_family").(string)
 	defaultRoute := d.Get("default_route").(bool)
 	log.Printf("[DEBUG] creating %s BGP session to device (%s)\n", addressFamily, dID)
-	bgpSession, _, err := client.BGPSessions.Create(
-		dID, packngo.CreateBGPSessionRequest{
-			AddressFamily: addressFamily,
+	bgpSession, resp, err := client.DevicesApi.CreateBgpSession(ctx, dID).
+		BGPSessionInput(metalv1.BGPSessionInput{
+			AddressFamily: metalv1.BGPSessionInputAddressFamily(addressFamily).Ptr(),
 			DefaultRoute:  &defaultRoute,
-		})
+		}).
+		Execute()
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	d.SetId(bgpSession.GetId())
+
+	if d.Get("wait_for_up").(bool) {
+		if err := waitForBGPSessionUp(ctx, client, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	d.SetId(bgpSession.ID)
-	return resourceMetalBGPSessionRead(d, meta)
+	return resourceMetalBGPSessionRead(ctx, d, meta)
 }
 
-func resourceMetalBGPSessionRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalBGPSessionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	bgpSession, _, err := client.BGPSessions.Get(d.Id(),
-		&packngo.GetOptions{Includes: []string{"device"}})
+	bgpSession, resp, err := client.BGPApi.FindBgpSessionById(ctx, d.Id()).Include([]string{"device"}).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		if equinix_errors.IsNotFound(err) {
 			log.Printf("[WARN] BGP Session (%s) not found, removing from state", d.Id())
 
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
+	}
+	deviceID := path.Base(bgpSession.Device.GetHref())
+	addressFamily := string(bgpSession.GetAddressFamily())
+	d.Set("device_id", deviceID)
+	d.Set("address_family", addressFamily)
+	d.Set("status", bgpSession.GetStatus())
+	d.Set("default_route", bgpSession.GetDefaultRoute())
+	d.Set("learned_routes", bgpSession.GetLearnedRoutes())
+	d.SetId(bgpSession.GetId())
+
+	// The routes are only listed per device and address family, by the BGP
+	// neighbor data. A failure to list them should not fail the refresh.
+	neighbors, _, err := client.DevicesApi.GetBgpNeighborData(ctx, deviceID).Execute()
+	if err != nil {
+		log.Printf("[WARN] Could not list BGP neighbors of device (%s), routes of BGP session (%s) are not refreshed: %s", deviceID, d.Id(), err)
+		return nil
+	}
+	routesIn, routesOut := bgpSessionRoutes(neighbors, addressFamily)
+	d.Set("routes_in", routesIn)
+	d.Set("routes_out", routesOut)
+
+	return nil
+}
+
+func resourceMetalBGPSessionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	if d.HasChange("default_route") {
+		defaultRoute := d.Get("default_route").(bool)
+		log.Printf("[DEBUG] updating BGP session (%s) default route to %t", d.Id(), defaultRoute)
+		if resp, err := client.BGPApi.UpdateBgpSession(ctx, d.Id()).Body(defaultRoute).Execute(); err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
 	}
-	defaultRoute := false
-	if bgpSession.DefaultRoute != nil {
-		if *(bgpSession.DefaultRoute) {
-			defaultRoute = true
+
+	if d.Get("wait_for_up").(bool) {
+		if err := waitForBGPSessionUp(ctx, client, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	d.Set("device_id", bgpSession.Device.ID)
-	d.Set("address_family", bgpSession.AddressFamily)
-	d.Set("status", bgpSession.Status)
-	d.Set("default_route", defaultRoute)
-	d.SetId(bgpSession.ID)
+
+	return resourceMetalBGPSessionRead(ctx, d, meta)
+}
+
+func resourceMetalBGPSessionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	resp, err := client.BGPApi.DeleteBgpSession(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return diag.FromErr(equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err))
+}
+
+func waitForBGPSessionUp(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"unknown", "down", ""},
+		Target:  []string{bgpSessionStatusUp},
+		Refresh: func() (interface{}, string, error) {
+			bgpSession, resp, err := client.BGPApi.FindBgpSessionById(ctx, id).Execute()
+			if err != nil {
+				return nil, "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			}
+			return bgpSession, bgpSession.GetStatus(), nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 10 * time.Second,
+		Delay:      5 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for BGP session (%s) to be up: %w", id, err)
+	}
 	return nil
 }
 
-func resourceMetalBGPSessionDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	resp, err := client.BGPSessions.Delete(d.Id())
-	return equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err)
+// bgpSessionRoutes returns the routes of the BGP neighbor with the address
+// family of the session.
+func bgpSessionRoutes(neighbors *metalv1.BgpSessionNeighbors, addressFamily string) (routesIn, routesOut []map[string]interface{}) {
+	family := int32(4)
+	if addressFamily == "ipv6" {
+		family = 6
+	}
+	for _, n := range neighbors.BgpNeighbors {
+		if n.GetAddressFamily() == family {
+			return getRoutesSlice(n.RoutesIn), getRoutesSlice(n.RoutesOut)
+		}
+	}
+	return []map[string]interface{}{}, []map[string]interface{}{}
 }
@@ -0,0 +1,126 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	recordedMetalBGPSession = `{
+  "id": "8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b",
+  "status": "up",
+  "learned_routes": ["10.1.1.0/24"],
+  "address_family": "ipv4",
+  "default_route": true,
+  "device": {"id": "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f", "hostname": "tf-device", "href": "/metal/v1/devices/1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f"},
+  "href": "/metal/v1/bgp/sessions/8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b",
+  "created_at": "2023-06-01T10:00:00Z",
+  "updated_at": "2023-06-01T10:00:00Z"
+}`
+	recordedMetalBGPNeighbors = `{
+  "bgp_neighbors": [
+    {
+      "address_family": 4,
+      "customer_as": 65000,
+      "customer_ip": "10.70.12.3",
+      "md5_enabled": false,
+      "multihop": false,
+      "peer_as": 65530,
+      "peer_ips": ["169.254.255.1", "169.254.255.2"],
+      "routes_in": [{"route": "10.1.1.0/24", "exact": false}],
+      "routes_out": [{"route": "0.0.0.0/0", "exact": true}]
+    },
+    {
+      "address_family": 6,
+      "customer_as": 65000,
+      "customer_ip": "2604:1380::3",
+      "md5_enabled": false,
+      "multihop": false,
+      "peer_as": 65530,
+      "peer_ips": ["fc00::1"],
+      "routes_in": [{"route": "2604:1380:1::/64", "exact": false}],
+      "routes_out": []
+    }
+  ]
+}`
+)
+
+// packngoResourceMetalBGPSessionRead is the packngo implementation of
+// resourceMetalBGPSessionRead, kept to compare the states both write
+func packngoResourceMetalBGPSessionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	bgpSession, _, err := client.BGPSessions.Get(d.Id(),
+		&packngo.GetOptions{Includes: []string{"device"}})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] BGP Session (%s) not found, removing from state", d.Id())
+
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	defaultRoute := false
+	if bgpSession.DefaultRoute != nil {
+		if *(bgpSession.DefaultRoute) {
+			defaultRoute = true
+		}
+	}
+	d.Set("device_id", bgpSession.Device.ID)
+	d.Set("address_family", bgpSession.AddressFamily)
+	d.Set("status", bgpSession.Status)
+	d.Set("default_route", defaultRoute)
+	d.Set("learned_routes", bgpSession.LearnedRoutes)
+	d.SetId(bgpSession.ID)
+
+	neighbors, _, err := meta.(*config.Config).Metalgo.DevicesApi.GetBgpNeighborData(ctx, bgpSession.Device.ID).Execute()
+	if err != nil {
+		log.Printf("[WARN] Could not list BGP neighbors of device (%s), routes of BGP session (%s) are not refreshed: %s", bgpSession.Device.ID, d.Id(), err)
+		return nil
+	}
+	routesIn, routesOut := bgpSessionRoutes(neighbors, bgpSession.AddressFamily)
+	d.Set("routes_in", routesIn)
+	d.Set("routes_out", routesOut)
+
+	return nil
+}
+
+func TestResourceMetalBGPSessionRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalBGPSession(), "8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b", nil, map[string]string{
+		"GET /metal/v1/bgp/sessions/8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b":          recordedMetalBGPSession,
+		"GET /metal/v1/devices/1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f/bgp/neighbors": recordedMetalBGPNeighbors,
+	}, resourceMetalBGPSessionRead, packngoResourceMetalBGPSessionRead)
+
+	assert.Equal(t, "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f", state["device_id"])
+	assert.Equal(t, "0.0.0.0/0", state["routes_out.0.route"])
+}
+
+func TestResourceMetalBGPSessionRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := resourceMetalBGPSession().TestResourceData()
+	d.SetId("8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b")
+	diags := resourceMetalBGPSessionRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestResourceMetalBGPSessionDelete_gone(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := resourceMetalBGPSession().TestResourceData()
+	d.SetId("8e2f4c4a-6b5e-4f1d-9b1e-0f6c1d2e3a4b")
+	diags := resourceMetalBGPSessionDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+}
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
@@ -21,7 +23,7 @@ func TestAccMetalBGPSetup_basic(t *testing.T) {
 		CheckDestroy:      testAccMetalBGPSetupCheckDestroyed,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccMetalBGPSetupConfig_basic(rs),
+				Config: testAccMetalBGPSetupConfig_basic(rs, true),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrPair(
 						"equinix_metal_device.test", "id",
@@ -42,25 +44,38 @@ func TestAccMetalBGPSetup_basic(t *testing.T) {
 						"data.equinix_metal_device_bgp_neighbors.test", "bgp_neighbors.#", "2"),
 				),
 			},
+			{
+				// default_route is updated in place
+				Config: testAccMetalBGPSetupConfig_basic(rs, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("equinix_metal_bgp_session.test4", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"equinix_metal_bgp_session.test4", "default_route", "false"),
+				),
+			},
 			{
 				ResourceName:      "equinix_metal_bgp_session.test4",
 				ImportState:       true,
 				ImportStateVerify: true,
 				// TODO(ocobleseqx) status returns "unknown" first and "down" after refresh. Should we add WaitForStateContext for "down"/"up"?
-				ImportStateVerifyIgnore: []string{"status"},
+				ImportStateVerifyIgnore: []string{"status", "wait_for_up"},
 			},
 		},
 	})
 }
 
 func testAccMetalBGPSetupCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_bgp_session" {
 			continue
 		}
-		if _, _, err := client.BGPSessions.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.BGPApi.FindBgpSessionById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal BGPSession still exists")
 		}
 	}
@@ -68,7 +83,7 @@ func testAccMetalBGPSetupCheckDestroyed(s *terraform.State) error {
 	return nil
 }
 
-func testAccMetalBGPSetupConfig_basic(name string) string {
+func testAccMetalBGPSetupConfig_basic(name string, defaultRoute bool) string {
 	return fmt.Sprintf(`
 %s
 
@@ -94,7 +109,7 @@ resource "equinix_metal_device" "test" {
 resource "equinix_metal_bgp_session" "test4" {
 	device_id = "${equinix_metal_device.test.id}"
 	address_family = "ipv4"
-	default_route = true
+	default_route = %t
 }
 
 resource "equinix_metal_bgp_session" "test6" {
@@ -112,5 +127,5 @@ data "equinix_metal_device_bgp_neighbors" "test" {
 	  equinix_metal_bgp_session.test6
 	]
 }
-`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), name, testDeviceTerminationTime())
+`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), name, testDeviceTerminationTime(), defaultRoute)
 }
@@ -1,11 +1,14 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
@@ -14,9 +17,11 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 	"golang.org/x/exp/slices"
 )
 
@@ -37,6 +42,78 @@ var (
 	}
 )
 
+// metalConnection is an interconnection as the API returns it. metal-go models
+// the speeds of connections, ports and service tokens as an int32, which can't
+// hold the 5Gbps and 10Gbps speeds above, so connections are read and written
+// with metalRequest
+type metalConnection struct {
+	ID           string    `json:"id"`
+	ContactEmail string    `json:"contact_email"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Redundancy   string    `json:"redundancy"`
+	Facility     metalCode `json:"facility"`
+	Metro        metalCode `json:"metro"`
+	Type         string    `json:"type"`
+	Mode         *string   `json:"mode"`
+	Description  string    `json:"description"`
+	Organization struct {
+		ID string `json:"id"`
+	} `json:"organization"`
+	Speed  uint64                        `json:"speed"`
+	Token  string                        `json:"token"`
+	Tokens []metalConnectionServiceToken `json:"service_tokens"`
+	Tags   []string                      `json:"tags"`
+	Ports  []metalConnectionPort         `json:"ports"`
+}
+
+type metalConnectionPort struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Role            string `json:"role"`
+	Speed           uint64 `json:"speed"`
+	Status          string `json:"status"`
+	LinkStatus      string `json:"link_status"`
+	VirtualCircuits []struct {
+		ID      string `json:"id"`
+		VNID    int    `json:"vnid"`
+		Project struct {
+			ID string `json:"id"`
+		} `json:"project"`
+	} `json:"virtual_circuits"`
+}
+
+type metalConnectionServiceToken struct {
+	ID               string     `json:"id"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	MaxAllowedSpeed  uint64     `json:"max_allowed_speed"`
+	Role             string     `json:"role"`
+	ServiceTokenType string     `json:"service_token_type"`
+	State            string     `json:"state"`
+}
+
+type metalConnectionInput struct {
+	ContactEmail     string   `json:"contact_email,omitempty"`
+	Description      *string  `json:"description,omitempty"`
+	Facility         string   `json:"facility,omitempty"`
+	Metro            string   `json:"metro,omitempty"`
+	Mode             string   `json:"mode,omitempty"`
+	Name             string   `json:"name,omitempty"`
+	Redundancy       string   `json:"redundancy,omitempty"`
+	ServiceTokenType string   `json:"service_token_type,omitempty"`
+	Speed            uint64   `json:"speed,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Type             string   `json:"type,omitempty"`
+	VLANs            []int    `json:"vlans,omitempty"`
+}
+
+type metalConnectionUpdateInput struct {
+	Redundancy  string   `json:"redundancy,omitempty"`
+	Mode        *string  `json:"mode,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
 func speedStrToUint(speed string) (uint64, error) {
 	allowedStrings := []string{}
 	for _, allowedSpeed := range allowedSpeeds {
@@ -64,13 +141,13 @@ func resourceMetalConnection() *schema.Resource {
 	for _, allowedSpeed := range allowedSpeeds {
 		speeds = append(speeds, allowedSpeed.Str)
 	}
-	return &schema.Resource{
-		Read:   resourceMetalConnectionRead,
-		Create: resourceMetalConnectionCreate,
-		Delete: resourceMetalConnectionDelete,
-		Update: resourceMetalConnectionUpdate,
+	r := &schema.Resource{
+		ReadContext:   resourceMetalConnectionRead,
+		CreateContext: resourceMetalConnectionCreate,
+		DeleteContext: resourceMetalConnectionDelete,
+		UpdateContext: resourceMetalConnectionUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -87,7 +164,6 @@ func resourceMetalConnection() *schema.Resource {
 				Description:   "Facility where the connection will be created",
 				Deprecated:    "Use metro instead of facility.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
 				ConflictsWith: []string{"metro"},
-				ForceNew:      true,
 			},
 			"metro": {
 				Type:          schema.TypeString,
@@ -95,7 +171,6 @@ func resourceMetalConnection() *schema.Resource {
 				Computed:      true,
 				Description:   "Metro where the connection will be created",
 				ConflictsWith: []string{"facility"},
-				ForceNew:      true,
 				StateFunc:     converters.ToLowerIf,
 			},
 			"redundancy": {
@@ -103,8 +178,8 @@ func resourceMetalConnection() *schema.Resource {
 				Required:    true,
 				Description: "Connection redundancy - redundant or primary",
 				ValidateFunc: validation.StringInSlice([]string{
-					string(packngo.ConnectionRedundant),
-					string(packngo.ConnectionPrimary),
+					string(metalv1.INTERCONNECTIONREDUNDANCY_REDUNDANT),
+					string(metalv1.INTERCONNECTIONREDUNDANCY_PRIMARY),
 				}, false),
 			},
 			"contact_email": {
@@ -112,7 +187,7 @@ func resourceMetalConnection() *schema.Resource {
 				Description: "The preferred email used for communication and notifications about the Equinix Fabric interconnection. Required when using a Project API key. Optional and defaults to the primary user email address when using a User API key",
 				Optional:    true,
 				Computed:    true,
-				ForceNew:    true, // TODO(displague) packngo needs updating
+				ForceNew:    true,
 			},
 			"type": {
 				Type:        schema.TypeString,
@@ -120,8 +195,8 @@ func resourceMetalConnection() *schema.Resource {
 				Description: "Connection type - dedicated or shared",
 				ForceNew:    true,
 				ValidateFunc: validation.StringInSlice([]string{
-					string(packngo.ConnectionDedicated),
-					string(packngo.ConnectionShared),
+					string(metalv1.INTERCONNECTIONTYPE_DEDICATED),
+					string(metalv1.INTERCONNECTIONTYPE_SHARED),
 				}, false),
 			},
 			"project_id": {
@@ -147,8 +222,8 @@ func resourceMetalConnection() *schema.Resource {
 				Optional:    true,
 				Default:     "standard",
 				ValidateFunc: validation.StringInSlice([]string{
-					string(packngo.ConnectionModeStandard),
-					string(packngo.ConnectionModeTunnel),
+					string(metalv1.INTERCONNECTIONMODE_STANDARD),
+					string(metalv1.INTERCONNECTIONMODE_TUNNEL),
 				}, false),
 			},
 			"tags": {
@@ -201,35 +276,93 @@ func resourceMetalConnection() *schema.Resource {
 				Elem:        serviceTokenSchema(),
 			},
 		},
+		CustomizeDiff: metalConnectionPlacement.customizeDiff,
+		SchemaVersion: 1,
+	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Type:    resourceMetalConnectionResourceV0().CoreConfigSchema().ImpliedType(),
+			Upgrade: metalConnectionPlacement.stateUpgrade,
+			Version: 0,
+		},
+	}
+
+	return r
+}
+
+// resourceMetalConnectionResourceV0 is the connection schema of version 0,
+// before facility and metro could be switched in place. It only keeps what
+// makes up the state type and must not follow later changes to the schema.
+func resourceMetalConnectionResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"contact_email":   {Type: schema.TypeString, Optional: true, Computed: true},
+			"description":     {Type: schema.TypeString, Optional: true},
+			"facility":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"metro":           {Type: schema.TypeString, Optional: true, Computed: true},
+			"mode":            {Type: schema.TypeString, Optional: true},
+			"name":            {Type: schema.TypeString, Required: true},
+			"organization_id": {Type: schema.TypeString, Optional: true, Computed: true},
+			"ports": {Type: schema.TypeList, Computed: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"id":                  {Type: schema.TypeString, Computed: true},
+				"link_status":         {Type: schema.TypeString, Computed: true},
+				"name":                {Type: schema.TypeString, Computed: true},
+				"role":                {Type: schema.TypeString, Computed: true},
+				"speed":               {Type: schema.TypeInt, Computed: true},
+				"status":              {Type: schema.TypeString, Computed: true},
+				"virtual_circuit_ids": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			}}},
+			"project_id":         {Type: schema.TypeString, Optional: true},
+			"redundancy":         {Type: schema.TypeString, Required: true},
+			"service_token_type": {Type: schema.TypeString, Optional: true},
+			"service_tokens": {Type: schema.TypeList, Computed: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"expires_at":        {Type: schema.TypeString, Computed: true},
+				"id":                {Type: schema.TypeString, Computed: true},
+				"max_allowed_speed": {Type: schema.TypeString, Computed: true},
+				"role":              {Type: schema.TypeString, Computed: true},
+				"state":             {Type: schema.TypeString, Computed: true},
+				"type":              {Type: schema.TypeString, Computed: true},
+			}}},
+			"speed":  {Type: schema.TypeString, Optional: true, Computed: true},
+			"status": {Type: schema.TypeString, Computed: true},
+			"tags":   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"token":  {Type: schema.TypeString, Computed: true},
+			"type":   {Type: schema.TypeString, Required: true},
+			"vlans":  {Type: schema.TypeList, Optional: true, MaxItems: 2, Elem: &schema.Schema{Type: schema.TypeInt}},
+		},
 	}
 }
 
-func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// metalConnectionPlacement allows to switch the connection attributes between
+// a facility and its metro without replacing the connection
+var metalConnectionPlacement = facilityMetroAttributes{Facility: "facility", Metro: "metro"}
+
+func resourceMetalConnectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	facility, facOk := d.GetOk("facility")
 	metro, metOk := d.GetOk("metro")
 
 	if !(metOk || facOk) {
-		return fmt.Errorf("you must set either metro or facility")
+		return diag.Errorf("you must set either metro or facility")
 	}
 
-	connType := packngo.ConnectionType(d.Get("type").(string))
+	connType := d.Get("type").(string)
 
 	connMode := d.Get("mode").(string)
 
 	tokenTypeRaw, tokenTypeOk := d.GetOk("service_token_type")
-	tokenType := packngo.FabricServiceTokenType(tokenTypeRaw.(string))
+	tokenType := tokenTypeRaw.(string)
 
 	vlans := []int{}
 	vlansNum := d.Get("vlans.#").(int)
 	if vlansNum > 0 {
 		vlans = converters.IfArrToIntArr(d.Get("vlans").([]interface{}))
 	}
-	connRedundancy := packngo.ConnectionRedundancy(d.Get("redundancy").(string))
+	connRedundancy := d.Get("redundancy").(string)
 
-	connReq := packngo.ConnectionCreateRequest{
+	connReq := metalConnectionInput{
 		Name:       d.Get("name").(string),
 		Redundancy: connRedundancy,
 		Type:       connType,
@@ -244,13 +377,13 @@ func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) err
 
 	// missing speed is tolerated only for shared connections of type z_side
 	// https://github.com/equinix/terraform-provider-equinix/issues/276
-	if (connType == packngo.ConnectionDedicated) || (tokenType == "a_side") {
+	if (connType == string(metalv1.INTERCONNECTIONTYPE_DEDICATED)) || (tokenType == string(metalv1.FABRICSERVICETOKENSERVICETOKENTYPE_A_SIDE)) {
 		if !speedOk {
-			return fmt.Errorf("you must set speed, it's optional only for shared connections of type z_side")
+			return diag.Errorf("you must set speed, it's optional only for shared connections of type z_side")
 		}
 		speed, err := speedStrToUint(speedRaw.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		connReq.Speed = speed
 	}
@@ -274,69 +407,57 @@ func resourceMetalConnectionCreate(d *schema.ResourceData, meta interface{}) err
 		connReq.Description = &description
 	}
 
+	conn := &metalConnection{}
 	projectId, projectIdOk := d.GetOk("project_id")
-	if connType == packngo.ConnectionShared {
+	if connType == string(metalv1.INTERCONNECTIONTYPE_SHARED) {
 		if !projectIdOk {
-			return fmt.Errorf("you must set project_id for \"shared\" connection")
+			return diag.Errorf("you must set project_id for \"shared\" connection")
 		}
-		if connMode == string(packngo.ConnectionModeTunnel) {
-			return fmt.Errorf("tunnel mode is not supported for \"shared\" connections")
+		if connMode == string(metalv1.INTERCONNECTIONMODE_TUNNEL) {
+			return diag.Errorf("tunnel mode is not supported for \"shared\" connections")
 		}
-		if connRedundancy == packngo.ConnectionPrimary && vlansNum == 2 {
-			return fmt.Errorf("when you create a \"shared\" connection without redundancy, you must only set max 1 vlan")
+		if connRedundancy == string(metalv1.INTERCONNECTIONREDUNDANCY_PRIMARY) && vlansNum == 2 {
+			return diag.Errorf("when you create a \"shared\" connection without redundancy, you must only set max 1 vlan")
 		}
 		connReq.VLANs = vlans
 		connReq.ServiceTokenType = tokenType
-		conn, _, err := client.Connections.ProjectCreate(projectId.(string), &connReq)
+		resp, err := metalRequest(ctx, client, http.MethodPost, "projects/"+projectId.(string)+"/connections", connReq, conn)
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
-		d.SetId(conn.ID)
 	} else {
 		organizationId, organizationIdOk := d.GetOk("organization_id")
 		if !organizationIdOk {
 			if !projectIdOk {
-				return fmt.Errorf("you must set one of organization_id or project_id for \"dedicated\" connection")
+				return diag.Errorf("you must set one of organization_id or project_id for \"dedicated\" connection")
 			}
-			proj, _, err := client.Projects.Get(projectId.(string), &packngo.GetOptions{Includes: []string{"organization"}})
+			proj, resp, err := client.ProjectsApi.FindProjectById(ctx, projectId.(string)).Include([]string{"organization"}).Execute()
 			if err != nil {
-				return equinix_errors.FriendlyError(err)
+				return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 			}
-			organizationId = proj.Organization.ID
+			organizationId = proj.Organization.GetId()
 		}
 		if tokenTypeOk {
-			return fmt.Errorf("when you create a \"dedicated\" connection, you must not set service_token_type")
+			return diag.Errorf("when you create a \"dedicated\" connection, you must not set service_token_type")
 		}
 		if vlansNum > 0 {
-			return fmt.Errorf("when you create a \"dedicated\" connection, you must not set vlans")
+			return diag.Errorf("when you create a \"dedicated\" connection, you must not set vlans")
 		}
-		connReq.Mode = packngo.ConnectionMode(connMode)
-		conn, _, err := client.Connections.OrganizationCreate(organizationId.(string), &connReq)
+		connReq.Mode = connMode
+		resp, err := metalRequest(ctx, client, http.MethodPost, "organizations/"+organizationId.(string)+"/connections", connReq, conn)
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
-		d.SetId(conn.ID)
 	}
+	d.SetId(conn.ID)
 
-	return resourceMetalConnectionRead(d, meta)
+	return resourceMetalConnectionRead(ctx, d, meta)
 }
 
-func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalConnectionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	if d.HasChange("locked") {
-		var action func(string) (*packngo.Response, error)
-		if d.Get("locked").(bool) {
-			action = client.Devices.Lock
-		} else {
-			action = client.Devices.Unlock
-		}
-		if _, err := action(d.Id()); err != nil {
-			return equinix_errors.FriendlyError(err)
-		}
-	}
-	ur := packngo.ConnectionUpdateRequest{}
+	ur := metalConnectionUpdateInput{}
 
 	if d.HasChange("description") {
 		desc := d.Get("description").(string)
@@ -344,16 +465,15 @@ func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	if d.HasChange("mode") {
-		mode := packngo.ConnectionMode(d.Get("mode").(string))
+		mode := d.Get("mode").(string)
 		ur.Mode = &mode
 	}
 
 	if d.HasChange("redundancy") {
-		redundancy := packngo.ConnectionRedundancy(d.Get("redundancy").(string))
-		ur.Redundancy = redundancy
+		ur.Redundancy = d.Get("redundancy").(string)
 	}
 
-	// TODO(displague) packngo does not implement ContactEmail for update
+	// TODO(displague) the API does not implement ContactEmail for update
 	// if d.HasChange("contact_email" {}
 
 	if d.HasChange("tags") {
@@ -367,21 +487,21 @@ func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) err
 			}
 			ur.Tags = sts
 		default:
-			return equinix_errors.FriendlyError(fmt.Errorf("garbage in tags: %s", ts))
+			return diag.Errorf("garbage in tags: %s", ts)
 		}
 	}
 
-	if !reflect.DeepEqual(ur, packngo.ConnectionUpdateRequest{}) {
-		if _, _, err := client.Connections.Update(d.Id(), &ur, nil); err != nil {
-			return equinix_errors.FriendlyError(err)
+	if !reflect.DeepEqual(ur, metalConnectionUpdateInput{}) {
+		if resp, err := metalRequest(ctx, client, http.MethodPut, "connections/"+d.Id(), ur, nil); err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 
-	// Don't update VLANs until _after_ the main ConnectionUpdateRequest has succeeded
+	// Don't update VLANs until _after_ the main connection update has succeeded
 	if d.HasChange("vlans") {
-		connType := packngo.ConnectionType(d.Get("type").(string))
+		connType := d.Get("type").(string)
 
-		if connType == packngo.ConnectionShared {
+		if connType == string(metalv1.INTERCONNECTIONTYPE_SHARED) {
 			old, new := d.GetChange("vlans")
 			oldVlans := converters.IfArrToIntArr(old.([]interface{}))
 			newVlans := converters.IfArrToIntArr(new.([]interface{}))
@@ -393,52 +513,56 @@ func resourceMetalConnectionUpdate(d *schema.ResourceData, meta interface{}) err
 				if d.HasChange(fmt.Sprintf("vlans.%d", i)) {
 					if i+1 > len(newVlans) {
 						// The VNID was removed; unassign the old VNID
-						if _, _, err := updateHiddenVirtualCircuitVNID(client, ports[i].(map[string]interface{}), ""); err != nil {
-							return equinix_errors.FriendlyError(err)
+						if err := updateHiddenVirtualCircuitVNID(ctx, client, ports[i].(map[string]interface{}), ""); err != nil {
+							return diag.FromErr(err)
 						}
 					} else {
 						j := slices.Index(oldVlans, newVlans[i])
 						if j > i {
 							// The VNID was moved to a different list index; unassign the VNID for the old index so that it is available for reassignment
-							if _, _, err := updateHiddenVirtualCircuitVNID(client, ports[j].(map[string]interface{}), ""); err != nil {
-								return equinix_errors.FriendlyError(err)
+							if err := updateHiddenVirtualCircuitVNID(ctx, client, ports[j].(map[string]interface{}), ""); err != nil {
+								return diag.FromErr(err)
 							}
 						}
 						// Assign the VNID (whether it is new or moved) to the correct port
-						if _, _, err := updateHiddenVirtualCircuitVNID(client, ports[i].(map[string]interface{}), strconv.Itoa(newVlans[i])); err != nil {
-							return equinix_errors.FriendlyError(err)
+						if err := updateHiddenVirtualCircuitVNID(ctx, client, ports[i].(map[string]interface{}), strconv.Itoa(newVlans[i])); err != nil {
+							return diag.FromErr(err)
 						}
 					}
 				}
 			}
 		} else {
-			return fmt.Errorf("when you update a \"dedicated\" connection, you cannot set vlans")
+			return diag.Errorf("when you update a \"dedicated\" connection, you cannot set vlans")
 		}
 	}
 
-	return resourceMetalConnectionRead(d, meta)
+	return resourceMetalConnectionRead(ctx, d, meta)
 }
 
-func updateHiddenVirtualCircuitVNID(client *packngo.Client, port map[string]interface{}, newVNID string) (*packngo.VirtualCircuit, *packngo.Response, error) {
+func updateHiddenVirtualCircuitVNID(ctx context.Context, client *metalv1.APIClient, port map[string]interface{}, newVNID string) error {
 	// This function is used to update the implicit virtual circuits attached to a shared `metal_connection` resource
 	// Do not use this function for a non-shared `metal_connection`
 	vcids := (port["virtual_circuit_ids"]).([]interface{})
 	vcid := vcids[0].(string)
-	ucr := packngo.VCUpdateRequest{}
-	ucr.VirtualNetworkID = &newVNID
-	return client.VirtualCircuits.Update(vcid, &ucr, nil)
+	ucr := metalv1.VirtualCircuitUpdateInput{
+		VlanVirtualCircuitUpdateInput: &metalv1.VlanVirtualCircuitUpdateInput{Vnid: &newVNID},
+	}
+	_, resp, err := client.InterconnectionsApi.UpdateVirtualCircuit(ctx, vcid).VirtualCircuitUpdateInput(ucr).Execute()
+	return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 }
 
-func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func getMetalConnection(ctx context.Context, client *metalv1.APIClient, id string) (*metalConnection, *http.Response, error) {
+	conn := &metalConnection{}
+	resp, err := metalRequest(ctx, client, http.MethodGet, "connections/"+id+"?include=service_tokens,organization,facility,metro,project", nil, conn)
+	return conn, resp, err
+}
 
-	connId := d.Id()
-	conn, _, err := client.Connections.Get(
-		connId,
-		&packngo.GetOptions{Includes: []string{"service_tokens", "organization", "facility", "metro", "project"}})
+func resourceMetalConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	conn, resp, err := getMetalConnection(ctx, client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	d.SetId(conn.ID)
@@ -446,27 +570,27 @@ func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error
 	projectId := d.Get("project_id").(string)
 	// fix the project id get when it's added straight to the Connection API resource
 	// https://github.com/packethost/packngo/issues/317
-	if conn.Type == packngo.ConnectionShared {
+	if conn.Type == string(metalv1.INTERCONNECTIONTYPE_SHARED) {
 		projectId = conn.Ports[0].VirtualCircuits[0].Project.ID
 	}
 	mode := "standard"
 	if conn.Mode != nil {
-		mode = string(*conn.Mode)
+		mode = *conn.Mode
 	}
 	side := ""
 	if len(conn.Tokens) > 0 {
-		side = string(conn.Tokens[0].ServiceTokenType)
+		side = conn.Tokens[0].ServiceTokenType
 	}
 	speed := "0"
 	if conn.Speed > 0 {
 		speed, err = speedUintToStr(conn.Speed)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	serviceTokens, err := getServiceTokens(conn.Tokens)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	vlans := getConnectionVlans(conn)
@@ -474,7 +598,7 @@ func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error
 		d.Set("vlans", vlans)
 	}
 
-	return equinix_schema.SetMap(d, map[string]interface{}{
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
 		"organization_id":    conn.Organization.ID,
 		"project_id":         projectId,
 		"contact_email":      conn.ContactEmail,
@@ -492,15 +616,44 @@ func resourceMetalConnectionRead(d *schema.ResourceData, meta interface{}) error
 		"tags":               conn.Tags,
 		"service_tokens":     serviceTokens,
 		"service_token_type": side,
-	})
+	}))
 }
 
-func resourceMetalConnectionDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	resp, err := client.Connections.Delete(d.Id(), true)
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+func resourceMetalConnectionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	resp, err := metalRequest(ctx, client, http.MethodDelete, "connections/"+d.Id(), nil, nil)
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+
+	// the connection is removed in the background
+	deleteConf := &retry.StateChangeConf{
+		Pending:    []string{"deleting"},
+		Target:     []string{"deleted"},
+		Refresh:    metalConnectionDeletedRefreshFunc(ctx, client, d.Id()),
+		Timeout:    60 * time.Second,
+		MinTimeout: 2 * time.Second,
+	}
+	if _, err := deleteConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for connection %s to be deleted: %s", d.Id(), err)
 	}
 	return nil
 }
+
+func metalConnectionDeletedRefreshFunc(ctx context.Context, client *metalv1.APIClient, id string) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		conn, resp, err := getMetalConnection(ctx, client, id)
+		if err != nil {
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			if equinix_errors.IsNotFound(err) {
+				return id, "deleted", nil
+			}
+			return nil, "", err
+		}
+		return conn, conn.Status, nil
+	}
+}
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -44,13 +45,13 @@ func TestSpeedConversion(t *testing.T) {
 }
 
 func testAccMetalConnectionCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_connection" {
 			continue
 		}
-		if _, _, err := client.Connections.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := getMetalConnection(context.Background(), client, rs.Primary.ID); err == nil {
 			return fmt.Errorf("Metal Connection still exists")
 		}
 	}
@@ -0,0 +1,247 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMetalSharedConnectionID    = "6e5d4c3b-2a19-4f8e-9d7c-6b5a49382716"
+	testMetalDedicatedConnectionID = "7f6e5d4c-3b2a-4190-8e7d-6c5b4a392817"
+)
+
+var recordedMetalConnections = map[string]string{
+	"GET /metal/v1/connections/" + testMetalSharedConnectionID: `{
+	  "id": "6e5d4c3b-2a19-4f8e-9d7c-6b5a49382716",
+	  "name": "shared",
+	  "status": "active",
+	  "redundancy": "redundant",
+	  "type": "shared",
+	  "speed": 10000000000,
+	  "description": "to the cloud",
+	  "contact_email": "ops@example.com",
+	  "tags": ["prod"],
+	  "facility": {"code": "sv15"},
+	  "metro": {"code": "sv"},
+	  "organization": {"id": "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"},
+	  "service_tokens": [
+	    {"id": "tok-1", "expires_at": "2026-12-01T00:00:00Z", "max_allowed_speed": 10000000000, "role": "primary", "service_token_type": "a_side", "state": "inactive"},
+	    {"id": "tok-2", "max_allowed_speed": 5000000000, "role": "secondary", "service_token_type": "a_side", "state": "inactive"}
+	  ],
+	  "ports": [
+	    {"id": "port-2", "name": "secondary-port", "role": "secondary", "speed": 10000000000, "status": "active", "link_status": "up",
+	     "virtual_circuits": [{"id": "vc-2", "vnid": 1001, "project": {"id": "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}}]},
+	    {"id": "port-1", "name": "primary-port", "role": "primary", "speed": 10000000000, "status": "active", "link_status": "up",
+	     "virtual_circuits": [{"id": "vc-1", "vnid": 1000, "project": {"id": "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}}]}
+	  ]
+	}`,
+	"GET /metal/v1/connections/" + testMetalDedicatedConnectionID: `{
+	  "id": "7f6e5d4c-3b2a-4190-8e7d-6c5b4a392817",
+	  "name": "dedicated",
+	  "status": "requested",
+	  "redundancy": "primary",
+	  "type": "dedicated",
+	  "mode": "tunnel",
+	  "speed": 5000000000,
+	  "facility": {"code": "da11"},
+	  "metro": {"code": "da"},
+	  "organization": {"id": "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"},
+	  "ports": [
+	    {"id": "port-3", "name": "dedicated-port", "role": "primary", "speed": 5000000000, "status": "requested", "link_status": "down"}
+	  ]
+	}`,
+}
+
+// packngoResourceMetalConnectionRead is the packngo implementation of
+// resourceMetalConnectionRead, kept to compare the states both write
+func packngoResourceMetalConnectionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	conn, _, err := client.Connections.Get(
+		d.Id(),
+		&packngo.GetOptions{Includes: []string{"service_tokens", "organization", "facility", "metro", "project"}})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(conn.ID)
+
+	projectId := d.Get("project_id").(string)
+	if conn.Type == packngo.ConnectionShared {
+		projectId = conn.Ports[0].VirtualCircuits[0].Project.ID
+	}
+	mode := "standard"
+	if conn.Mode != nil {
+		mode = string(*conn.Mode)
+	}
+	side := ""
+	if len(conn.Tokens) > 0 {
+		side = string(conn.Tokens[0].ServiceTokenType)
+	}
+	speed := "0"
+	if conn.Speed > 0 {
+		speed, err = speedUintToStr(conn.Speed)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	tokens := []map[string]interface{}{}
+	for _, token := range conn.Tokens {
+		speed, err := speedUintToStr(token.MaxAllowedSpeed)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		rawToken := map[string]interface{}{
+			"id":                token.ID,
+			"max_allowed_speed": speed,
+			"role":              string(token.Role),
+			"state":             token.State,
+			"type":              string(token.ServiceTokenType),
+		}
+		if token.ExpiresAt != nil {
+			rawToken["expires_at"] = token.ExpiresAt.String()
+		}
+		tokens = append(tokens, rawToken)
+	}
+
+	order := map[packngo.ConnectionPortRole]int{
+		packngo.ConnectionPortPrimary:   0,
+		packngo.ConnectionPortSecondary: 1,
+	}
+	ports := make([]map[string]interface{}, len(conn.Ports))
+	rawVlans := make([]int, len(conn.Ports))
+	for _, p := range conn.Ports {
+		vcIDs := []string{}
+		for _, vc := range p.VirtualCircuits {
+			vcIDs = append(vcIDs, vc.ID)
+		}
+		ports[order[p.Role]] = map[string]interface{}{
+			"name":                p.Name,
+			"id":                  p.ID,
+			"role":                string(p.Role),
+			"speed":               p.Speed,
+			"status":              p.Status,
+			"link_status":         p.LinkStatus,
+			"virtual_circuit_ids": vcIDs,
+		}
+		if conn.Type == packngo.ConnectionShared {
+			rawVlans[order[p.Role]] = p.VirtualCircuits[0].VNID
+		}
+	}
+	var vlans []int
+	for _, v := range rawVlans {
+		if v > 0 {
+			vlans = append(vlans, v)
+		}
+	}
+	if vlans != nil {
+		d.Set("vlans", vlans)
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"organization_id":    conn.Organization.ID,
+		"project_id":         projectId,
+		"contact_email":      conn.ContactEmail,
+		"name":               conn.Name,
+		"description":        conn.Description,
+		"status":             conn.Status,
+		"redundancy":         conn.Redundancy,
+		"facility":           conn.Facility.Code,
+		"metro":              conn.Metro.Code,
+		"token":              conn.Token,
+		"type":               conn.Type,
+		"speed":              speed,
+		"ports":              ports,
+		"mode":               mode,
+		"tags":               conn.Tags,
+		"service_tokens":     tokens,
+		"service_token_type": side,
+	}))
+}
+
+func TestResourceMetalConnectionRead_recorded(t *testing.T) {
+	shared := assertMetalReadParity(t, resourceMetalConnection(), testMetalSharedConnectionID, nil, recordedMetalConnections,
+		resourceMetalConnectionRead, packngoResourceMetalConnectionRead)
+	assert.Equal(t, "10Gbps", shared["speed"])
+	assert.Equal(t, "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e", shared["project_id"])
+	assert.Equal(t, "primary-port", shared["ports.0.name"])
+	assert.Equal(t, "1000", shared["vlans.0"])
+	assert.Equal(t, "1001", shared["vlans.1"])
+	assert.Equal(t, "5Gbps", shared["service_tokens.1.max_allowed_speed"])
+	assert.Equal(t, "a_side", shared["service_token_type"])
+
+	dedicated := assertMetalReadParity(t, resourceMetalConnection(), testMetalDedicatedConnectionID, nil, recordedMetalConnections,
+		resourceMetalConnectionRead, packngoResourceMetalConnectionRead)
+	assert.Equal(t, "5Gbps", dedicated["speed"])
+	assert.Equal(t, "tunnel", dedicated["mode"])
+	assert.NotContains(t, dedicated, "vlans.0")
+}
+
+func TestDataSourceMetalConnectionRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceMetalConnection(), "", map[string]interface{}{
+		"connection_id": testMetalSharedConnectionID,
+	}, recordedMetalConnections, dataSourceMetalConnectionRead, func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		d.SetId(d.Get("connection_id").(string))
+		return packngoResourceMetalConnectionRead(ctx, d, meta)
+	})
+	assert.Equal(t, testMetalSharedConnectionID, state["id"])
+	assert.Equal(t, "2", state["service_tokens.#"])
+}
+
+func TestResourceMetalConnectionCreate_shared(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, recordedMetalConnections)
+	fake.responses["POST /metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e/connections"] = recordedMetalConnections["GET /metal/v1/connections/"+testMetalSharedConnectionID]
+
+	d := schema.TestResourceDataRaw(t, resourceMetalConnection().Schema, map[string]interface{}{
+		"name":               "shared",
+		"project_id":         "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e",
+		"metro":              "sv",
+		"type":               "shared",
+		"redundancy":         "redundant",
+		"speed":              "10Gbps",
+		"service_token_type": "a_side",
+		"vlans":              []interface{}{1000, 1001},
+	})
+	if diags := resourceMetalConnectionCreate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected create error: %v", diags)
+	}
+	assert.Equal(t, testMetalSharedConnectionID, d.Id())
+
+	body, _ := fake.request("POST /metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e/connections")
+	assert.JSONEq(t, `{
+		"name": "shared",
+		"metro": "sv",
+		"type": "shared",
+		"redundancy": "redundant",
+		"speed": 10000000000,
+		"service_token_type": "a_side",
+		"vlans": [1000, 1001]
+	}`, body, "the speed is sent in bits per second")
+}
+
+func TestResourceMetalConnectionDelete_waitsForRemoval(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"DELETE /metal/v1/connections/" + testMetalDedicatedConnectionID: "",
+	})
+	fake.queued["GET /metal/v1/connections/"+testMetalDedicatedConnectionID] = []string{
+		`{"id": "` + testMetalDedicatedConnectionID + `", "status": "deleting"}`,
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceMetalConnection().Schema, map[string]interface{}{})
+	d.SetId(testMetalDedicatedConnectionID)
+	if diags := resourceMetalConnectionDelete(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected delete error: %v", diags)
+	}
+	assert.Equal(t, []string{
+		"DELETE /metal/v1/connections/" + testMetalDedicatedConnectionID,
+		"GET /metal/v1/connections/" + testMetalDedicatedConnectionID,
+		"GET /metal/v1/connections/" + testMetalDedicatedConnectionID,
+	}, fake.requestOrder())
+}
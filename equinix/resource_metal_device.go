@@ -27,7 +27,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
@@ -41,7 +40,7 @@ var (
 )
 
 func resourceMetalDevice() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(30 * time.Minute),
@@ -93,7 +92,6 @@ func resourceMetalDevice() *schema.Resource {
 				Type:          schema.TypeString,
 				Description:   "Metro area for the new device. Conflicts with facilities",
 				Optional:      true,
-				ForceNew:      true,
 				ConflictsWith: []string{"facilities"},
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					if len(old) > 0 && new == "" {
@@ -113,7 +111,6 @@ func resourceMetalDevice() *schema.Resource {
 				Deprecated:  "Use metro instead of facilities.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
-				ForceNew:    true,
 				MinItems:    1,
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					fsRaw := d.Get("facilities")
@@ -335,17 +332,7 @@ func resourceMetalDevice() *schema.Resource {
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-			"storage": {
-				Type:        schema.TypeString,
-				Description: "JSON for custom partitioning. Only usable on reserved hardware. More information in in the [Custom Partitioning and RAID](https://metal.equinix.com/developers/docs/servers/custom-partitioning-raid/) doc",
-				Optional:    true,
-				ForceNew:    true,
-				StateFunc: func(v interface{}) string {
-					s, _ := structure.NormalizeJsonString(v)
-					return s
-				},
-				ValidateFunc: validation.StringIsJSON,
-			},
+			"storage": metalDeviceStorageSchema(),
 			"project_ssh_key_ids": {
 				Type:        schema.TypeList,
 				Description: "Array of IDs of the project SSH keys which should be added to the device. If you specify this array, only the listed project SSH keys (and any SSH keys for the users specified in user_ssh_key_ids) will be added. If no SSH keys are specified (both user_ssh_keys_ids and project_ssh_key_ids are empty lists or omitted), all parent project keys, parent project members keys and organization members keys will be included.  Project SSH keys can be created with the [equinix_metal_project_ssh_key](equinix_metal_project_ssh_key.md) resource",
@@ -455,8 +442,34 @@ func resourceMetalDevice() *schema.Resource {
 			customdiff.ForceNewIf("custom_data", reinstallDisabledAndNoChangesAllowed("custom_data")),
 			customdiff.ForceNewIf("operating_system", reinstallDisabled),
 			customdiff.ForceNewIf("user_data", reinstallDisabledAndNoChangesAllowed("user_data")),
+			validateMetalDeviceStorageDiff,
+			metalDevicePlacement.customizeDiff,
 		),
+		SchemaVersion: 2,
+	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Type:    resourceMetalDeviceResourceV0().CoreConfigSchema().ImpliedType(),
+			Upgrade: resourceMetalDeviceStateUpgradeV0,
+			Version: 0,
+		},
+		{
+			Type:    resourceMetalDeviceResourceV1().CoreConfigSchema().ImpliedType(),
+			Upgrade: metalDevicePlacement.stateUpgrade,
+			Version: 1,
+		},
 	}
+
+	return r
+}
+
+// metalDevicePlacement allows to switch the device attributes between the
+// facility the device is deployed in and its metro without replacing the device
+var metalDevicePlacement = facilityMetroAttributes{
+	Facility:         "facilities",
+	Metro:            "metro",
+	DeployedFacility: "deployed_facility",
 }
 
 // This method returns true if reinstall is disabled, and false if it is enabled.
@@ -509,8 +522,7 @@ func reinstallDisabledAndNoChangesAllowed(attribute string) customdiff.ResourceC
 }
 
 func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	createRequest := metalv1.CreateDeviceRequest{}
 
@@ -549,9 +561,9 @@ func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	start := time.Now()
 	projectID := d.Get("project_id").(string)
-	newDevice, _, err := client.DevicesApi.CreateDevice(ctx, projectID).CreateDeviceRequest(createRequest).Execute()
+	newDevice, resp, err := client.DevicesApi.CreateDevice(ctx, projectID).CreateDeviceRequest(createRequest).Execute()
 	if err != nil {
-		retErr := equinix_errors.FriendlyError(err)
+		retErr := equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		if equinix_errors.IsNotFound(retErr) {
 			retErr = fmt.Errorf("%s, make sure project \"%s\" exists", retErr, projectID)
 		}
@@ -569,8 +581,7 @@ func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	device, resp, err := client.DevicesApi.FindDeviceById(context.Background(), d.Id()).Include(deviceCommonIncludes).Execute()
 	if err != nil {
@@ -607,26 +618,15 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("project_id", device.Project.GetId())
 	d.Set("sos_hostname", device.GetSos())
 	if device.Storage != nil {
-		rawStorageBytes, err := json.Marshal(device.Storage)
-		if err != nil {
-			return diag.Errorf("[ERR] Error getting storage JSON string for device (%s): %s", d.Id(), err)
+		if err := d.Set("storage", flattenMetalDeviceStorage(device.Storage)); err != nil {
+			return diag.Errorf("[ERR] Error setting storage for device (%s): %s", d.Id(), err)
 		}
-
-		storageString, err := structure.NormalizeJsonString(string(rawStorageBytes))
-		if err != nil {
-			return diag.Errorf("[ERR] Error normalizing storage JSON string for device (%s): %s", d.Id(), err)
-		}
-		d.Set("storage", storageString)
 	}
 	if device.HardwareReservation != nil {
 		d.Set("deployed_hardware_reservation_id", device.HardwareReservation.GetId())
 	}
 
-	networkType, err := getNetworkType(device)
-	if err != nil {
-		return diag.Errorf("[ERR] Error computing network type for device (%s): %s", d.Id(), err)
-	}
-	d.Set("network_type", networkType)
+	d.Set("network_type", deviceNetworkType(device))
 
 	wfrd := "wait_for_reservation_deprovision"
 	if _, ok := d.GetOk(wfrd); !ok {
@@ -676,8 +676,7 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 }
 
 func resourceMetalDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	ur := metalv1.DeviceUpdateInput{}
 
@@ -730,8 +729,8 @@ func resourceMetalDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta
 
 	start := time.Now()
 	if !reflect.DeepEqual(ur, metalv1.DeviceUpdateInput{}) {
-		if _, _, err := client.DevicesApi.UpdateDevice(ctx, d.Id()).DeviceUpdateInput(ur).Execute(); err != nil {
-			return diag.FromErr(equinix_errors.FriendlyError(err))
+		if _, resp, err := client.DevicesApi.UpdateDevice(ctx, d.Id()).DeviceUpdateInput(ur).Execute(); err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 
@@ -767,8 +766,8 @@ func doReinstall(ctx context.Context, client *metalv1.APIClient, d *schema.Resou
 			DeprovisionFast: metalv1.PtrBool(reinstall_config["deprovision_fast"].(bool)),
 		}
 
-		if _, err := client.DevicesApi.PerformAction(ctx, d.Id()).DeviceActionInput(reinstallOptions).Execute(); err != nil {
-			return equinix_errors.FriendlyError(err)
+		if resp, err := client.DevicesApi.PerformAction(ctx, d.Id()).DeviceActionInput(reinstallOptions).Execute(); err != nil {
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		}
 
 		updateTimeout := d.Timeout(schema.TimeoutUpdate) - 30*time.Second - time.Since(start)
@@ -781,8 +780,7 @@ func doReinstall(ctx context.Context, client *metalv1.APIClient, d *schema.Resou
 }
 
 func resourceMetalDeviceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	fdvIf, fdvOk := d.GetOk("force_detach_volumes")
 	fdv := false
@@ -793,8 +791,11 @@ func resourceMetalDeviceDelete(ctx context.Context, d *schema.ResourceData, meta
 	start := time.Now()
 
 	resp, err := client.DevicesApi.DeleteDevice(ctx, d.Id()).ForceDelete(fdv).Execute()
-	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return diag.FromErr(equinix_errors.FriendlyError(err))
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	resId, resIdOk := d.GetOk("deployed_hardware_reservation_id")
@@ -821,15 +822,14 @@ func waitForActiveDevice(ctx context.Context, d *schema.ResourceData, meta inter
 		Pending: pending,
 		Target:  targets,
 		Refresh: func() (interface{}, string, error) {
-			meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-			client := meta.(*config.Config).Metalgo
+			client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-			device, _, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Include([]string{"project"}).Execute()
+			device, resp, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Include([]string{"project"}).Execute()
 			if err == nil {
 				retAttrVal := fmt.Sprint(device.GetState())
 				return retAttrVal, retAttrVal, nil
 			}
-			return "error", "error", err
+			return "error", "error", equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		},
 		Timeout:    timeout,
 		Delay:      10 * time.Second,
@@ -840,13 +840,12 @@ func waitForActiveDevice(ctx context.Context, d *schema.ResourceData, meta inter
 	state, err := waitForDeviceAttribute(ctx, d, stateConf)
 	if err != nil {
 		d.SetId("")
-		fErr := equinix_errors.FriendlyError(err)
-		if equinix_errors.IsForbidden(fErr) {
+		if equinix_errors.IsForbidden(err) {
 			// If the device doesn't get to the active state, we can't recover it from here.
 
 			return errors.New("provisioning time limit exceeded; the Equinix Metal team will investigate")
 		}
-		return fErr
+		return err
 	}
 
 	if state != "active" {
@@ -983,17 +982,8 @@ func setupDeviceCreateRequest(d *schema.ResourceData, createRequest deviceCreate
 		createRequest.SetTags(converters.IfArrToStringArr(d.Get("tags").([]interface{})))
 	}
 
-	if attr, ok := d.GetOk("storage"); ok {
-		s, err := structure.NormalizeJsonString(attr.(string))
-		if err != nil {
-			return diag.Errorf("storage param contains invalid JSON: %s", err)
-		}
-		var storage metalv1.Storage
-		err = json.Unmarshal([]byte(s), &storage)
-		if err != nil {
-			return diag.Errorf("error parsing Storage string: %s", err)
-		}
-		createRequest.SetStorage(storage)
+	if storage := expandMetalDeviceStorage(d.Get("storage").([]interface{})); storage != nil {
+		createRequest.SetStorage(*storage)
 	}
 
 	return nil
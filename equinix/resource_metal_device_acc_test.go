@@ -42,34 +42,34 @@ func testSweepDevices(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping devices: %s", err)
 	}
-	metal := config.NewMetalClient()
-	ps, _, err := metal.Projects.List(nil)
+	metal := config.NewMetalGoClient()
+	ps, err := metal.ProjectsApi.FindProjects(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting project list for sweepeing devices: %s", err)
 	}
 	pids := []string{}
-	for _, p := range ps {
-		if isSweepableTestResource(p.Name) {
-			pids = append(pids, p.ID)
+	for _, p := range ps.Projects {
+		if isSweepableTestResource(p.GetName()) {
+			pids = append(pids, p.GetId())
 		}
 	}
 	dids := []string{}
 	for _, pid := range pids {
-		ds, _, err := metal.Devices.List(pid, nil)
+		ds, err := metal.DevicesApi.FindProjectDevices(context.Background(), pid).ExecuteWithPagination()
 		if err != nil {
 			log.Printf("Error listing devices to sweep: %s", err)
 			continue
 		}
-		for _, d := range ds {
-			if isSweepableTestResource(d.Hostname) {
-				dids = append(dids, d.ID)
+		for _, d := range ds.Devices {
+			if isSweepableTestResource(d.GetHostname()) {
+				dids = append(dids, d.GetId())
 			}
 		}
 	}
 
 	for _, did := range dids {
 		log.Printf("Removing device %s", did)
-		_, err := metal.Devices.Delete(did, true)
+		_, err := metal.DevicesApi.DeleteDevice(context.Background(), did).ForceDelete(true).Execute()
 		if err != nil {
 			return fmt.Errorf("Error deleting device %s", err)
 		}
@@ -518,13 +518,13 @@ func TestAccMetalDevice_allowChangesErrorOnUnsupportedAttribute(t *testing.T) {
 }
 
 func testAccMetalDeviceCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_device" {
 			continue
 		}
-		if _, _, err := client.Devices.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.DevicesApi.FindDeviceById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal Device still exists")
 		}
 	}
@@ -1092,8 +1092,7 @@ func testAccWaitForMetalDeviceActive(project, deviceHostName string) resource.Im
 
 		meta := testAccProvider.Meta()
 		rd := new(schema.ResourceData)
-		meta.(*config.Config).AddModuleToMetalGoUserAgent(rd)
-		client := meta.(*config.Config).Metalgo
+		client := meta.(*config.Config).NewMetalClientForSDK(rd)
 		resp, _, err := client.DevicesApi.FindProjectDevices(context.TODO(), rs.Primary.ID).Search(deviceHostName).Execute()
 		if err != nil {
 			return "", fmt.Errorf("error while fetching devices for project [%s], error: %w", rs.Primary.ID, err)
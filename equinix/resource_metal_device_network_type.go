@@ -1,26 +1,30 @@
 package equinix
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strings"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	"github.com/equinix/terraform-provider-equinix/internal/network"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
 func resourceMetalDeviceNetworkType() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalDeviceNetworkTypeCreate,
-		Read:   resourceMetalDeviceNetworkTypeRead,
-		Delete: resourceMetalDeviceNetworkTypeDelete,
-		Update: resourceMetalDeviceNetworkTypeUpdate,
+		CreateContext: resourceMetalDeviceNetworkTypeCreate,
+		ReadContext:   resourceMetalDeviceNetworkTypeRead,
+		DeleteContext: resourceMetalDeviceNetworkTypeDelete,
+		UpdateContext: resourceMetalDeviceNetworkTypeUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -36,73 +40,252 @@ func resourceMetalDeviceNetworkType() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validation.StringInSlice(network.DeviceNetworkTypesHB, false),
 			},
+			"transition_steps": {
+				Type:        schema.TypeList,
+				Description: "Steps of the last planned network type change, in the order they are run",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
+		CustomizeDiff: resourceMetalDeviceNetworkTypeCustomizeDiff,
 	}
 }
 
-func getDevIDandNetworkType(d *schema.ResourceData, c *packngo.Client) (string, string, error) {
+func getDevIDandNetworkType(ctx context.Context, d *schema.ResourceData, c *metalv1.APIClient) (string, string, error) {
 	deviceID := d.Id()
 	if len(deviceID) == 0 {
 		deviceID = d.Get("device_id").(string)
 	}
 
-	dev, _, err := c.Devices.Get(deviceID, nil)
+	dev, resp, err := c.DevicesApi.FindDeviceById(ctx, deviceID).Execute()
 	if err != nil {
-		return "", "", err
+		return "", "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
 	}
-	devType := dev.GetNetworkType()
+	devType := deviceNetworkType(dev)
 
-	return dev.ID, devType, nil
+	return dev.GetId(), devType, nil
 }
 
-func getAndPossiblySetNetworkType(d *schema.ResourceData, c *packngo.Client, targetType string) error {
+func getAndPossiblySetNetworkType(ctx context.Context, d *schema.ResourceData, c *metalv1.APIClient, targetType string) error {
 	// "hybrid-bonded" is an alias for "layer3" with VLAN(s) connected. We use
 	// other resource for VLAN attachment, so we treat these two as equivalent
 	if targetType == "hybrid-bonded" {
 		targetType = "layer3"
 	}
-	devID, devType, err := getDevIDandNetworkType(d, c)
+	devID, devType, err := getDevIDandNetworkType(ctx, d, c)
 	if err != nil {
 		return err
 	}
 
 	if devType != targetType {
-		_, err := c.DevicePorts.DeviceToNetworkType(devID, targetType)
+		return deviceToNetworkType(ctx, c, devID, targetType)
+	}
+	return nil
+}
+
+// deviceToNetworkType converts the ports of the device to the network type
+// and checks the network type of the converted device
+func deviceToNetworkType(ctx context.Context, c *metalv1.APIClient, deviceID, targetType string) error {
+	device, resp, err := c.DevicesApi.FindDeviceById(ctx, deviceID).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	currentType := deviceNetworkType(device)
+	if err := convertDeviceNetworkType(ctx, c, device, targetType); err != nil {
+		return err
+	}
+
+	device, resp, err = c.DevicesApi.FindDeviceById(ctx, deviceID).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	if finalType := deviceNetworkType(device); finalType != targetType {
+		return fmt.Errorf("Failed to convert device %s from %s to %s. New type was %s", deviceID, currentType, targetType, finalType)
+	}
+	return nil
+}
+
+// convertDeviceNetworkType converts the ports of the device to the network
+// type, the same way as the Equinix Metal console does. The ports are bonded,
+// disbonded and converted in the order planned by
+// planDeviceNetworkTypeTransition.
+func convertDeviceNetworkType(ctx context.Context, c *metalv1.APIClient, device *metalv1.Device, targetType string) error {
+	bondPorts := devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_BOND_PORT)
+
+	bondAll := func(ports map[string]*metalv1.Port) error {
+		for _, name := range portNames(ports) {
+			if err := bondDevicePort(ctx, c, ports[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the physical ports need to be refreshed after the bond port changed
+	physicalPorts := func(names ...string) (map[string]*metalv1.Port, error) {
+		device, resp, err := c.DevicesApi.FindDeviceById(ctx, device.GetId()).Execute()
+		if err != nil {
+			return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		ports := devicePortsOfType(device, metalv1.PORTTYPE_NETWORK_PORT)
+		if len(names) == 0 {
+			return ports, nil
+		}
+		named := map[string]*metalv1.Port{}
+		for _, name := range names {
+			if port, ok := ports[name]; ok {
+				named[name] = port
+			}
+		}
+		return named, nil
+	}
+
+	switch metalv1.PortNetworkType(targetType) {
+	case metalv1.PORTNETWORKTYPE_LAYER3:
+		if err := bondAll(bondPorts); err != nil {
+			return err
+		}
+		if err := convertDevicePortToLayer3(ctx, c, device.GetId(), "bond0"); err != nil {
+			return err
+		}
+		ethPorts, err := physicalPorts()
 		if err != nil {
 			return err
 		}
+		return bondAll(ethPorts)
+	case metalv1.PORTNETWORKTYPE_HYBRID:
+		if err := bondAll(bondPorts); err != nil {
+			return err
+		}
+		if err := convertDevicePortToLayer3(ctx, c, device.GetId(), "bond0"); err != nil {
+			return err
+		}
+		oddPorts, err := physicalPorts("eth1", "eth3")
+		if err != nil {
+			return err
+		}
+		for _, name := range portNames(oddPorts) {
+			if err := disbondDevicePort(ctx, c, oddPorts[name], false); err != nil {
+				return err
+			}
+		}
+	case metalv1.PORTNETWORKTYPE_LAYER2_INDIVIDUAL:
+		if err := convertDevicePortToLayer2(ctx, c, device.GetId(), "bond0"); err != nil {
+			return err
+		}
+		for _, name := range portNames(bondPorts) {
+			if err := disbondDevicePort(ctx, c, bondPorts[name], true); err != nil {
+				return err
+			}
+		}
+	case metalv1.PORTNETWORKTYPE_LAYER2_BONDED:
+		for _, name := range portNames(bondPorts) {
+			if err := convertDevicePortToLayer2(ctx, c, device.GetId(), name); err != nil {
+				return err
+			}
+		}
+		ethPorts, err := physicalPorts()
+		if err != nil {
+			return err
+		}
+		return bondAll(ethPorts)
 	}
 	return nil
 }
 
-func resourceMetalDeviceNetworkTypeCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func bondDevicePort(ctx context.Context, c *metalv1.APIClient, port *metalv1.Port) error {
+	if port.Data.GetBonded() {
+		return nil
+	}
+	_, resp, err := c.PortsApi.BondPort(ctx, port.GetId()).BulkEnable(false).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
+}
 
-	ntype := d.Get("type").(string)
-	err := getAndPossiblySetNetworkType(d, client, ntype)
+func disbondDevicePort(ctx context.Context, c *metalv1.APIClient, port *metalv1.Port, bulkDisable bool) error {
+	if !port.Data.GetBonded() {
+		return nil
+	}
+	_, resp, err := c.PortsApi.DisbondPort(ctx, port.GetId()).BulkDisable(bulkDisable).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
+}
+
+// convertDevicePortToLayer2 converts the named port of the device to layer2,
+// unless it is in a layer2 mode already
+func convertDevicePortToLayer2(ctx context.Context, c *metalv1.APIClient, deviceID, name string) error {
+	port, err := getDevicePortByName(ctx, c, deviceID, name)
 	if err != nil {
 		return err
 	}
-	d.SetId(d.Get("device_id").(string))
-	return resourceMetalDeviceNetworkTypeRead(d, meta)
+	if strings.HasPrefix(string(port.GetNetworkType()), "layer2") {
+		return nil
+	}
+	_, resp, err := c.PortsApi.ConvertLayer2(ctx, port.GetId()).PortAssignInput(metalv1.PortAssignInput{}).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
 }
 
-func resourceMetalDeviceNetworkTypeRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// convertDevicePortToLayer3 converts the named port of the device to layer3,
+// unless it is in layer3 or hybrid mode already. Public IPv4, private IPv4
+// and public IPv6 addresses are requested for the port.
+func convertDevicePortToLayer3(ctx context.Context, c *metalv1.APIClient, deviceID, name string) error {
+	port, err := getDevicePortByName(ctx, c, deviceID, name)
+	if err != nil {
+		return err
+	}
+	if port.GetNetworkType() == metalv1.PORTNETWORKTYPE_LAYER3 || port.GetNetworkType() == metalv1.PORTNETWORKTYPE_HYBRID {
+		return nil
+	}
+	ips := []metalv1.PortConvertLayer3InputRequestIpsInner{
+		{AddressFamily: metalv1.PtrInt32(4), Public: metalv1.PtrBool(true)},
+		{AddressFamily: metalv1.PtrInt32(4), Public: metalv1.PtrBool(false)},
+		{AddressFamily: metalv1.PtrInt32(6), Public: metalv1.PtrBool(true)},
+	}
+	_, resp, err := c.PortsApi.ConvertLayer3(ctx, port.GetId()).PortConvertLayer3Input(metalv1.PortConvertLayer3Input{RequestIps: ips}).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
+}
+
+func getDevicePortByName(ctx context.Context, c *metalv1.APIClient, deviceID, name string) (*metalv1.Port, error) {
+	device, resp, err := c.DevicesApi.FindDeviceById(ctx, deviceID).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return devicePortByName(device, name)
+}
+
+func resourceMetalDeviceNetworkTypeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	_, devNType, err := getDevIDandNetworkType(d, client)
+	ntype := d.Get("type").(string)
+	err := getAndPossiblySetNetworkType(ctx, d, client, ntype)
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		return diag.FromErr(err)
+	}
+	d.SetId(d.Get("device_id").(string))
+	return resourceMetalDeviceNetworkTypeRead(ctx, d, meta)
+}
+
+func resourceMetalDeviceNetworkTypeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
+	_, devNType, err := getDevIDandNetworkType(ctx, d, client)
+	if err != nil {
 		if equinix_errors.IsNotFound(err) {
 			log.Printf("[WARN] Device (%s) for Network Type request not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 
-		return err
+		return diag.FromErr(err)
 	}
 
 	// if "hybrid-bonded" is set as desired state and current state is "layer3",
@@ -117,20 +300,19 @@ func resourceMetalDeviceNetworkTypeRead(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
-func resourceMetalDeviceNetworkTypeUpdate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalDeviceNetworkTypeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	ntype := d.Get("type").(string)
 	if d.HasChange("type") {
-		err := getAndPossiblySetNetworkType(d, client, ntype)
+		err := getAndPossiblySetNetworkType(ctx, d, client, ntype)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
-	return resourceMetalDeviceNetworkTypeRead(d, meta)
+	return resourceMetalDeviceNetworkTypeRead(ctx, d, meta)
 }
 
-func resourceMetalDeviceNetworkTypeDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceMetalDeviceNetworkTypeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return nil
 }
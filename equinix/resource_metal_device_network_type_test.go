@@ -0,0 +1,94 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalNetworkTypeDeviceID = "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f"
+
+// recordedMetalNetworkTypeDevice returns a device with the plan, the bonding
+// of bond0, eth0 and eth1 and, optionally, a management IP
+func recordedMetalNetworkTypeDevice(plan string, bond0, eth0, eth1, management bool) string {
+	return fmt.Sprintf(`{
+  "id": "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f",
+  "hostname": "tf-device",
+  "plan": {"slug": %q},
+  "network_ports": [
+    {"id": "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a", "name": "bond0", "type": "NetworkBondPort", "data": {"bonded": %t}},
+    {"id": "0a9b8c7d-6e5f-4a3b-8c2d-1e0f9a8b7c6d", "name": "eth0", "type": "NetworkPort", "data": {"bonded": %t}},
+    {"id": "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a", "name": "eth1", "type": "NetworkPort", "data": {"bonded": %t}}
+  ],
+  "ip_addresses": [
+    {"id": "7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b", "address_family": 4, "public": false, "management": %t, "network": "10.70.12.2", "cidr": 31}
+  ],
+  "href": "/metal/v1/devices/1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f"
+}`, plan, bond0, eth0, eth1, management)
+}
+
+// packngoResourceMetalDeviceNetworkTypeRead is the packngo implementation of
+// resourceMetalDeviceNetworkTypeRead, kept to compare the states both write
+func packngoResourceMetalDeviceNetworkTypeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	dev, _, err := client.Devices.Get(d.Id(), nil)
+	if err != nil {
+		err = packngoFriendlyError(err)
+
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] Device (%s) for Network Type request not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+	devNType := dev.GetNetworkType()
+
+	currentType := d.Get("type").(string)
+	if currentType == "hybrid-bonded" && devNType == "layer3" {
+		devNType = "hybrid-bonded"
+	}
+
+	d.Set("type", devNType)
+
+	return nil
+}
+
+func TestResourceMetalDeviceNetworkTypeRead_recorded(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		device     string
+		want       string
+	}{
+		{"layer3", "layer3", recordedMetalNetworkTypeDevice("c3.small.x86", true, true, true, true), "layer3"},
+		{"hybrid-bonded is kept", "hybrid-bonded", recordedMetalNetworkTypeDevice("c3.small.x86", true, true, true, true), "hybrid-bonded"},
+		{"hybrid", "hybrid", recordedMetalNetworkTypeDevice("c3.small.x86", true, true, false, true), "hybrid"},
+		{"layer2-bonded", "layer2-bonded", recordedMetalNetworkTypeDevice("c3.small.x86", true, true, true, false), "layer2-bonded"},
+		{"layer2-individual", "layer2-individual", recordedMetalNetworkTypeDevice("c3.small.x86", false, false, false, false), "layer2-individual"},
+		{"baremetal_0 plan", "hybrid", recordedMetalNetworkTypeDevice("baremetal_0", false, false, false, false), "layer3"},
+		{"baremetal_1e plan", "layer3", recordedMetalNetworkTypeDevice("baremetal_1e", true, true, true, true), "hybrid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := assertMetalReadParity(t, resourceMetalDeviceNetworkType(), recordedMetalNetworkTypeDeviceID, map[string]interface{}{
+				"device_id": recordedMetalNetworkTypeDeviceID,
+				"type":      tt.configured,
+			}, map[string]string{
+				"GET /metal/v1/devices/" + recordedMetalNetworkTypeDeviceID: tt.device,
+			}, resourceMetalDeviceNetworkTypeRead, packngoResourceMetalDeviceNetworkTypeRead)
+
+			assert.Equal(t, tt.want, state["type"])
+		})
+	}
+}
@@ -0,0 +1,527 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	matchDevicePath        = regexp.MustCompile(`^/dev/\S+$`)
+	matchPartitionSize     = regexp.MustCompile(`^[0-9]+[KMGTkmgt]?$`)
+	deviceRaidLevels       = []string{"0", "1", "5", "6", "10"}
+	deviceFilesystemFormat = []string{"ext2", "ext3", "ext4", "xfs", "btrfs", "vfat", "swap"}
+
+	// deviceRaidMinDevices is the number of devices each RAID level needs
+	deviceRaidMinDevices = map[string]int{"0": 2, "1": 2, "5": 3, "6": 4, "10": 4}
+)
+
+func metalDeviceStorageSchema() *schema.Schema {
+	devicePath := validation.StringMatch(matchDevicePath, "must be a device path such as /dev/sda")
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Custom partitioning and RAID layout. Only usable on reserved hardware. More information in in the [Custom Partitioning and RAID](https://metal.equinix.com/developers/docs/servers/custom-partitioning-raid/) doc",
+		Optional:    true,
+		ForceNew:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"disk": {
+					Type:        schema.TypeList,
+					Description: "Disks to partition",
+					Optional:    true,
+					ForceNew:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"device": {
+								Type:         schema.TypeString,
+								Description:  "Path of the disk, such as /dev/sda",
+								Required:     true,
+								ForceNew:     true,
+								ValidateFunc: devicePath,
+							},
+							"wipe_table": {
+								Type:        schema.TypeBool,
+								Description: "Whether to wipe the partition table of the disk before partitioning it",
+								Optional:    true,
+								ForceNew:    true,
+							},
+							"partition": {
+								Type:        schema.TypeList,
+								Description: "Partitions to create on the disk",
+								Optional:    true,
+								ForceNew:    true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"label": {
+											Type:        schema.TypeString,
+											Description: "Partition label",
+											Optional:    true,
+											ForceNew:    true,
+										},
+										"number": {
+											Type:         schema.TypeInt,
+											Description:  "Partition number, the partition device is the disk path followed by the number",
+											Required:     true,
+											ForceNew:     true,
+											ValidateFunc: validation.IntAtLeast(1),
+										},
+										"size": {
+											Type:         schema.TypeString,
+											Description:  "Partition size in sectors, or with a K, M, G or T unit suffix. \"0\" makes the partition use the rest of the disk",
+											Required:     true,
+											ForceNew:     true,
+											ValidateFunc: validation.StringMatch(matchPartitionSize, "must be a number of sectors, optionally followed by K, M, G or T"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"raid": {
+					Type:        schema.TypeList,
+					Description: "Software RAID arrays to assemble",
+					Optional:    true,
+					ForceNew:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:         schema.TypeString,
+								Description:  "Path of the RAID array device, such as /dev/md/ROOT",
+								Required:     true,
+								ForceNew:     true,
+								ValidateFunc: devicePath,
+							},
+							"level": {
+								Type:         schema.TypeString,
+								Description:  fmt.Sprintf("RAID level, one of %s", strings.Join(deviceRaidLevels, ", ")),
+								Required:     true,
+								ForceNew:     true,
+								ValidateFunc: validation.StringInSlice(deviceRaidLevels, false),
+							},
+							"devices": {
+								Type:        schema.TypeList,
+								Description: "Paths of the partitions or disks in the array",
+								Required:    true,
+								ForceNew:    true,
+								MinItems:    2,
+								Elem: &schema.Schema{
+									Type:         schema.TypeString,
+									ValidateFunc: devicePath,
+								},
+							},
+						},
+					},
+				},
+				"filesystem": {
+					Type:        schema.TypeList,
+					Description: "Filesystems to create and mount",
+					Optional:    true,
+					ForceNew:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"mount": {
+								Type:     schema.TypeList,
+								Required: true,
+								ForceNew: true,
+								MaxItems: 1,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"device": {
+											Type:         schema.TypeString,
+											Description:  "Path of the partition, disk or RAID array holding the filesystem",
+											Required:     true,
+											ForceNew:     true,
+											ValidateFunc: devicePath,
+										},
+										"format": {
+											Type:         schema.TypeString,
+											Description:  fmt.Sprintf("Filesystem format, one of %s", strings.Join(deviceFilesystemFormat, ", ")),
+											Required:     true,
+											ForceNew:     true,
+											ValidateFunc: validation.StringInSlice(deviceFilesystemFormat, false),
+										},
+										"point": {
+											Type:         schema.TypeString,
+											Description:  "Mount point of the filesystem, \"none\" for swap",
+											Required:     true,
+											ForceNew:     true,
+											ValidateFunc: validation.StringMatch(regexp.MustCompile(`^(/.*|none)$`), "must be an absolute path or \"none\""),
+										},
+										"options": {
+											Type:        schema.TypeList,
+											Description: "Mount options",
+											Optional:    true,
+											ForceNew:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+										},
+										"create_options": {
+											Type:        schema.TypeList,
+											Description: "Options passed to the command creating the filesystem, such as [\"-L\", \"ROOT\"]",
+											Optional:    true,
+											ForceNew:    true,
+											Elem:        &schema.Schema{Type: schema.TypeString},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandMetalDeviceStorage(raw []interface{}) *metalv1.Storage {
+	if len(raw) == 0 || raw[0] == nil {
+		return nil
+	}
+	m := raw[0].(map[string]interface{})
+	storage := metalv1.NewStorage()
+
+	for _, rd := range m["disk"].([]interface{}) {
+		disk := rd.(map[string]interface{})
+		d := metalv1.NewDisk()
+		d.SetDevice(disk["device"].(string))
+		if disk["wipe_table"].(bool) {
+			d.SetWipeTable(true)
+		}
+		for _, rp := range disk["partition"].([]interface{}) {
+			partition := rp.(map[string]interface{})
+			p := metalv1.NewPartition()
+			if label := partition["label"].(string); label != "" {
+				p.SetLabel(label)
+			}
+			p.SetNumber(int32(partition["number"].(int)))
+			p.SetSize(partition["size"].(string))
+			d.Partitions = append(d.Partitions, *p)
+		}
+		storage.Disks = append(storage.Disks, *d)
+	}
+
+	for _, rr := range m["raid"].([]interface{}) {
+		raid := rr.(map[string]interface{})
+		r := metalv1.NewRaid()
+		r.SetName(raid["name"].(string))
+		r.SetLevel(raid["level"].(string))
+		r.SetDevices(converters.IfArrToStringArr(raid["devices"].([]interface{})))
+		storage.Raid = append(storage.Raid, *r)
+	}
+
+	for _, rf := range m["filesystem"].([]interface{}) {
+		filesystem := rf.(map[string]interface{})
+		f := metalv1.NewFilesystem()
+		if mounts := filesystem["mount"].([]interface{}); len(mounts) > 0 && mounts[0] != nil {
+			mount := mounts[0].(map[string]interface{})
+			mt := metalv1.NewMount()
+			mt.SetDevice(mount["device"].(string))
+			mt.SetFormat(mount["format"].(string))
+			mt.SetPoint(mount["point"].(string))
+			if options := converters.IfArrToStringArr(mount["options"].([]interface{})); len(options) > 0 {
+				mt.SetOptions(options)
+			}
+			if createOptions := converters.IfArrToStringArr(mount["create_options"].([]interface{})); len(createOptions) > 0 {
+				mt.AdditionalProperties = map[string]interface{}{
+					"create": map[string]interface{}{"options": createOptions},
+				}
+			}
+			f.SetMount(*mt)
+		}
+		storage.Filesystems = append(storage.Filesystems, *f)
+	}
+
+	return storage
+}
+
+func flattenMetalDeviceStorage(storage *metalv1.Storage) []interface{} {
+	if storage == nil {
+		return nil
+	}
+
+	disks := make([]interface{}, 0, len(storage.Disks))
+	for _, d := range storage.Disks {
+		partitions := make([]interface{}, 0, len(d.Partitions))
+		for _, p := range d.Partitions {
+			partitions = append(partitions, map[string]interface{}{
+				"label":  p.GetLabel(),
+				"number": int(p.GetNumber()),
+				"size":   p.GetSize(),
+			})
+		}
+		disks = append(disks, map[string]interface{}{
+			"device":     d.GetDevice(),
+			"wipe_table": d.GetWipeTable(),
+			"partition":  partitions,
+		})
+	}
+
+	raids := make([]interface{}, 0, len(storage.Raid))
+	for _, r := range storage.Raid {
+		raids = append(raids, map[string]interface{}{
+			"name":    r.GetName(),
+			"level":   r.GetLevel(),
+			"devices": converters.StringArrToIfArr(r.GetDevices()),
+		})
+	}
+
+	filesystems := make([]interface{}, 0, len(storage.Filesystems))
+	for _, f := range storage.Filesystems {
+		mounts := []interface{}{}
+		if f.Mount != nil {
+			mounts = append(mounts, map[string]interface{}{
+				"device":         f.Mount.GetDevice(),
+				"format":         f.Mount.GetFormat(),
+				"point":          f.Mount.GetPoint(),
+				"options":        converters.StringArrToIfArr(f.Mount.GetOptions()),
+				"create_options": converters.StringArrToIfArr(mountCreateOptions(f.Mount)),
+			})
+		}
+		filesystems = append(filesystems, map[string]interface{}{
+			"mount": mounts,
+		})
+	}
+
+	return []interface{}{map[string]interface{}{
+		"disk":       disks,
+		"raid":       raids,
+		"filesystem": filesystems,
+	}}
+}
+
+// mountCreateOptions reads the options of the "create" object of a mount,
+// which the SDK model does not have a field for.
+func mountCreateOptions(mount *metalv1.Mount) []string {
+	create, ok := mount.AdditionalProperties["create"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	switch options := create["options"].(type) {
+	case []string:
+		return options
+	case []interface{}:
+		return converters.IfArrToStringArr(options)
+	}
+	return nil
+}
+
+// partitionDevicePath returns the device path of a partition. Disks whose
+// path ends in a digit, such as /dev/nvme0n1, use a "p" separator.
+func partitionDevicePath(disk string, number int32) string {
+	if last := disk[len(disk)-1]; last >= '0' && last <= '9' {
+		return fmt.Sprintf("%sp%d", disk, number)
+	}
+	return fmt.Sprintf("%s%d", disk, number)
+}
+
+// validateMetalDeviceStorage checks that the layout is consistent: partition
+// numbers and RAID names are unique, RAID arrays have enough devices for
+// their level and filesystems are on devices which the layout creates.
+func validateMetalDeviceStorage(storage *metalv1.Storage) error {
+	if storage == nil {
+		return nil
+	}
+	devices := map[string]bool{}
+
+	for _, d := range storage.Disks {
+		disk := d.GetDevice()
+		if devices[disk] {
+			return fmt.Errorf("storage disk %s is defined more than once", disk)
+		}
+		devices[disk] = true
+
+		for i, p := range d.Partitions {
+			path := partitionDevicePath(disk, p.GetNumber())
+			if devices[path] {
+				return fmt.Errorf("storage disk %s has more than one partition number %d", disk, p.GetNumber())
+			}
+			devices[path] = true
+			if p.GetSize() == "0" && i != len(d.Partitions)-1 {
+				return fmt.Errorf("storage disk %s partition %d uses the rest of the disk, it must be the last partition", disk, p.GetNumber())
+			}
+		}
+	}
+
+	for _, r := range storage.Raid {
+		name := r.GetName()
+		if devices[name] {
+			return fmt.Errorf("storage raid %s is defined more than once", name)
+		}
+		if min := deviceRaidMinDevices[r.GetLevel()]; len(r.Devices) < min {
+			return fmt.Errorf("storage raid %s is level %s, which needs at least %d devices, got %d", name, r.GetLevel(), min, len(r.Devices))
+		}
+		if len(storage.Disks) > 0 {
+			for _, device := range r.Devices {
+				if !devices[device] {
+					return fmt.Errorf("storage raid %s uses %s, which is not a disk or partition of the storage layout", name, device)
+				}
+			}
+		}
+		devices[name] = true
+	}
+
+	points := map[string]bool{}
+	for _, f := range storage.Filesystems {
+		if f.Mount == nil {
+			continue
+		}
+		device, point := f.Mount.GetDevice(), f.Mount.GetPoint()
+		if len(storage.Disks) > 0 && !devices[device] {
+			return fmt.Errorf("storage filesystem %s is on %s, which is not a disk, partition or raid of the storage layout", point, device)
+		}
+		if (f.Mount.GetFormat() == "swap") != (point == "none") {
+			return fmt.Errorf("storage filesystem on %s must use mount point \"none\" if and only if its format is swap", device)
+		}
+		if point != "none" && points[point] {
+			return fmt.Errorf("storage mount point %s is used more than once", point)
+		}
+		points[point] = true
+	}
+	return nil
+}
+
+func validateMetalDeviceStorageDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("storage") {
+		return nil
+	}
+	return validateMetalDeviceStorage(expandMetalDeviceStorage(d.Get("storage").([]interface{})))
+}
+
+// resourceMetalDeviceResourceV0 is the device schema of version 0, where
+// storage was a JSON string. It only keeps what makes up the state type and
+// must not follow later changes to the device schema.
+func resourceMetalDeviceResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_private_ipv4": {Type: schema.TypeString, Computed: true},
+			"access_public_ipv4":  {Type: schema.TypeString, Computed: true},
+			"access_public_ipv6":  {Type: schema.TypeString, Computed: true},
+			"always_pxe":          {Type: schema.TypeBool, Optional: true},
+			"behavior": {Type: schema.TypeList, Optional: true, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"allow_changes": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			}}},
+			"billing_cycle":                    {Type: schema.TypeString, Optional: true, Computed: true},
+			"created":                          {Type: schema.TypeString, Computed: true},
+			"custom_data":                      {Type: schema.TypeString, Optional: true},
+			"deployed_facility":                {Type: schema.TypeString, Computed: true},
+			"deployed_hardware_reservation_id": {Type: schema.TypeString, Computed: true},
+			"description":                      {Type: schema.TypeString, Optional: true},
+			"facilities":                       {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"force_detach_volumes":             {Type: schema.TypeBool, Optional: true},
+			"hardware_reservation_id":          {Type: schema.TypeString, Optional: true},
+			"hostname":                         {Type: schema.TypeString, Optional: true, Computed: true},
+			"ip_address": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"cidr":            {Type: schema.TypeInt, Optional: true},
+				"reservation_ids": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"type":            {Type: schema.TypeString, Required: true},
+			}}},
+			"ipxe_script_url": {Type: schema.TypeString, Optional: true},
+			"locked":          {Type: schema.TypeBool, Optional: true, Computed: true},
+			"metro":           {Type: schema.TypeString, Optional: true},
+			"network": {Type: schema.TypeList, Computed: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"address": {Type: schema.TypeString, Computed: true},
+				"cidr":    {Type: schema.TypeInt, Computed: true},
+				"family":  {Type: schema.TypeInt, Computed: true},
+				"gateway": {Type: schema.TypeString, Computed: true},
+				"public":  {Type: schema.TypeBool, Computed: true},
+			}}},
+			"network_type":     {Type: schema.TypeString, Computed: true},
+			"operating_system": {Type: schema.TypeString, Required: true},
+			"plan":             {Type: schema.TypeString, Required: true},
+			"ports": {Type: schema.TypeList, Computed: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"bonded": {Type: schema.TypeBool, Computed: true},
+				"id":     {Type: schema.TypeString, Computed: true},
+				"mac":    {Type: schema.TypeString, Computed: true},
+				"name":   {Type: schema.TypeString, Computed: true},
+				"type":   {Type: schema.TypeString, Computed: true},
+			}}},
+			"project_id":          {Type: schema.TypeString, Required: true},
+			"project_ssh_key_ids": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"reinstall": {Type: schema.TypeList, Optional: true, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"deprovision_fast": {Type: schema.TypeBool, Optional: true},
+				"enabled":          {Type: schema.TypeBool, Optional: true},
+				"preserve_data":    {Type: schema.TypeBool, Optional: true},
+			}}},
+			"root_password":                    {Type: schema.TypeString, Computed: true},
+			"sos_hostname":                     {Type: schema.TypeString, Computed: true},
+			"ssh_key_ids":                      {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"state":                            {Type: schema.TypeString, Computed: true},
+			"storage":                          {Type: schema.TypeString, Optional: true},
+			"tags":                             {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"termination_time":                 {Type: schema.TypeString, Optional: true},
+			"updated":                          {Type: schema.TypeString, Computed: true},
+			"user_data":                        {Type: schema.TypeString, Optional: true},
+			"user_ssh_key_ids":                 {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"wait_for_reservation_deprovision": {Type: schema.TypeBool, Optional: true},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+// resourceMetalDeviceResourceV1 is the device schema of version 1, where
+// storage became a typed block, before facilities and metro could be switched
+// in place. Like version 0, it must not follow later changes.
+func resourceMetalDeviceResourceV1() *schema.Resource {
+	r := resourceMetalDeviceResourceV0()
+	r.Schema["storage"] = &schema.Schema{Type: schema.TypeList, Optional: true, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+		"disk": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"device": {Type: schema.TypeString, Required: true},
+			"partition": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"label":  {Type: schema.TypeString, Optional: true},
+				"number": {Type: schema.TypeInt, Required: true},
+				"size":   {Type: schema.TypeString, Required: true},
+			}}},
+			"wipe_table": {Type: schema.TypeBool, Optional: true},
+		}}},
+		"filesystem": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"mount": {Type: schema.TypeList, Required: true, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"create_options": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"device":         {Type: schema.TypeString, Required: true},
+				"format":         {Type: schema.TypeString, Required: true},
+				"options":        {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				"point":          {Type: schema.TypeString, Required: true},
+			}}},
+		}}},
+		"raid": {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+			"devices": {Type: schema.TypeList, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"level":   {Type: schema.TypeString, Required: true},
+			"name":    {Type: schema.TypeString, Required: true},
+		}}},
+	}},
+	}
+	return r
+}
+
+// resourceMetalDeviceStateUpgradeV0 converts the storage JSON string of
+// version 0 states into the typed storage block.
+func resourceMetalDeviceStateUpgradeV0(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	raw, _ := rawState["storage"].(string)
+	if strings.TrimSpace(raw) == "" {
+		rawState["storage"] = []interface{}{}
+		return rawState, nil
+	}
+
+	var storage metalv1.Storage
+	if err := json.Unmarshal([]byte(raw), &storage); err != nil {
+		return nil, fmt.Errorf("error converting storage JSON of device %v: %w", rawState["id"], err)
+	}
+	rawState["storage"] = flattenMetalDeviceStorage(&storage)
+	return rawState, nil
+}
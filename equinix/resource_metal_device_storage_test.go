@@ -0,0 +1,213 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMetalDeviceStorageJSON = `{
+  "disks": [
+    {
+      "device": "/dev/sda",
+      "wipeTable": true,
+      "partitions": [
+        {"label": "BIOS", "number": 1, "size": "4096"},
+        {"label": "SWAP", "number": 2, "size": "3993600"},
+        {"label": "ROOT", "number": 3, "size": "0"}
+      ]
+    }
+  ],
+  "filesystems": [
+    {"mount": {"device": "/dev/sda3", "format": "ext4", "point": "/", "create": {"options": ["-L", "ROOT"]}}},
+    {"mount": {"device": "/dev/sda2", "format": "swap", "point": "none", "create": {"options": ["-L", "SWAP"]}}}
+  ]
+}`
+
+// recordedMetalDevice is a device provisioned with testMetalDeviceStorageJSON,
+// as returned by the API
+const recordedMetalDevice = `{
+  "id": "device-id",
+  "hostname": "storage",
+  "state": "active",
+  "billing_cycle": "hourly",
+  "created_at": "2024-03-01T12:00:00Z",
+  "updated_at": "2024-03-01T12:10:00Z",
+  "plan": {"slug": "c3.small.x86"},
+  "metro": {"code": "sv"},
+  "facility": {"code": "sv15"},
+  "operating_system": {"slug": "ubuntu_22_04"},
+  "project": {"id": "project-id"},
+  "network_ports": [],
+  "ip_addresses": [],
+  "storage": ` + testMetalDeviceStorageJSON + `
+}`
+
+func TestMetalDevice_readStorage(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/devices/device-id": recordedMetalDevice,
+	})
+	d := resourceMetalDevice().TestResourceData()
+	d.SetId("device-id")
+	if diags := resourceMetalDeviceRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+
+	partition := func(label string, number int, size string) map[string]interface{} {
+		return map[string]interface{}{"label": label, "number": number, "size": size}
+	}
+	filesystem := func(device, format, point string, createOptions ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"mount": []interface{}{map[string]interface{}{
+			"device":         device,
+			"format":         format,
+			"point":          point,
+			"create_options": createOptions,
+		}}}
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"hostname":         "storage",
+		"plan":             "c3.small.x86",
+		"metro":            "sv",
+		"operating_system": "ubuntu_22_04",
+		"billing_cycle":    "hourly",
+		"project_id":       "project-id",
+		"storage": []interface{}{map[string]interface{}{
+			"disk": []interface{}{map[string]interface{}{
+				"device":     "/dev/sda",
+				"wipe_table": true,
+				"partition": []interface{}{
+					partition("BIOS", 1, "4096"),
+					partition("SWAP", 2, "3993600"),
+					partition("ROOT", 3, "0"),
+				},
+			}},
+			"filesystem": []interface{}{
+				filesystem("/dev/sda3", "ext4", "/", "-L", "ROOT"),
+				filesystem("/dev/sda2", "swap", "none", "-L", "SWAP"),
+			},
+		}},
+	})
+
+	diff, err := resourceMetalDevice().Diff(context.Background(), d.State(), config, meta)
+	assert.NoError(t, err)
+	if diff != nil {
+		assert.Empty(t, diff.Attributes, "the storage read back from the API matches the configuration")
+	}
+}
+
+func TestMetalDevice_StateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":      "device-id",
+		"storage": testMetalDeviceStorageJSON,
+	}
+
+	state, err := resourceMetalDeviceStateUpgradeV0(context.Background(), rawState, nil)
+	assert.NoError(t, err)
+
+	storage := expandMetalDeviceStorage(state["storage"].([]interface{}))
+	assert.NoError(t, validateMetalDeviceStorage(storage))
+	assert.Len(t, storage.Disks, 1)
+	assert.Equal(t, "/dev/sda", storage.Disks[0].GetDevice())
+	assert.True(t, storage.Disks[0].GetWipeTable())
+	assert.Len(t, storage.Disks[0].Partitions, 3)
+	assert.Equal(t, "0", storage.Disks[0].Partitions[2].GetSize())
+	assert.Len(t, storage.Filesystems, 2)
+	assert.Equal(t, []string{"-L", "ROOT"}, mountCreateOptions(storage.Filesystems[0].Mount))
+
+	state, err = resourceMetalDeviceStateUpgradeV0(context.Background(), map[string]interface{}{"storage": ""}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, state["storage"])
+
+	_, err = resourceMetalDeviceStateUpgradeV0(context.Background(), map[string]interface{}{"storage": "{"}, nil)
+	assert.Error(t, err)
+}
+
+func TestMetalDevice_validateStorage(t *testing.T) {
+	disk := func(device string, sizes ...string) metalv1.Disk {
+		d := metalv1.Disk{Device: &device}
+		for i := range sizes {
+			d.Partitions = append(d.Partitions, metalv1.Partition{Number: metalv1.PtrInt32(int32(i + 1)), Size: &sizes[i]})
+		}
+		return d
+	}
+	mount := func(device, format, point string) metalv1.Filesystem {
+		return metalv1.Filesystem{Mount: &metalv1.Mount{Device: &device, Format: &format, Point: &point}}
+	}
+	raid := func(name, level string, devices ...string) metalv1.Raid {
+		return metalv1.Raid{Name: &name, Level: &level, Devices: devices}
+	}
+
+	tests := []struct {
+		name    string
+		storage metalv1.Storage
+		wantErr string
+	}{
+		{
+			name: "raid on nvme",
+			storage: metalv1.Storage{
+				Disks: []metalv1.Disk{disk("/dev/nvme0n1", "4G", "0"), disk("/dev/nvme1n1", "4G", "0")},
+				Raid:  []metalv1.Raid{raid("/dev/md/ROOT", "1", "/dev/nvme0n1p2", "/dev/nvme1n1p2")},
+				Filesystems: []metalv1.Filesystem{
+					mount("/dev/md/ROOT", "ext4", "/"),
+					mount("/dev/nvme0n1p1", "swap", "none"),
+				},
+			},
+		},
+		{
+			name:    "duplicate disk",
+			storage: metalv1.Storage{Disks: []metalv1.Disk{disk("/dev/sda", "0"), disk("/dev/sda", "0")}},
+			wantErr: "defined more than once",
+		},
+		{
+			name:    "rest of disk before last partition",
+			storage: metalv1.Storage{Disks: []metalv1.Disk{disk("/dev/sda", "0", "4G")}},
+			wantErr: "must be the last partition",
+		},
+		{
+			name: "too few raid devices",
+			storage: metalv1.Storage{
+				Disks: []metalv1.Disk{disk("/dev/sda", "0"), disk("/dev/sdb", "0")},
+				Raid:  []metalv1.Raid{raid("/dev/md/DATA", "5", "/dev/sda1", "/dev/sdb1")},
+			},
+			wantErr: "needs at least 3 devices",
+		},
+		{
+			name: "unknown filesystem device",
+			storage: metalv1.Storage{
+				Disks:       []metalv1.Disk{disk("/dev/sda", "0")},
+				Filesystems: []metalv1.Filesystem{mount("/dev/sdb1", "ext4", "/")},
+			},
+			wantErr: "not a disk, partition or raid",
+		},
+		{
+			name: "swap with mount point",
+			storage: metalv1.Storage{
+				Disks:       []metalv1.Disk{disk("/dev/sda", "0")},
+				Filesystems: []metalv1.Filesystem{mount("/dev/sda1", "swap", "/swap")},
+			},
+			wantErr: "if and only if its format is swap",
+		},
+		{
+			name: "duplicate mount point",
+			storage: metalv1.Storage{
+				Disks:       []metalv1.Disk{disk("/dev/sda", "4G", "0")},
+				Filesystems: []metalv1.Filesystem{mount("/dev/sda1", "ext4", "/"), mount("/dev/sda2", "xfs", "/")},
+			},
+			wantErr: "used more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetalDeviceStorage(&tt.storage)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
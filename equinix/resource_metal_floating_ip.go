@@ -0,0 +1,313 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMetalFloatingIP() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceMetalFloatingIPCreate,
+		ReadContext:   resourceMetalFloatingIPRead,
+		UpdateContext: resourceMetalFloatingIPUpdate,
+		DeleteContext: resourceMetalFloatingIPDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMetalFloatingIPImport,
+		},
+		CustomizeDiff: resourceMetalFloatingIPCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"reserved_ip_block_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "UUID of the reserved IP block the floating IP is from",
+			},
+			"address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsIPv4Address,
+				Description:  "The address of the floating IP in the reserved IP block. Defaults to the network address of the block, which must then be a /32 block",
+			},
+			"target_device_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the device the floating IP is assigned to. Changing it assigns the floating IP to the new device before it is unassigned from the old one",
+			},
+			"assignment_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the assignment of the floating IP to the target device",
+			},
+			"previous_assignment_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of the assignment of the floating IP to the previous target device, if it could not be removed yet. The next apply removes it",
+			},
+			"cidr_notation": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The floating IP in CIDR notation, e.g. 147.75.1.2/32",
+			},
+			"gateway": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The gateway of the floating IP",
+			},
+			"public": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag indicating whether the floating IP is addressable from the Internet",
+			},
+			"global": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag indicating whether the floating IP is global, i.e. assignable in any location",
+			},
+		},
+	}
+}
+
+// floatingIPLockID serialises the changes of the floating IPs of a block, so
+// that an IP is not assigned and unassigned by two runs at the same time
+func floatingIPLockID(blockID string) string {
+	return "floating-ip-" + blockID
+}
+
+func resourceMetalFloatingIPCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	blockID := d.Get("reserved_ip_block_id").(string)
+	block, err := getFloatingIPBlock(ctx, client, blockID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	address, err := floatingIPAddress(block, d.Get("address").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	lockID := floatingIPLockID(blockID)
+	mutexkv.Metal.Lock(lockID)
+	defer mutexkv.Metal.Unlock(lockID)
+
+	assignment, err := assignFloatingIP(ctx, client, address, d.Get("target_device_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", blockID, address))
+	d.Set("assignment_id", assignment.GetId())
+	return resourceMetalFloatingIPRead(ctx, d, meta)
+}
+
+// getFloatingIPBlock reads the reserved IP block the floating IPs are from
+func getFloatingIPBlock(ctx context.Context, client *metalv1.APIClient, blockID string, includes []string) (*metalv1.IPReservation, error) {
+	ip, resp, err := client.IPAddressesApi.FindIPAddressById(ctx, blockID).Include(includes).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error reading reserved IP block %s: %w", blockID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	if ip.IPReservation == nil {
+		return nil, fmt.Errorf("%s is not a reserved IP block", blockID)
+	}
+	return ip.IPReservation, nil
+}
+
+// floatingIPAddress returns the configured address of the floating IP after
+// checking that it is in the block, or the address of a /32 block
+func floatingIPAddress(block *metalv1.IPReservation, address string) (string, error) {
+	if address == "" {
+		if block.GetCidr() != 32 {
+			return "", fmt.Errorf("reserved IP block %s is a /%d block, set the address of the floating IP", block.GetId(), block.GetCidr())
+		}
+		return block.GetNetwork(), nil
+	}
+	_, network, err := net.ParseCIDR(fmt.Sprintf("%s/%d", block.GetNetwork(), block.GetCidr()))
+	if err != nil {
+		return "", fmt.Errorf("error parsing the network of reserved IP block %s: %w", block.GetId(), err)
+	}
+	if !network.Contains(net.ParseIP(address)) {
+		return "", fmt.Errorf("address %s is not in reserved IP block %s (%s)", address, block.GetId(), network)
+	}
+	return address, nil
+}
+
+// getFloatingIPAssignment reads the assignment of a floating IP to a device
+func getFloatingIPAssignment(ctx context.Context, client *metalv1.APIClient, assignmentID string) (*metalv1.IPAssignment, *http.Response, error) {
+	ip, resp, err := client.IPAddressesApi.FindIPAddressById(ctx, assignmentID).Execute()
+	if err != nil {
+		return nil, resp, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	if ip.IPAssignment == nil {
+		return nil, resp, fmt.Errorf("IP address %s is not an IP assignment", assignmentID)
+	}
+	return ip.IPAssignment, resp, nil
+}
+
+// assignFloatingIP assigns the address to the device and checks that the
+// assignment is in place before it returns
+func assignFloatingIP(ctx context.Context, client *metalv1.APIClient, address, deviceID string) (*metalv1.IPAssignment, error) {
+	input := metalv1.IPAssignmentInput{Address: address + "/32"}
+	created, resp, err := client.DevicesApi.CreateIPAssignment(ctx, deviceID).IPAssignmentInput(input).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("error assigning floating IP %s to device %s: %w", address, deviceID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	assignment, _, err := getFloatingIPAssignment(ctx, client, created.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("error verifying assignment %s of floating IP %s: %w", created.GetId(), address, err)
+	}
+	if assigned := path.Base(assignment.AssignedTo.GetHref()); assigned != deviceID || assignment.GetAddress() != address {
+		if err := unassignFloatingIP(ctx, client, assignment.GetId()); err != nil {
+			log.Printf("[WARN] Could not remove unexpected assignment %s of floating IP %s: %s", assignment.GetId(), address, err)
+		}
+		return nil, fmt.Errorf("assignment %s of floating IP %s is for %s on device %s, expected device %s", assignment.GetId(), address, assignment.GetAddress(), assigned, deviceID)
+	}
+	return assignment, nil
+}
+
+// unassignFloatingIP removes an assignment of a floating IP. An assignment
+// that is already gone is not an error.
+func unassignFloatingIP(ctx context.Context, client *metalv1.APIClient, assignmentID string) error {
+	resp, err := client.IPAddressesApi.DeleteIPAddress(ctx, assignmentID).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err)
+}
+
+// unassignPreviousFloatingIP removes the assignment to the device the floating
+// IP was moved away from. It stays in previous_assignment_id until it is gone,
+// so that a failed removal is retried by the next apply.
+func unassignPreviousFloatingIP(ctx context.Context, d *schema.ResourceData, client *metalv1.APIClient) error {
+	previous := d.Get("previous_assignment_id").(string)
+	if previous == "" {
+		return nil
+	}
+	if err := unassignFloatingIP(ctx, client, previous); err != nil {
+		return fmt.Errorf("error unassigning floating IP %s from its previous device, the next apply retries: %w", d.Get("address").(string), err)
+	}
+	d.Set("previous_assignment_id", "")
+	return nil
+}
+
+func resourceMetalFloatingIPRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	assignment, _, err := getFloatingIPAssignment(ctx, client, d.Get("assignment_id").(string))
+	if err != nil {
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] Floating IP (%s) is not assigned, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("address", assignment.GetAddress())
+	d.Set("target_device_id", path.Base(assignment.AssignedTo.GetHref()))
+	d.Set("cidr_notation", fmt.Sprintf("%s/%d", assignment.GetAddress(), assignment.GetCidr()))
+	d.Set("gateway", assignment.GetGateway())
+	d.Set("public", assignment.GetPublic())
+	d.Set("global", assignment.GetGlobalIp())
+	return nil
+}
+
+// resourceMetalFloatingIPCustomizeDiff plans the removal of an assignment to a
+// previous device that the last apply could not remove
+func resourceMetalFloatingIPCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("previous_assignment_id").(string) != "" {
+		return d.SetNew("previous_assignment_id", "")
+	}
+	return nil
+}
+
+// resourceMetalFloatingIPUpdate moves the floating IP to the new target
+// device. The IP is assigned to the new device before it is unassigned from
+// the old one, so that it is always routed to one of them.
+func resourceMetalFloatingIPUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	lockID := floatingIPLockID(d.Get("reserved_ip_block_id").(string))
+	mutexkv.Metal.Lock(lockID)
+	defer mutexkv.Metal.Unlock(lockID)
+
+	// The plan clears previous_assignment_id, it is kept in the state until
+	// the assignment is removed
+	previous, _ := d.GetChange("previous_assignment_id")
+	d.Set("previous_assignment_id", previous)
+
+	if d.HasChange("target_device_id") {
+		// the assignment left by an earlier move goes first, so that the IP
+		// is never assigned to more than two devices
+		if err := unassignPreviousFloatingIP(ctx, d, client); err != nil {
+			return diag.FromErr(err)
+		}
+
+		assignment, err := assignFloatingIP(ctx, client, d.Get("address").(string), d.Get("target_device_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("previous_assignment_id", d.Get("assignment_id"))
+		d.Set("assignment_id", assignment.GetId())
+	}
+
+	if err := unassignPreviousFloatingIP(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceMetalFloatingIPRead(ctx, d, meta)
+}
+
+func resourceMetalFloatingIPDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	lockID := floatingIPLockID(d.Get("reserved_ip_block_id").(string))
+	mutexkv.Metal.Lock(lockID)
+	defer mutexkv.Metal.Unlock(lockID)
+
+	if err := unassignPreviousFloatingIP(ctx, d, client); err != nil {
+		return diag.FromErr(err)
+	}
+	return diag.FromErr(unassignFloatingIP(ctx, client, d.Get("assignment_id").(string)))
+}
+
+// resourceMetalFloatingIPImport imports a floating IP by the id of its
+// reserved IP block and its address, joined by a colon
+func resourceMetalFloatingIPImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	blockID, address, ok := strings.Cut(d.Id(), ":")
+	if !ok || blockID == "" || address == "" {
+		return nil, fmt.Errorf("invalid import id %q, expected <reserved_ip_block_id>:<address>", d.Id())
+	}
+
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	block, err := getFloatingIPBlock(ctx, client, blockID, []string{"assignments"})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, assignment := range block.Assignments {
+		if assignment.GetAddress() == address {
+			d.Set("reserved_ip_block_id", blockID)
+			d.Set("assignment_id", assignment.GetId())
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+	return nil, fmt.Errorf("address %s of reserved IP block %s is not assigned to a device", address, blockID)
+}
@@ -0,0 +1,208 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFloatingIPBlockID     = "8e1a9b6c-3d4f-4a2b-9c8d-1e2f3a4b5c6d"
+	testFloatingIPDevice1     = "1f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f1"
+	testFloatingIPDevice2     = "2f2e3d4c-5b6a-4978-8695-a4b3c2d1e0f2"
+	testFloatingIPAssignment1 = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c51"
+	testFloatingIPAssignment2 = "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c52"
+)
+
+func recordedFloatingIPAssignment(assignmentID, deviceID string) string {
+	return fmt.Sprintf(`{
+  "id": %q,
+  "type": "IPAssignment",
+  "address": "147.75.1.2",
+  "network": "147.75.1.2",
+  "gateway": "147.75.1.2",
+  "cidr": 32,
+  "public": true,
+  "global_ip": false,
+  "assigned_to": {"href": "/metal/v1/devices/%s"},
+  "href": "/metal/v1/ips/%s"
+}`, assignmentID, deviceID, assignmentID)
+}
+
+func TestResourceMetalFloatingIPUpdate_assignBeforeUnassign(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips": recordedFloatingIPAssignment(testFloatingIPAssignment2, testFloatingIPDevice2),
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2:           recordedFloatingIPAssignment(testFloatingIPAssignment2, testFloatingIPDevice2),
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1:        "",
+	})
+
+	r := resourceMetalFloatingIP()
+	state := &terraform.InstanceState{
+		ID: testFloatingIPBlockID + ":147.75.1.2",
+		Attributes: map[string]string{
+			"id":                   testFloatingIPBlockID + ":147.75.1.2",
+			"reserved_ip_block_id": testFloatingIPBlockID,
+			"address":              "147.75.1.2",
+			"target_device_id":     testFloatingIPDevice1,
+			"assignment_id":        testFloatingIPAssignment1,
+		},
+	}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"reserved_ip_block_id": testFloatingIPBlockID,
+		"target_device_id":     testFloatingIPDevice2,
+	}), meta)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew(), "moving the floating IP keeps it")
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	assert.NoError(t, err)
+
+	diags := resourceMetalFloatingIPUpdate(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, []string{
+		"POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips",
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2,
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1,
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2,
+	}, fake.requestOrder())
+	body, _ := fake.request("POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips")
+	assert.JSONEq(t, `{"address":"147.75.1.2/32"}`, body)
+
+	assert.Equal(t, testFloatingIPAssignment2, d.Get("assignment_id"))
+	assert.Equal(t, testFloatingIPDevice2, d.Get("target_device_id"))
+	assert.Equal(t, "147.75.1.2/32", d.Get("cidr_notation"))
+}
+
+func TestResourceMetalFloatingIPUpdate_failedAssignmentKeepsOld(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{})
+
+	r := resourceMetalFloatingIP()
+	state := &terraform.InstanceState{
+		ID: testFloatingIPBlockID + ":147.75.1.2",
+		Attributes: map[string]string{
+			"id":                   testFloatingIPBlockID + ":147.75.1.2",
+			"reserved_ip_block_id": testFloatingIPBlockID,
+			"address":              "147.75.1.2",
+			"target_device_id":     testFloatingIPDevice1,
+			"assignment_id":        testFloatingIPAssignment1,
+		},
+	}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"reserved_ip_block_id": testFloatingIPBlockID,
+		"target_device_id":     testFloatingIPDevice2,
+	}), meta)
+	assert.NoError(t, err)
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	assert.NoError(t, err)
+
+	diags := resourceMetalFloatingIPUpdate(context.Background(), d, meta)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, []string{"POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips"}, fake.requestOrder(), "the old assignment is not removed")
+	assert.Equal(t, testFloatingIPAssignment1, d.Get("assignment_id"))
+}
+
+func TestResourceMetalFloatingIPUpdate_failedUnassignIsRetried(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips": recordedFloatingIPAssignment(testFloatingIPAssignment2, testFloatingIPDevice2),
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2:           recordedFloatingIPAssignment(testFloatingIPAssignment2, testFloatingIPDevice2),
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1:        `{"errors":["Internal server error"]}`,
+	})
+	fake.statuses["DELETE /metal/v1/ips/"+testFloatingIPAssignment1] = http.StatusInternalServerError
+
+	r := resourceMetalFloatingIP()
+	state := &terraform.InstanceState{
+		ID: testFloatingIPBlockID + ":147.75.1.2",
+		Attributes: map[string]string{
+			"id":                   testFloatingIPBlockID + ":147.75.1.2",
+			"reserved_ip_block_id": testFloatingIPBlockID,
+			"address":              "147.75.1.2",
+			"target_device_id":     testFloatingIPDevice1,
+			"assignment_id":        testFloatingIPAssignment1,
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"reserved_ip_block_id": testFloatingIPBlockID,
+		"target_device_id":     testFloatingIPDevice2,
+	})
+	diff, err := r.Diff(context.Background(), state, config, meta)
+	assert.NoError(t, err)
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	assert.NoError(t, err)
+
+	diags := resourceMetalFloatingIPUpdate(context.Background(), d, meta)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, testFloatingIPAssignment2, d.Get("assignment_id"), "the state points to the new assignment")
+	assert.Equal(t, testFloatingIPAssignment1, d.Get("previous_assignment_id"), "the old assignment is recorded")
+
+	// the next apply retries the removal of the old assignment
+	state = d.State()
+	state.Attributes["target_device_id"] = testFloatingIPDevice2
+	diff, err = r.Diff(context.Background(), state, config, meta)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, diff) {
+		t.Fatalf("no change is planned for the old assignment")
+	}
+	assert.Equal(t, "", diff.Attributes["previous_assignment_id"].New)
+	assert.False(t, diff.RequiresNew())
+
+	delete(fake.statuses, "DELETE /metal/v1/ips/"+testFloatingIPAssignment1)
+	fake.responses["DELETE /metal/v1/ips/"+testFloatingIPAssignment1] = ""
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	assert.NoError(t, err)
+	diags = resourceMetalFloatingIPUpdate(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, testFloatingIPAssignment2, d.Get("assignment_id"))
+	assert.Equal(t, "", d.Get("previous_assignment_id"))
+	assert.Equal(t, []string{
+		"POST /metal/v1/devices/" + testFloatingIPDevice2 + "/ips",
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2,
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1,
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1,
+		"GET /metal/v1/ips/" + testFloatingIPAssignment2,
+	}, fake.requestOrder(), "the new assignment is not made again")
+}
+
+func TestResourceMetalFloatingIPDelete_previousAssignment(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1: "",
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment2: "",
+	})
+
+	d := resourceMetalFloatingIP().TestResourceData()
+	d.SetId(testFloatingIPBlockID + ":147.75.1.2")
+	d.Set("reserved_ip_block_id", testFloatingIPBlockID)
+	d.Set("assignment_id", testFloatingIPAssignment2)
+	d.Set("previous_assignment_id", testFloatingIPAssignment1)
+
+	diags := resourceMetalFloatingIPDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, []string{
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment1,
+		"DELETE /metal/v1/ips/" + testFloatingIPAssignment2,
+	}, fake.requestOrder())
+}
+
+func Test_floatingIPAddress(t *testing.T) {
+	block32 := &metalv1.IPReservation{Id: metalv1.PtrString("b32"), Network: metalv1.PtrString("147.75.1.2"), Cidr: metalv1.PtrInt32(32)}
+	block29 := &metalv1.IPReservation{Id: metalv1.PtrString("b29"), Network: metalv1.PtrString("147.75.2.8"), Cidr: metalv1.PtrInt32(29)}
+
+	address, err := floatingIPAddress(block32, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "147.75.1.2", address)
+
+	address, err = floatingIPAddress(block29, "147.75.2.12")
+	assert.NoError(t, err)
+	assert.Equal(t, "147.75.2.12", address)
+
+	_, err = floatingIPAddress(block29, "")
+	assert.ErrorContains(t, err, "set the address of the floating IP")
+
+	_, err = floatingIPAddress(block29, "147.75.2.16")
+	assert.ErrorContains(t, err, "is not in reserved IP block b29")
+}
@@ -1,17 +1,22 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"net/netip"
 	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 var subnetSizes = []int{8, 16, 32, 64, 128}
@@ -37,11 +42,11 @@ func intInSlice(valid []int) schema.SchemaValidateFunc {
 
 func resourceMetalGateway() *schema.Resource {
 	return &schema.Resource{
-		Read:   resourceMetalGatewayRead,
-		Create: resourceMetalGatewayCreate,
-		Delete: resourceMetalGatewayDelete,
+		ReadContext:   resourceMetalGatewayRead,
+		CreateContext: resourceMetalGatewayCreate,
+		DeleteContext: resourceMetalGatewayDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -94,105 +99,172 @@ func resourceMetalGateway() *schema.Resource {
 				Description: "Status of the gateway resource",
 			},
 		},
+		CustomizeDiff: validateGatewayIPReservation,
 	}
 }
 
-func resourceMetalGatewayCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// Metal Gateways only accept IPv4 reservations with a prefix length between
+// /8 and /29. The longest accepted prefix, /29, is a block of 8 addresses
+var gatewayIPv4Sizes = equinix_validation.PrefixLengthRange{Min: 8, Max: 29}
+
+// validateGatewayIPReservation looks up an already existing ip_reservation_id
+// and checks its address family and size before the gateway is planned.
+func validateGatewayIPReservation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	reservationID := d.Get("ip_reservation_id").(string)
+	if !d.NewValueKnown("ip_reservation_id") || reservationID == "" || !d.HasChange("ip_reservation_id") {
+		return nil
+	}
+
+	client := meta.(*config.Config).Metalgo
+	ip, _, err := client.IPAddressesApi.FindIPAddressById(ctx, reservationID).Execute()
+	if err != nil {
+		log.Printf("[WARN] Could not read IP reservation (%s) to validate gateway: %s", reservationID, err)
+		return nil
+	}
+	reservation, ok := ip.GetActualInstance().(interface {
+		GetNetwork() string
+		GetCidr() int32
+	})
+	if !ok {
+		log.Printf("[WARN] IP reservation (%s) has no network, the gateway is not validated", reservationID)
+		return nil
+	}
+
+	prefix, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", reservation.GetNetwork(), reservation.GetCidr()))
+	if err != nil {
+		log.Printf("[WARN] Could not parse IP reservation (%s) network: %s", reservationID, err)
+		return nil
+	}
+	if err := equinix_validation.CheckPrefixLength(prefix, gatewayIPv4Sizes, equinix_validation.PrefixLengthRange{}); err != nil {
+		return fmt.Errorf("ip_reservation_id %s can not be used for a Metal Gateway: %w", reservationID, err)
+	}
+	return nil
+}
+
+// metalGatewayIncludes are the relations of the gateway read into the state
+var metalGatewayIncludes = []string{"project", "ip_reservation", "virtual_network", "vrf"}
+
+func resourceMetalGatewayCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	_, hasIPReservation := d.GetOk("ip_reservation_id")
 	_, hasSubnetSize := d.GetOk("private_ipv4_subnet_size")
 	if !(hasIPReservation || hasSubnetSize) {
-		return fmt.Errorf("You must set either ip_reservation_id or private_ipv4_subnet_size")
+		return diag.Errorf("You must set either ip_reservation_id or private_ipv4_subnet_size")
 	}
 
-	mgcr := packngo.MetalGatewayCreateRequest{
-		VirtualNetworkID:      d.Get("vlan_id").(string),
-		IPReservationID:       d.Get("ip_reservation_id").(string),
-		PrivateIPv4SubnetSize: d.Get("private_ipv4_subnet_size").(int),
+	// VRF IP reservations are passed in the same input, the API tells the
+	// type of the gateway from the reservation
+	input := metalv1.MetalGatewayCreateInput{
+		VirtualNetworkId: d.Get("vlan_id").(string),
+	}
+	if reservationID := d.Get("ip_reservation_id").(string); reservationID != "" {
+		input.IpReservationId = metalv1.PtrString(reservationID)
+	}
+	if size := d.Get("private_ipv4_subnet_size").(int); size != 0 {
+		input.PrivateIpv4SubnetSize = metalv1.PtrInt32(int32(size))
 	}
 	projectId := d.Get("project_id").(string)
 
-	mg, _, err := client.MetalGateways.Create(projectId, &mgcr)
+	mg, resp, err := client.MetalGatewaysApi.CreateMetalGateway(ctx, projectId).
+		CreateMetalGatewayRequest(metalv1.CreateMetalGatewayRequest{MetalGatewayCreateInput: &input}).
+		Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	d.SetId(mg.ID)
+	d.SetId(metalGatewayID(mg))
+
+	return resourceMetalGatewayRead(ctx, d, meta)
+}
 
-	return resourceMetalGatewayRead(d, meta)
+func metalGatewayID(mg *metalv1.FindMetalGatewayById200Response) string {
+	if mg.VrfMetalGateway != nil {
+		return mg.VrfMetalGateway.GetId()
+	}
+	return mg.MetalGateway.GetId()
 }
 
-func resourceMetalGatewayRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalGatewayRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	mgId := d.Id()
-	includes := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
-	mg, _, err := client.MetalGateways.Get(mgId, includes)
+	mg, resp, err := client.MetalGatewaysApi.FindMetalGatewayById(ctx, mgId).Include(metalGatewayIncludes).Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	privateIPv4SubnetSize := uint(0)
-	if !mg.IPReservation.Public {
-		// privateIPv4SubnetSize = bits.RotateLeft(1, 32-mg.IPReservation.CIDR)
-		privateIPv4SubnetSize = 1 << (32 - mg.IPReservation.CIDR)
+	m := map[string]interface{}{}
+	var reservationPublic bool
+	var reservationCIDR int32
+	if vg := mg.VrfMetalGateway; vg != nil {
+		m["project_id"] = vg.Project.GetId()
+		m["vlan_id"] = vg.VirtualNetwork.GetId()
+		m["ip_reservation_id"] = vg.IpReservation.GetId()
+		m["state"] = string(vg.GetState())
+		if vg.Vrf != nil {
+			m["vrf_id"] = vg.Vrf.GetId()
+		}
+		reservationPublic, reservationCIDR = vg.IpReservation.GetPublic(), vg.IpReservation.GetCidr()
+	} else {
+		gw := mg.MetalGateway
+		m["project_id"] = gw.Project.GetId()
+		m["vlan_id"] = gw.VirtualNetwork.GetId()
+		m["ip_reservation_id"] = gw.IpReservation.GetId()
+		m["state"] = string(gw.GetState())
+		reservationPublic, reservationCIDR = gw.IpReservation.GetPublic(), gw.IpReservation.GetCidr()
 	}
 
-	return equinix_schema.SetMap(d, map[string]interface{}{
-		"project_id":               mg.Project.ID,
-		"vlan_id":                  mg.VirtualNetwork.ID,
-		"ip_reservation_id":        mg.IPReservation.ID,
-		"private_ipv4_subnet_size": int(privateIPv4SubnetSize),
-		"state":                    mg.State,
-		"vrf_id": func(d *schema.ResourceData, k string) error {
-			if mg.VRF == nil {
-				return nil
-			}
-			return d.Set(k, mg.VRF.ID)
-		},
-	})
+	privateIPv4SubnetSize := 0
+	if !reservationPublic {
+		privateIPv4SubnetSize = 1 << (32 - reservationCIDR)
+	}
+	m["private_ipv4_subnet_size"] = privateIPv4SubnetSize
+
+	return diag.FromErr(equinix_schema.SetMap(d, m))
 }
 
-func resourceMetalGatewayDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	resp, err := client.MetalGateways.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+func resourceMetalGatewayDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	_, resp, err := client.MetalGatewaysApi.DeleteMetalGateway(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	deleteWaiter := getGatewayStateWaiter(
+		ctx,
 		client,
 		d.Id(),
 		d.Timeout(schema.TimeoutDelete),
-		[]string{string(packngo.MetalGatewayDeleting)},
+		[]string{string(metalv1.METALGATEWAYSTATE_DELETING)},
 		[]string{},
 	)
 
-	_, err = deleteWaiter.WaitForState()
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(nil, err) != nil {
-		return fmt.Errorf("Error deleting Metal Gateway %s: %s", d.Id(), err)
+	_, err = deleteWaiter.WaitForStateContext(ctx)
+	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(nil, err) != nil {
+		return diag.Errorf("Error deleting Metal Gateway %s: %s", d.Id(), err)
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func getGatewayStateWaiter(client *packngo.Client, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
+func getGatewayStateWaiter(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
 	return &retry.StateChangeConf{
 		Pending: pending,
 		Target:  target,
 		Refresh: func() (interface{}, string, error) {
-			getOpts := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
-
-			gw, _, err := client.MetalGateways.Get(id, getOpts) // TODO: we are not using the returned VRF. Remove the includes?
+			gw, resp, err := client.MetalGatewaysApi.FindMetalGatewayById(ctx, id).Execute()
 			if err != nil {
-				return 0, "", err
+				return 0, "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			}
+			if gw.VrfMetalGateway != nil {
+				return gw, string(gw.VrfMetalGateway.GetState()), nil
 			}
-			return gw, string(gw.State), nil
+			return gw, string(gw.MetalGateway.GetState()), nil
 		},
 		Timeout:    timeout,
 		Delay:      10 * time.Second,
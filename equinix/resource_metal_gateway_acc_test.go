@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -100,13 +101,13 @@ resource "equinix_metal_gateway" "test" {
 }
 
 func testAccMetalGatewayCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_gateway" {
 			continue
 		}
-		if _, _, err := client.MetalGateways.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.MetalGatewaysApi.FindMetalGatewayById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal Gateway still exists")
 		}
 	}
@@ -0,0 +1,134 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGatewayIPReservationID = "4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+
+func TestMetalGatewayCustomizeDiff_ipReservation(t *testing.T) {
+	tests := []struct {
+		name        string
+		reservation string
+		wantErr     string
+	}{
+		{"/29 is the smallest block", `{"network": "147.75.1.0", "cidr": 29, "address_family": 4, "type": "public_ipv4"}`, ""},
+		{"/8 is the largest block", `{"network": "10.0.0.0", "cidr": 8, "address_family": 4, "type": "private_ipv4"}`, ""},
+		{"/30 is too small", `{"network": "147.75.1.0", "cidr": 30, "address_family": 4, "type": "public_ipv4"}`, "IPv4 ranges must be between /8 and /29, got 147.75.1.0/30"},
+		{"IPv6 is not supported", `{"network": "2604:1380:4641:c900::", "cidr": 64, "address_family": 6, "type": "public_ipv6"}`, "IPv6 ranges are not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, meta := newMetalFakeAPI(t, map[string]string{
+				"GET /metal/v1/ips/" + testGatewayIPReservationID: tt.reservation,
+			})
+			_, err := resourceMetalGateway().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"project_id":        "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de",
+				"vlan_id":           "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+				"ip_reservation_id": testGatewayIPReservationID,
+			}), meta)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// packngoResourceMetalGatewayRead is the packngo implementation of
+// resourceMetalGatewayRead, kept to compare the states both write
+func packngoResourceMetalGatewayRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	mgId := d.Id()
+	includes := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
+	mg, _, err := client.MetalGateways.Get(mgId, includes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	privateIPv4SubnetSize := uint(0)
+	if !mg.IPReservation.Public {
+		privateIPv4SubnetSize = 1 << (32 - mg.IPReservation.CIDR)
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"project_id":               mg.Project.ID,
+		"vlan_id":                  mg.VirtualNetwork.ID,
+		"ip_reservation_id":        mg.IPReservation.ID,
+		"private_ipv4_subnet_size": int(privateIPv4SubnetSize),
+		"state":                    mg.State,
+		"vrf_id": func(d *schema.ResourceData, k string) error {
+			if mg.VRF == nil {
+				return nil
+			}
+			return d.Set(k, mg.VRF.ID)
+		},
+	}))
+}
+
+func TestResourceMetalGatewayRead_recorded(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantSize string
+	}{
+		{
+			name: "public reservation",
+			response: `{
+  "id": "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+  "state": "ready",
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "virtual_network": {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1001, "href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"},
+  "ip_reservation": {"id": "4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "type": "public_ipv4", "network": "147.75.1.0", "cidr": 29, "address_family": 4, "public": true, "href": "/metal/v1/ips/4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"},
+  "href": "/metal/v1/metal-gateways/9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"
+}`,
+			wantSize: "0",
+		},
+		{
+			name: "private reservation",
+			response: `{
+  "id": "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+  "state": "ready",
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "virtual_network": {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1001, "href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"},
+  "ip_reservation": {"id": "4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "type": "private_ipv4", "network": "10.1.2.0", "cidr": 28, "address_family": 4, "public": false, "href": "/metal/v1/ips/4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"},
+  "href": "/metal/v1/metal-gateways/9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"
+}`,
+			wantSize: "16",
+		},
+		{
+			name: "vrf reservation",
+			response: `{
+  "id": "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+  "state": "ready",
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "virtual_network": {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1001, "href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"},
+  "ip_reservation": {"id": "4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d", "type": "vrf", "network": "192.168.100.0", "cidr": 29, "address_family": 4, "public": false, "vrf": {"id": "3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10", "href": "/metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10"}, "href": "/metal/v1/ips/4b3a2c1d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"},
+  "vrf": {"id": "3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10", "href": "/metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10"},
+  "href": "/metal/v1/metal-gateways/9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"
+}`,
+			wantSize: "8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := assertMetalReadParity(t, resourceMetalGateway(), "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", nil, map[string]string{
+				"GET /metal/v1/metal-gateways/9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a": tt.response,
+			}, resourceMetalGatewayRead, packngoResourceMetalGatewayRead)
+
+			assert.Equal(t, tt.wantSize, state["private_ipv4_subnet_size"])
+		})
+	}
+}
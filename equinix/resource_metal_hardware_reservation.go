@@ -0,0 +1,212 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var hardwareReservationIncludes = []string{"project", "facility", "device", "plan"}
+
+func resourceMetalHardwareReservation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceMetalHardwareReservationCreate,
+		ReadContext:   resourceMetalHardwareReservationRead,
+		UpdateContext: resourceMetalHardwareReservationUpdate,
+		DeleteContext: resourceMetalHardwareReservationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hardware_reservation_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the existing hardware reservation to manage",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the project the reservation should be in. Changing it moves the reservation to the new project",
+			},
+			"short_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Reservation short ID",
+			},
+			"plan": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Plan type for the reservation",
+			},
+			"facility": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Facility for the reservation",
+			},
+			"metro": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Metro of the reservation facility",
+			},
+			"device_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "UUID of device occupying the reservation",
+			},
+			"provisionable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag indicating whether the reserved server is provisionable or not. Spare devices can't be provisioned unless they are activated first",
+			},
+			"spare": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag indicating whether the Hardware Reservation is a spare. Spare Hardware Reservations are used when a Hardware Reservations requires service from Metal Equinix",
+			},
+			"need_of_service": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Flag indicating whether the reserved server requires assistance from Equinix Metal",
+			},
+			"switch_uuid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Switch short ID, can be used to determine if two devices are connected to the same switch",
+			},
+			"termination_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiration date of the reservation",
+			},
+		},
+	}
+}
+
+func resourceMetalHardwareReservationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	id := d.Get("hardware_reservation_id").(string)
+	hr, resp, err := client.HardwareReservationsApi.FindHardwareReservationById(ctx, id).Include(hardwareReservationIncludes).Execute()
+	if err != nil {
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	projectID := d.Get("project_id").(string)
+	if hr.Project.GetId() != projectID {
+		if err := moveHardwareReservation(ctx, client, hr, projectID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(hr.GetId())
+
+	return resourceMetalHardwareReservationRead(ctx, d, meta)
+}
+
+func resourceMetalHardwareReservationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	hr, resp, err := client.HardwareReservationsApi.FindHardwareReservationById(ctx, d.Id()).Include(hardwareReservationIncludes).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] Hardware reservation (%s) not accessible, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	metro := hr.Facility.GetMetro()
+	terminationTime := ""
+	if hr.TerminationTime != nil {
+		terminationTime = hr.GetTerminationTime().Format(time.RFC3339)
+	}
+
+	m := map[string]interface{}{
+		"hardware_reservation_id": hr.GetId(),
+		"project_id":              hr.Project.GetId(),
+		"short_id":                hr.GetShortId(),
+		"plan":                    hr.Plan.GetSlug(),
+		"facility":                hr.Facility.GetCode(),
+		"metro":                   metro.GetCode(),
+		"device_id":               hr.Device.GetId(),
+		"provisionable":           hr.GetProvisionable(),
+		"spare":                   hr.GetSpare(),
+		"need_of_service":         hr.GetNeedOfService(),
+		"switch_uuid":             hr.GetSwitchUuid(),
+		"termination_time":        terminationTime,
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, m))
+}
+
+func resourceMetalHardwareReservationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	if d.HasChange("project_id") {
+		hr, resp, err := client.HardwareReservationsApi.FindHardwareReservationById(ctx, d.Id()).Include(hardwareReservationIncludes).Execute()
+		if err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+		if err := moveHardwareReservation(ctx, client, hr, d.Get("project_id").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceMetalHardwareReservationRead(ctx, d, meta)
+}
+
+// resourceMetalHardwareReservationDelete only removes the reservation from the
+// state. Hardware reservations can not be deleted through the API, they stay
+// in the project they were last moved to.
+func resourceMetalHardwareReservationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Hardware reservation (%s) is no longer managed, it stays in project %s", d.Id(), d.Get("project_id").(string))
+	d.SetId("")
+	return nil
+}
+
+func moveHardwareReservation(ctx context.Context, client *metalv1.APIClient, hr *metalv1.HardwareReservation, projectID string) error {
+	if hr.Device != nil && hr.Device.GetId() != "" {
+		return fmt.Errorf("hardware reservation %s can not be moved to project %s, it is in use by device %s", hr.GetId(), projectID, hr.Device.GetId())
+	}
+
+	log.Printf("[DEBUG] Moving hardware reservation (%s) from project %s to %s", hr.GetId(), hr.Project.GetId(), projectID)
+	moveRequest := metalv1.MoveHardwareReservationRequest{ProjectId: &projectID}
+	_, resp, err := client.HardwareReservationsApi.MoveHardwareReservation(ctx, hr.GetId()).MoveHardwareReservationRequest(moveRequest).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
+}
+
+// projectHardwareReservationIDs lists the IDs of the hardware reservations
+// held by a project. Errors are reported as equinix_errors.ErrorResponse, so
+// that the HTTP status of the failed page can be matched.
+func projectHardwareReservationIDs(ctx context.Context, client *metalv1.APIClient, projectID string) ([]string, error) {
+	ids := []string{}
+	for page := int32(1); ; page++ {
+		list, resp, err := client.HardwareReservationsApi.FindProjectHardwareReservations(ctx, projectID).Page(page).Execute()
+		if err != nil {
+			return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		for _, hr := range list.HardwareReservations {
+			ids = append(ids, hr.GetId())
+		}
+		if list.Meta.GetLastPage() <= list.Meta.GetCurrentPage() {
+			return ids, nil
+		}
+	}
+}
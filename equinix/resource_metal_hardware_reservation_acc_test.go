@@ -0,0 +1,115 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const metalHardwareReservationIDEnvVar = "TF_ACC_METAL_HARDWARE_RESERVATION_ID"
+
+func TestAccMetalHardwareReservation_move(t *testing.T) {
+	reservationID := os.Getenv(metalHardwareReservationIDEnvVar)
+	if reservationID == "" {
+		t.Skipf("%s is not set to an unused hardware reservation; skipping", metalHardwareReservationIDEnvVar)
+	}
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+	testAccPreCheck(t)
+	// The steps are built before the test case runs, so the project to move
+	// the reservation back to is read up front
+	originalProjectID := testAccMetalHardwareReservationProjectID(t, reservationID)
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ExternalProviders: testExternalProviders,
+		Providers:         testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalHardwareReservationConfig_move(rs, reservationID, "one"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_hardware_reservation.test", "project_id",
+						"equinix_metal_project.one", "id"),
+					resource.TestCheckResourceAttr(
+						"equinix_metal_hardware_reservation.test", "device_id", ""),
+					resource.TestCheckResourceAttrSet(
+						"equinix_metal_hardware_reservation.test", "plan"),
+				),
+			},
+			{
+				Config: testAccMetalHardwareReservationConfig_move(rs, reservationID, "two"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_hardware_reservation.test", "project_id",
+						"equinix_metal_project.two", "id"),
+				),
+			},
+			{
+				ResourceName:      "equinix_metal_hardware_reservation.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// Move the reservation back, destroying the resource leaves it
+				// in the project it was last moved to
+				Config: testAccMetalHardwareReservationConfig_projects(rs) +
+					testAccMetalHardwareReservationConfig(reservationID, fmt.Sprintf("%q", originalProjectID)),
+				Check: resource.TestCheckResourceAttr(
+					"equinix_metal_hardware_reservation.test", "project_id", originalProjectID),
+			},
+		},
+	})
+}
+
+func testAccMetalHardwareReservationConfig_projects(name string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_project" "one" {
+    name  = "tfacc-hwr-one-%[1]s"
+    force = true
+}
+
+resource "equinix_metal_project" "two" {
+    name  = "tfacc-hwr-two-%[1]s"
+    force = true
+}
+`, name)
+}
+
+// testAccMetalHardwareReservationProjectID reads the project holding the
+// reservation before the test moves it
+func testAccMetalHardwareReservationProjectID(t *testing.T, reservationID string) string {
+	meta, err := sharedConfigForRegion("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	hr, resp, err := meta.Metalgo.HardwareReservationsApi.FindHardwareReservationById(context.Background(), reservationID).Include([]string{"project"}).Execute()
+	if err != nil {
+		t.Fatalf("error reading hardware reservation %s: %s", reservationID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	return hr.Project.GetId()
+}
+
+func testAccMetalHardwareReservationConfig(reservationID, projectID string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_hardware_reservation" "test" {
+    hardware_reservation_id = "%s"
+    project_id              = %s
+}
+`, reservationID, projectID)
+}
+
+func testAccMetalHardwareReservationConfig_move(name, reservationID, project string) string {
+	return testAccMetalHardwareReservationConfig_projects(name) + testAccMetalHardwareReservationConfig(reservationID, "equinix_metal_project."+project+".id")
+}
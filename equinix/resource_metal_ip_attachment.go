@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path"
@@ -9,8 +10,9 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func resourceMetalIPAttachment() *schema.Resource {
@@ -26,40 +28,38 @@ func resourceMetalIPAttachment() *schema.Resource {
 		Required: true,
 	}
 	return &schema.Resource{
-		Create: resourceMetalIPAttachmentCreate,
-		Read:   resourceMetalIPAttachmentRead,
-		Delete: resourceMetalIPAttachmentDelete,
+		CreateContext: resourceMetalIPAttachmentCreate,
+		ReadContext:   resourceMetalIPAttachmentRead,
+		DeleteContext: resourceMetalIPAttachmentDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: ipAttachmentSchema,
 	}
 }
 
-func resourceMetalIPAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalIPAttachmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	deviceID := d.Get("device_id").(string)
 	ipa := d.Get("cidr_notation").(string)
-	req := packngo.AddressStruct{Address: ipa}
-	assignment, _, err := client.DeviceIPs.Assign(deviceID, &req)
+	input := metalv1.IPAssignmentInput{Address: ipa}
+	assignment, resp, err := client.DevicesApi.CreateIPAssignment(ctx, deviceID).IPAssignmentInput(input).Execute()
 	if err != nil {
-		return fmt.Errorf("error assigning address %s to device %s: %s", ipa, deviceID, err)
+		return diag.Errorf("error assigning address %s to device %s: %s", ipa, deviceID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	d.SetId(assignment.ID)
+	d.SetId(assignment.GetId())
 
-	return resourceMetalIPAttachmentRead(d, meta)
+	return resourceMetalIPAttachmentRead(ctx, d, meta)
 }
 
-func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	assignment, _, err := client.DeviceIPs.Get(d.Id(), nil)
+func resourceMetalIPAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	ip, resp, err := client.IPAddressesApi.FindIPAddressById(ctx, d.Id()).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 
 		// If the IP attachment was already destroyed, mark as succesfully gone.
 		if equinix_errors.IsNotFound(err) {
@@ -67,36 +67,42 @@ func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) err
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
+	}
+	assignment := ip.IPAssignment
+	if assignment == nil {
+		return diag.Errorf("IP address %s is not an IP assignment", d.Id())
 	}
 
-	d.SetId(assignment.ID)
-	d.Set("address", assignment.Address)
-	d.Set("gateway", assignment.Gateway)
-	d.Set("network", assignment.Network)
-	d.Set("netmask", assignment.Netmask)
-	d.Set("address_family", assignment.AddressFamily)
-	d.Set("cidr", assignment.CIDR)
-	d.Set("public", assignment.Public)
-	d.Set("management", assignment.Management)
-	d.Set("manageable", assignment.Manageable)
+	d.SetId(assignment.GetId())
+	d.Set("address", assignment.GetAddress())
+	d.Set("gateway", assignment.GetGateway())
+	d.Set("network", assignment.GetNetwork())
+	d.Set("netmask", assignment.GetNetmask())
+	d.Set("address_family", assignment.GetAddressFamily())
+	d.Set("cidr", assignment.GetCidr())
+	d.Set("public", assignment.GetPublic())
+	d.Set("management", assignment.GetManagement())
+	d.Set("manageable", assignment.GetManageable())
 
-	d.Set("global", assignment.Global)
+	d.Set("global", assignment.GetGlobalIp())
 
-	d.Set("device_id", path.Base(assignment.AssignedTo.Href))
+	d.Set("device_id", path.Base(assignment.AssignedTo.GetHref()))
 	d.Set("cidr_notation",
-		fmt.Sprintf("%s/%d", assignment.Network, assignment.CIDR))
+		fmt.Sprintf("%s/%d", assignment.GetNetwork(), assignment.GetCidr()))
 
 	return nil
 }
 
-func resourceMetalIPAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalIPAttachmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.DeviceIPs.Unassign(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+	resp, err := client.IPAddressesApi.DeleteIPAddress(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId("")
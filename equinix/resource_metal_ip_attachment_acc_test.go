@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -129,13 +130,13 @@ resource "equinix_metal_ip_attachment" "test" {
 }
 
 func testAccMetalIPAttachmentCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_ip_attachment" {
 			continue
 		}
-		if _, _, err := client.ProjectIPs.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.IPAddressesApi.FindIPAddressById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal IP attachment still exists")
 		}
 	}
@@ -0,0 +1,87 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalIPAssignment = `{
+  "id": "7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b",
+  "type": "IPAssignment",
+  "address_family": 4,
+  "netmask": "255.255.255.248",
+  "public": true,
+  "cidr": 29,
+  "management": false,
+  "manageable": true,
+  "global_ip": false,
+  "network": "147.75.1.8",
+  "address": "147.75.1.9",
+  "gateway": "147.75.1.9",
+  "assigned_to": {"href": "/metal/v1/devices/1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f"},
+  "href": "/metal/v1/ips/7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b"
+}`
+
+// packngoResourceMetalIPAttachmentRead is the packngo implementation of
+// resourceMetalIPAttachmentRead, kept to compare the states both write
+func packngoResourceMetalIPAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	assignment, _, err := client.DeviceIPs.Get(d.Id(), nil)
+	if err != nil {
+		err = packngoFriendlyError(err)
+
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] IP attachment (%q) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId(assignment.ID)
+	d.Set("address", assignment.Address)
+	d.Set("gateway", assignment.Gateway)
+	d.Set("network", assignment.Network)
+	d.Set("netmask", assignment.Netmask)
+	d.Set("address_family", assignment.AddressFamily)
+	d.Set("cidr", assignment.CIDR)
+	d.Set("public", assignment.Public)
+	d.Set("management", assignment.Management)
+	d.Set("manageable", assignment.Manageable)
+
+	d.Set("global", assignment.Global)
+
+	d.Set("device_id", path.Base(assignment.AssignedTo.Href))
+	d.Set("cidr_notation",
+		fmt.Sprintf("%s/%d", assignment.Network, assignment.CIDR))
+
+	return nil
+}
+
+func TestResourceMetalIPAttachmentRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalIPAttachment(), "7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b", nil, map[string]string{
+		"GET /metal/v1/ips/7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b": recordedMetalIPAssignment,
+	}, resourceMetalIPAttachmentRead, packngoResourceMetalIPAttachmentRead)
+
+	assert.Equal(t, "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f", state["device_id"])
+	assert.Equal(t, "147.75.1.8/29", state["cidr_notation"])
+}
+
+func TestResourceMetalIPAttachmentRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := resourceMetalIPAttachment().TestResourceData()
+	d.SetId("7c6b5a49-3827-4165-9a8b-7c6d5e4f3a2b")
+	diags := resourceMetalIPAttachmentRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
@@ -1,26 +1,29 @@
 package equinix
 
 import (
+	"context"
 	"regexp"
+	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
 func resourceMetalOrganization() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalOrganizationCreate,
-		Read:   resourceMetalOrganizationRead,
-		Update: resourceMetalOrganizationUpdate,
-		Delete: resourceMetalOrganizationDelete,
+		CreateContext: resourceMetalOrganizationCreate,
+		ReadContext:   resourceMetalOrganizationRead,
+		UpdateContext: resourceMetalOrganizationUpdate,
+		DeleteContext: resourceMetalOrganizationDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -104,48 +107,46 @@ func createMetalOrganizationAddressResourceSchema() map[string]*schema.Schema {
 	}
 }
 
-func resourceMetalOrganizationCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	createRequest := &packngo.OrganizationCreateRequest{
-		Name:    d.Get("name").(string),
+	createRequest := metalv1.OrganizationInput{
+		Name:    metalv1.PtrString(d.Get("name").(string)),
 		Address: expandMetalOrganizationAddress(d.Get("address").([]interface{})),
 	}
 
 	if attr, ok := d.GetOk("website"); ok {
-		createRequest.Website = attr.(string)
+		createRequest.Website = metalv1.PtrString(attr.(string))
 	}
 
 	if attr, ok := d.GetOk("description"); ok {
-		createRequest.Description = attr.(string)
+		createRequest.Description = metalv1.PtrString(attr.(string))
 	}
 
 	if attr, ok := d.GetOk("twitter"); ok {
-		createRequest.Twitter = attr.(string)
+		createRequest.Twitter = metalv1.PtrString(attr.(string))
 	}
 
 	if attr, ok := d.GetOk("logo"); ok {
-		createRequest.Logo = attr.(string)
+		createRequest.Logo = metalv1.PtrString(attr.(string))
 	}
 
-	org, _, err := client.Organizations.Create(createRequest)
+	org, resp, err := client.OrganizationsApi.CreateOrganization(ctx).OrganizationInput(createRequest).Execute()
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	d.SetId(org.ID)
+	d.SetId(org.GetId())
 
-	return resourceMetalOrganizationRead(d, meta)
+	return resourceMetalOrganizationRead(ctx, d, meta)
 }
 
-func resourceMetalOrganizationRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	key, _, err := client.Organizations.Get(d.Id(), &packngo.GetOptions{Includes: []string{"address"}})
+	key, resp, err := client.OrganizationsApi.FindOrganizationById(ctx, d.Id()).Include([]string{"address"}).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 
 		// If the project somehow already destroyed, mark as succesfully gone.
 		if equinix_errors.IsNotFound(err) {
@@ -154,103 +155,97 @@ func resourceMetalOrganizationRead(d *schema.ResourceData, meta interface{}) err
 			return nil
 		}
 
-		return err
+		return diag.FromErr(err)
 	}
 
-	d.SetId(key.ID)
-	return equinix_schema.SetMap(d, map[string]interface{}{
-		"name":        key.Name,
-		"description": key.Description,
-		"website":     key.Website,
-		"twitter":     key.Twitter,
-		"logo":        key.Logo,
-		"created":     key.Created,
-		"updated":     key.Updated,
+	d.SetId(key.GetId())
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"name":        key.GetName(),
+		"description": key.GetDescription(),
+		"website":     key.GetWebsite(),
+		"twitter":     key.GetTwitter(),
+		"logo":        key.GetLogo(),
+		"created":     key.GetCreatedAt().Format(time.RFC3339),
+		"updated":     key.GetUpdatedAt().Format(time.RFC3339),
 		"address":     flattenMetalOrganizationAddress(key.Address),
-	})
+	}))
 }
 
-func resourceMetalOrganizationUpdate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	changes := equinix_schema.GetResourceDataChangedKeys([]string{"name", "description", "website", "twitter", "logo", "address"}, d)
-	updateRequest := &packngo.OrganizationUpdateRequest{}
+	updateRequest := metalv1.OrganizationInput{}
 	for change, changeValue := range changes {
 		switch change {
 		case "name":
-			cv := changeValue.(string)
-			updateRequest.Name = &cv
+			updateRequest.Name = metalv1.PtrString(changeValue.(string))
 		case "description":
-			cv := changeValue.(string)
-			updateRequest.Description = &cv
+			updateRequest.Description = metalv1.PtrString(changeValue.(string))
 		case "website":
-			cv := changeValue.(string)
-			updateRequest.Website = &cv
+			updateRequest.Website = metalv1.PtrString(changeValue.(string))
 		case "twitter":
-			cv := changeValue.(string)
-			updateRequest.Twitter = &cv
+			updateRequest.Twitter = metalv1.PtrString(changeValue.(string))
 		case "logo":
-			cv := changeValue.(string)
-			updateRequest.Logo = &cv
+			updateRequest.Logo = metalv1.PtrString(changeValue.(string))
 		case "address":
-			cv := expandMetalOrganizationAddress(changeValue.([]interface{}))
-			updateRequest.Address = &cv
+			updateRequest.Address = expandMetalOrganizationAddress(changeValue.([]interface{}))
 		}
 	}
 
-	_, _, err := client.Organizations.Update(d.Id(), updateRequest)
+	_, resp, err := client.OrganizationsApi.UpdateOrganization(ctx, d.Id()).OrganizationInput(updateRequest).Execute()
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	return resourceMetalOrganizationRead(d, meta)
+	return resourceMetalOrganizationRead(ctx, d, meta)
 }
 
-func resourceMetalOrganizationDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.Organizations.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+	resp, err := client.OrganizationsApi.DeleteOrganization(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func flattenMetalOrganizationAddress(addr packngo.Address) interface{} {
+func flattenMetalOrganizationAddress(addr *metalv1.Address) interface{} {
 	result := make(map[string]interface{})
-	if addr.Address != "" {
-		result["address"] = addr.Address
+	if addr.GetAddress() != "" {
+		result["address"] = addr.GetAddress()
 	}
-	if addr.City != nil && *addr.City != "" {
-		result["city"] = addr.City
+	if addr.GetCity() != "" {
+		result["city"] = addr.GetCity()
 	}
-	if addr.Country != "" {
-		result["country"] = addr.Country
+	if addr.GetCountry() != "" {
+		result["country"] = addr.GetCountry()
 	}
-	if addr.State != nil && *addr.State != "" {
-		result["state"] = addr.State
+	if addr.GetState() != "" {
+		result["state"] = addr.GetState()
 	}
-	if addr.ZipCode != "" {
-		result["zip_code"] = addr.ZipCode
+	if addr.GetZipCode() != "" {
+		result["zip_code"] = addr.GetZipCode()
 	}
 
 	return []interface{}{result}
 }
 
-func expandMetalOrganizationAddress(address []interface{}) packngo.Address {
-	transformed := packngo.Address{}
+func expandMetalOrganizationAddress(address []interface{}) *metalv1.Address {
+	transformed := metalv1.Address{}
 	addr := address[0].(map[string]interface{})
 
 	if v, ok := addr["address"]; ok {
 		transformed.Address = v.(string)
 	}
 	if v, ok := addr["city"]; ok {
-		city := v.(string)
-		transformed.City = &city
+		transformed.City = metalv1.PtrString(v.(string))
 	}
 	if v, ok := addr["zip_code"]; ok {
 		transformed.ZipCode = v.(string)
@@ -259,9 +254,8 @@ func expandMetalOrganizationAddress(address []interface{}) packngo.Address {
 		transformed.Country = v.(string)
 	}
 	if v, ok := addr["state"]; ok {
-		state := v.(string)
-		transformed.State = &state
+		transformed.State = metalv1.PtrString(v.(string))
 	}
 
-	return transformed
+	return &transformed
 }
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
@@ -8,10 +9,10 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func init() {
@@ -28,20 +29,20 @@ func testSweepOrganizations(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping organizations: %s", err)
 	}
-	metal := config.NewMetalClient()
-	os, _, err := metal.Organizations.List(nil)
+	metal := config.NewMetalGoClient()
+	os, err := metal.OrganizationsApi.FindOrganizations(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting org list for sweeping organizations: %s", err)
 	}
 	oids := []string{}
-	for _, o := range os {
-		if isSweepableTestResource(o.Name) {
-			oids = append(oids, o.ID)
+	for _, o := range os.Organizations {
+		if isSweepableTestResource(o.GetName()) {
+			oids = append(oids, o.GetId())
 		}
 	}
 	for _, oid := range oids {
 		log.Printf("Removing organization %s", oid)
-		_, err := metal.Organizations.Delete(oid)
+		_, err := metal.OrganizationsApi.DeleteOrganization(context.Background(), oid).Execute()
 		if err != nil {
 			return fmt.Errorf("Error deleting organization %s", err)
 		}
@@ -50,7 +51,7 @@ func testSweepOrganizations(region string) error {
 }
 
 func TestAccMetalOrganization_create(t *testing.T) {
-	var org, org2 packngo.Organization
+	var org, org2 metalv1.Organization
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -97,10 +98,10 @@ func TestAccMetalOrganization_create(t *testing.T) {
 	})
 }
 
-func testAccMetalSameOrganization(t *testing.T, before, after *packngo.Organization) resource.TestCheckFunc {
+func testAccMetalSameOrganization(t *testing.T, before, after *metalv1.Organization) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		if before.ID != after.ID {
-			t.Fatalf("Expected organization to be the same, but it was recreated: %s -> %s", before.ID, after.ID)
+		if before.GetId() != after.GetId() {
+			t.Fatalf("Expected organization to be the same, but it was recreated: %s -> %s", before.GetId(), after.GetId())
 		}
 		return nil
 	}
@@ -135,13 +136,13 @@ func TestAccMetalOrganization_importBasic(t *testing.T) {
 }
 
 func testAccMetalOrganizationCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_organization" {
 			continue
 		}
-		if _, _, err := client.Organizations.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.OrganizationsApi.FindOrganizationById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal Organization still exists")
 		}
 	}
@@ -149,7 +150,7 @@ func testAccMetalOrganizationCheckDestroyed(s *terraform.State) error {
 	return nil
 }
 
-func testAccMetalOrganizationExists(n string, org *packngo.Organization) resource.TestCheckFunc {
+func testAccMetalOrganizationExists(n string, org *metalv1.Organization) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
@@ -159,13 +160,13 @@ func testAccMetalOrganizationExists(n string, org *packngo.Organization) resourc
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*config.Config).Metal
+		client := testAccProvider.Meta().(*config.Config).Metalgo
 
-		foundOrg, _, err := client.Organizations.Get(rs.Primary.ID, &packngo.GetOptions{Includes: []string{"address", "primary_owner"}})
+		foundOrg, _, err := client.OrganizationsApi.FindOrganizationById(context.Background(), rs.Primary.ID).Include([]string{"address", "primary_owner"}).Execute()
 		if err != nil {
 			return err
 		}
-		if foundOrg.ID != rs.Primary.ID {
+		if foundOrg.GetId() != rs.Primary.ID {
 			return fmt.Errorf("Record not found: %v - %v", rs.Primary.ID, foundOrg)
 		}
 
@@ -175,6 +176,14 @@ func testAccMetalOrganizationExists(n string, org *packngo.Organization) resourc
 	}
 }
 
+// testAccMetalOrganizationOwnerEmail returns the email of the primary owner of
+// an organization, which metal-go does not model
+func testAccMetalOrganizationOwnerEmail(org *metalv1.Organization) string {
+	owner, _ := org.AdditionalProperties["primary_owner"].(map[string]interface{})
+	email, _ := owner["email"].(string)
+	return email
+}
+
 func testAccMetalOrganizationConfig_basic(r int) string {
 	return fmt.Sprintf(`
 resource "equinix_metal_organization" "test" {
@@ -1,10 +1,14 @@
 package equinix
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
@@ -13,39 +17,111 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
+// metalOrganizationMember and metalOrganizationInvitation are the members
+// and open invitations of an organization as listed by the Equinix Metal API.
+// metal-go has no request for the members of an organization, decodes
+// invitations as memberships and only accepts the roles of some invitations.
+type metalOrganizationMember struct {
+	ID    string   `json:"id"`
+	Roles []string `json:"roles"`
+	User  struct {
+		Email string `json:"email"`
+	} `json:"user"`
+	Organization metalHref   `json:"organization"`
+	Projects     []metalHref `json:"projects"`
+}
+
+type metalOrganizationInvitation struct {
+	ID           string      `json:"id"`
+	Invitee      string      `json:"invitee"`
+	Nonce        string      `json:"nonce"`
+	Roles        []string    `json:"roles"`
+	Organization metalHref   `json:"organization"`
+	InvitedBy    metalHref   `json:"invited_by"`
+	Projects     []metalHref `json:"projects"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type metalHref struct {
+	Href string `json:"href"`
+}
+
+type metalInvitationInput struct {
+	Invitee     string   `json:"invitee"`
+	Message     string   `json:"message,omitempty"`
+	ProjectsIDs []string `json:"projects_ids,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+// listMetalOrganizationInvitations returns the open invitations of an
+// organization, from every page
+func listMetalOrganizationInvitations(ctx context.Context, client *metalv1.APIClient, orgID string) ([]metalOrganizationInvitation, *http.Response, error) {
+	invitations := []metalOrganizationInvitation{}
+	resp, err := metalListRequest(ctx, client, "organizations/"+orgID+"/invitations?include=user", func(page json.RawMessage) error {
+		list := struct {
+			Invitations []metalOrganizationInvitation `json:"invitations"`
+		}{}
+		if err := json.Unmarshal(page, &list); err != nil {
+			return err
+		}
+		invitations = append(invitations, list.Invitations...)
+		return nil
+	})
+	return invitations, resp, err
+}
+
+// listMetalOrganizationMembers returns the members of an organization, from
+// every page
+func listMetalOrganizationMembers(ctx context.Context, client *metalv1.APIClient, orgID string) ([]metalOrganizationMember, *http.Response, error) {
+	members := []metalOrganizationMember{}
+	resp, err := metalListRequest(ctx, client, "organizations/"+orgID+"/members?include=user", func(page json.RawMessage) error {
+		list := struct {
+			Members []metalOrganizationMember `json:"members"`
+		}{}
+		if err := json.Unmarshal(page, &list); err != nil {
+			return err
+		}
+		members = append(members, list.Members...)
+		return nil
+	})
+	return members, resp, err
+}
+
 type member struct {
-	*packngo.Member
-	*packngo.Invitation
+	*metalOrganizationMember
+	*metalOrganizationInvitation
 }
 
 func (m *member) isMember() bool {
-	return m.Member != nil
+	return m.metalOrganizationMember != nil
 }
 
 func (m *member) isInvitation() bool {
-	return m.Invitation != nil
+	return m.metalOrganizationInvitation != nil
 }
 
 func resourceMetalOrganizationMember() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalOrganizationMemberCreate,
-		Read:   resourceMetalOrganizationMemberRead,
-		Delete: resourceMetalOrganizationMemberDelete,
+		CreateContext: resourceMetalOrganizationMemberCreate,
+		ReadContext:   resourceMetalOrganizationMemberRead,
+		DeleteContext: resourceMetalOrganizationMemberDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				parts := strings.Split(d.Id(), ":")
 				invitee := parts[0]
 				orgID := parts[1]
 				d.SetId(d.Id())
 				d.Set("invitee", invitee)
 				d.Set("organization_id", orgID)
-				if err := resourceMetalOrganizationMemberRead(d, meta); err != nil {
-					return nil, err
+				if diags := resourceMetalOrganizationMemberRead(ctx, d, meta); diags.HasError() {
+					return nil, fmt.Errorf("%s", diags[0].Summary)
 				}
 				if d.Id() == "" {
 					return nil, fmt.Errorf("Member %s does not exist in organization %s.", invitee, orgID)
@@ -82,7 +158,7 @@ func resourceMetalOrganizationMember() *schema.Resource {
 					Type:         schema.TypeString,
 					ValidateFunc: validation.StringIsNotEmpty,
 				},
-				// TODO: Update should be supported. packngo.InvitationService does not offer an Update	method.
+				// TODO: Update should be supported. the API does not offer an Update method for invitations.
 				ForceNew: true,
 			},
 			"nonce": {
@@ -111,7 +187,7 @@ func resourceMetalOrganizationMember() *schema.Resource {
 				Description: "Organization roles (owner, collaborator, limited_collaborator, billing)",
 				Required:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
-				// TODO: Update should be supported. packngo.InvitationService does not offer an Update	method.
+				// TODO: Update should be supported. the API does not offer an Update method for invitations.
 				ForceNew: true,
 			},
 			"state": {
@@ -123,11 +199,11 @@ func resourceMetalOrganizationMember() *schema.Resource {
 	}
 }
 
-func resourceMetalOrganizationMemberCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationMemberCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	email := d.Get("invitee").(string)
-	createRequest := &packngo.InvitationCreateRequest{
+	createRequest := metalInvitationInput{
 		Invitee:     email,
 		Roles:       converters.IfArrToStringArr(d.Get("roles").(*schema.Set).List()),
 		ProjectsIDs: converters.IfArrToStringArr(d.Get("projects_ids").(*schema.Set).List()),
@@ -135,58 +211,57 @@ func resourceMetalOrganizationMemberCreate(d *schema.ResourceData, meta interfac
 	}
 
 	orgID := d.Get("organization_id").(string)
-	_, _, err := client.Invitations.Create(orgID, createRequest, nil)
+	resp, err := metalRequest(ctx, client, http.MethodPost, "organizations/"+orgID+"/invitations", createRequest, nil)
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	d.SetId(fmt.Sprintf("%s:%s", email, orgID))
 
-	return resourceMetalOrganizationMemberRead(d, meta)
+	return resourceMetalOrganizationMemberRead(ctx, d, meta)
 }
 
-func findMember(invitee string, members []packngo.Member, invitations []packngo.Invitation) (*member, error) {
+func findMember(invitee string, members []metalOrganizationMember, invitations []metalOrganizationInvitation) (*member, error) {
 	for _, mbr := range members {
 		if mbr.User.Email == invitee {
-			return &member{Member: &mbr}, nil
+			return &member{metalOrganizationMember: &mbr}, nil
 		}
 	}
 
 	for _, inv := range invitations {
 		if inv.Invitee == invitee {
-			return &member{Invitation: &inv}, nil
+			return &member{metalOrganizationInvitation: &inv}, nil
 		}
 	}
 	return nil, fmt.Errorf("member not found")
 }
 
-func resourceMetalOrganizationMemberRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationMemberRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	parts := strings.Split(d.Id(), ":")
 	invitee := parts[0]
 	orgID := parts[1]
 
-	listOpts := &packngo.ListOptions{Includes: []string{"user"}}
-	invitations, _, err := client.Invitations.List(orgID, listOpts)
+	invitations, resp, err := listMetalOrganizationInvitations(ctx, client, orgID)
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		// If the org was destroyed, mark as gone.
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
-	members, _, err := client.Members.List(orgID, &packngo.GetOptions{Includes: []string{"user"}})
+	members, resp, err := listMetalOrganizationMembers(ctx, client, orgID)
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		// If the org was destroyed, mark as gone.
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	member, err := findMember(invitee, members, invitations)
 	if !d.IsNewResource() && err != nil {
@@ -197,59 +272,61 @@ func resourceMetalOrganizationMemberRead(d *schema.ResourceData, meta interface{
 
 	if member.isMember() {
 		projectIDs := []string{}
-		for _, project := range member.Member.Projects {
-			projectIDs = append(projectIDs, path.Base(project.URL))
+		for _, project := range member.metalOrganizationMember.Projects {
+			projectIDs = append(projectIDs, path.Base(project.Href))
 		}
-		return equinix_schema.SetMap(d, map[string]interface{}{
+		return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
 			"state":           "active",
-			"roles":           converters.StringArrToIfArr(member.Member.Roles),
+			"roles":           converters.StringArrToIfArr(member.metalOrganizationMember.Roles),
 			"projects_ids":    converters.StringArrToIfArr(projectIDs),
-			"organization_id": path.Base(member.Member.Organization.URL),
-		})
+			"organization_id": path.Base(member.metalOrganizationMember.Organization.Href),
+		}))
 	} else if member.isInvitation() {
 		projectIDs := []string{}
-		for _, project := range member.Invitation.Projects {
+		for _, project := range member.metalOrganizationInvitation.Projects {
 			projectIDs = append(projectIDs, path.Base(project.Href))
 		}
-		return equinix_schema.SetMap(d, map[string]interface{}{
+		return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
 			"state":           "invited",
-			"organization_id": path.Base(member.Invitation.Organization.Href),
-			"roles":           member.Invitation.Roles,
+			"organization_id": path.Base(member.metalOrganizationInvitation.Organization.Href),
+			"roles":           member.metalOrganizationInvitation.Roles,
 			"projects_ids":    projectIDs,
-			"created":         member.Invitation.CreatedAt.String(),
-			"updated":         member.Invitation.UpdatedAt.String(),
-			"nonce":           member.Invitation.Nonce,
-			"invited_by":      path.Base(member.Invitation.InvitedBy.Href),
-		})
+			"created":         member.metalOrganizationInvitation.CreatedAt.String(),
+			"updated":         member.metalOrganizationInvitation.UpdatedAt.String(),
+			"nonce":           member.metalOrganizationInvitation.Nonce,
+			"invited_by":      path.Base(member.metalOrganizationInvitation.InvitedBy.Href),
+		}))
 	}
-	return fmt.Errorf("got an invalid member object")
+	return diag.Errorf("got an invalid member object")
 }
 
-func resourceMetalOrganizationMemberDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func resourceMetalOrganizationMemberDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	listOpts := &packngo.ListOptions{Includes: []string{"user"}}
-	invitations, _, err := client.Invitations.List(d.Get("organization_id").(string), listOpts)
+	invitations, resp, err := listMetalOrganizationInvitations(ctx, client, d.Get("organization_id").(string))
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		// If the org was destroyed, mark as gone.
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
 	orgID := d.Get("organization_id").(string)
-	org, _, err := client.Organizations.Get(orgID, &packngo.GetOptions{Includes: []string{"members", "members.user"}})
+	org := struct {
+		Members []metalOrganizationMember `json:"members"`
+	}{}
+	resp, err = metalRequest(ctx, client, http.MethodGet, "organizations/"+orgID+"?include=members,members.user", nil, &org)
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		// If the org was destroyed, mark as gone.
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 
 	member, err := findMember(d.Get("invitee").(string), org.Members, invitations)
@@ -259,26 +336,27 @@ func resourceMetalOrganizationMemberDelete(d *schema.ResourceData, meta interfac
 	}
 
 	if member.isMember() {
-		_, err = client.Members.Delete(orgID, member.Member.ID)
+		resp, err = metalRequest(ctx, client, http.MethodDelete, "organizations/"+orgID+"/members/"+member.metalOrganizationMember.ID, nil, nil)
 		if err != nil {
-			err = equinix_errors.FriendlyError(err)
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			// If the member was deleted, mark as gone.
 			if equinix_errors.IsNotFound(err) {
 				d.SetId("")
 				return nil
 			}
-			return err
+			return diag.FromErr(err)
 		}
 	} else if member.isInvitation() {
-		_, err = client.Invitations.Delete(member.Invitation.ID)
+		// declining an invitation is the request that deletes it
+		resp, err = client.InvitationsApi.DeclineInvitation(ctx, member.metalOrganizationInvitation.ID).Execute()
 		if err != nil {
-			err = equinix_errors.FriendlyError(err)
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			// If the invitation was deleted, mark as gone.
 			if equinix_errors.IsNotFound(err) {
 				d.SetId("")
 				return nil
 			}
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	return nil
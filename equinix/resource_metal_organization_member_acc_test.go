@@ -4,15 +4,15 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func TestAccResourceMetalOrganizationMember_owner(t *testing.T) {
 	rInt := acctest.RandInt()
-	org := &packngo.Organization{}
+	org := &metalv1.Organization{}
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ExternalProviders: testExternalProviders,
@@ -30,7 +30,7 @@ func TestAccResourceMetalOrganizationMember_owner(t *testing.T) {
 				ResourceName: "equinix_metal_organization_member.owner",
 				Config:       testAccResourceMetalOrganizationMember_basic(rInt) + testAccResourceMetalOrganizationMember_owner(),
 				ImportStateIdFunc: resource.ImportStateIdFunc(func(s *terraform.State) (string, error) {
-					return fmt.Sprintf("%s:%s", org.PrimaryOwner.Email, org.ID), nil
+					return fmt.Sprintf("%s:%s", testAccMetalOrganizationOwnerEmail(org), org.GetId()), nil
 				}),
 				ImportState: true,
 			},
@@ -50,7 +50,7 @@ func TestAccResourceMetalOrganizationMember_owner(t *testing.T) {
 
 func TestAccResourceMetalOrganizationMember_basic(t *testing.T) {
 	rInt := acctest.RandInt()
-	org := &packngo.Organization{}
+	org := &metalv1.Organization{}
 	resource.ParallelTest(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ExternalProviders: testExternalProviders,
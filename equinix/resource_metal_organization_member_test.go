@@ -0,0 +1,129 @@
+package equinix
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMetalOrganizationID = "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e"
+
+var recordedMetalOrganizationMembers = map[string]string{
+	"GET /metal/v1/organizations/" + testMetalOrganizationID + "/invitations": `{"invitations": [{
+		"id": "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+		"invitee": "bob@example.com",
+		"nonce": "5e4d3c2b",
+		"roles": ["owner"],
+		"organization": {"href": "/metal/v1/organizations/` + testMetalOrganizationID + `"},
+		"invited_by": {"href": "/metal/v1/users/7c6b5a49-3827-4160-9f8e-7d6c5b4a3928"},
+		"projects": [{"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}],
+		"created_at": "2023-05-04T10:11:12Z",
+		"updated_at": "2023-05-04T10:11:12Z"
+	}], "meta": {"current_page": 1, "last_page": 1}}`,
+	"GET /metal/v1/organizations/" + testMetalOrganizationID + "/members": `{"members": [{
+		"id": "6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c",
+		"roles": ["collaborator"],
+		"user": {"id": "8d7c6b5a-4f3e-4d2c-9b1a-0f9e8d7c6b5a", "email": "alice@example.com"},
+		"organization": {"href": "/metal/v1/organizations/` + testMetalOrganizationID + `"},
+		"projects": [{"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}, {"href": "/metal/v1/projects/2c3d4e5f-6a7b-8c9d-0e1f-2a3b4c5d6e7f"}]
+	}], "meta": {"current_page": 1, "last_page": 1}}`,
+	"GET /metal/v1/user": `{"id": "7c6b5a49-3827-4160-9f8e-7d6c5b4a3928", "email": "Owner@Example.com"}`,
+}
+
+// packngoResourceMetalOrganizationMemberRead is the packngo implementation of
+// resourceMetalOrganizationMemberRead, kept to compare the states both write
+func packngoResourceMetalOrganizationMemberRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	parts := strings.Split(d.Id(), ":")
+	invitee, orgID := parts[0], parts[1]
+
+	invitations, _, err := client.Invitations.List(orgID, &packngo.ListOptions{Includes: []string{"user"}})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	members, _, err := client.Members.List(orgID, &packngo.GetOptions{Includes: []string{"user"}})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, mbr := range members {
+		if mbr.User.Email == invitee {
+			projectIDs := []string{}
+			for _, project := range mbr.Projects {
+				projectIDs = append(projectIDs, path.Base(project.URL))
+			}
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"state":           "active",
+				"roles":           converters.StringArrToIfArr(mbr.Roles),
+				"projects_ids":    converters.StringArrToIfArr(projectIDs),
+				"organization_id": path.Base(mbr.Organization.URL),
+			}))
+		}
+	}
+	for _, inv := range invitations {
+		if inv.Invitee == invitee {
+			projectIDs := []string{}
+			for _, project := range inv.Projects {
+				projectIDs = append(projectIDs, path.Base(project.Href))
+			}
+			return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+				"state":           "invited",
+				"organization_id": path.Base(inv.Organization.Href),
+				"roles":           inv.Roles,
+				"projects_ids":    projectIDs,
+				"created":         inv.CreatedAt.String(),
+				"updated":         inv.UpdatedAt.String(),
+				"nonce":           inv.Nonce,
+				"invited_by":      path.Base(inv.InvitedBy.Href),
+			}))
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+func TestResourceMetalOrganizationMemberRead_recorded(t *testing.T) {
+	member := assertMetalReadParity(t, resourceMetalOrganizationMember(), "alice@example.com:"+testMetalOrganizationID, nil,
+		recordedMetalOrganizationMembers, resourceMetalOrganizationMemberRead, packngoResourceMetalOrganizationMemberRead)
+	assert.Equal(t, "active", member["state"])
+	assert.Equal(t, "2", member["projects_ids.#"])
+
+	invitation := assertMetalReadParity(t, resourceMetalOrganizationMember(), "bob@example.com:"+testMetalOrganizationID, nil,
+		recordedMetalOrganizationMembers, resourceMetalOrganizationMemberRead, packngoResourceMetalOrganizationMemberRead)
+	assert.Equal(t, "invited", invitation["state"])
+	assert.Equal(t, "2023-05-04 10:11:12 +0000 UTC", invitation["created"])
+	assert.Equal(t, "7c6b5a49-3827-4160-9f8e-7d6c5b4a3928", invitation["invited_by"])
+}
+
+func TestResourceMetalOrganizationMemberDelete_member(t *testing.T) {
+	responses := map[string]string{
+		"GET /metal/v1/organizations/" + testMetalOrganizationID: `{"id": "` + testMetalOrganizationID + `", "members": [{
+			"id": "6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c", "user": {"email": "alice@example.com"}
+		}]}`,
+		"DELETE /metal/v1/organizations/" + testMetalOrganizationID + "/members/6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c": "",
+	}
+	for k, v := range recordedMetalOrganizationMembers {
+		responses[k] = v
+	}
+	fake, meta := newMetalFakeAPI(t, responses)
+
+	d := schema.TestResourceDataRaw(t, resourceMetalOrganizationMember().Schema, map[string]interface{}{
+		"invitee":         "alice@example.com",
+		"organization_id": testMetalOrganizationID,
+	})
+	d.SetId("alice@example.com:" + testMetalOrganizationID)
+	if diags := resourceMetalOrganizationMemberDelete(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected delete error: %v", diags)
+	}
+	_, deleted := fake.request("DELETE /metal/v1/organizations/" + testMetalOrganizationID + "/members/6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c")
+	assert.True(t, deleted, "the membership is deleted from the organization")
+}
@@ -0,0 +1,497 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// organizationMemberSpec is the access of one invitee, either as configured or
+// as found in the organization.
+type organizationMemberSpec struct {
+	Roles      []string
+	ProjectIDs []string
+	Message    string
+}
+
+func (s organizationMemberSpec) sameAccess(o organizationMemberSpec) bool {
+	return strings.Join(s.Roles, ",") == strings.Join(o.Roles, ",") &&
+		strings.Join(s.ProjectIDs, ",") == strings.Join(o.ProjectIDs, ",")
+}
+
+// organizationMemberStatus is an invitee found in the organization, either as
+// an active member or as a pending invitation.
+type organizationMemberStatus struct {
+	organizationMemberSpec
+	MemberID     string
+	InvitationID string
+}
+
+func (s organizationMemberStatus) state() string {
+	if s.MemberID != "" {
+		return "active"
+	}
+	return "invited"
+}
+
+// organizationMemberSync lists the invitees to invite, to re-invite with new
+// access and to remove, each sorted by email.
+type organizationMemberSync struct {
+	Invite   []string
+	Reinvite []string
+	Remove   []string
+}
+
+// removals are the invitees losing their membership or invitation, either
+// for good or until they accept the invitation with their new access.
+func (s organizationMemberSync) removals() []string {
+	removals := append(append([]string{}, s.Remove...), s.Reinvite...)
+	sort.Strings(removals)
+	return removals
+}
+
+func planOrganizationMemberSync(desired map[string]organizationMemberSpec, current map[string]organizationMemberStatus, self string) organizationMemberSync {
+	sync := organizationMemberSync{}
+	for email, spec := range desired {
+		status, ok := current[email]
+		switch {
+		case !ok:
+			sync.Invite = append(sync.Invite, email)
+		case !spec.sameAccess(status.organizationMemberSpec):
+			sync.Reinvite = append(sync.Reinvite, email)
+		}
+	}
+	for email := range current {
+		if _, ok := desired[email]; !ok && !strings.EqualFold(email, self) {
+			sync.Remove = append(sync.Remove, email)
+		}
+	}
+	sort.Strings(sync.Invite)
+	sort.Strings(sync.Reinvite)
+	sort.Strings(sync.Remove)
+	return sync
+}
+
+func resourceMetalOrganizationMembers() *schema.Resource {
+	memberResource := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"invitee": {
+				Type:         schema.TypeString,
+				Description:  "The email address of the member, compared case-insensitively",
+				Required:     true,
+				ValidateFunc: equinix_validation.StringIsEmailAddress,
+				StateFunc:    converters.ToLowerIf,
+			},
+			"roles": {
+				Type:        schema.TypeSet,
+				Description: "Organization roles (owner, collaborator, limited_collaborator, billing)",
+				Required:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"projects_ids": {
+				Type:        schema.TypeSet,
+				Description: "Project IDs the member has access to within the organization. If the member is an 'owner', the projects list should be empty.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+			"message": {
+				Type:        schema.TypeString,
+				Description: "A message to the invitee (only used during the invitation stage)",
+				Optional:    true,
+			},
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceMetalOrganizationMembersCreate,
+		ReadContext:   resourceMetalOrganizationMembersRead,
+		UpdateContext: resourceMetalOrganizationMembersUpdate,
+		DeleteContext: resourceMetalOrganizationMembersDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				d.Set("organization_id", d.Id())
+				d.Set("max_removals", organizationMembersDefaultMaxRemovals)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		CustomizeDiff: resourceMetalOrganizationMembersCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": {
+				Type:         schema.TypeString,
+				Description:  "The organization whose members are managed",
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			// A map keyed by invitee would be closer to how teams list their
+			// members, but map values of SDKv2 schemas can only be primitives,
+			// so each invitee is a block and duplicates fail the plan.
+			"member": {
+				Type:        schema.TypeSet,
+				Description: "The complete list of organization members. Members and invitations not listed here are removed, except for the user the provider authenticates as",
+				Optional:    true,
+				Elem:        memberResource,
+				Set:         hashOrganizationMember(memberResource),
+			},
+			"max_removals": {
+				Type:         schema.TypeInt,
+				Description:  "The most members and invitations one plan may remove or re-invite with changed roles or projects. Plans which would remove more fail and list the invitees, so they can be reviewed before the limit is raised",
+				Optional:     true,
+				Default:      organizationMembersDefaultMaxRemovals,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"member_state": {
+				Type:        schema.TypeMap,
+				Description: "The state of each membership keyed by invitee, 'invited' when an invitation is open, 'active' when the user is an organization member",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"pending_invitations": {
+				Type:        schema.TypeList,
+				Description: "Invitees which did not accept their invitation yet",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+const organizationMembersDefaultMaxRemovals = 5
+
+// hashOrganizationMember hashes member blocks with the invitee in lower case.
+// The API returns email addresses in lower case, and set elements are only
+// compared by their hash, so an invitee spelled with capitals in the
+// configuration would otherwise be planned as removed and added again.
+func hashOrganizationMember(member *schema.Resource) schema.SchemaSetFunc {
+	hash := schema.HashResource(member)
+	return func(v interface{}) int {
+		m := map[string]interface{}{}
+		for k, val := range v.(map[string]interface{}) {
+			m[k] = val
+		}
+		if invitee, ok := m["invitee"].(string); ok {
+			m["invitee"] = strings.ToLower(invitee)
+		}
+		return hash(m)
+	}
+}
+
+func expandOrganizationMemberSpecs(members *schema.Set) map[string]organizationMemberSpec {
+	specs := map[string]organizationMemberSpec{}
+	for _, raw := range members.List() {
+		m := raw.(map[string]interface{})
+		roles := converters.IfArrToStringArr(m["roles"].(*schema.Set).List())
+		projectIDs := converters.IfArrToStringArr(m["projects_ids"].(*schema.Set).List())
+		sort.Strings(roles)
+		sort.Strings(projectIDs)
+		specs[strings.ToLower(m["invitee"].(string))] = organizationMemberSpec{
+			Roles:      roles,
+			ProjectIDs: projectIDs,
+			Message:    strings.TrimSpace(m["message"].(string)),
+		}
+	}
+	return specs
+}
+
+// listOrganizationMembers returns the members and open invitations of an
+// organization keyed by lower case email.
+func listOrganizationMembers(ctx context.Context, client *metalv1.APIClient, orgID string) (map[string]organizationMemberStatus, error) {
+	invitations, resp, err := listMetalOrganizationInvitations(ctx, client, orgID)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	members, resp, err := listMetalOrganizationMembers(ctx, client, orgID)
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+
+	current := map[string]organizationMemberStatus{}
+	for _, inv := range invitations {
+		projectIDs := []string{}
+		for _, project := range inv.Projects {
+			projectIDs = append(projectIDs, path.Base(project.Href))
+		}
+		roles := append([]string{}, inv.Roles...)
+		sort.Strings(roles)
+		sort.Strings(projectIDs)
+		current[strings.ToLower(inv.Invitee)] = organizationMemberStatus{
+			organizationMemberSpec: organizationMemberSpec{Roles: roles, ProjectIDs: projectIDs},
+			InvitationID:           inv.ID,
+		}
+	}
+	for _, mbr := range members {
+		projectIDs := []string{}
+		for _, project := range mbr.Projects {
+			projectIDs = append(projectIDs, path.Base(project.Href))
+		}
+		roles := append([]string{}, mbr.Roles...)
+		sort.Strings(roles)
+		sort.Strings(projectIDs)
+		current[strings.ToLower(mbr.User.Email)] = organizationMemberStatus{
+			organizationMemberSpec: organizationMemberSpec{Roles: roles, ProjectIDs: projectIDs},
+			MemberID:               mbr.ID,
+		}
+	}
+	return current, nil
+}
+
+func currentUserEmail(ctx context.Context, client *metalv1.APIClient) (string, error) {
+	user, resp, err := client.UsersApi.FindCurrentUser(ctx).Execute()
+	if err != nil {
+		return "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return strings.ToLower(user.GetEmail()), nil
+}
+
+func checkOrganizationMemberRemovals(removals []string, maxRemovals int) error {
+	if len(removals) > maxRemovals {
+		return fmt.Errorf("removing or re-inviting %d organization members exceeds max_removals (%d), review the removals and raise the limit if they are intended: %s",
+			len(removals), maxRemovals, strings.Join(removals, ", "))
+	}
+	return nil
+}
+
+// resourceMetalOrganizationMembersCustomizeDiff enforces max_removals at plan
+// time, counting members added out of band and re-invites as well.
+func resourceMetalOrganizationMembersCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("member") || !d.NewValueKnown("organization_id") {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, raw := range d.Get("member").(*schema.Set).List() {
+		invitee := strings.ToLower(raw.(map[string]interface{})["invitee"].(string))
+		if seen[invitee] {
+			return fmt.Errorf("invitee %s is listed in more than one member block", invitee)
+		}
+		seen[invitee] = true
+	}
+	orgID := d.Get("organization_id").(string)
+	if orgID == "" {
+		return nil
+	}
+
+	client := meta.(*config.Config).Metalgo
+	current, err := listOrganizationMembers(ctx, client, orgID)
+	if err != nil {
+		log.Printf("[WARN] Could not list members of organization (%s) to check max_removals: %s", orgID, err)
+		return nil
+	}
+	self, err := currentUserEmail(ctx, client)
+	if err != nil {
+		log.Printf("[WARN] Could not read the current user to check max_removals: %s", err)
+		return nil
+	}
+
+	sync := planOrganizationMemberSync(expandOrganizationMemberSpecs(d.Get("member").(*schema.Set)), current, self)
+	return checkOrganizationMemberRemovals(sync.removals(), d.Get("max_removals").(int))
+}
+
+func resourceMetalOrganizationMembersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	orgID := d.Get("organization_id").(string)
+	if err := syncOrganizationMembers(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(orgID)
+	return resourceMetalOrganizationMembersRead(ctx, d, meta)
+}
+
+func resourceMetalOrganizationMembersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := syncOrganizationMembers(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceMetalOrganizationMembersRead(ctx, d, meta)
+}
+
+// syncOrganizationMembers invites, re-invites and removes members so the
+// organization matches the configuration. The API can not change the roles or
+// projects of an invitation or membership, so changed access is applied by
+// removing the invitee and sending a new invitation. Members lose their access
+// until they accept it, so re-invites count towards max_removals.
+func syncOrganizationMembers(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	orgID := d.Get("organization_id").(string)
+
+	current, err := listOrganizationMembers(ctx, client, orgID)
+	if err != nil {
+		return err
+	}
+	self, err := currentUserEmail(ctx, client)
+	if err != nil {
+		return err
+	}
+	desired := expandOrganizationMemberSpecs(d.Get("member").(*schema.Set))
+
+	sync := planOrganizationMemberSync(desired, current, self)
+	if err := checkOrganizationMemberRemovals(sync.removals(), d.Get("max_removals").(int)); err != nil {
+		return err
+	}
+
+	for _, email := range sync.Remove {
+		log.Printf("[DEBUG] Removing %s from organization %s", email, orgID)
+		if err := removeOrganizationMember(ctx, client, orgID, current[email]); err != nil {
+			return fmt.Errorf("error removing %s from organization %s: %w", email, orgID, err)
+		}
+	}
+	for _, email := range sync.Reinvite {
+		log.Printf("[DEBUG] Re-inviting %s to organization %s with roles %v", email, orgID, desired[email].Roles)
+		if err := removeOrganizationMember(ctx, client, orgID, current[email]); err != nil {
+			return fmt.Errorf("error removing %s from organization %s: %w", email, orgID, err)
+		}
+		if err := inviteOrganizationMember(ctx, client, orgID, email, desired[email]); err != nil {
+			return err
+		}
+	}
+	for _, email := range sync.Invite {
+		log.Printf("[DEBUG] Inviting %s to organization %s with roles %v", email, orgID, desired[email].Roles)
+		if err := inviteOrganizationMember(ctx, client, orgID, email, desired[email]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inviteOrganizationMember(ctx context.Context, client *metalv1.APIClient, orgID, email string, spec organizationMemberSpec) error {
+	createRequest := metalInvitationInput{
+		Invitee:     email,
+		Roles:       spec.Roles,
+		ProjectsIDs: spec.ProjectIDs,
+		Message:     spec.Message,
+	}
+	if resp, err := metalRequest(ctx, client, http.MethodPost, "organizations/"+orgID+"/invitations", createRequest, nil); err != nil {
+		return fmt.Errorf("error inviting %s to organization %s: %w", email, orgID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	return nil
+}
+
+func removeOrganizationMember(ctx context.Context, client *metalv1.APIClient, orgID string, status organizationMemberStatus) error {
+	var resp *http.Response
+	var err error
+	if status.MemberID != "" {
+		resp, err = metalRequest(ctx, client, http.MethodDelete, "organizations/"+orgID+"/members/"+status.MemberID, nil, nil)
+	} else {
+		// declining an invitation is the request that deletes it
+		resp, err = client.InvitationsApi.DeclineInvitation(ctx, status.InvitationID).Execute()
+	}
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceMetalOrganizationMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	orgID := d.Id()
+
+	current, err := listOrganizationMembers(ctx, client, orgID)
+	if err != nil {
+		// If the org was destroyed, mark as gone.
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] Organization (%s) not found, removing members from state", orgID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	self, err := currentUserEmail(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Messages are not returned by the API, keep the configured ones
+	messages := map[string]string{}
+	for email, spec := range expandOrganizationMemberSpecs(d.Get("member").(*schema.Set)) {
+		messages[email] = spec.Message
+	}
+
+	members := []interface{}{}
+	memberState := map[string]interface{}{}
+	pending := []string{}
+	for email, status := range current {
+		if _, configured := messages[email]; !configured && strings.EqualFold(email, self) {
+			continue
+		}
+		members = append(members, map[string]interface{}{
+			"invitee":      email,
+			"roles":        converters.StringArrToIfArr(status.Roles),
+			"projects_ids": converters.StringArrToIfArr(status.ProjectIDs),
+			"message":      messages[email],
+		})
+		memberState[email] = status.state()
+		if status.InvitationID != "" {
+			pending = append(pending, email)
+		}
+	}
+	sort.Strings(pending)
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"organization_id":     orgID,
+		"member":              members,
+		"member_state":        memberState,
+		"pending_invitations": pending,
+	}))
+}
+
+func resourceMetalOrganizationMembersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	orgID := d.Id()
+
+	current, err := listOrganizationMembers(ctx, client, orgID)
+	if err != nil {
+		if equinix_errors.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	self, err := currentUserEmail(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	removals := []string{}
+	for email := range expandOrganizationMemberSpecs(d.Get("member").(*schema.Set)) {
+		if _, ok := current[email]; ok && !strings.EqualFold(email, self) {
+			removals = append(removals, email)
+		}
+	}
+	sort.Strings(removals)
+	if err := checkOrganizationMemberRemovals(removals, d.Get("max_removals").(int)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, email := range removals {
+		if err := removeOrganizationMember(ctx, client, orgID, current[email]); err != nil {
+			return diag.Errorf("error removing %s from organization %s: %s", email, orgID, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,185 @@
+package equinix
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_planOrganizationMemberSync(t *testing.T) {
+	desired := map[string]organizationMemberSpec{
+		"new@example.com":     {Roles: []string{"collaborator"}, ProjectIDs: []string{"p1"}},
+		"same@example.com":    {Roles: []string{"collaborator"}, ProjectIDs: []string{"p1", "p2"}},
+		"changed@example.com": {Roles: []string{"owner"}},
+	}
+	current := map[string]organizationMemberStatus{
+		"same@example.com": {
+			organizationMemberSpec: organizationMemberSpec{Roles: []string{"collaborator"}, ProjectIDs: []string{"p1", "p2"}},
+			MemberID:               "m1",
+		},
+		"changed@example.com": {
+			organizationMemberSpec: organizationMemberSpec{Roles: []string{"collaborator"}, ProjectIDs: []string{"p1"}},
+			InvitationID:           "i1",
+		},
+		"gone@example.com": {
+			organizationMemberSpec: organizationMemberSpec{Roles: []string{"billing"}},
+			MemberID:               "m2",
+		},
+		"self@example.com": {
+			organizationMemberSpec: organizationMemberSpec{Roles: []string{"owner"}},
+			MemberID:               "m3",
+		},
+	}
+
+	sync := planOrganizationMemberSync(desired, current, "self@example.com")
+	assert.Equal(t, organizationMemberSync{
+		Invite:   []string{"new@example.com"},
+		Reinvite: []string{"changed@example.com"},
+		Remove:   []string{"gone@example.com"},
+	}, sync)
+	assert.Equal(t, []string{"changed@example.com", "gone@example.com"}, sync.removals(), "re-invites count as removals")
+}
+
+func Test_checkOrganizationMemberRemovals(t *testing.T) {
+	assert.NoError(t, checkOrganizationMemberRemovals([]string{"a@example.com"}, 1))
+	err := checkOrganizationMemberRemovals([]string{"a@example.com", "b@example.com"}, 1)
+	assert.ErrorContains(t, err, "a@example.com, b@example.com")
+}
+
+func TestMetalOrganizationMembersDiff_inviteeCase(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+	r := resourceMetalOrganizationMembers()
+
+	// The API returns email addresses in lower case
+	d := r.TestResourceData()
+	d.SetId("org")
+	d.Set("organization_id", "org")
+	d.Set("max_removals", organizationMembersDefaultMaxRemovals)
+	d.Set("member", []interface{}{map[string]interface{}{
+		"invitee": "alice@example.com",
+		"roles":   []interface{}{"collaborator"},
+	}})
+	d.Set("member_state", map[string]interface{}{"alice@example.com": "active"})
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization_id": "org",
+		"member": []interface{}{map[string]interface{}{
+			"invitee": "Alice@Example.com",
+			"roles":   []interface{}{"collaborator"},
+		}},
+	})
+	diff, err := r.Diff(context.Background(), d.State(), config, meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for k := range diff.Attributes {
+		assert.NotRegexp(t, `^member\.`, k, "no member changes are planned")
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"organization_id": "org",
+		"member": []interface{}{
+			map[string]interface{}{"invitee": "Alice@Example.com", "roles": []interface{}{"collaborator"}},
+			map[string]interface{}{"invitee": "alice@example.com", "roles": []interface{}{"owner"}},
+		},
+	})
+	_, err = r.Diff(context.Background(), d.State(), config, meta)
+	assert.ErrorContains(t, err, "invitee alice@example.com is listed in more than one member block")
+}
+
+// packngoListOrganizationMembers is the packngo implementation of
+// listOrganizationMembers, kept to compare the members both list
+func packngoListOrganizationMembers(client *packngo.Client, orgID string) (map[string]organizationMemberStatus, error) {
+	invitations, _, err := client.Invitations.List(orgID, &packngo.ListOptions{Includes: []string{"user"}})
+	if err != nil {
+		return nil, err
+	}
+	members, _, err := client.Members.List(orgID, &packngo.ListOptions{Includes: []string{"user"}})
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]organizationMemberStatus{}
+	for _, inv := range invitations {
+		projectIDs := []string{}
+		for _, project := range inv.Projects {
+			projectIDs = append(projectIDs, path.Base(project.Href))
+		}
+		sort.Strings(inv.Roles)
+		sort.Strings(projectIDs)
+		current[strings.ToLower(inv.Invitee)] = organizationMemberStatus{
+			organizationMemberSpec: organizationMemberSpec{Roles: inv.Roles, ProjectIDs: projectIDs},
+			InvitationID:           inv.ID,
+		}
+	}
+	for _, mbr := range members {
+		projectIDs := []string{}
+		for _, project := range mbr.Projects {
+			projectIDs = append(projectIDs, path.Base(project.URL))
+		}
+		sort.Strings(mbr.Roles)
+		sort.Strings(projectIDs)
+		current[strings.ToLower(mbr.User.Email)] = organizationMemberStatus{
+			organizationMemberSpec: organizationMemberSpec{Roles: mbr.Roles, ProjectIDs: projectIDs},
+			MemberID:               mbr.ID,
+		}
+	}
+	return current, nil
+}
+
+func TestListOrganizationMembers_recorded(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, recordedMetalOrganizationMembers)
+
+	current, err := listOrganizationMembers(context.Background(), meta.Metalgo, testMetalOrganizationID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	packngoCurrent, err := packngoListOrganizationMembers(packngoClient(meta), testMetalOrganizationID)
+	if err != nil {
+		t.Fatalf("unexpected packngo error: %v", err)
+	}
+	assert.Equal(t, packngoCurrent, current)
+	assert.Equal(t, "6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c", current["alice@example.com"].MemberID)
+	assert.Equal(t, "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", current["bob@example.com"].InvitationID)
+}
+
+func TestSyncOrganizationMembers_requests(t *testing.T) {
+	responses := map[string]string{
+		"POST /metal/v1/organizations/" + testMetalOrganizationID + "/invitations":                                    `{"id": "new"}`,
+		"DELETE /metal/v1/organizations/" + testMetalOrganizationID + "/members/6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c": "",
+	}
+	for k, v := range recordedMetalOrganizationMembers {
+		responses[k] = v
+	}
+	fake, meta := newMetalFakeAPI(t, responses)
+
+	d := schema.TestResourceDataRaw(t, resourceMetalOrganizationMembers().Schema, map[string]interface{}{
+		"organization_id": testMetalOrganizationID,
+		"max_removals":    1,
+		"member": []interface{}{
+			map[string]interface{}{
+				"invitee":      "bob@example.com",
+				"roles":        []interface{}{"owner"},
+				"projects_ids": []interface{}{"1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+			},
+			map[string]interface{}{"invitee": "carol@example.com", "roles": []interface{}{"limited_collaborator"}, "message": " welcome "},
+		},
+	})
+	if err := syncOrganizationMembers(context.Background(), d, meta); err != nil {
+		t.Fatalf("unexpected sync error: %v", err)
+	}
+
+	_, removed := fake.request("DELETE /metal/v1/organizations/" + testMetalOrganizationID + "/members/6f5e4d3c-2b1a-4f9e-8d7c-6b5a4f3e2d1c")
+	assert.True(t, removed, "alice is not configured and is removed")
+	body, _ := fake.request("POST /metal/v1/organizations/" + testMetalOrganizationID + "/invitations")
+	assert.JSONEq(t, `{"invitee": "carol@example.com", "roles": ["limited_collaborator"], "message": "welcome"}`, body)
+	for _, call := range fake.requestOrder() {
+		assert.NotContains(t, call, "/invitations/", "bob's invitation is unchanged")
+	}
+}
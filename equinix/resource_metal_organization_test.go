@@ -0,0 +1,123 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalOrganization = `{
+  "id": "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e",
+  "name": "Terraform Acceptance",
+  "description": "organization for the acceptance tests",
+  "website": "https://www.equinix.com",
+  "twitter": "@equinixmetal",
+  "logo": "",
+  "created_at": "2023-05-04T10:11:12Z",
+  "updated_at": "2023-06-07T13:14:15Z",
+  "address": {"address": "123 Main St", "city": "San Francisco", "country": "US", "state": "CA", "zip_code": "94105"},
+  "projects": [{"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}]
+}`
+
+// packngoResourceMetalOrganizationRead is the packngo implementation of
+// resourceMetalOrganizationRead, kept to compare the states both write
+func packngoResourceMetalOrganizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	key, _, err := client.Organizations.Get(d.Id(), &packngo.GetOptions{Includes: []string{"address"}})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	address := map[string]interface{}{
+		"address":  key.Address.Address,
+		"city":     *key.Address.City,
+		"country":  key.Address.Country,
+		"state":    *key.Address.State,
+		"zip_code": key.Address.ZipCode,
+	}
+	d.SetId(key.ID)
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"name":        key.Name,
+		"description": key.Description,
+		"website":     key.Website,
+		"twitter":     key.Twitter,
+		"logo":        key.Logo,
+		"created":     key.Created,
+		"updated":     key.Updated,
+		"address":     []interface{}{address},
+	}))
+}
+
+func TestResourceMetalOrganizationRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalOrganization(), "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e", nil, map[string]string{
+		"GET /metal/v1/organizations/3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e": recordedMetalOrganization,
+	}, resourceMetalOrganizationRead, packngoResourceMetalOrganizationRead)
+
+	assert.Equal(t, "Terraform Acceptance", state["name"])
+	assert.Equal(t, "2023-05-04T10:11:12Z", state["created"])
+	assert.Equal(t, "San Francisco", state["address.0.city"])
+}
+
+func TestResourceMetalOrganizationRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := schema.TestResourceDataRaw(t, resourceMetalOrganization().Schema, nil)
+	d.SetId("3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e")
+	if diags := resourceMetalOrganizationRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Equal(t, "", d.Id(), "a deleted organization is removed from the state")
+}
+
+func TestResourceMetalOrganizationUpdate_changedFields(t *testing.T) {
+	id := "3e7b8a6c-2f1d-4c5b-9a8e-7d6c5b4a3f2e"
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"PUT /metal/v1/organizations/" + id: recordedMetalOrganization,
+		"GET /metal/v1/organizations/" + id: recordedMetalOrganization,
+	})
+
+	r := resourceMetalOrganization()
+	config := map[string]interface{}{
+		"name":        "Terraform Acceptance",
+		"description": "organization for the acceptance tests",
+		"website":     "https://www.equinix.com",
+		"twitter":     "@equinixmetal",
+		"address": []interface{}{map[string]interface{}{
+			"address": "123 Main St", "city": "San Francisco", "country": "US", "state": "CA", "zip_code": "94105",
+		}},
+	}
+	state := schema.TestResourceDataRaw(t, r.Schema, config)
+	state.SetId(id)
+	if diags := resourceMetalOrganizationRead(context.Background(), state, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	config["description"] = "renamed"
+	diff, err := r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diags := resourceMetalOrganizationUpdate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected update error: %v", diags)
+	}
+
+	body, _ := fake.request("PUT /metal/v1/organizations/" + id)
+	assert.JSONEq(t, `{"description": "renamed"}`, body, "only the changed fields are sent")
+}
@@ -121,40 +121,62 @@ func resourceMetalPort() *schema.Resource {
 				Computed:    true,
 				Description: "MAC address of the port",
 			},
+			"transition_steps": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Steps of the planned change of the port, in the order they are run. Only set in plans which change the port",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
+		CustomizeDiff: resourceMetalPortCustomizeDiff,
 	}
 }
 
 func resourceMetalPortUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	start := time.Now()
-	cpr, _, err := getClientPortResource(d, meta)
+	cpr, _, err := getClientPortResource(ctx, d, meta)
 	if err != nil {
-		return diag.FromErr(equinix_errors.FriendlyError(err))
+		return diag.FromErr(err)
 	}
 
-	for _, f := range [](func(*ClientPortResource) error){
+	// VLANs are only attached and detached when they are configured, the
+	// port may share them with equinix_metal_port_vlan_attachment resources
+	removeVlans, assignVlans := noPortChange, noPortChange
+	if portVlansConfigured(d.GetRawConfig()) {
+		removeVlans, assignVlans = batchVlans(start, true), batchVlans(start, false)
+	}
+	for _, f := range [](func(context.Context, *ClientPortResource) error){
 		portSanityChecks,
-		batchVlans(ctx, start, true),
+		removeVlans,
 		makeDisbond,
 		convertToL2,
 		makeBond,
 		convertToL3,
-		batchVlans(ctx, start, false),
+		assignVlans,
 		updateNativeVlan,
 	} {
-		if err := f(cpr); err != nil {
-			return diag.FromErr(equinix_errors.FriendlyError(err))
+		if err := f(ctx, cpr); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceMetalPortRead(ctx, d, meta)
+	// The state keeps the steps of the plan that was applied, the next
+	// refresh clears them
+	steps := d.Get("transition_steps")
+	if diags := resourceMetalPortRead(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return diag.FromErr(d.Set("transition_steps", steps))
+}
+
+func noPortChange(context.Context, *ClientPortResource) error {
+	return nil
 }
 
 func resourceMetalPortRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	port, err := getPortByResourceData(d, client)
+	port, err := getPortByResourceData(ctx, d, client)
 	if err != nil {
 		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
 			log.Printf("[WARN] Port (%s) not accessible, removing from state", d.Id())
@@ -165,16 +187,17 @@ func resourceMetalPortRead(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 	m := map[string]interface{}{
-		"port_id":           port.ID,
-		"type":              port.Type,
-		"name":              port.Name,
-		"network_type":      port.NetworkType,
-		"mac":               port.Data.MAC,
-		"bonded":            port.Data.Bonded,
-		"disbond_supported": port.DisbondOperationSupported,
+		"port_id":           port.GetId(),
+		"type":              string(port.GetType()),
+		"name":              port.GetName(),
+		"network_type":      string(port.GetNetworkType()),
+		"mac":               port.Data.GetMac(),
+		"bonded":            port.Data.GetBonded(),
+		"disbond_supported": port.GetDisbondOperationSupported(),
+		"transition_steps":  []string{},
 	}
-	l2 := slices.Contains(l2Types, port.NetworkType)
-	l3 := slices.Contains(l3Types, port.NetworkType)
+	l2 := slices.Contains(l2Types, string(port.GetNetworkType()))
+	l3 := slices.Contains(l3Types, string(port.GetNetworkType()))
 
 	if l2 {
 		m["layer2"] = true
@@ -184,24 +207,24 @@ func resourceMetalPortRead(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	if port.NativeVirtualNetwork != nil {
-		m["native_vlan_id"] = port.NativeVirtualNetwork.ID
+		m["native_vlan_id"] = port.NativeVirtualNetwork.GetId()
 	}
 
 	vlans := []string{}
 	vxlans := []int{}
-	for _, n := range port.AttachedVirtualNetworks {
-		vlans = append(vlans, n.ID)
-		vxlans = append(vxlans, n.VXLAN)
+	for _, n := range portVirtualNetworks(port) {
+		vlans = append(vlans, n.GetId())
+		vxlans = append(vxlans, int(n.GetVxlan()))
 	}
 	m["vlan_ids"] = vlans
 	m["vxlan_ids"] = vxlans
 
 	if port.Bond != nil {
-		m["bond_id"] = port.Bond.ID
-		m["bond_name"] = port.Bond.Name
+		m["bond_id"] = port.Bond.GetId()
+		m["bond_name"] = port.Bond.GetName()
 	}
 
-	d.SetId(port.ID)
+	d.SetId(port.GetId())
 	return diag.FromErr(equinix_schema.SetMap(d, m))
 }
 
@@ -209,9 +232,12 @@ func resourceMetalPortDelete(ctx context.Context, d *schema.ResourceData, meta i
 	resetRaw, resetOk := d.GetOk("reset_on_delete")
 	if resetOk && resetRaw.(bool) {
 		start := time.Now()
-		cpr, resp, err := getClientPortResource(d, meta)
-		if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-			return diag.FromErr(err)
+		cpr, resp, err := getClientPortResource(ctx, d, meta)
+		if err != nil {
+			if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+				return diag.FromErr(err)
+			}
+			return nil
 		}
 
 		// to reset the port to defaults we iterate through helpers (used in
@@ -229,12 +255,12 @@ func resourceMetalPortDelete(ctx context.Context, d *schema.ResourceData, meta i
 		}); err != nil {
 			return diag.FromErr(err)
 		}
-		for _, f := range [](func(*ClientPortResource) error){
-			batchVlans(ctx, start, true),
+		for _, f := range [](func(context.Context, *ClientPortResource) error){
+			batchVlans(start, true),
 			makeBond,
 			convertToL3,
 		} {
-			if err := f(cpr); err != nil {
+			if err := f(ctx, cpr); err != nil {
 				return diag.FromErr(err)
 			}
 		}
@@ -1,17 +1,16 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 var (
@@ -356,7 +355,7 @@ func TestAccMetalPort_hybridBonded(t *testing.T) {
 }
 
 func testAccMetalPortDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	port_ids := []string{}
 
@@ -369,7 +368,7 @@ func testAccMetalPortDestroyed(s *terraform.State) error {
 		}
 	}
 	for _, pid := range port_ids {
-		p, _, err := client.Ports.Get(pid, nil)
+		p, _, err := client.PortsApi.FindPortById(context.Background(), pid).Execute()
 		if err != nil {
 			return fmt.Errorf("Error getting port %s during destroy check", pid)
 		}
@@ -391,11 +390,8 @@ func testAccWaitForPortActive(deviceName, portName string) resource.ImportStateI
 			return "", fmt.Errorf("No Record ID is set")
 		}
 
-		meta := testAccProvider.Meta()
-		rd := new(schema.ResourceData)
-		meta.(*config.Config).AddModuleToMetalUserAgent(rd)
-		client := meta.(*config.Config).Metal
-		device, _, err := client.Devices.Get(rs.Primary.ID, &packngo.GetOptions{Includes: []string{"ports"}})
+		client := testAccProvider.Meta().(*config.Config).Metalgo
+		device, _, err := client.DevicesApi.FindDeviceById(context.Background(), rs.Primary.ID).Include([]string{"ports"}).Execute()
 		if err != nil {
 			return "", fmt.Errorf("error while fetching device with Id [%s], error: %w", rs.Primary.ID, err)
 		}
@@ -407,8 +403,8 @@ func testAccWaitForPortActive(deviceName, portName string) resource.ImportStateI
 		}
 
 		for _, port := range device.NetworkPorts {
-			if port.Name == portName {
-				return port.ID, nil
+			if port.GetName() == portName {
+				return port.GetId(), nil
 			}
 		}
 
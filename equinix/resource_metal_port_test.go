@@ -0,0 +1,98 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	recordedMetalPortID = "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a"
+	recordedMetalPort   = `{
+  "id": "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a",
+  "name": "bond0",
+  "type": "NetworkBondPort",
+  "network_type": "hybrid-bonded",
+  "data": {"bonded": true, "mac": "b4:96:91:aa:bb:cc"},
+  "disbond_operation_supported": true,
+  "native_virtual_network": {"id": "2f3e4d5c-6b7a-4890-a1b2-c3d4e5f6a7b8", "vxlan": 1001, "href": "/metal/v1/virtual-networks/2f3e4d5c-6b7a-4890-a1b2-c3d4e5f6a7b8"},
+  "virtual_networks": [
+    {"id": "2f3e4d5c-6b7a-4890-a1b2-c3d4e5f6a7b8", "vxlan": 1001, "href": "/metal/v1/virtual-networks/2f3e4d5c-6b7a-4890-a1b2-c3d4e5f6a7b8"},
+    {"id": "8b7a6c5d-4e3f-4a2b-9c1d-0e9f8a7b6c5d", "vxlan": 1002, "href": "/metal/v1/virtual-networks/8b7a6c5d-4e3f-4a2b-9c1d-0e9f8a7b6c5d"}
+  ],
+  "href": "/metal/v1/ports/7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a"
+}`
+)
+
+// packngoResourceMetalPortRead is the packngo implementation of
+// resourceMetalPortRead, kept to compare the states both write
+func packngoResourceMetalPortRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	port, _, err := client.Ports.Get(d.Get("port_id").(string), &packngo.GetOptions{Includes: portIncludes})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] Port (%s) not accessible, removing from state", d.Id())
+			d.SetId("")
+
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	m := map[string]interface{}{
+		"port_id":           port.ID,
+		"type":              port.Type,
+		"name":              port.Name,
+		"network_type":      port.NetworkType,
+		"mac":               port.Data.MAC,
+		"bonded":            port.Data.Bonded,
+		"disbond_supported": port.DisbondOperationSupported,
+		"transition_steps":  []string{},
+	}
+	if slices.Contains(l2Types, port.NetworkType) {
+		m["layer2"] = true
+	}
+	if slices.Contains(l3Types, port.NetworkType) {
+		m["layer2"] = false
+	}
+
+	if port.NativeVirtualNetwork != nil {
+		m["native_vlan_id"] = port.NativeVirtualNetwork.ID
+	}
+
+	vlans := []string{}
+	vxlans := []int{}
+	for _, n := range port.AttachedVirtualNetworks {
+		vlans = append(vlans, n.ID)
+		vxlans = append(vxlans, n.VXLAN)
+	}
+	m["vlan_ids"] = vlans
+	m["vxlan_ids"] = vxlans
+
+	if port.Bond != nil {
+		m["bond_id"] = port.Bond.ID
+		m["bond_name"] = port.Bond.Name
+	}
+
+	d.SetId(port.ID)
+	return diag.FromErr(equinix_schema.SetMap(d, m))
+}
+
+func TestResourceMetalPortRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalPort(), recordedMetalPortID, map[string]interface{}{"port_id": recordedMetalPortID}, map[string]string{
+		"GET /metal/v1/ports/" + recordedMetalPortID: recordedMetalPort,
+	}, resourceMetalPortRead, packngoResourceMetalPortRead)
+
+	assert.Equal(t, "2f3e4d5c-6b7a-4890-a1b2-c3d4e5f6a7b8", state["native_vlan_id"])
+	assert.Equal(t, "2", state["vlan_ids.#"])
+}
@@ -1,7 +1,7 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
 	"log"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
@@ -9,18 +9,19 @@ import (
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func resourceMetalPortVlanAttachment() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalPortVlanAttachmentCreate,
-		Read:   resourceMetalPortVlanAttachmentRead,
-		Delete: resourceMetalPortVlanAttachmentDelete,
-		Update: resourceMetalPortVlanAttachmentUpdate,
+		CreateContext: resourceMetalPortVlanAttachmentCreate,
+		ReadContext:   resourceMetalPortVlanAttachmentRead,
+		DeleteContext: resourceMetalPortVlanAttachmentDelete,
+		UpdateContext: resourceMetalPortVlanAttachmentUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -69,32 +70,33 @@ func resourceMetalPortVlanAttachment() *schema.Resource {
 	}
 }
 
-func resourceMetalPortVlanAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// portVlanAttachmentIncludes are the device relations the attachments are
+// read from
+var portVlanAttachmentIncludes = []string{"virtual_networks", "project", "native_virtual_network"}
+
+func resourceMetalPortVlanAttachmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	deviceID := d.Get("device_id").(string)
 	pName := d.Get("port_name").(string)
 	vlanVNID := d.Get("vlan_vnid").(int)
 
-	dev, _, err := client.Devices.Get(deviceID, &packngo.GetOptions{
-		Includes: []string{"virtual_networks,project,native_virtual_network"},
-	})
+	dev, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceID).Include(portVlanAttachmentIncludes).Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	portFound := false
 	vlanFound := false
 	vlanID := ""
-	var port packngo.Port
+	var port metalv1.Port
 	for _, p := range dev.NetworkPorts {
-		if p.Name == pName {
+		if p.GetName() == pName {
 			portFound = true
 			port = p
-			for _, n := range p.AttachedVirtualNetworks {
-				if vlanVNID == n.VXLAN {
+			for _, n := range portVirtualNetworks(&p) {
+				if vlanVNID == int(n.GetVxlan()) {
 					vlanFound = true
-					vlanID = n.ID
+					vlanID = n.GetId()
 					break
 				}
 			}
@@ -102,83 +104,78 @@ func resourceMetalPortVlanAttachmentCreate(d *schema.ResourceData, meta interfac
 		}
 	}
 	if !portFound {
-		return fmt.Errorf("Device %s doesn't have port %s", deviceID, pName)
+		return diag.Errorf("Device %s doesn't have port %s", deviceID, pName)
 	}
 
-	par := &packngo.PortAssignRequest{PortID: port.ID}
 	if vlanFound {
 		log.Printf("Port %s already has VLAN %d assigned", pName, vlanVNID)
-		par.VirtualNetworkID = vlanID
 	} else {
-		projectID := dev.Project.ID
-		deviceMetro := dev.Metro.Code
-		deviceFacility := dev.Facility.Code
-		vlans, _, err := client.ProjectVirtualNetworks.List(projectID, nil)
+		projectID := dev.Project.GetId()
+		deviceMetro := dev.Metro.GetCode()
+		deviceFacility := dev.Facility.GetCode()
+		vlans, err := listProjectVlans(ctx, client, projectID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		for _, n := range vlans.VirtualNetworks {
+		for _, n := range vlans {
 			// looking up vlan with given vxlan, in the same location as
 			// the device - either in the same faclility or metro or both
-			vlanMetro := n.MetroCode
-			vlanFacility := n.FacilityCode
-			if n.VXLAN == vlanVNID {
+			vlanMetro := n.GetMetroCode()
+			vlanFacility := vlanFacilityCode(&n)
+			if int(n.GetVxlan()) == vlanVNID {
 				facilitiesMatch := deviceFacility == vlanFacility
 				metrosMatch := deviceMetro == vlanMetro
 				if metrosMatch || facilitiesMatch {
-					vlanID = n.ID
+					vlanID = n.GetId()
 					break
 				}
 			}
 		}
 		if len(vlanID) == 0 {
-			return fmt.Errorf("VLAN with VNID %d doesn't exist in project %s", vlanVNID, projectID)
+			return diag.Errorf("VLAN with VNID %d doesn't exist in project %s", vlanVNID, projectID)
 		}
 
-		par.VirtualNetworkID = vlanID
-
 		// Equinix Metal doesn't allow multiple VLANs to be assigned
 		// to the same port at the same time
-		lockId := "vlan-attachment-" + port.ID
+		lockId := "vlan-attachment-" + port.GetId()
 		mutexkv.Metal.Lock(lockId)
 		defer mutexkv.Metal.Unlock(lockId)
 
-		_, _, err = client.DevicePorts.Assign(par)
+		_, resp, err := client.PortsApi.AssignPort(ctx, port.GetId()).PortAssignInput(metalv1.PortAssignInput{Vnid: &vlanID}).Execute()
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 
-	d.SetId(port.ID + ":" + vlanID)
+	d.SetId(port.GetId() + ":" + vlanID)
 
 	native := d.Get("native").(bool)
 	if native {
-		_, _, err = client.DevicePorts.AssignNative(par)
+		_, resp, err := client.PortsApi.AssignNativeVlan(ctx, port.GetId()).Vnid(vlanID).Execute()
 		if err != nil {
-			return err
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 
-	return resourceMetalPortVlanAttachmentRead(d, meta)
+	return resourceMetalPortVlanAttachmentRead(ctx, d, meta)
 }
 
-func resourceMetalPortVlanAttachmentRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalPortVlanAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	deviceID := d.Get("device_id").(string)
 	pName := d.Get("port_name").(string)
 	vlanVNID := d.Get("vlan_vnid").(int)
 
-	dev, _, err := client.Devices.Get(deviceID, &packngo.GetOptions{Includes: []string{"virtual_networks,project,native_virtual_network"}})
+	dev, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceID).Include(portVlanAttachmentIncludes).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 
 		if equinix_errors.IsNotFound(err) {
 			log.Printf("[WARN] Device (%s) for Port Vlan Attachment not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	portFound := false
 	vlanFound := false
@@ -186,15 +183,15 @@ func resourceMetalPortVlanAttachmentRead(d *schema.ResourceData, meta interface{
 	vlanID := ""
 	vlanNative := false
 	for _, p := range dev.NetworkPorts {
-		if p.Name == pName {
+		if p.GetName() == pName {
 			portFound = true
-			portID = p.ID
-			for _, n := range p.AttachedVirtualNetworks {
-				if vlanVNID == n.VXLAN {
+			portID = p.GetId()
+			for _, n := range portVirtualNetworks(&p) {
+				if vlanVNID == int(n.GetVxlan()) {
 					vlanFound = true
-					vlanID = n.ID
+					vlanID = n.GetId()
 					if p.NativeVirtualNetwork != nil {
-						vlanNative = vlanID == p.NativeVirtualNetwork.ID
+						vlanNative = vlanID == p.NativeVirtualNetwork.GetId()
 					}
 					break
 				}
@@ -205,7 +202,7 @@ func resourceMetalPortVlanAttachmentRead(d *schema.ResourceData, meta interface{
 	if !portFound {
 		// TODO(displague) should we clear state if the port is unexpectedly
 		// gone? Can we treat this like a deletion?
-		return fmt.Errorf("Device %s doesn't have port %s", deviceID, pName)
+		return diag.Errorf("Device %s doesn't have port %s", deviceID, pName)
 	}
 	if !vlanFound {
 		d.SetId("")
@@ -216,60 +213,62 @@ func resourceMetalPortVlanAttachmentRead(d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func resourceMetalPortVlanAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalPortVlanAttachmentUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	if d.HasChange("native") {
 		native := d.Get("native").(bool)
 		portID := d.Get("port_id").(string)
 		if native {
 			vlanID := d.Get("vlan_id").(string)
-			par := &packngo.PortAssignRequest{PortID: portID, VirtualNetworkID: vlanID}
-			_, _, err := client.DevicePorts.AssignNative(par)
+			_, resp, err := client.PortsApi.AssignNativeVlan(ctx, portID).Vnid(vlanID).Execute()
 			if err != nil {
-				return err
+				return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 			}
 		} else {
-			_, _, err := client.DevicePorts.UnassignNative(portID)
+			_, resp, err := client.PortsApi.DeleteNativeVlan(ctx, portID).Execute()
 			if err != nil {
-				return err
+				return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 			}
 		}
 	}
-	return resourceMetalPortVlanAttachmentRead(d, meta)
+	return resourceMetalPortVlanAttachmentRead(ctx, d, meta)
 }
 
-func resourceMetalPortVlanAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalPortVlanAttachmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	pID := d.Get("port_id").(string)
 	vlanID := d.Get("vlan_id").(string)
 	native := d.Get("native").(bool)
 	if native {
-		_, resp, err := client.DevicePorts.UnassignNative(pID)
-		if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-			return err
+		_, resp, err := client.PortsApi.DeleteNativeVlan(ctx, pID).Execute()
+		if err != nil {
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
-	par := &packngo.PortAssignRequest{PortID: pID, VirtualNetworkID: vlanID}
 	lockId := "vlan-detachment-" + pID
 	mutexkv.Metal.Lock(lockId)
 	defer mutexkv.Metal.Unlock(lockId)
-	portPtr, resp, err := client.DevicePorts.Unassign(par)
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound, equinix_errors.IsNotAssigned)(resp, err) != nil {
-		return err
+	port, resp, err := client.PortsApi.UnassignPort(ctx, pID).PortAssignInput(metalv1.PortAssignInput{Vnid: &vlanID}).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound, equinix_errors.IsNotAssigned)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 	forceBond := d.Get("force_bond").(bool)
-	if forceBond && (len(portPtr.AttachedVirtualNetworks) == 0) {
+	// there is no port in the response when the VLAN was already unassigned
+	if forceBond && (port == nil || len(port.VirtualNetworks) == 0) {
 		deviceID := d.Get("device_id").(string)
 		portName := d.Get("port_name").(string)
-		port, err := client.DevicePorts.GetPortByName(deviceID, portName)
+		port, err := getDevicePortByName(ctx, client, deviceID, portName)
 		if err != nil {
-			return equinix_errors.FriendlyError(err)
+			return diag.FromErr(err)
 		}
-		_, _, err = client.DevicePorts.Bond(port, false)
-		if err != nil {
-			return equinix_errors.FriendlyError(err)
+		if err := bondDevicePort(ctx, client, port); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 	return nil
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"strings"
@@ -348,7 +349,7 @@ func TestAccMetalPortVlanAttachment_hybridMultipleVlans(t *testing.T) {
 }
 
 func testAccMetalPortVlanAttachmentCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	device_id := ""
 	vlan_id := ""
@@ -364,15 +365,15 @@ func testAccMetalPortVlanAttachmentCheckDestroyed(s *terraform.State) error {
 			port_id = port_vlan[1]
 		}
 	}
-	d, _, err := client.Devices.Get(device_id, nil)
+	d, _, err := client.DevicesApi.FindDeviceById(context.Background(), device_id).Execute()
 	if err != nil {
 		// if device doesn't exists, its port can't be attached
 		return nil
 	}
 	for _, p := range d.NetworkPorts {
-		if p.ID == port_id {
-			if len(p.AttachedVirtualNetworks) == 1 {
-				if path.Base(p.AttachedVirtualNetworks[0].Href) == vlan_id {
+		if p.GetId() == port_id {
+			if len(p.VirtualNetworks) == 1 {
+				if path.Base(p.VirtualNetworks[0].GetHref()) == vlan_id {
 					return fmt.Errorf("Vlan is still attached to the device")
 				}
 			}
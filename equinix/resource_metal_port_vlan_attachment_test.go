@@ -0,0 +1,197 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMetalAttachmentDeviceID = "3a4b5c6d-7e8f-4a9b-8c0d-1e2f3a4b5c6d"
+	testMetalAttachmentPortID   = "4b5c6d7e-8f9a-4b0c-9d1e-2f3a4b5c6d7e"
+	testMetalAttachmentVlanID   = "5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f"
+)
+
+func recordedMetalAttachmentDevice(virtualNetworks, native string) string {
+	return `{
+	  "id": "` + testMetalAttachmentDeviceID + `",
+	  "project": {"id": "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+	  "metro": {"code": "sv"},
+	  "facility": {"code": "sv15"},
+	  "network_ports": [
+	    {"id": "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d", "name": "bond0", "type": "NetworkBondPort", "data": {"bonded": true}},
+	    {"id": "` + testMetalAttachmentPortID + `", "name": "eth1", "type": "NetworkPort", "data": {"bonded": false},
+	     "virtual_networks": ` + virtualNetworks + `, "native_virtual_network": ` + native + `}
+	  ]
+	}`
+}
+
+// packngoResourceMetalPortVlanAttachmentRead is the packngo implementation
+// of resourceMetalPortVlanAttachmentRead, kept to compare the states both
+// write
+func packngoResourceMetalPortVlanAttachmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	deviceID := d.Get("device_id").(string)
+	pName := d.Get("port_name").(string)
+	vlanVNID := d.Get("vlan_vnid").(int)
+
+	dev, _, err := client.Devices.Get(deviceID, &packngo.GetOptions{Includes: []string{"virtual_networks,project,native_virtual_network"}})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			log.Printf("[WARN] Device (%s) for Port Vlan Attachment not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	portFound := false
+	vlanFound := false
+	portID := ""
+	vlanID := ""
+	vlanNative := false
+	for _, p := range dev.NetworkPorts {
+		if p.Name == pName {
+			portFound = true
+			portID = p.ID
+			for _, n := range p.AttachedVirtualNetworks {
+				if vlanVNID == n.VXLAN {
+					vlanFound = true
+					vlanID = n.ID
+					if p.NativeVirtualNetwork != nil {
+						vlanNative = vlanID == p.NativeVirtualNetwork.ID
+					}
+					break
+				}
+			}
+			break
+		}
+	}
+	if !portFound {
+		return diag.FromErr(fmt.Errorf("Device %s doesn't have port %s", deviceID, pName))
+	}
+	if !vlanFound {
+		d.SetId("")
+	}
+	d.Set("port_id", portID)
+	d.Set("vlan_id", vlanID)
+	d.Set("native", vlanNative)
+	return nil
+}
+
+func TestResourceMetalPortVlanAttachmentRead_recorded(t *testing.T) {
+	attached := map[string]string{
+		"GET /metal/v1/devices/" + testMetalAttachmentDeviceID: recordedMetalAttachmentDevice(
+			`[{"id": "6d7e8f9a-0b1c-4d2e-9f3a-4b5c6d7e8f9a", "href": "/metal/v1/virtual-networks/6d7e8f9a-0b1c-4d2e-9f3a-4b5c6d7e8f9a", "vxlan": 1000}, {"id": "`+testMetalAttachmentVlanID+`", "href": "/metal/v1/virtual-networks/`+testMetalAttachmentVlanID+`", "vxlan": 1001}]`,
+			`{"id": "`+testMetalAttachmentVlanID+`", "vxlan": 1001}`),
+	}
+	raw := map[string]interface{}{
+		"device_id": testMetalAttachmentDeviceID,
+		"port_name": "eth1",
+		"vlan_vnid": 1001,
+	}
+	state := assertMetalReadParity(t, resourceMetalPortVlanAttachment(), testMetalAttachmentPortID+":"+testMetalAttachmentVlanID, raw, attached,
+		resourceMetalPortVlanAttachmentRead, packngoResourceMetalPortVlanAttachmentRead)
+	assert.Equal(t, testMetalAttachmentPortID, state["port_id"])
+	assert.Equal(t, testMetalAttachmentVlanID, state["vlan_id"])
+	assert.Equal(t, "true", state["native"])
+
+}
+
+func TestResourceMetalPortVlanAttachmentRead_detached(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/devices/" + testMetalAttachmentDeviceID: recordedMetalAttachmentDevice(`[]`, `null`),
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMetalPortVlanAttachment().Schema, map[string]interface{}{
+		"device_id": testMetalAttachmentDeviceID,
+		"port_name": "eth1",
+		"vlan_vnid": 1001,
+	})
+	d.SetId(testMetalAttachmentPortID + ":" + testMetalAttachmentVlanID)
+	if diags := resourceMetalPortVlanAttachmentRead(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Empty(t, d.Id(), "a VLAN which is no longer attached removes the attachment")
+}
+
+func TestResourceMetalPortVlanAttachmentCreate_assignsProjectVlan(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e/virtual-networks": `{"virtual_networks": [
+			{"id": "7e8f9a0b-1c2d-4e3f-8a4b-5c6d7e8f9a0b", "vxlan": 1001, "metro_code": "da"},
+			{"id": "` + testMetalAttachmentVlanID + `", "vxlan": 1001, "metro_code": "sv"}
+		]}`,
+		"POST /metal/v1/ports/" + testMetalAttachmentPortID + "/assign":      `{"id": "` + testMetalAttachmentPortID + `"}`,
+		"POST /metal/v1/ports/" + testMetalAttachmentPortID + "/native-vlan": `{"id": "` + testMetalAttachmentPortID + `"}`,
+	})
+	fake.queued["GET /metal/v1/devices/"+testMetalAttachmentDeviceID] = []string{recordedMetalAttachmentDevice(`[]`, `null`)}
+	fake.responses["GET /metal/v1/devices/"+testMetalAttachmentDeviceID] = recordedMetalAttachmentDevice(
+		`[{"id": "`+testMetalAttachmentVlanID+`", "href": "/metal/v1/virtual-networks/`+testMetalAttachmentVlanID+`", "vxlan": 1001}]`, `{"id": "`+testMetalAttachmentVlanID+`"}`)
+
+	d := schema.TestResourceDataRaw(t, resourceMetalPortVlanAttachment().Schema, map[string]interface{}{
+		"device_id": testMetalAttachmentDeviceID,
+		"port_name": "eth1",
+		"vlan_vnid": 1001,
+		"native":    true,
+	})
+	if diags := resourceMetalPortVlanAttachmentCreate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected create error: %v", diags)
+	}
+	assert.Equal(t, testMetalAttachmentPortID+":"+testMetalAttachmentVlanID, d.Id())
+	assert.Equal(t, true, d.Get("native"))
+
+	body, _ := fake.request("POST /metal/v1/ports/" + testMetalAttachmentPortID + "/assign")
+	assert.JSONEq(t, `{"vnid": "`+testMetalAttachmentVlanID+`"}`, body, "the VLAN in the metro of the device is assigned")
+}
+
+func TestResourceMetalPortVlanAttachmentDelete_notAssigned(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/ports/" + testMetalAttachmentPortID + "/unassign": `{"errors": ["Virtual network ` + testMetalAttachmentVlanID + ` not assigned"]}`,
+		"GET /metal/v1/devices/" + testMetalAttachmentDeviceID:            recordedMetalAttachmentDevice(`[]`, `null`),
+		"POST /metal/v1/ports/" + testMetalAttachmentPortID + "/bond":     `{"id": "` + testMetalAttachmentPortID + `"}`,
+	})
+	fake.statuses["POST /metal/v1/ports/"+testMetalAttachmentPortID+"/unassign"] = http.StatusUnprocessableEntity
+
+	d := schema.TestResourceDataRaw(t, resourceMetalPortVlanAttachment().Schema, map[string]interface{}{
+		"device_id":  testMetalAttachmentDeviceID,
+		"port_name":  "eth1",
+		"vlan_vnid":  1001,
+		"force_bond": true,
+	})
+	d.SetId(testMetalAttachmentPortID + ":" + testMetalAttachmentVlanID)
+	d.Set("port_id", testMetalAttachmentPortID)
+	d.Set("vlan_id", testMetalAttachmentVlanID)
+	if diags := resourceMetalPortVlanAttachmentDelete(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("a VLAN which is not assigned is not an error: %v", diags)
+	}
+	assert.Contains(t, fake.requestOrder(), "POST /metal/v1/ports/"+testMetalAttachmentPortID+"/bond")
+}
+
+func TestResourceMetalPortVlanAttachmentDelete_unassignError(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/ports/" + testMetalAttachmentPortID + "/unassign": `{"errors": ["Port is locked"]}`,
+	})
+	fake.statuses["POST /metal/v1/ports/"+testMetalAttachmentPortID+"/unassign"] = http.StatusUnprocessableEntity
+
+	d := schema.TestResourceDataRaw(t, resourceMetalPortVlanAttachment().Schema, map[string]interface{}{
+		"device_id": testMetalAttachmentDeviceID,
+		"port_name": "eth1",
+		"vlan_vnid": 1001,
+	})
+	d.Set("port_id", testMetalAttachmentPortID)
+	d.Set("vlan_id", testMetalAttachmentVlanID)
+	diags := resourceMetalPortVlanAttachmentDelete(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected the unassign error")
+	}
+	assert.Contains(t, diags[0].Summary, "Port is locked")
+}
@@ -0,0 +1,203 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceMetalPortVlans() *schema.Resource {
+	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		ReadWithoutTimeout: resourceMetalPortVlansRead,
+		// Create and Update are the same func
+		CreateContext: resourceMetalPortVlansUpdate,
+		UpdateContext: resourceMetalPortVlansUpdate,
+		DeleteContext: resourceMetalPortVlansDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				d.Set("port_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"port_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "UUID of the port whose VLANs are managed",
+				ForceNew:    true,
+			},
+			"vlan_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "UUIDs of all the VLANs the port should be attached to. VLANs attached outside of this resource are detached",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsUUID,
+				},
+			},
+			"native_vlan_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "UUID of native VLAN of the port. It must be one of vlan_ids, and at least two VLANs must be attached",
+				ValidateFunc: validation.IsUUID,
+			},
+			"vxlans": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "VXLAN ids of the attached VLANs",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the port, e.g. bond0, eth1",
+			},
+			"network_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "One of layer2-bonded, layer2-individual, layer3, hybrid and hybrid-bonded. This attribute is only set on bond ports.",
+			},
+		},
+	}
+}
+
+func resourceMetalPortVlansUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
+	cpr, _, err := getClientPortResource(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, f := range [](func(context.Context, *ClientPortResource) error){
+		nativeVlanSanityCheck,
+		unassignChangedNativeVlan,
+		batchPortVlans(start),
+		refreshPort,
+		updateNativeVlan,
+	} {
+		if err := f(ctx, cpr); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(cpr.Port.GetId())
+	return resourceMetalPortVlansRead(ctx, d, meta)
+}
+
+// batchPortVlans detaches the VLANs which are not specified and attaches the
+// missing ones in a single batch. Newly attached VLANs are made native in the
+// same batch, an already attached VLAN is made native by updateNativeVlan.
+func batchPortVlans(start time.Time) func(context.Context, *ClientPortResource) error {
+	return func(ctx context.Context, cpr *ClientPortResource) error {
+		specified := specifiedVlanIds(cpr.Resource)
+		attached := attachedVlanIds(cpr.Port)
+		specifiedNative := getSpecifiedNative(cpr.Resource)
+
+		input := metalv1.PortVlanAssignmentBatchCreateInput{}
+		for _, v := range converters.Difference(attached, specified) {
+			input.VlanAssignments = append(input.VlanAssignments,
+				vlanAssignment(v, metalv1.PORTVLANASSIGNMENTBATCHVLANASSIGNMENTSINNERSTATE_UNASSIGNED))
+		}
+		for _, v := range converters.Difference(specified, attached) {
+			assignment := vlanAssignment(v, metalv1.PORTVLANASSIGNMENTBATCHVLANASSIGNMENTSINNERSTATE_ASSIGNED)
+			assignment.Native = metalv1.PtrBool(specifiedNative == v)
+			input.VlanAssignments = append(input.VlanAssignments, assignment)
+		}
+		log.Printf("[DEBUG] Applying %d VLAN assignment changes to port (%s)", len(input.VlanAssignments), cpr.Port.GetId())
+		return createAndWaitForBatch(ctx, start, cpr, input)
+	}
+}
+
+// unassignChangedNativeVlan unassigns the native VLAN before the batch, if it
+// is going to change, so the batch does not detach a native VLAN.
+func unassignChangedNativeVlan(ctx context.Context, cpr *ClientPortResource) error {
+	currentNative := getCurrentNative(cpr.Port)
+	if currentNative == "" || currentNative == getSpecifiedNative(cpr.Resource) {
+		return nil
+	}
+	port, resp, err := cpr.Client.PortsApi.DeleteNativeVlan(ctx, cpr.Port.GetId()).Include(portIncludes).Execute()
+	if err != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	*(cpr.Port) = *port
+	return nil
+}
+
+func resourceMetalPortVlansRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	port, resp, err := client.PortsApi.FindPortById(ctx, d.Id()).Include(portIncludes).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] Port (%s) not accessible, removing VLANs from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// All attached VLANs are read, so VLANs attached out of band show as drift
+	vlans := []string{}
+	vxlans := []int{}
+	for _, n := range portVirtualNetworks(port) {
+		vlans = append(vlans, n.GetId())
+		vxlans = append(vxlans, int(n.GetVxlan()))
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"port_id":        port.GetId(),
+		"vlan_ids":       vlans,
+		"native_vlan_id": getCurrentNative(port),
+		"vxlans":         vxlans,
+		"name":           port.GetName(),
+		"network_type":   string(port.GetNetworkType()),
+	}))
+}
+
+func resourceMetalPortVlansDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
+	cpr, resp, err := getClientPortResource(ctx, d, meta)
+	if err != nil {
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+
+	// detach everything, reusing the update helpers with ephemeral state
+	cpr.Resource = resourceMetalPortVlans().Data(d.State())
+	if err := equinix_schema.SetMap(cpr.Resource, map[string]interface{}{
+		"native_vlan_id": nil,
+		"vlan_ids":       []string{},
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+	for _, f := range [](func(context.Context, *ClientPortResource) error){
+		unassignChangedNativeVlan,
+		batchPortVlans(start),
+	} {
+		if err := f(ctx, cpr); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
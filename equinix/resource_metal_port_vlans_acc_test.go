@@ -0,0 +1,97 @@
+package equinix
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func confAccMetalPortVlans(name, vlans, native string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_port" "bond0" {
+  port_id = local.bond0_id
+  layer2  = true
+  bonded  = true
+  reset_on_delete = true
+}
+
+resource "equinix_metal_vlan" "test1" {
+  description = "tfacc-vlan test1"
+  metro       = equinix_metal_device.test.metro
+  project_id  = equinix_metal_project.test.id
+  vxlan       = 1001
+}
+
+resource "equinix_metal_vlan" "test2" {
+  description = "tfacc-vlan test2"
+  metro       = equinix_metal_device.test.metro
+  project_id  = equinix_metal_project.test.id
+  vxlan       = 1002
+}
+
+resource "equinix_metal_vlan" "test3" {
+  description = "tfacc-vlan test3"
+  metro       = equinix_metal_device.test.metro
+  project_id  = equinix_metal_project.test.id
+  vxlan       = 1003
+}
+
+resource "equinix_metal_port_vlans" "bond0" {
+  port_id        = equinix_metal_port.bond0.id
+  vlan_ids       = [%s]
+  native_vlan_id = %s
+}
+
+`, confAccMetalPort_base(name), vlans, native)
+}
+
+func TestAccMetalPortVlans_basic(t *testing.T) {
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ExternalProviders: testExternalProviders,
+		Providers:         testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: confAccMetalPortVlans(rs,
+					"equinix_metal_vlan.test1.id, equinix_metal_vlan.test2.id",
+					"equinix_metal_vlan.test1.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("equinix_metal_port_vlans.bond0", "vlan_ids.#", "2"),
+					resource.TestCheckResourceAttr("equinix_metal_port_vlans.bond0", "vxlans.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_port_vlans.bond0", "native_vlan_id",
+						"equinix_metal_vlan.test1", "id"),
+				),
+			},
+			{
+				// swap a VLAN and move the native VLAN in one apply
+				Config: confAccMetalPortVlans(rs,
+					"equinix_metal_vlan.test2.id, equinix_metal_vlan.test3.id",
+					"equinix_metal_vlan.test3.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("equinix_metal_port_vlans.bond0", "vlan_ids.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair(
+						"equinix_metal_port_vlans.bond0", "vlan_ids.*",
+						"equinix_metal_vlan.test2", "id"),
+					resource.TestCheckTypeSetElemAttrPair(
+						"equinix_metal_port_vlans.bond0", "vlan_ids.*",
+						"equinix_metal_vlan.test3", "id"),
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_port_vlans.bond0", "native_vlan_id",
+						"equinix_metal_vlan.test3", "id"),
+				),
+			},
+			{
+				ResourceName:      "equinix_metal_port_vlans.bond0",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+// packngoResourceMetalPortVlansRead is the packngo implementation of
+// resourceMetalPortVlansRead, kept to compare the states both write
+func packngoResourceMetalPortVlansRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	port, _, err := client.Ports.Get(d.Id(), &packngo.GetOptions{Includes: portIncludes})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] Port (%s) not accessible, removing VLANs from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	vlans := []string{}
+	vxlans := []int{}
+	for _, n := range port.AttachedVirtualNetworks {
+		vlans = append(vlans, n.ID)
+		vxlans = append(vxlans, n.VXLAN)
+	}
+	currentNative := ""
+	if port.NativeVirtualNetwork != nil {
+		currentNative = port.NativeVirtualNetwork.ID
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"port_id":        port.ID,
+		"vlan_ids":       vlans,
+		"native_vlan_id": currentNative,
+		"vxlans":         vxlans,
+		"name":           port.Name,
+		"network_type":   port.NetworkType,
+	}))
+}
+
+func TestResourceMetalPortVlansRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalPortVlans(), recordedMetalPortID, nil, map[string]string{
+		"GET /metal/v1/ports/" + recordedMetalPortID: recordedMetalPort,
+	}, resourceMetalPortVlansRead, packngoResourceMetalPortVlansRead)
+
+	assert.Equal(t, "2", state["vxlans.#"])
+	assert.Equal(t, "hybrid-bonded", state["network_type"])
+}
+
+func TestResourceMetalPortVlansRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := resourceMetalPortVlans().TestResourceData()
+	d.SetId(recordedMetalPortID)
+	diags := resourceMetalPortVlansRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
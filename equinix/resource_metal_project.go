@@ -48,6 +48,11 @@ func resourceMetalProject() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
+			"force": {
+				Type:        schema.TypeBool,
+				Description: "Destroy the project even when it still holds hardware reservations. Without it, destroying such a project fails",
+				Optional:    true,
+			},
 			"payment_method_id": {
 				Type:        schema.TypeString,
 				Description: "The UUID of payment method for this project. The payment method and the project need to belong to the same organization (passed with organization_id, or default)",
@@ -132,8 +137,7 @@ func expandBGPConfig(d *schema.ResourceData) (*metalv1.BgpConfigRequestInput, er
 }
 
 func resourceMetalProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	createRequest := metalv1.ProjectCreateFromRootInput{
 		Name: d.Get("name").(string),
@@ -168,7 +172,7 @@ func resourceMetalProjectCreate(ctx context.Context, d *schema.ResourceData, met
 		pur := metalv1.ProjectUpdateInput{
 			BackendTransferEnabled: &backendTransfer,
 		}
-		_, _, err := client.ProjectsApi.UpdateProject(ctx, project.GetId()).ProjectUpdateInput(pur).Execute()
+		_, resp, err = client.ProjectsApi.UpdateProject(ctx, project.GetId()).ProjectUpdateInput(pur).Execute()
 		if err != nil {
 			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
@@ -177,8 +181,7 @@ func resourceMetalProjectCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceMetalProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	proj, resp, err := client.ProjectsApi.FindProjectById(ctx, d.Id()).Execute()
 	if err != nil {
@@ -199,8 +202,6 @@ func resourceMetalProjectRead(ctx context.Context, d *schema.ResourceData, meta
 		d.Set("payment_method_id", path.Base(proj.PaymentMethod.GetHref()))
 	}
 	d.Set("name", proj.Name)
-	fmt.Println(proj.Organization.AdditionalProperties)
-	fmt.Println(proj.Organization.AdditionalProperties["href"])
 	d.Set("organization_id", path.Base(proj.Organization.AdditionalProperties["href"].(string))) // spec: organization has no href
 	d.Set("created", proj.GetCreatedAt().Format(time.RFC3339))
 	d.Set("updated", proj.GetUpdatedAt().Format(time.RFC3339))
@@ -251,8 +252,7 @@ func flattenBGPConfig(l *metalv1.BgpConfig) []map[string]interface{} {
 }
 
 func resourceMetalProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	updateRequest := metalv1.ProjectUpdateInput{}
 	if d.HasChange("name") {
 		pName := d.Get("name").(string)
@@ -306,12 +306,25 @@ func resourceMetalProjectUpdate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func resourceMetalProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalGoUserAgent(d)
-	client := meta.(*config.Config).Metalgo
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	if !d.Get("force").(bool) {
+		reservationIDs, err := projectHardwareReservationIDs(ctx, client, d.Id())
+		// Like the delete below, a project that is already gone is not an error
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(nil, err) != nil {
+			return diag.Errorf("error listing hardware reservations of project %s: %s", d.Id(), err)
+		}
+		if len(reservationIDs) > 0 {
+			return diag.Errorf("project %s still holds hardware reservations %v, move them to another project or set force to destroy it anyway", d.Id(), reservationIDs)
+		}
+	}
 
 	resp, err := client.ProjectsApi.DeleteProject(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
 	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
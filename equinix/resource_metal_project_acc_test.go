@@ -11,12 +11,12 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func init() {
@@ -33,20 +33,20 @@ func testSweepProjects(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping projects: %s", err)
 	}
-	metal := config.NewMetalClient()
-	ps, _, err := metal.Projects.List(nil)
+	metal := config.NewMetalGoClient()
+	ps, err := metal.ProjectsApi.FindProjects(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting project list for sweeping projects: %s", err)
 	}
 	pids := []string{}
-	for _, p := range ps {
-		if isSweepableTestResource(p.Name) {
-			pids = append(pids, p.ID)
+	for _, p := range ps.Projects {
+		if isSweepableTestResource(p.GetName()) {
+			pids = append(pids, p.GetId())
 		}
 	}
 	for _, pid := range pids {
 		log.Printf("Removing project %s", pid)
-		_, err := metal.Projects.Delete(pid)
+		_, err := metal.ProjectsApi.DeleteProject(context.Background(), pid).Execute()
 		if err != nil {
 			return fmt.Errorf("Error deleting project %s", err)
 		}
@@ -55,7 +55,7 @@ func testSweepProjects(region string) error {
 }
 
 func TestAccMetalProject_basic(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -145,7 +145,7 @@ func TestAccMetalProject_apiErrorHandling(t *testing.T) {
 }
 
 func TestAccMetalProject_BGPBasic(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -173,7 +173,7 @@ func TestAccMetalProject_BGPBasic(t *testing.T) {
 }
 
 func TestAccMetalProject_backendTransferUpdate(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -216,7 +216,7 @@ func TestAccMetalProject_backendTransferUpdate(t *testing.T) {
 }
 
 func TestAccMetalProject_update(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -245,17 +245,17 @@ func TestAccMetalProject_update(t *testing.T) {
 	})
 }
 
-func testAccCheckMetalSameProject(t *testing.T, before, after *packngo.Project) resource.TestCheckFunc {
+func testAccCheckMetalSameProject(t *testing.T, before, after *metalv1.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		if before.ID != after.ID {
-			t.Fatalf("Expected device to be the same, but it was recreated: %s -> %s", before.ID, after.ID)
+		if before.GetId() != after.GetId() {
+			t.Fatalf("Expected device to be the same, but it was recreated: %s -> %s", before.GetId(), after.GetId())
 		}
 		return nil
 	}
 }
 
 func TestAccMetalProject_BGPUpdate(t *testing.T) {
-	var p1, p2, p3 packngo.Project
+	var p1, p2, p3 metalv1.Project
 	rInt := acctest.RandInt()
 	res := "equinix_metal_project.foobar"
 
@@ -303,13 +303,13 @@ func TestAccMetalProject_BGPUpdate(t *testing.T) {
 }
 
 func testAccMetalProjectCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_project" {
 			continue
 		}
-		if _, _, err := client.Projects.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.ProjectsApi.FindProjectById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal Project still exists")
 		}
 	}
@@ -317,7 +317,7 @@ func testAccMetalProjectCheckDestroyed(s *terraform.State) error {
 	return nil
 }
 
-func testAccMetalProjectExists(n string, project *packngo.Project) resource.TestCheckFunc {
+func testAccMetalProjectExists(n string, project *metalv1.Project) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
@@ -327,13 +327,13 @@ func testAccMetalProjectExists(n string, project *packngo.Project) resource.Test
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*config.Config).Metal
+		client := testAccProvider.Meta().(*config.Config).Metalgo
 
-		foundProject, _, err := client.Projects.Get(rs.Primary.ID, nil)
+		foundProject, _, err := client.ProjectsApi.FindProjectById(context.Background(), rs.Primary.ID).Execute()
 		if err != nil {
 			return err
 		}
-		if foundProject.ID != rs.Primary.ID {
+		if foundProject.GetId() != rs.Primary.ID {
 			return fmt.Errorf("Record not found: %v - %v", rs.Primary.ID, foundProject)
 		}
 
@@ -389,7 +389,7 @@ resource "equinix_metal_project" "foobar" {
 }
 
 func TestAccMetalProject_organization(t *testing.T) {
-	var project packngo.Project
+	var project metalv1.Project
 	rn := acctest.RandStringFromCharSet(12, "abcdef0123456789")
 
 	resource.ParallelTest(t, resource.TestCase{
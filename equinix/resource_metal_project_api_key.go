@@ -1,19 +1,29 @@
 package equinix
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func schemaMetalAPIKey() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "UUID of the API key, which changes when the key is rotated",
+		},
 		"read_only": {
 			Type:        schema.TypeBool,
 			ForceNew:    true,
@@ -32,9 +42,110 @@ func schemaMetalAPIKey() map[string]*schema.Schema {
 			Computed:    true,
 			Description: "API token for API clients",
 		},
+		"created": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The timestamp for when the API key was created",
+		},
+		"rotation": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Rotate the API key once it gets too old. The new key is created before the previous one is revoked",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"rotate_after_days": {
+						Type:         schema.TypeInt,
+						Required:     true,
+						ValidateFunc: validation.IntAtLeast(1),
+						Description:  "Age in days after which the next apply replaces the key",
+					},
+					"overlap_days": {
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Default:      0,
+						ValidateFunc: validation.IntAtLeast(0),
+						Description:  "Days the previous key stays valid after a rotation, exposed as previous_token. With 0 the previous key is revoked right after the new one is created",
+					},
+				},
+			},
+		},
+		"previous_key_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "UUID of the previous API key while it is still valid after a rotation",
+		},
+		"previous_token": {
+			Type:        schema.TypeString,
+			Sensitive:   true,
+			Computed:    true,
+			Description: "API token of the previous API key while it is still valid after a rotation",
+		},
+		"previous_expires": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The timestamp after which the next apply revokes the previous API key",
+		},
 	}
 }
 
+// apiKeyRotationDue tells whether a key created at created is older than
+// rotateAfterDays.
+func apiKeyRotationDue(created string, rotateAfterDays int, now time.Time) bool {
+	createdAt, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		return false
+	}
+	return !now.Before(createdAt.AddDate(0, 0, rotateAfterDays))
+}
+
+// apiKeyPreviousExpired tells whether the overlap window of the previous key
+// ended.
+func apiKeyPreviousExpired(expires string, now time.Time) bool {
+	expiresAt, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return true
+	}
+	return !now.Before(expiresAt)
+}
+
+// resourceMetalAPIKeyCustomizeDiff shows a rotation of the token in the plan
+// once the key is older than rotation.rotate_after_days, and the removal of the
+// previous token once its overlap window ended. Both are in-place updates: a
+// replacement would revoke the key with the resource, and the rotated key has
+// to outlive it for the overlap window.
+func resourceMetalAPIKeyCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	now := time.Now()
+
+	if d.Get("previous_key_id").(string) != "" && apiKeyPreviousExpired(d.Get("previous_expires").(string), now) {
+		for _, k := range []string{"previous_key_id", "previous_token", "previous_expires"} {
+			if err := d.SetNew(k, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	rotation := d.Get("rotation").([]interface{})
+	if len(rotation) == 0 || rotation[0] == nil {
+		return nil
+	}
+	rotateAfterDays := rotation[0].(map[string]interface{})["rotate_after_days"].(int)
+	if !apiKeyRotationDue(d.Get("created").(string), rotateAfterDays, now) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] API key (%s) is older than %d days, planning rotation", d.Id(), rotateAfterDays)
+	for _, k := range []string{"id", "token", "created", "previous_key_id", "previous_token", "previous_expires"} {
+		if err := d.SetNewComputed(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func resourceMetalProjectAPIKey() *schema.Resource {
 	projectKeySchema := schemaMetalAPIKey()
 	projectKeySchema["project_id"] = &schema.Schema{
@@ -44,38 +155,125 @@ func resourceMetalProjectAPIKey() *schema.Resource {
 		Description: "UUID of project which the new API key is scoped to",
 	}
 	return &schema.Resource{
-		Create: resourceMetalAPIKeyCreate,
-		Read:   resourceMetalAPIKeyRead,
-		Delete: resourceMetalAPIKeyDelete,
-		Schema: projectKeySchema,
+		CreateContext: resourceMetalAPIKeyCreate,
+		ReadContext:   resourceMetalAPIKeyRead,
+		UpdateContext: resourceMetalAPIKeyUpdate,
+		DeleteContext: resourceMetalAPIKeyDelete,
+		Schema:        projectKeySchema,
+		CustomizeDiff: resourceMetalAPIKeyCustomizeDiff,
+	}
+}
+
+func resourceMetalAPIKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	apiKey, err := createMetalAPIKey(ctx, d, client)
+	if err != nil {
+		return diag.FromErr(err)
 	}
+
+	d.SetId(apiKey.GetId())
+
+	return resourceMetalAPIKeyRead(ctx, d, meta)
 }
 
-func resourceMetalAPIKeyCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func createMetalAPIKey(ctx context.Context, d *schema.ResourceData, client *metalv1.APIClient) (*metalv1.AuthToken, error) {
+	createRequest := metalv1.AuthTokenInput{
+		ReadOnly:    metalv1.PtrBool(d.Get("read_only").(bool)),
+		Description: metalv1.PtrString(d.Get("description").(string)),
+	}
+
+	var apiKey *metalv1.AuthToken
+	var resp *http.Response
+	var err error
+	if projectID := projectIdFromResourceData(d); projectID != "" {
+		apiKey, resp, err = client.AuthenticationApi.CreateProjectAPIKey(ctx, projectID).AuthTokenInput(createRequest).Execute()
+	} else {
+		apiKey, resp, err = client.AuthenticationApi.CreateAPIKey(ctx).AuthTokenInput(createRequest).Execute()
+	}
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return apiKey, nil
+}
 
-	projectId := ""
+// resourceMetalAPIKeyUpdate rotates the key when it is due. The new key is
+// created first and the current one becomes the previous key, which is revoked
+// once the overlap window ends. An expired previous key is revoked as well.
+func resourceMetalAPIKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	now := time.Now()
 
-	projectIdRaw, projectIdOk := d.GetOk("project_id")
-	if projectIdOk {
-		projectId = projectIdRaw.(string)
+	// The plan marks rotated attributes as unknown, so decisions are made on
+	// the values from the state.
+	fromState := func(k string) string {
+		o, _ := d.GetChange(k)
+		return o.(string)
 	}
 
-	createRequest := &packngo.APIKeyCreateRequest{
-		ProjectID:   projectId,
-		ReadOnly:    d.Get("read_only").(bool),
-		Description: d.Get("description").(string),
+	previousID := fromState("previous_key_id")
+	if previousID != "" && apiKeyPreviousExpired(fromState("previous_expires"), now) {
+		if err := deleteMetalAPIKey(ctx, client, previousID); err != nil {
+			return diag.Errorf("error revoking previous API key %s: %s", previousID, err)
+		}
+		previousID = ""
+		if err := equinix_schema.SetMap(d, map[string]interface{}{
+			"previous_key_id":  "",
+			"previous_token":   "",
+			"previous_expires": "",
+		}); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	apiKey, _, err := client.APIKeys.Create(createRequest)
+	rotation := d.Get("rotation").([]interface{})
+	if len(rotation) == 0 || rotation[0] == nil {
+		return resourceMetalAPIKeyRead(ctx, d, meta)
+	}
+	rotationConf := rotation[0].(map[string]interface{})
+	if !apiKeyRotationDue(fromState("created"), rotationConf["rotate_after_days"].(int), now) {
+		return resourceMetalAPIKeyRead(ctx, d, meta)
+	}
+
+	if previousID != "" {
+		// A key rotated again before its overlap ended only keeps one predecessor
+		if err := deleteMetalAPIKey(ctx, client, previousID); err != nil {
+			return diag.Errorf("error revoking previous API key %s: %s", previousID, err)
+		}
+	}
+
+	apiKey, err := createMetalAPIKey(ctx, d, client)
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(err)
 	}
+	currentID, currentToken := d.Id(), fromState("token")
+	d.SetId(apiKey.GetId())
+	log.Printf("[DEBUG] Rotated API key (%s) to (%s)", currentID, apiKey.GetId())
 
-	d.SetId(apiKey.ID)
+	// The rotated key is recorded as the previous key before it may be
+	// revoked, so that a failed revoke is retried by the next apply
+	overlapDays := rotationConf["overlap_days"].(int)
+	if err := equinix_schema.SetMap(d, map[string]interface{}{
+		"previous_key_id":  currentID,
+		"previous_token":   currentToken,
+		"previous_expires": now.AddDate(0, 0, overlapDays).UTC().Format(time.RFC3339),
+	}); err != nil {
+		return diag.FromErr(err)
+	}
+	if overlapDays == 0 {
+		if err := deleteMetalAPIKey(ctx, client, currentID); err != nil {
+			return diag.Errorf("error revoking rotated API key %s: %s", currentID, err)
+		}
+		if err := equinix_schema.SetMap(d, map[string]interface{}{
+			"previous_key_id":  "",
+			"previous_token":   "",
+			"previous_expires": "",
+		}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	return resourceMetalAPIKeyRead(d, meta)
+	return resourceMetalAPIKeyRead(ctx, d, meta)
 }
 
 func projectIdFromResourceData(d *schema.ResourceData) string {
@@ -86,66 +284,85 @@ func projectIdFromResourceData(d *schema.ResourceData) string {
 	return ""
 }
 
-func resourceMetalAPIKeyRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalAPIKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	projectId := projectIdFromResourceData(d)
 
-	var apiKey *packngo.APIKey
+	var apiKeys *metalv1.AuthTokenList
+	var resp *http.Response
 	var err error
 
-	// if project has been set in the resource, look up project API key
-	// (this is the reason project API key can't be imported)
+	// The API has no endpoint to get a key, so the keys of the project or the
+	// user are listed (this is the reason project API key can't be imported)
 	if projectId != "" {
-		apiKey, err = client.APIKeys.ProjectGet(projectId, d.Id(),
-			&packngo.GetOptions{Includes: []string{"project"}})
+		apiKeys, resp, err = client.AuthenticationApi.FindProjectAPIKeys(ctx, projectId).Include([]string{"project"}).Execute()
 	} else {
-		apiKey, err = client.APIKeys.UserGet(d.Id(),
-			&packngo.GetOptions{Includes: []string{"user"}})
+		apiKeys, resp, err = client.AuthenticationApi.FindAPIKeys(ctx).Include([]string{"user"}).Execute()
 	}
-
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
-		// If the key is somehow already destroyed, mark as
-		// succesfully gone
-		if equinix_errors.IsNotFound(err) {
-			log.Printf("[WARN] Project APIKey (%s) not found, removing from state", d.Id())
-			d.SetId("")
-			return nil
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	var apiKey *metalv1.AuthToken
+	for i, k := range apiKeys.GetApiKeys() {
+		if k.GetId() == d.Id() {
+			apiKey = &apiKeys.GetApiKeys()[i]
+			break
 		}
-		return err
+	}
+	// If the key is somehow already destroyed, mark as
+	// succesfully gone
+	if apiKey == nil {
+		log.Printf("[WARN] Project APIKey (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
 	}
 
-	d.SetId(apiKey.ID)
+	d.SetId(apiKey.GetId())
 	attrMap := map[string]interface{}{
-		"description": apiKey.Description,
-		"read_only":   apiKey.ReadOnly,
-		"token":       apiKey.Token,
+		"description": apiKey.GetDescription(),
+		"read_only":   apiKey.GetReadOnly(),
+		"token":       apiKey.GetToken(),
+		"created":     "",
+	}
+	if apiKey.CreatedAt != nil {
+		attrMap["created"] = apiKey.CreatedAt.UTC().Format(time.RFC3339)
 	}
 
 	// this is kind of unnecessary as the project ID most likely already set,
 	// because project API key can't be imported. But let's refresh the
 	// project ID for future-proofing
-	if apiKey.Project != nil && apiKey.Project.ID != "" {
-		attrMap["project_id"] = apiKey.Project.ID
+	if apiKey.Project.GetId() != "" {
+		attrMap["project_id"] = apiKey.Project.GetId()
 	}
-	if apiKey.User != nil && apiKey.User.ID != "" {
-		attrMap["user_id"] = apiKey.User.ID
+	if apiKey.User.GetId() != "" {
+		attrMap["user_id"] = apiKey.User.GetId()
 	}
 
-	return equinix_schema.SetMap(d, attrMap)
+	return diag.FromErr(equinix_schema.SetMap(d, attrMap))
 }
 
-func resourceMetalAPIKeyDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalAPIKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.APIKeys.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+	if previousID := d.Get("previous_key_id").(string); previousID != "" {
+		if err := deleteMetalAPIKey(ctx, client, previousID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := deleteMetalAPIKey(ctx, client, d.Id()); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
 	return nil
 }
+
+func deleteMetalAPIKey(ctx context.Context, client *metalv1.APIClient, id string) error {
+	resp, err := client.AuthenticationApi.DeleteUserAPIKey(ctx, id).Execute()
+	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+		return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return nil
+}
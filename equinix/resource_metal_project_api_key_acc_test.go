@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -46,13 +47,20 @@ resource "equinix_metal_project_api_key" "test" {
 }
 
 func testAccMetalProjectAPIKeyCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_project_api_key" {
 			continue
 		}
-		if _, err := client.APIKeys.ProjectGet(rs.Primary.ID, rs.Primary.Attributes["project_id"], nil); err == nil {
-			return fmt.Errorf("Metal ProjectAPI key still exists")
+		// The API has no endpoint to get a key, the keys of the project are listed
+		keys, _, err := client.AuthenticationApi.FindProjectAPIKeys(context.Background(), rs.Primary.Attributes["project_id"]).Execute()
+		if err != nil {
+			continue
+		}
+		for _, k := range keys.GetApiKeys() {
+			if k.GetId() == rs.Primary.ID {
+				return fmt.Errorf("Metal ProjectAPI key still exists")
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,119 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_apiKeyRotationDue(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, apiKeyRotationDue("2024-03-01T12:00:01Z", 9, now), "key younger than rotate_after_days")
+	assert.True(t, apiKeyRotationDue("2024-03-01T12:00:00Z", 9, now), "key exactly rotate_after_days old")
+	assert.True(t, apiKeyRotationDue("2023-12-01T00:00:00Z", 30, now), "key older than rotate_after_days")
+	assert.False(t, apiKeyRotationDue("", 1, now), "unknown creation time")
+}
+
+func Test_apiKeyPreviousExpired(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, apiKeyPreviousExpired("2024-03-11T12:00:00Z", now))
+	assert.True(t, apiKeyPreviousExpired("2024-03-10T12:00:00Z", now))
+	assert.True(t, apiKeyPreviousExpired("", now))
+}
+
+func TestMetalAPIKeyUpdate_revokeFailure(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/projects/project/api-keys": `{"id": "new", "token": "new-token", "created_at": "2024-03-10T12:00:00Z", "project": {"id": "project"}}`,
+		"DELETE /metal/v1/user/api-keys/old":       `{"errors": ["Internal error"]}`,
+	})
+	fake.statuses["DELETE /metal/v1/user/api-keys/old"] = http.StatusInternalServerError
+
+	d := resourceMetalProjectAPIKey().Data(&terraform.InstanceState{
+		ID: "old",
+		Attributes: map[string]string{
+			"id":                           "old",
+			"project_id":                   "project",
+			"description":                  "ci",
+			"read_only":                    "false",
+			"token":                        "old-token",
+			"created":                      "2020-01-01T00:00:00Z",
+			"rotation.#":                   "1",
+			"rotation.0.rotate_after_days": "30",
+			"rotation.0.overlap_days":      "0",
+		},
+	})
+
+	diags := resourceMetalAPIKeyUpdate(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected an error revoking the rotated key")
+	}
+	assert.Contains(t, diags[0].Summary, "error revoking rotated API key old")
+	assert.Equal(t, "new", d.Id())
+	assert.Equal(t, "old", d.Get("previous_key_id"), "the next apply retries the revoke")
+	assert.Equal(t, "old-token", d.Get("previous_token"))
+}
+
+func TestMetalAPIKeyCustomizeDiff_rotationUpdatesInPlace(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "old",
+		Attributes: map[string]string{
+			"id":          "old",
+			"project_id":  "project",
+			"description": "ci",
+			"read_only":   "false",
+			"token":       "old-token",
+			"created":     "2020-01-01T00:00:00Z",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id":  "project",
+		"description": "ci",
+		"read_only":   false,
+		"rotation": []interface{}{map[string]interface{}{
+			"rotate_after_days": 30,
+		}},
+	})
+
+	diff, err := resourceMetalProjectAPIKey().Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assert.False(t, diff.RequiresNew(), "a rotation keeps the resource so the previous key can outlive it")
+	assert.True(t, diff.Attributes["token"].NewComputed)
+	assert.True(t, diff.Attributes["id"].NewComputed)
+}
+
+func TestMetalAPIKeyRead(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/project/api-keys": `{"api_keys": [
+			{"id": "other", "token": "other-token"},
+			{"id": "key", "token": "key-token", "description": "ci", "read_only": true, "created_at": "2024-03-10T12:00:00Z", "project": {"id": "project"}}
+		]}`,
+	})
+
+	d := resourceMetalProjectAPIKey().Data(&terraform.InstanceState{
+		ID:         "key",
+		Attributes: map[string]string{"id": "key", "project_id": "project"},
+	})
+	diags := resourceMetalAPIKeyRead(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, "key-token", d.Get("token"))
+	assert.Equal(t, true, d.Get("read_only"))
+	assert.Equal(t, "2024-03-10T12:00:00Z", d.Get("created"))
+
+	// A revoked key is removed from the state
+	d.SetId("revoked")
+	diags = resourceMetalAPIKeyRead(context.Background(), d, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, "", d.Id())
+}
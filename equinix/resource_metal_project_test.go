@@ -0,0 +1,53 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetalProjectDelete_hardwareReservations(t *testing.T) {
+	tests := []struct {
+		name         string
+		reservations string
+		wantErr      string
+	}{
+		{
+			name:         "no reservations",
+			reservations: `{"hardware_reservations": [], "meta": {"current_page": 1, "last_page": 1}}`,
+		},
+		{
+			// The project is already gone, like a 404 of the delete itself
+			name: "project not found",
+		},
+		{
+			name:         "reservations held",
+			reservations: `{"hardware_reservations": [{"id": "hwr"}], "meta": {"current_page": 1, "last_page": 1}}`,
+			wantErr:      "still holds hardware reservations [hwr]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := map[string]string{}
+			if tt.reservations != "" {
+				responses["GET /metal/v1/projects/project/hardware-reservations"] = tt.reservations
+				responses["DELETE /metal/v1/projects/project"] = ""
+			}
+			fake, meta := newMetalFakeAPI(t, responses)
+			d := resourceMetalProject().TestResourceData()
+			d.SetId("project")
+
+			diags := resourceMetalProjectDelete(context.Background(), d, meta)
+			if tt.wantErr != "" {
+				if assert.True(t, diags.HasError()) {
+					assert.Contains(t, diags[0].Summary, tt.wantErr)
+				}
+				assert.NotContains(t, fake.requestOrder(), "DELETE /metal/v1/projects/project")
+				return
+			}
+			assert.False(t, diags.HasError(), "unexpected error: %v", diags)
+			assert.Contains(t, fake.requestOrder(), "DELETE /metal/v1/projects/project")
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"path"
 	"strings"
 	"time"
@@ -13,21 +15,111 @@ import (
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/packethost/packngo"
 )
 
 const (
 	ReservedIPCreateTimeout = 10 * time.Minute
+
+	metalIPReservationStateCreated = "created"
+	metalIPReservationStatePending = "pending"
 )
 
+// metalIPReservation is a reserved IP block of any type. metal-go decodes IP
+// reservations as one of several models, one per type, where the blocks of all
+// types share the fields the resource and data sources read.
+type metalIPReservation struct {
+	ID            string             `json:"id"`
+	Address       string             `json:"address"`
+	Gateway       string             `json:"gateway"`
+	Network       string             `json:"network"`
+	AddressFamily int                `json:"address_family"`
+	Netmask       string             `json:"netmask"`
+	Public        bool               `json:"public"`
+	CIDR          int                `json:"cidr"`
+	Management    bool               `json:"management"`
+	Manageable    bool               `json:"manageable"`
+	Global        bool               `json:"global_ip"`
+	Type          string             `json:"type"`
+	State         string             `json:"state"`
+	Tags          []string           `json:"tags"`
+	CustomData    interface{}        `json:"customdata"`
+	Description   *string            `json:"details"`
+	Project       metalHref          `json:"project"`
+	Facility      *metalFacilityCode `json:"facility"`
+	Metro         *metalCode         `json:"metro"`
+	VRF           *struct {
+		ID string `json:"id"`
+	} `json:"vrf"`
+}
+
+// metalCode and metalFacilityCode are the codes of the metro and the facility
+// of a resource
+type metalCode struct {
+	Code string `json:"code"`
+}
+
+type metalFacilityCode struct {
+	Code  string     `json:"code"`
+	Metro *metalCode `json:"metro"`
+}
+
+type metalIPReservationInput struct {
+	Type        string      `json:"type"`
+	Quantity    int         `json:"quantity"`
+	Description string      `json:"details,omitempty"`
+	Facility    *string     `json:"facility,omitempty"`
+	Metro       *string     `json:"metro,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	CustomData  interface{} `json:"customdata,omitempty"`
+	VRFID       string      `json:"vrf_id,omitempty"`
+	Network     string      `json:"network,omitempty"`
+	CIDR        int         `json:"cidr,omitempty"`
+}
+
+type metalIPReservationUpdateInput struct {
+	Tags        *[]string   `json:"tags,omitempty"`
+	Description *string     `json:"details,omitempty"`
+	CustomData  interface{} `json:"customdata,omitempty"`
+}
+
+// metalIPReservationQuery includes the facility, metro, project and VRF of
+// the blocks and, unless types is empty, only returns blocks of those types
+func metalIPReservationQuery(types string) string {
+	query := url.Values{"include": {"facility,metro,project,vrf"}}
+	if types != "" {
+		query.Set("types", types)
+	}
+	return "?" + query.Encode()
+}
+
+func getMetalIPReservation(ctx context.Context, client *metalv1.APIClient, id, query string) (*metalIPReservation, *http.Response, error) {
+	block := &metalIPReservation{}
+	resp, err := metalRequest(ctx, client, http.MethodGet, "ips/"+id+query, nil, block)
+	if err != nil {
+		return nil, resp, err
+	}
+	return block, resp, nil
+}
+
+func listMetalIPReservations(ctx context.Context, client *metalv1.APIClient, projectID, query string) ([]metalIPReservation, *http.Response, error) {
+	list := struct {
+		IPAddresses []metalIPReservation `json:"ip_addresses"`
+	}{}
+	resp, err := metalRequest(ctx, client, http.MethodGet, "projects/"+projectID+"/ips"+query, nil, &list)
+	return list.IPAddresses, resp, err
+}
+
 func metalIPComputedFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"address": {
@@ -99,7 +191,6 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 	reservedBlockSchema["facility"] = &schema.Schema{
 		Type:          schema.TypeString,
 		Optional:      true,
-		ForceNew:      true,
 		ConflictsWith: []string{"metro"},
 		Description:   "Facility where to allocate the public IP address block, makes sense only for type==public_ipv4, must be empty for type==global_ipv4, conflicts with metro",
 		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
@@ -113,7 +204,6 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 	reservedBlockSchema["metro"] = &schema.Schema{
 		Type:          schema.TypeString,
 		Optional:      true,
-		ForceNew:      true,
 		ConflictsWith: []string{"facility"},
 		Description:   "Metro where to allocate the public IP address block, makes sense only for type==public_ipv4, must be empty for type==global_ipv4, conflicts with facility",
 		DiffSuppressFunc: func(k, fromState, fromHCL string, d *schema.ResourceData) bool {
@@ -188,13 +278,13 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 	reservedBlockSchema["wait_for_state"] = &schema.Schema{
 		Type:        schema.TypeString,
 		Description: "Wait for the IP reservation block to reach a desired state on resource creation. One of: `pending`, `created`. The `created` state is default and recommended if the addresses are needed within the configuration. An error will be returned if a timeout or the `denied` state is encountered.",
-		Default:     packngo.IPReservationStateCreated,
+		Default:     metalIPReservationStateCreated,
 		Optional:    true,
 		ForceNew:    false,
 		ValidateDiagFunc: validation.ToDiagFunc(
 			validation.StringInSlice([]string{
-				string(packngo.IPReservationStateCreated),
-				string(packngo.IPReservationStatePending),
+				metalIPReservationStateCreated,
+				metalIPReservationStatePending,
 			}, false),
 		),
 	}
@@ -213,6 +303,7 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		ForceNew:     true,
 		Computed:     true,
 		Description:  "an unreserved network address from an existing vrf ip_range. `network` can only be specified with vrf_id",
+		ValidateFunc: validation.IsIPAddress,
 	}
 	reservedBlockSchema["cidr"] = &schema.Schema{
 		Type:         schema.TypeInt,
@@ -223,7 +314,7 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Description:  "the size of the network to reserve from an existing vrf ip_range. `cidr` can only be specified with `vrf_id`. Minimum range is 22-29, with 30-31 supported and necessary for virtual-circuits",
 	}
 	// TODO: add comments field, used for reservations that are not automatically approved
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext:        resourceMetalReservedIPBlockCreate,
 		ReadWithoutTimeout:   resourceMetalReservedIPBlockRead,
 		UpdateWithoutTimeout: resourceMetalReservedIPBlockUpdate,
@@ -236,18 +327,112 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(ReservedIPCreateTimeout),
 		},
+		CustomizeDiff: customdiff.Sequence(
+			validateReservedIPBlockVRFNetwork,
+			metalReservedIPBlockPlacement.customizeDiff,
+		),
+		SchemaVersion: 1,
+	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Type:    resourceMetalReservedIPBlockResourceV0().CoreConfigSchema().ImpliedType(),
+			Upgrade: metalReservedIPBlockPlacement.stateUpgrade,
+			Version: 0,
+		},
+	}
+
+	return r
+}
+
+// resourceMetalReservedIPBlockResourceV0 is the IP block schema of version 0,
+// before facility and metro could be switched in place. It only keeps what
+// makes up the state type and must not follow later changes to the schema.
+func resourceMetalReservedIPBlockResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address":        {Type: schema.TypeString, Computed: true},
+			"address_family": {Type: schema.TypeInt, Computed: true},
+			"cidr":           {Type: schema.TypeInt, Optional: true, Computed: true},
+			"cidr_notation":  {Type: schema.TypeString, Computed: true},
+			"custom_data":    {Type: schema.TypeString, Optional: true},
+			"description":    {Type: schema.TypeString, Optional: true},
+			"facility":       {Type: schema.TypeString, Optional: true},
+			"gateway":        {Type: schema.TypeString, Computed: true},
+			"global":         {Type: schema.TypeBool, Computed: true},
+			"manageable":     {Type: schema.TypeBool, Computed: true},
+			"management":     {Type: schema.TypeBool, Computed: true},
+			"metro":          {Type: schema.TypeString, Optional: true},
+			"netmask":        {Type: schema.TypeString, Computed: true},
+			"network":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"project_id":     {Type: schema.TypeString, Required: true},
+			"public":         {Type: schema.TypeBool, Computed: true},
+			"quantity":       {Type: schema.TypeInt, Optional: true, Computed: true},
+			"tags":           {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"type":           {Type: schema.TypeString, Optional: true},
+			"vrf_id":         {Type: schema.TypeString, Optional: true},
+			"wait_for_state": {Type: schema.TypeString, Optional: true},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(ReservedIPCreateTimeout),
+		},
 	}
 }
 
+// metalReservedIPBlockPlacement allows to switch the IP block attributes
+// between a facility and its metro without replacing the block
+var metalReservedIPBlockPlacement = facilityMetroAttributes{Facility: "facility", Metro: "metro"}
+
+var (
+	vrfReservationIPv4Sizes = equinix_validation.PrefixLengthRange{Min: 22, Max: 31}
+	vrfReservationIPv6Sizes = equinix_validation.PrefixLengthRange{Min: 0, Max: 128}
+)
+
+// validateReservedIPBlockVRFNetwork checks that network and cidr of a VRF
+// reservation form a valid network, and that it is within one of the VRF
+// ip_ranges when the VRF already exists.
+func validateReservedIPBlockVRFNetwork(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	network, cidr := d.Get("network").(string), d.Get("cidr").(int)
+	if !d.NewValueKnown("network") || !d.NewValueKnown("cidr") || network == "" || cidr == 0 {
+		return nil
+	}
+	if d.Get("type").(string) != "vrf" || !d.HasChanges("network", "cidr", "vrf_id") {
+		return nil
+	}
+
+	subnet := fmt.Sprintf("%s/%d", network, cidr)
+	prefix, err := equinix_validation.ParseNetworkCIDR(subnet)
+	if err != nil {
+		return fmt.Errorf("invalid network and cidr: %w", err)
+	}
+	if err := equinix_validation.CheckPrefixLength(prefix, vrfReservationIPv4Sizes, vrfReservationIPv6Sizes); err != nil {
+		return fmt.Errorf("invalid cidr: %w", err)
+	}
+
+	vrfID := d.Get("vrf_id").(string)
+	if !d.NewValueKnown("vrf_id") || vrfID == "" {
+		return nil
+	}
+	client := meta.(*config.Config).Metalgo
+	vrf, _, err := client.VRFsApi.FindVrfById(ctx, vrfID).Execute()
+	if err != nil {
+		log.Printf("[WARN] Could not read VRF (%s) to validate network: %s", vrfID, err)
+		return nil
+	}
+	if err := equinix_validation.CIDRWithinAny(subnet, vrf.GetIpRanges()); err != nil {
+		return fmt.Errorf("invalid network for VRF %s: %w", vrfID, err)
+	}
+	return nil
+}
+
 func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	quantity := d.Get("quantity").(int)
 	typ := d.Get("type").(string)
 
-	req := packngo.IPReservationCreateRequest{
-		Type:     packngo.IPReservationType(typ),
+	req := metalIPReservationInput{
+		Type:     typ,
 		Quantity: quantity,
 	}
 	facility, facOk := d.GetOk("facility")
@@ -295,23 +480,24 @@ func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceD
 	req.CIDR = d.Get("cidr").(int)
 
 	start := time.Now()
-	blockAddr, _, err := client.ProjectIPs.Create(projectID, &req)
+	blockAddr := metalIPReservation{}
+	resp, err := metalRequest(ctx, client, http.MethodPost, "projects/"+projectID+"/ips", req, &blockAddr)
 	if err != nil {
-		return diag.Errorf("error reserving IP address block: %s", err)
+		return diag.Errorf("error reserving IP address block: %s", equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 	d.Set("project_id", projectID)
 	d.SetId(blockAddr.ID)
 
 	wfs := d.Get("wait_for_state").(string)
 	log.Printf("[DEBUG] Waiting for IP Reservation (%s) to become %s", d.Id(), wfs)
-	target := []string{string(packngo.IPReservationStateCreated)}
-	if wfs != string(packngo.IPReservationStateCreated) {
+	target := []string{metalIPReservationStateCreated}
+	if wfs != metalIPReservationStateCreated {
 		target = append(target, wfs)
 	}
 	stateConf := &retry.StateChangeConf{
-		Pending:    []string{string(packngo.IPReservationStatePending)},
+		Pending:    []string{metalIPReservationStatePending},
 		Target:     target,
-		Refresh:    reservedIPStateRefreshFunc(client, d.Id()),
+		Refresh:    reservedIPStateRefreshFunc(ctx, client, d.Id()),
 		Timeout:    d.Timeout(schema.TimeoutCreate) - 30*time.Second - time.Since(start),
 		MinTimeout: 15 * time.Second,
 	}
@@ -323,10 +509,9 @@ func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceMetalReservedIPBlockUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	id := d.Id()
-	req := &packngo.IPAddressUpdateRequest{}
+	req := metalIPReservationUpdateInput{}
 	if d.HasChange("tags") {
 		tags := []string{}
 		if tagsRaw, tagsOk := d.GetOk("tags"); tagsOk {
@@ -344,31 +529,31 @@ func resourceMetalReservedIPBlockUpdate(ctx context.Context, d *schema.ResourceD
 
 	if d.HasChange("custom_data") {
 		var v interface{}
-		if err := json.Unmarshal([]byte(d.Get("custom_data").(string)), v); err != nil {
+		if err := json.Unmarshal([]byte(d.Get("custom_data").(string)), &v); err != nil {
 			return diag.FromErr(fmt.Errorf("error unmarshalling custom_data: %w", err))
 		}
 		req.CustomData = v
 	}
 
-	if _, _, err := client.ProjectIPs.Update(id, req, nil); err != nil {
-		return diag.FromErr(fmt.Errorf("error updating IP reservation: %w", err))
+	if resp, err := metalRequest(ctx, client, http.MethodPatch, "ips/"+id, req, nil); err != nil {
+		return diag.FromErr(fmt.Errorf("error updating IP reservation: %w", equinix_errors.FriendlyErrorForMetalGo(err, resp)))
 	}
 
 	return resourceMetalReservedIPBlockRead(ctx, d, meta)
 }
 
-func reservedIPStateRefreshFunc(client *packngo.Client, reservedIPId string) retry.StateRefreshFunc {
+func reservedIPStateRefreshFunc(ctx context.Context, client *metalv1.APIClient, reservedIPId string) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
-		reservedIP, _, err := client.ProjectIPs.Get(reservedIPId, nil)
+		reservedIP, resp, err := getMetalIPReservation(ctx, client, reservedIPId, "")
 		if err != nil {
-			return nil, "", fmt.Errorf("error retrieving reserved IP block %s: %s", reservedIPId, err)
+			return nil, "", fmt.Errorf("error retrieving reserved IP block %s: %s", reservedIPId, equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 
-		return reservedIP, string(reservedIP.State), nil
+		return reservedIP, reservedIP.State, nil
 	}
 }
 
-func getType(r *packngo.IPAddressReservation) (string, error) {
+func getType(r *metalIPReservation) (string, error) {
 	switch {
 	case !r.Public:
 		return fmt.Sprintf("private_ipv%d", r.AddressFamily), nil
@@ -380,7 +565,7 @@ func getType(r *packngo.IPAddressReservation) (string, error) {
 	return "", fmt.Errorf("unknown reservation type %+v", r)
 }
 
-func loadBlock(d *schema.ResourceData, reservedBlock *packngo.IPAddressReservation) error {
+func loadBlock(d *schema.ResourceData, reservedBlock *metalIPReservation) error {
 	d.SetId(reservedBlock.ID)
 
 	quantity := 0
@@ -465,16 +650,14 @@ func loadBlock(d *schema.ResourceData, reservedBlock *packngo.IPAddressReservati
 }
 
 func resourceMetalReservedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	id := d.Id()
-	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
-	getOpts = getOpts.Filter("types", "public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
+	query := metalIPReservationQuery("public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
 
-	reservedBlock, _, err := client.ProjectIPs.Get(id, getOpts)
+	reservedBlock, resp, err := getMetalIPReservation(ctx, client, id, query)
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		if equinix_errors.IsNotFound(err) {
 			log.Printf("[WARN] Reserved IP Block (%s) not found, removing from state", d.Id())
 			d.SetId("")
@@ -496,15 +679,16 @@ func resourceMetalReservedIPBlockRead(ctx context.Context, d *schema.ResourceDat
 }
 
 func resourceMetalReservedIPBlockDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	id := d.Id()
 
-	resp, err := client.ProjectIPs.Remove(id)
-
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return diag.Errorf("error deleting IP reservation block %s: %s", id, err)
+	resp, err := client.IPAddressesApi.DeleteIPAddress(ctx, id).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.Errorf("error deleting IP reservation block %s: %s", id, err)
+		}
 	}
 
 	d.SetId("")
@@ -2,6 +2,7 @@ package equinix
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -210,13 +211,13 @@ func TestAccMetalReservedIPBlock_importBasic(t *testing.T) {
 }
 
 func testAccMetalReservedIPBlockCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_reserved_ip_block" {
 			continue
 		}
-		if _, _, err := client.ProjectIPs.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := getMetalIPReservation(context.Background(), client, rs.Primary.ID, ""); err == nil {
 			return fmt.Errorf("Metal Reserved IP block still exists")
 		}
 	}
@@ -0,0 +1,237 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMetalIPProjectID     = "1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"
+	testMetalPublicIPBlockID = "4c3b2a19-0f8e-4d7c-b6a5-948372615a0b"
+	testMetalVRFIPBlockID    = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+)
+
+const recordedMetalPublicIPBlock = `{
+  "id": "4c3b2a19-0f8e-4d7c-b6a5-948372615a0b",
+  "type": "public_ipv4",
+  "state": "created",
+  "address_family": 4,
+  "address": "147.75.100.9",
+  "gateway": "147.75.100.9",
+  "network": "147.75.100.8",
+  "netmask": "255.255.255.252",
+  "cidr": 30,
+  "public": true,
+  "global_ip": false,
+  "management": false,
+  "manageable": true,
+  "details": "",
+  "tags": ["web"],
+  "customdata": {"owner": "ipam"},
+  "project": {"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+  "facility": {"id": "8e6470b3-b75e-47d1-bb93-45b225750975", "code": "ams1", "metro": {"id": "d50fd052-34ec-4977-a173-ad6f9266995d", "code": "AM"}}
+}`
+
+const recordedMetalVRFIPBlock = `{
+  "id": "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d",
+  "type": "vrf",
+  "state": "created",
+  "address_family": 4,
+  "address": "192.168.100.1",
+  "gateway": "192.168.100.1",
+  "network": "192.168.100.0",
+  "netmask": "255.255.255.0",
+  "cidr": 24,
+  "public": false,
+  "global_ip": false,
+  "details": "vrf block",
+  "project": {"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+  "metro": {"id": "108b2cfb-246b-45e3-885a-bf3e82fce1a0", "code": "DA"},
+  "vrf": {"id": "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a"}
+}`
+
+var recordedMetalIPBlocks = map[string]string{
+	"GET /metal/v1/ips/" + testMetalPublicIPBlockID: recordedMetalPublicIPBlock,
+	"GET /metal/v1/ips/" + testMetalVRFIPBlockID:    recordedMetalVRFIPBlock,
+	"GET /metal/v1/projects/" + testMetalIPProjectID + "/ips": `{"ip_addresses": [` + recordedMetalPublicIPBlock + `, ` + recordedMetalVRFIPBlock + `, {
+		"id": "0e1f2a3b-4c5d-4e6f-9a0b-1c2d3e4f5a6b",
+		"type": "public_ipv6",
+		"address_family": 6,
+		"address": "2604:1380:4641:c500::",
+		"network": "2604:1380:4641:c500::",
+		"cidr": 56,
+		"public": true,
+		"project": {"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"},
+		"facility": {"code": "da11", "metro": {"code": "da"}}
+	}, {
+		"id": "2f3e4d5c-6b7a-4980-a1b2-c3d4e5f6a7b8",
+		"type": "global_ipv4",
+		"address_family": 4,
+		"address": "147.75.40.3",
+		"network": "147.75.40.3",
+		"cidr": 32,
+		"public": true,
+		"global_ip": true,
+		"project": {"href": "/metal/v1/projects/1b2c3d4e-5f6a-7b8c-9d0e-1f2a3b4c5d6e"}
+	}]}`,
+}
+
+// packngoResourceMetalReservedIPBlockRead is the packngo implementation of
+// resourceMetalReservedIPBlockRead, with packngoLoadBlock the packngo
+// implementation of loadBlock, kept to compare the states both write
+func packngoResourceMetalReservedIPBlockRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	getOpts := &packngo.GetOptions{Includes: []string{"facility", "metro", "project", "vrf"}}
+	getOpts = getOpts.Filter("types", "public_ipv4,global_ipv4,private_ipv4,public_ipv6,vrf")
+
+	reservedBlock, _, err := client.ProjectIPs.Get(d.Id(), getOpts)
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if err := packngoLoadBlock(d, reservedBlock); err != nil {
+		return diag.FromErr(err)
+	}
+	if (reservedBlock.Description != nil) && (*(reservedBlock.Description) != "") {
+		d.Set("description", *(reservedBlock.Description))
+	}
+	d.Set("global", reservedBlock.Global)
+	return nil
+}
+
+func packngoLoadBlock(d *schema.ResourceData, block *packngo.IPAddressReservation) error {
+	return loadBlock(d, packngoIPReservation(block))
+}
+
+// packngoIPReservation converts a packngo block to the model loadBlock and
+// the location filters read, so that both read the same response fields
+func packngoIPReservation(block *packngo.IPAddressReservation) *metalIPReservation {
+	converted := &metalIPReservation{
+		ID:            block.ID,
+		Address:       block.Address,
+		Gateway:       block.Gateway,
+		Network:       block.Network,
+		AddressFamily: block.AddressFamily,
+		Netmask:       block.Netmask,
+		Public:        block.Public,
+		CIDR:          block.CIDR,
+		Management:    block.Management,
+		Manageable:    block.Manageable,
+		Global:        block.Global,
+		Type:          string(block.Type),
+		State:         string(block.State),
+		Tags:          block.Tags,
+		CustomData:    block.CustomData,
+		Description:   block.Description,
+		Project:       metalHref{Href: block.Project.Href},
+	}
+	if block.Facility != nil {
+		converted.Facility = &metalFacilityCode{Code: block.Facility.Code}
+		if block.Facility.Metro != nil {
+			converted.Facility.Metro = &metalCode{Code: block.Facility.Metro.Code}
+		}
+	}
+	if block.Metro != nil {
+		converted.Metro = &metalCode{Code: block.Metro.Code}
+	}
+	if block.VRF != nil {
+		converted.VRF = &struct {
+			ID string `json:"id"`
+		}{ID: block.VRF.ID}
+	}
+	return converted
+}
+
+func TestResourceMetalReservedIPBlockRead_recorded(t *testing.T) {
+	public := assertMetalReadParity(t, resourceMetalReservedIPBlock(), testMetalPublicIPBlockID, nil, recordedMetalIPBlocks,
+		resourceMetalReservedIPBlockRead, packngoResourceMetalReservedIPBlockRead)
+	assert.Equal(t, "ams1", public["facility"])
+	assert.Equal(t, "am", public["metro"])
+	assert.Equal(t, "4", public["quantity"])
+	assert.Equal(t, "public_ipv4", public["type"])
+	assert.JSONEq(t, `{"owner": "ipam"}`, public["custom_data"])
+
+	vrf := assertMetalReadParity(t, resourceMetalReservedIPBlock(), testMetalVRFIPBlockID, nil, recordedMetalIPBlocks,
+		resourceMetalReservedIPBlockRead, packngoResourceMetalReservedIPBlockRead)
+	assert.Equal(t, "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a", vrf["vrf_id"])
+	assert.Equal(t, "da", vrf["metro"])
+	assert.Equal(t, "vrf block", vrf["description"])
+}
+
+func TestResourceMetalReservedIPBlockCreate_waitsForState(t *testing.T) {
+	pending := `{"id": "` + testMetalPublicIPBlockID + `", "state": "pending"}`
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"POST /metal/v1/projects/" + testMetalIPProjectID + "/ips": pending,
+		"GET /metal/v1/ips/" + testMetalPublicIPBlockID:            recordedMetalPublicIPBlock,
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMetalReservedIPBlock().Schema, map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+		"type":       "public_ipv4",
+		"metro":      "am",
+		"quantity":   4,
+		"tags":       []interface{}{"web"},
+	})
+	if diags := resourceMetalReservedIPBlockCreate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected create error: %v", diags)
+	}
+	assert.Equal(t, testMetalPublicIPBlockID, d.Id())
+	assert.Equal(t, "147.75.100.8/30", d.Get("cidr_notation"))
+
+	body, _ := fake.request("POST /metal/v1/projects/" + testMetalIPProjectID + "/ips")
+	assert.JSONEq(t, `{"type": "public_ipv4", "quantity": 4, "metro": "am", "tags": ["web"], "customdata": "{}"}`, body)
+}
+
+func TestResourceMetalReservedIPBlockUpdate_customData(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"PATCH /metal/v1/ips/" + testMetalPublicIPBlockID: recordedMetalPublicIPBlock,
+		"GET /metal/v1/ips/" + testMetalPublicIPBlockID:   recordedMetalPublicIPBlock,
+	})
+
+	r := resourceMetalReservedIPBlock()
+	config := map[string]interface{}{
+		"project_id": testMetalIPProjectID,
+		"type":       "public_ipv4",
+		"facility":   "ams1",
+		"quantity":   4,
+		"tags":       []interface{}{"web"},
+	}
+	state := schema.TestResourceDataRaw(t, r.Schema, config)
+	state.SetId(testMetalPublicIPBlockID)
+	if diags := resourceMetalReservedIPBlockRead(context.Background(), state, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	config["custom_data"] = `{"owner": "terraform"}`
+	diff, err := r.Diff(context.Background(), state.State(), terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state.State(), diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diags := resourceMetalReservedIPBlockUpdate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected update error: %v", diags)
+	}
+
+	body, _ := fake.request("PATCH /metal/v1/ips/" + testMetalPublicIPBlockID)
+	request := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("invalid update request %q: %v", body, err)
+	}
+	assert.Equal(t, map[string]interface{}{"customdata": map[string]interface{}{"owner": "terraform"}}, request,
+		"custom_data is sent as an object")
+}
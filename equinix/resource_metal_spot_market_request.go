@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
@@ -17,15 +18,15 @@ import (
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceMetalSpotMarketRequest() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceMetalSpotMarketRequestCreate,
 		ReadContext:   resourceMetalSpotMarketRequestRead,
+		UpdateContext: resourceMetalSpotMarketRequestUpdate,
 		DeleteContext: resourceMetalSpotMarketRequestDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -44,10 +45,12 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 				ForceNew:    true,
 			},
 			"max_bid_price": {
-				Type:        schema.TypeFloat,
-				Description: "Maximum price user is willing to pay per hour per device",
-				Required:    true,
-				ForceNew:    true,
+				Type:         schema.TypeFloat,
+				Description:  "Maximum price user is willing to pay per hour per device. Computed from the winning bid when `bidding_strategy` is used",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"max_bid_price", "bidding_strategy"},
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					oldF, err := strconv.ParseFloat(old, 64)
 					if err != nil {
@@ -115,10 +118,79 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 				Type:          schema.TypeString,
 				Description:   "Metro where devices should be created",
 				Optional:      true,
+				Computed:      true,
 				ForceNew:      true,
-				ConflictsWith: []string{"facilities"},
+				ConflictsWith: []string{"facilities", "bidding_strategy"},
 				StateFunc:     converters.ToLowerIf,
 			},
+			"bidding_strategy": {
+				Type:          schema.TypeList,
+				Description:   "Bid at a margin above the current spot market price, trying the candidate metros and plans in order until `devices_min` devices are created. Changes re-bid in place instead of replacing the resource",
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"facilities"},
+				ExactlyOneOf:  []string{"max_bid_price", "bidding_strategy"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_price": {
+							Type:         schema.TypeFloat,
+							Description:  "Highest price per hour per device the strategy will bid",
+							Required:     true,
+							ValidateFunc: validation.FloatAtLeast(0),
+						},
+						"margin_percent": {
+							Type:         schema.TypeFloat,
+							Description:  "Percentage above the current spot market price to bid, capped at `max_price`",
+							Optional:     true,
+							Default:      10.0,
+							ValidateFunc: validation.FloatAtLeast(0),
+						},
+						"attempt_timeout": {
+							Type:         schema.TypeString,
+							Description:  "How long to wait for a bid to get `devices_min` devices before trying the next candidate, as a duration string such as `10m`",
+							Optional:     true,
+							Default:      "5m",
+							ValidateFunc: validateDurationString,
+						},
+						"candidate": {
+							Type:        schema.TypeList,
+							Description: "Ordered list of metros and plans to bid on",
+							Required:    true,
+							MinItems:    1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"metro": {
+										Type:        schema.TypeString,
+										Description: "Metro code to bid in",
+										Required:    true,
+										StateFunc:   converters.ToLowerIf,
+									},
+									"plan": {
+										Type:        schema.TypeString,
+										Description: "Plan to bid on, defaults to `instance_parameters.plan`",
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"bid_metro": {
+				Type:        schema.TypeString,
+				Description: "Metro of the winning `bidding_strategy` candidate, read from the request",
+				Computed:    true,
+			},
+			"bid_plan": {
+				Type:        schema.TypeString,
+				Description: "Plan of the winning `bidding_strategy` candidate, read from the request",
+				Computed:    true,
+			},
+			"bid_market_price": {
+				Type:        schema.TypeFloat,
+				Description: "Spot market price of the winning `bidding_strategy` candidate at the time of bidding. Imported requests get the spot market price at the time of import",
+				Computed:    true,
+			},
 			"instance_parameters": {
 				Type:        schema.TypeList,
 				Description: "Parameters for devices provisioned from this request. You can find the parameter description from the [equinix_metal_device doc](device.md)",
@@ -215,161 +287,208 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 	}
 }
 
 func resourceMetalSpotMarketRequestCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	var waitForDevices bool
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	metro := d.Get("metro").(string)
+	smrc, diags := expandSpotMarketRequestCreateRequest(d)
+	if diags.HasError() {
+		return diags
+	}
 
-	facilitiesRaw := d.Get("facilities").([]interface{})
-	facilities := []string{}
+	start := time.Now()
+	strategy, err := expandSpotMarketBiddingStrategy(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if strategy != nil {
+		smr, bid, err := bidSpotMarketRequest(ctx, client, d.Get("project_id").(string), *smrc, strategy)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(smr.ID)
+		setSpotMarketBid(d, bid)
+	} else {
+		smr, err := createSpotMarketRequest(ctx, client, d.Get("project_id").(string), *smrc)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(smr.ID)
+	}
 
-	for _, f := range facilitiesRaw {
-		facilities = append(facilities, f.(string))
+	if d.Get("wait_for_devices").(bool) {
+		if err := waitForSpotMarketRequestDevices(ctx, client, d.Id(), d.Timeout(schema.TimeoutCreate)-time.Since(start)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	params := packngo.SpotMarketRequestInstanceParameters{
-		Hostname:        d.Get("instance_parameters.0.hostname").(string),
-		BillingCycle:    d.Get("instance_parameters.0.billing_cycle").(string),
-		Plan:            d.Get("instance_parameters.0.plan").(string),
-		OperatingSystem: d.Get("instance_parameters.0.operating_system").(string),
+	return resourceMetalSpotMarketRequestRead(ctx, d, meta)
+}
+
+// resourceMetalSpotMarketRequestUpdate re-bids when the bidding strategy
+// changes. Spot market requests can not be modified, so a new request is
+// fulfilled first and the previous one is removed afterwards. The state keeps
+// the previous request until then, a new request that fails is removed again.
+func resourceMetalSpotMarketRequestUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	if !d.HasChange("bidding_strategy") {
+		return resourceMetalSpotMarketRequestRead(ctx, d, meta)
+	}
+	strategy, err := expandSpotMarketBiddingStrategy(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if strategy == nil {
+		return resourceMetalSpotMarketRequestRead(ctx, d, meta)
 	}
 
-	if val, ok := d.GetOk("instance_parameters.0.userdata"); ok {
-		params.UserData = val.(string)
+	smrc, diags := expandSpotMarketRequestCreateRequest(d)
+	if diags.HasError() {
+		return diags
 	}
 
-	if val, ok := d.GetOk("instance_parameters.0.customdata"); ok {
-		params.CustomData = val.(string)
+	// The bidding strategy is only recorded with the request it placed
+	d.Partial(true)
+	start := time.Now()
+	previousID := d.Id()
+	smr, bid, err := bidSpotMarketRequest(ctx, client, d.Get("project_id").(string), *smrc, strategy)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("wait_for_devices").(bool) {
+		if err := waitForSpotMarketRequestDevices(ctx, client, smr.ID, d.Timeout(schema.TimeoutUpdate)-time.Since(start)); err != nil {
+			return diag.FromErr(removeFailedSpotMarketRequest(ctx, client, smr.ID, err))
+		}
+	}
+
+	log.Printf("[DEBUG] Re-bid spot market request %s as %s, removing the previous request", previousID, smr.ID)
+	if err := deleteSpotMarketRequest(ctx, client, previousID); err != nil {
+		err = fmt.Errorf("error removing previous spot market request %s: %s", previousID, err)
+		return diag.FromErr(removeFailedSpotMarketRequest(ctx, client, smr.ID, err))
+	}
+
+	d.Partial(false)
+	d.SetId(smr.ID)
+	setSpotMarketBid(d, bid)
+	return resourceMetalSpotMarketRequestRead(ctx, d, meta)
+}
+
+// removeFailedSpotMarketRequest removes a re-bid spot market request which
+// could not replace the previous one, and returns the error of the re-bid
+func removeFailedSpotMarketRequest(ctx context.Context, client *metalv1.APIClient, id string, err error) error {
+	log.Printf("[DEBUG] Removing spot market request %s after a failed re-bid: %s", id, err)
+	if deleteErr := deleteSpotMarketRequest(ctx, client, id); deleteErr != nil {
+		return fmt.Errorf("%s; error removing spot market request %s: %s", err, id, deleteErr)
+	}
+	return err
+}
+
+func setSpotMarketBid(d *schema.ResourceData, bid *spotMarketBid) {
+	d.Set("bid_metro", bid.Metro)
+	d.Set("bid_plan", bid.Plan)
+	d.Set("bid_market_price", bid.MarketPrice)
+}
+
+// expandSpotMarketRequestCreateRequest builds the request from the resource
+// data. metal-go has no ipxe_script_url and types customdata as an object, so
+// both are sent as additional properties
+func expandSpotMarketRequestCreateRequest(d *schema.ResourceData) (*metalv1.SpotMarketRequestCreateInput, diag.Diagnostics) {
+	params := metalv1.SpotMarketRequestCreateInputInstanceParameters{
+		BillingCycle:         metalv1.PtrString(d.Get("instance_parameters.0.billing_cycle").(string)),
+		Plan:                 metalv1.PtrString(d.Get("instance_parameters.0.plan").(string)),
+		OperatingSystem:      metalv1.PtrString(d.Get("instance_parameters.0.operating_system").(string)),
+		AlwaysPxe:            metalv1.PtrBool(d.Get("instance_parameters.0.always_pxe").(bool)),
+		Locked:               metalv1.PtrBool(d.Get("instance_parameters.0.locked").(bool)),
+		AdditionalProperties: map[string]interface{}{},
 	}
 
-	if val, ok := d.GetOk("instance_parameters.0.ipxe_script_url"); ok {
-		params.IPXEScriptURL = val.(string)
+	if val, ok := d.GetOk("instance_parameters.0.hostname"); ok {
+		params.Hostname = metalv1.PtrString(val.(string))
+	}
+
+	userData := d.Get("instance_parameters.0.userdata").(string)
+	params.Userdata = metalv1.PtrString(userData)
+
+	if val, ok := d.GetOk("instance_parameters.0.customdata"); ok {
+		params.AdditionalProperties["customdata"] = val.(string)
 	}
 
-	if val, ok := d.GetOk("instance_parameters.0.always_pxe"); ok {
-		params.AlwaysPXE = val.(bool)
+	ipxeScriptURL := d.Get("instance_parameters.0.ipxe_script_url").(string)
+	if ipxeScriptURL != "" {
+		params.AdditionalProperties["ipxe_script_url"] = ipxeScriptURL
 	}
 
-	if params.OperatingSystem == "custom_ipxe" {
-		if params.IPXEScriptURL == "" && params.UserData == "" {
-			return diag.Errorf("\"ipxe_script_url\" or \"user_data\"" +
+	if params.GetOperatingSystem() == "custom_ipxe" {
+		if ipxeScriptURL == "" && userData == "" {
+			return nil, diag.Errorf("\"ipxe_script_url\" or \"user_data\"" +
 				" must be provided when \"custom_ipxe\" OS is selected.")
 		}
 
 		// ipxe_script_url + user_data is OK, unless user_data is an ipxe script in
 		// which case it's an error.
-		if params.IPXEScriptURL != "" {
-			if matchIPXEScript.MatchString(params.UserData) {
-				return diag.Errorf("\"user_data\" should not be an iPXE " +
+		if ipxeScriptURL != "" {
+			if matchIPXEScript.MatchString(userData) {
+				return nil, diag.Errorf("\"user_data\" should not be an iPXE " +
 					"script when \"ipxe_script_url\" is also provided.")
 			}
 		}
 	}
 
-	if params.OperatingSystem != "custom_ipxe" && params.IPXEScriptURL != "" {
-		return diag.Errorf("\"ipxe_script_url\" argument provided, but" +
+	if params.GetOperatingSystem() != "custom_ipxe" && ipxeScriptURL != "" {
+		return nil, diag.Errorf("\"ipxe_script_url\" argument provided, but" +
 			" OS is not \"custom_ipxe\". Please verify and fix device arguments.")
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.description"); ok {
-		params.Description = val.(string)
+		params.Description = metalv1.PtrString(val.(string))
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.features"); ok {
-		temp := val.([]interface{})
-		for _, i := range temp {
-			if i != nil {
-				params.Features = append(params.Features, i.(string))
-			}
-		}
-	}
-
-	if val, ok := d.GetOk("wait_for_devices"); ok {
-		waitForDevices = val.(bool)
-	}
-
-	if val, ok := d.GetOk("instance_parameters.0.locked"); ok {
-		params.Locked = val.(bool)
+		params.Features = converters.IfArrToStringArr(val.([]interface{}))
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.project_ssh_keys"); ok {
-		temp := val.([]interface{})
-		for _, i := range temp {
-			if i != nil {
-				params.ProjectSSHKeys = append(params.ProjectSSHKeys, i.(string))
-			}
-		}
+		params.ProjectSshKeys = converters.IfArrToStringArr(val.([]interface{}))
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.tags"); ok {
-		temp := val.([]interface{})
-		for _, i := range temp {
-			if i != nil {
-				params.Tags = append(params.Tags, i.(string))
-			}
-		}
+		params.Tags = converters.IfArrToStringArr(val.([]interface{}))
 	}
 
 	if val, ok := d.GetOk("instance_parameters.0.user_ssh_keys"); ok {
-		temp := val.([]interface{})
-		for _, i := range temp {
-			if i != nil {
-				params.UserSSHKeys = append(params.UserSSHKeys, i.(string))
-			}
-		}
+		params.UserSshKeys = converters.IfArrToStringArr(val.([]interface{}))
 	}
 
-	smrc := &packngo.SpotMarketRequestCreateRequest{
-		DevicesMax:  d.Get("devices_max").(int),
-		DevicesMin:  d.Get("devices_min").(int),
-		MaxBidPrice: d.Get("max_bid_price").(float64),
-		FacilityIDs: facilities,
-		Metro:       metro,
-		Parameters:  params,
+	smrc := &metalv1.SpotMarketRequestCreateInput{
+		DevicesMax:         metalv1.PtrInt32(int32(d.Get("devices_max").(int))),
+		DevicesMin:         metalv1.PtrInt32(int32(d.Get("devices_min").(int))),
+		MaxBidPrice:        metalv1.PtrFloat32(float32(d.Get("max_bid_price").(float64))),
+		InstanceParameters: &params,
 	}
 
-	start := time.Now()
-	smr, _, err := client.SpotMarketRequests.Create(smrc, d.Get("project_id").(string))
-	if err != nil {
-		return diag.FromErr(err)
+	if facilities := d.Get("facilities").([]interface{}); len(facilities) > 0 {
+		smrc.Facilities = converters.IfArrToStringArr(facilities)
 	}
 
-	d.SetId(smr.ID)
-
-	if waitForDevices {
-		stateConf := &retry.StateChangeConf{
-			Pending:        []string{"not_done"},
-			Target:         []string{"done"},
-			Refresh:        resourceStateRefreshFunc(d, meta),
-			Timeout:        d.Timeout(schema.TimeoutCreate) - time.Since(start) - time.Second*10, // reduce 30s to avoid context deadline
-			MinTimeout:     5 * time.Second,
-			Delay:          3 * time.Second, // Wait 10 secs before starting
-			NotFoundChecks: 600,             // Setting high number, to support long timeouts
-		}
-
-		_, err = stateConf.WaitForStateContext(ctx)
-		if err != nil {
-			return diag.FromErr(err)
-		}
+	if metro := d.Get("metro").(string); metro != "" {
+		smrc.Metro = metalv1.PtrString(metro)
 	}
 
-	return resourceMetalSpotMarketRequestRead(ctx, d, meta)
+	return smrc, nil
 }
 
 func resourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+	smr, err := getSpotMarketRequest(ctx, client, d.Id(), "facilities", "metro", "plan")
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
 		if equinix_errors.IsNotFound(err) {
 			log.Printf("[WARN] SpotMarketRequest (%s) not found, removing from state", d.Id())
 			d.SetId("")
@@ -382,21 +501,33 @@ func resourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceD
 	if smr.Metro != nil {
 		metro = smr.Metro.Code
 	}
+	plan := smr.Plan.Slug
+
+	// The market price at the time of bidding is not recorded with the
+	// request. Requests read without it, such as imported ones, get the
+	// current spot market price
+	marketPrice := d.Get("bid_market_price").(float64)
+	if marketPrice == 0 && metro != "" && plan != "" {
+		if prices, err := metroSpotMarketPrices(ctx, client); err != nil {
+			log.Printf("[WARN] Spot market price of %s in %s not read: %s", plan, metro, err)
+		} else {
+			marketPrice = prices[metro][plan]
+		}
+	}
 
 	err = equinix_schema.SetMap(d, map[string]interface{}{
-		"metro":         metro,
-		"project_id":    smr.Project.ID,
-		"devices_min":   smr.DevicesMin,
-		"devices_max":   smr.DevicesMax,
-		"max_bid_price": smr.MaxBidPrice,
+		"metro":            metro,
+		"project_id":       smr.ProjectID(),
+		"devices_min":      smr.DevicesMin,
+		"devices_max":      smr.DevicesMax,
+		"max_bid_price":    smr.MaxBidPrice,
+		"bid_metro":        metro,
+		"bid_plan":         plan,
+		"bid_market_price": marketPrice,
 		"facilities": func(d *schema.ResourceData, k string) error {
-			facilityIDs := make([]string, len(smr.Facilities))
 			facilityCodes := make([]string, len(smr.Facilities))
-			if len(smr.Facilities) > 0 {
-				for i, f := range smr.Facilities {
-					facilityIDs[i] = f.ID
-					facilityCodes[i] = f.Code
-				}
+			for i, f := range smr.Facilities {
+				facilityCodes[i] = f.Code
 			}
 			return d.Set(k, facilityCodes)
 		},
@@ -406,71 +537,24 @@ func resourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceD
 }
 
 func resourceMetalSpotMarketRequestDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	var waitForDevices bool
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	if val, ok := d.GetOk("wait_for_devices"); ok {
-		waitForDevices = val.(bool)
-	}
-	if waitForDevices {
-		smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+	if d.Get("wait_for_devices").(bool) {
+		smr, err := getSpotMarketRequestWithDevices(ctx, client, d.Id())
 		if err != nil {
 			return nil
 		}
 
-		stateConf := &retry.StateChangeConf{
-			Pending:        []string{"not_done"},
-			Target:         []string{"done"},
-			Refresh:        resourceStateRefreshFunc(d, meta),
-			Timeout:        d.Timeout(schema.TimeoutDelete) - 30*time.Second,
-			MinTimeout:     5 * time.Second,
-			Delay:          3 * time.Second, // Wait 10 secs before starting
-			NotFoundChecks: 600,             // Setting high number, to support long timeouts
-		}
-
-		_, err = stateConf.WaitForStateContext(ctx)
-		if err != nil {
+		if err := waitForSpotMarketRequestDevices(ctx, client, d.Id(), d.Timeout(schema.TimeoutDelete)-20*time.Second); err != nil {
 			return diag.FromErr(err)
 		}
 
-		for _, d := range smr.Devices {
-			resp, err := client.Devices.Delete(d.ID, true)
-			if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-				return diag.FromErr(err)
+		for _, id := range smr.DeviceIDs() {
+			resp, err := client.DevicesApi.DeleteDevice(ctx, id).ForceDelete(true).Execute()
+			if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+				return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 			}
 		}
 	}
-	resp, err := client.SpotMarketRequests.Delete(d.Id(), true)
-	return diag.FromErr(equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err))
-}
-
-func resourceStateRefreshFunc(d *schema.ResourceData, meta interface{}) retry.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		meta.(*config.Config).AddModuleToMetalUserAgent(d)
-		client := meta.(*config.Config).Metal
-
-		smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
-		if err != nil {
-			return nil, "", fmt.Errorf("Failed to fetch Spot market request with following error: %s", err.Error())
-		}
-		var finished bool
-
-		for _, d := range smr.Devices {
-
-			dev, _, err := client.Devices.Get(d.ID, nil)
-			if err != nil {
-				return nil, "", fmt.Errorf("Failed to fetch Device with following error: %s", err.Error())
-			}
-			if dev.State != "active" {
-				break
-			} else {
-				finished = true
-			}
-		}
-		if finished {
-			return smr, "done", nil
-		}
-		return nil, "not_done", nil
-	}
+	return diag.FromErr(deleteSpotMarketRequest(ctx, client, d.Id()))
 }
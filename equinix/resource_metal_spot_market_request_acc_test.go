@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -9,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 var (
@@ -18,7 +18,7 @@ var (
 )
 
 func TestAccMetalSpotMarketRequest_basic(t *testing.T) {
-	var key packngo.SpotMarketRequest
+	var key metalSpotMarketRequest
 	projSuffix := acctest.RandString(10)
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -42,13 +42,13 @@ func TestAccMetalSpotMarketRequest_basic(t *testing.T) {
 }
 
 func testAccMetalSpotMarketRequestCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_spot_market_request" {
 			continue
 		}
-		if _, _, err := client.SpotMarketRequests.Get(rs.Primary.ID, nil); err == nil {
+		if _, err := getSpotMarketRequest(context.Background(), client, rs.Primary.ID); err == nil {
 			return fmt.Errorf("Metal Spot market request key still exists")
 		}
 	}
@@ -56,7 +56,7 @@ func testAccMetalSpotMarketRequestCheckDestroyed(s *terraform.State) error {
 	return nil
 }
 
-func testAccCheckMetalSpotMarketRequestExists(n string, key *packngo.SpotMarketRequest) resource.TestCheckFunc {
+func testAccCheckMetalSpotMarketRequestExists(n string, key *metalSpotMarketRequest) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
@@ -66,9 +66,9 @@ func testAccCheckMetalSpotMarketRequestExists(n string, key *packngo.SpotMarketR
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*config.Config).Metal
+		client := testAccProvider.Meta().(*config.Config).Metalgo
 
-		foundKey, _, err := client.SpotMarketRequests.Get(rs.Primary.ID, &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+		foundKey, err := getSpotMarketRequest(context.Background(), client, rs.Primary.ID, "project", "devices", "facilities", "metro")
 		if err != nil {
 			return err
 		}
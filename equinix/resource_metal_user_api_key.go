@@ -12,13 +12,15 @@ func resourceMetalUserAPIKey() *schema.Resource {
 		Description: "UUID of user owning this key",
 	}
 	return &schema.Resource{
-		Create: resourceMetalAPIKeyCreate,
-		Read:   resourceMetalAPIKeyRead,
-		Delete: resourceMetalAPIKeyDelete,
+		CreateContext: resourceMetalAPIKeyCreate,
+		ReadContext:   resourceMetalAPIKeyRead,
+		UpdateContext: resourceMetalAPIKeyUpdate,
+		DeleteContext: resourceMetalAPIKeyDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
-		Schema: userKeySchema,
+		Schema:        userKeySchema,
+		CustomizeDiff: resourceMetalAPIKeyCustomizeDiff,
 	}
 }
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -23,21 +24,21 @@ func testSweepUserAPIKeys(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping user_api keys: %s", err)
 	}
-	metal := config.NewMetalClient()
-	userApiKeys, _, err := metal.APIKeys.UserList(nil)
+	metal := config.NewMetalGoClient()
+	userApiKeys, _, err := metal.AuthenticationApi.FindAPIKeys(context.Background()).Execute()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting list for sweeping user_api keys: %s", err)
 	}
 	ids := []string{}
-	for _, k := range userApiKeys {
-		if isSweepableTestResource(k.Description) {
-			ids = append(ids, k.ID)
+	for _, k := range userApiKeys.GetApiKeys() {
+		if isSweepableTestResource(k.GetDescription()) {
+			ids = append(ids, k.GetId())
 		}
 	}
 	for _, id := range ids {
 		log.Printf("Removing user api key %s", id)
-		resp, err := metal.APIKeys.Delete(id)
-		if err != nil && resp.StatusCode != http.StatusNotFound {
+		resp, err := metal.AuthenticationApi.DeleteUserAPIKey(context.Background(), id).Execute()
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
 			return fmt.Errorf("Error deleting user_api key %s", err)
 		}
 	}
@@ -69,13 +70,19 @@ func TestAccMetalUserAPIKey_basic(t *testing.T) {
 }
 
 func testAccMetalUserAPIKeyCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_user_api_key" {
 			continue
 		}
-		if _, err := client.APIKeys.UserGet(rs.Primary.ID, nil); err == nil {
-			return fmt.Errorf("Metal UserAPI key still exists")
+		keys, _, err := client.AuthenticationApi.FindAPIKeys(context.Background()).Execute()
+		if err != nil {
+			continue
+		}
+		for _, k := range keys.GetApiKeys() {
+			if k.GetId() == rs.Primary.ID {
+				return fmt.Errorf("Metal UserAPI key still exists")
+			}
 		}
 	}
 	return nil
@@ -2,8 +2,9 @@ package equinix
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"reflect"
+	"path"
 	"regexp"
 	"strconv"
 	"time"
@@ -12,13 +13,15 @@ import (
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceMetalVirtualCircuit() *schema.Resource {
@@ -106,18 +109,21 @@ func resourceMetalVirtualCircuit() *schema.Resource {
 				Description: `A subnet from one of the IP blocks associated with the VRF that we will help create an IP reservation for. Can only be either a /30 or /31.
 				 * For a /31 block, it will only have two IP addresses, which will be used for the metal_ip and customer_ip.
 				 * For a /30 block, it will have four IP addresses, but the first and last IP addresses are not usable. We will default to the first usable IP address for the metal_ip.`,
+				ValidateFunc: equinix_validation.StringIsCIDRWithPrefixLength(vcSubnetIPv4Sizes, equinix_validation.PrefixLengthRange{}),
 			},
 			"metal_ip": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				RequiredWith: []string{"vrf_id"},
 				Description:  "The Metal IP address for the SVI (Switch Virtual Interface) of the VirtualCircuit. Will default to the first usable IP in the subnet.",
+				ValidateFunc: validation.IsIPAddress,
 			},
 			"customer_ip": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				RequiredWith: []string{"vrf_id"},
 				Description:  "The Customer IP address which the CSR switch will peer with. Will default to the other usable IP in the subnet.",
+				ValidateFunc: validation.IsIPAddress,
 			},
 			"md5": {
 				Type:        schema.TypeString,
@@ -142,80 +148,196 @@ func resourceMetalVirtualCircuit() *schema.Resource {
 				Description: "Status of the virtual circuit resource",
 			},
 		},
+		CustomizeDiff: validateVirtualCircuitSubnet,
 	}
 }
 
-func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-	vncr := packngo.VCCreateRequest{
-		VirtualNetworkID: d.Get("vlan_id").(string),
-		Name:             d.Get("name").(string),
-		Description:      d.Get("description").(string),
-		Speed:            d.Get("speed").(string),
-		VRFID:            d.Get("vrf_id").(string),
-		PeerASN:          d.Get("peer_asn").(int),
-		Subnet:           d.Get("subnet").(string),
-		MetalIP:          d.Get("metal_ip").(string),
-		CustomerIP:       d.Get("customer_ip").(string),
-		MD5:              d.Get("md5").(string),
+var vcSubnetIPv4Sizes = equinix_validation.PrefixLengthRange{Min: 30, Max: 31}
+
+// validateVirtualCircuitSubnet checks that metal_ip and customer_ip are
+// distinct host addresses inside subnet, which excludes the network and
+// broadcast addresses of a /30, and that subnet fits within one of the
+// ip_ranges of the VRF. The VRF is only looked up when its ID is already known.
+func validateVirtualCircuitSubnet(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	subnet := d.Get("subnet").(string)
+	if !d.NewValueKnown("subnet") || subnet == "" {
+		return nil
+	}
+
+	for _, key := range []string{"metal_ip", "customer_ip"} {
+		ip := d.Get(key).(string)
+		if !d.NewValueKnown(key) || ip == "" {
+			continue
+		}
+		if err := equinix_validation.CIDRContainsHost(subnet, ip); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	if d.NewValueKnown("metal_ip") && d.NewValueKnown("customer_ip") {
+		if metalIP := d.Get("metal_ip").(string); metalIP != "" && metalIP == d.Get("customer_ip").(string) {
+			return fmt.Errorf("metal_ip and customer_ip must be different addresses, both are %s", metalIP)
+		}
 	}
 
+	vrfID := d.Get("vrf_id").(string)
+	if !d.NewValueKnown("vrf_id") || vrfID == "" || !d.HasChanges("subnet", "vrf_id") {
+		return nil
+	}
+	client := meta.(*config.Config).Metalgo
+	vrf, _, err := client.VRFsApi.FindVrfById(ctx, vrfID).Execute()
+	if err != nil {
+		// The VRF may not be readable yet, leave the final word to the API
+		log.Printf("[WARN] Could not read VRF (%s) to validate subnet: %s", vrfID, err)
+		return nil
+	}
+	if err := equinix_validation.CIDRWithinAny(subnet, vrf.GetIpRanges()); err != nil {
+		return fmt.Errorf("invalid subnet for VRF %s: %w", vrfID, err)
+	}
+	return nil
+}
+
+func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
 	connId := d.Get("connection_id").(string)
 	portId := d.Get("port_id").(string)
 	projectId := d.Get("project_id").(string)
 
-	tags := d.Get("tags.#").(int)
-	if tags > 0 {
-		vncr.Tags = converters.IfArrToStringArr(d.Get("tags").([]interface{}))
+	var tags []string
+	if d.Get("tags.#").(int) > 0 {
+		tags = converters.IfArrToStringArr(d.Get("tags").([]interface{}))
+	}
+	// speed may be given with units, the API accepts it as a string
+	var additionalProperties map[string]interface{}
+	if speed := d.Get("speed").(string); speed != "" {
+		additionalProperties = map[string]interface{}{"speed": speed}
 	}
 
-	if nniVlan, ok := d.GetOk("nni_vlan"); ok {
-		vncr.NniVLAN = nniVlan.(int)
+	vncr := metalv1.VirtualCircuitCreateInput{}
+	if vrfID, ok := d.GetOk("vrf_id"); ok {
+		input := &metalv1.VrfVirtualCircuitCreateInput{
+			ProjectId:            projectId,
+			Vrf:                  vrfID.(string),
+			NniVlan:              int32(d.Get("nni_vlan").(int)),
+			PeerAsn:              int32(d.Get("peer_asn").(int)),
+			Subnet:               d.Get("subnet").(string),
+			Tags:                 tags,
+			AdditionalProperties: additionalProperties,
+		}
+		if name, ok := d.GetOk("name"); ok {
+			input.SetName(name.(string))
+		}
+		if description, ok := d.GetOk("description"); ok {
+			input.SetDescription(description.(string))
+		}
+		if metalIP, ok := d.GetOk("metal_ip"); ok {
+			input.SetMetalIp(metalIP.(string))
+		}
+		if customerIP, ok := d.GetOk("customer_ip"); ok {
+			input.SetCustomerIp(customerIP.(string))
+		}
+		if md5, ok := d.GetOk("md5"); ok {
+			input.SetMd5(md5.(string))
+		}
+		vncr.VrfVirtualCircuitCreateInput = input
+	} else {
+		input := &metalv1.VlanVirtualCircuitCreateInput{
+			ProjectId:            projectId,
+			Tags:                 tags,
+			AdditionalProperties: additionalProperties,
+		}
+		if vlanID, ok := d.GetOk("vlan_id"); ok {
+			input.SetVnid(vlanID.(string))
+		}
+		if nniVlan, ok := d.GetOk("nni_vlan"); ok {
+			input.SetNniVlan(int32(nniVlan.(int)))
+		}
+		if name, ok := d.GetOk("name"); ok {
+			input.SetName(name.(string))
+		}
+		if description, ok := d.GetOk("description"); ok {
+			input.SetDescription(description.(string))
+		}
+		vncr.VlanVirtualCircuitCreateInput = input
 	}
-	conn, _, err := client.Connections.Get(connId, nil)
+
+	conn, resp, err := client.InterconnectionsApi.GetInterconnection(ctx, connId).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
-	if conn.Status == string(packngo.VCStatusPending) {
-		return diag.Errorf("Connection request with name %s and ID %s wasn't approved yet", conn.Name, conn.ID)
+	if conn.GetStatus() == string(metalv1.VLANVIRTUALCIRCUITSTATUS_PENDING) {
+		return diag.Errorf("Connection request with name %s and ID %s wasn't approved yet", conn.GetName(), conn.GetId())
 	}
 
-	vc, _, err := client.VirtualCircuits.Create(projectId, connId, portId, &vncr, nil)
+	vc, resp, err := client.InterconnectionsApi.CreateInterconnectionPortVirtualCircuit(ctx, connId, portId).
+		VirtualCircuitCreateInput(vncr).
+		Execute()
 	if err != nil {
 		log.Printf("[DEBUG] Error creating virtual circuit: %s", err)
-		return diag.FromErr(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
+	vcID := virtualCircuitID(vc)
 	// TODO: offer to wait while VCStatusPending
 	createWaiter := getVCStateWaiter(
+		ctx,
 		client,
-		vc.ID,
+		vcID,
 		d.Timeout(schema.TimeoutCreate)-30*time.Second,
-		[]string{string(packngo.VCStatusActivating)},
-		[]string{string(packngo.VCStatusActive)},
+		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_ACTIVATING)},
+		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_ACTIVE)},
 	)
 
 	_, err = createWaiter.WaitForStateContext(ctx)
 	if err != nil {
-		return diag.Errorf("Error waiting for virtual circuit %s to be created: %s", vc.ID, err.Error())
+		return diag.Errorf("Error waiting for virtual circuit %s to be created: %s", vcID, err.Error())
 	}
 
-	d.SetId(vc.ID)
+	d.SetId(vcID)
 
 	return resourceMetalVirtualCircuitRead(ctx, d, meta)
 }
 
 func resourceMetalVirtualCircuitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	vcId := d.Id()
 
-	vc, _, err := client.VirtualCircuits.Get(
-		vcId,
-		&packngo.GetOptions{Includes: []string{"project", "virtual_network", "vrf"}},
-	)
+	vc, resp, err := client.InterconnectionsApi.GetVirtualCircuit(ctx, vcId).
+		Include([]string{"project", "virtual_network", "vrf"}).
+		Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	var (
+		portHref, projectHref, vlanID, vrfID   string
+		name, description, status              string
+		subnet, metalIP, customerIP, md5       string
+		speed, nniVlan, vnid, nniVnid, peerASN int
+		tags                                   []string
+	)
+	switch {
+	case vc.VlanVirtualCircuit != nil:
+		v := vc.VlanVirtualCircuit
+		portHref, projectHref = v.Port.GetHref(), v.Project.GetHref()
+		if v.VirtualNetwork != nil {
+			vlanID = path.Base(v.VirtualNetwork.GetHref())
+		}
+		name, description, status = v.GetName(), v.GetDescription(), string(v.GetStatus())
+		speed, nniVlan, vnid = int(v.GetSpeed()), int(v.GetNniVlan()), int(v.GetVnid())
+		nniVnid = additionalPropertyInt(v.AdditionalProperties, "nni_vnid")
+		tags = v.GetTags()
+	case vc.VrfVirtualCircuit != nil:
+		v := vc.VrfVirtualCircuit
+		portHref, projectHref = v.Port.GetHref(), v.Project.GetHref()
+		vrfID = v.Vrf.GetId()
+		name, description, status = v.GetName(), v.GetDescription(), string(v.GetStatus())
+		speed, nniVlan, peerASN = int(v.GetSpeed()), int(v.GetNniVlan()), int(v.GetPeerAsn())
+		vnid = additionalPropertyInt(v.AdditionalProperties, "vnid")
+		nniVnid = additionalPropertyInt(v.AdditionalProperties, "nni_vnid")
+		subnet, metalIP, customerIP, md5 = v.GetSubnet(), v.GetMetalIp(), v.GetCustomerIp(), v.GetMd5()
+		tags = v.GetTags()
+	default:
+		return diag.Errorf("virtual circuit %s is neither a VLAN nor a VRF virtual circuit", vcId)
 	}
 
 	// TODO: use API field from VC responses when available The regexp is
@@ -224,42 +346,42 @@ func resourceMetalVirtualCircuitRead(ctx context.Context, d *schema.ResourceData
 	connectionID := "" // vc.Connection.ID is not available yet
 	portID := ""       // vc.Port.ID would be available with ?include=port
 	connectionRe := regexp.MustCompile("/connections/([0-9a-z-]+)/ports/([0-9a-z-]+)")
-	matches := connectionRe.FindStringSubmatch(vc.Port.Href.Href)
+	matches := connectionRe.FindStringSubmatch(portHref)
 	if len(matches) == 3 {
 		connectionID = matches[1]
 		portID = matches[2]
 	} else {
-		log.Printf("[DEBUG] Could not parse connection and port ID from port href %s", vc.Port.Href.Href)
+		log.Printf("[DEBUG] Could not parse connection and port ID from port href %s", portHref)
 	}
 
 	err = equinix_schema.SetMap(d, map[string]interface{}{
-		"project_id": vc.Project.ID,
+		"project_id": path.Base(projectHref),
 		"port_id":    portID,
 		"vlan_id": func(d *schema.ResourceData, k string) error {
-			if vc.VirtualNetwork != nil {
-				return d.Set(k, vc.VirtualNetwork.ID)
+			if vlanID != "" {
+				return d.Set(k, vlanID)
 			}
 			return nil
 		},
 		"vrf_id": func(d *schema.ResourceData, k string) error {
-			if vc.VRF != nil {
-				return d.Set(k, vc.VRF.ID)
+			if vrfID != "" {
+				return d.Set(k, vrfID)
 			}
 			return nil
 		},
-		"status":      vc.Status,
-		"nni_vlan":    vc.NniVLAN,
-		"vnid":        vc.VNID,
-		"nni_vnid":    vc.NniVNID,
-		"name":        vc.Name,
-		"speed":       strconv.Itoa(vc.Speed),
-		"description": vc.Description,
-		"tags":        vc.Tags,
-		"peer_asn":    vc.PeerASN,
-		"subnet":      vc.Subnet,
-		"metal_ip":    vc.MetalIP,
-		"customer_ip": vc.CustomerIP,
-		"md5":         vc.MD5,
+		"status":      status,
+		"nni_vlan":    nniVlan,
+		"vnid":        vnid,
+		"nni_vnid":    nniVnid,
+		"name":        name,
+		"speed":       strconv.Itoa(speed),
+		"description": description,
+		"tags":        tags,
+		"peer_asn":    peerASN,
+		"subnet":      subnet,
+		"metal_ip":    metalIP,
+		"customer_ip": customerIP,
+		"md5":         md5,
 		"connection_id": func(d *schema.ResourceData, k string) error {
 			if connectionID != "" {
 				return d.Set(k, connectionID)
@@ -271,16 +393,39 @@ func resourceMetalVirtualCircuitRead(ctx context.Context, d *schema.ResourceData
 	return diag.FromErr(err)
 }
 
-func getVCStateWaiter(client *packngo.Client, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
+// additionalPropertyInt returns the number in a field of an API response
+// which is not part of the metalv1 model, or 0.
+func additionalPropertyInt(props map[string]interface{}, key string) int {
+	if v, ok := props[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+func virtualCircuitID(vc *metalv1.VirtualCircuit) string {
+	if vc.VrfVirtualCircuit != nil {
+		return vc.VrfVirtualCircuit.GetId()
+	}
+	return vc.VlanVirtualCircuit.GetId()
+}
+
+func virtualCircuitStatus(vc *metalv1.VirtualCircuit) string {
+	if vc.VrfVirtualCircuit != nil {
+		return string(vc.VrfVirtualCircuit.GetStatus())
+	}
+	return string(vc.VlanVirtualCircuit.GetStatus())
+}
+
+func getVCStateWaiter(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
 	return &retry.StateChangeConf{
 		Pending: pending,
 		Target:  target,
 		Refresh: func() (interface{}, string, error) {
-			vc, _, err := client.VirtualCircuits.Get(id, nil)
+			vc, resp, err := client.InterconnectionsApi.GetVirtualCircuit(ctx, id).Execute()
 			if err != nil {
-				return 0, "", err
+				return 0, "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			}
-			return vc, string(vc.Status), nil
+			return vc, virtualCircuitStatus(vc), nil
 		},
 		Timeout:    timeout,
 		Delay:      10 * time.Second,
@@ -289,72 +434,78 @@ func getVCStateWaiter(client *packngo.Client, id string, timeout time.Duration,
 }
 
 func resourceMetalVirtualCircuitUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-
-	ur := packngo.VCUpdateRequest{}
-	if d.HasChange("vlan_id") {
-		vnid := d.Get("vlan_id").(string)
-		ur.VirtualNetworkID = &vnid
-	}
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
+	var (
+		name, description, speed *string
+		tags                     []string
+	)
 	if d.HasChange("name") {
-		name := d.Get("name").(string)
-		ur.Name = &name
+		name = metalv1.PtrString(d.Get("name").(string))
 	}
-
 	if d.HasChange("description") {
-		desc := d.Get("description").(string)
-		ur.Description = &desc
+		description = metalv1.PtrString(d.Get("description").(string))
 	}
-
-	if d.HasChange("speed") {
-		speed := d.Get("speed").(string)
-		ur.Speed = speed
+	if d.HasChange("speed") && d.Get("speed").(string) != "" {
+		speed = metalv1.PtrString(d.Get("speed").(string))
 	}
-
 	if d.HasChange("tags") {
-		ts := d.Get("tags")
-		sts := []string{}
+		tags = converters.IfArrToStringArr(d.Get("tags").([]interface{}))
+	}
 
-		switch ts.(type) {
-		case []interface{}:
-			for _, v := range ts.([]interface{}) {
-				sts = append(sts, v.(string))
-			}
-			ur.Tags = &sts
-		default:
-			return diag.Errorf("garbage in tags: %s", ts)
+	ur := metalv1.VirtualCircuitUpdateInput{}
+	changed := name != nil || description != nil || speed != nil || tags != nil
+	if _, ok := d.GetOk("vrf_id"); ok {
+		ur.VrfVirtualCircuitUpdateInput = &metalv1.VrfVirtualCircuitUpdateInput{
+			Name:        name,
+			Description: description,
+			Speed:       speed,
+			Tags:        tags,
+		}
+	} else {
+		input := &metalv1.VlanVirtualCircuitUpdateInput{
+			Name:        name,
+			Description: description,
+			Speed:       speed,
+			Tags:        tags,
+		}
+		if d.HasChange("vlan_id") {
+			input.SetVnid(d.Get("vlan_id").(string))
+			changed = true
 		}
+		ur.VlanVirtualCircuitUpdateInput = input
 	}
 
-	if !reflect.DeepEqual(ur, packngo.VCUpdateRequest{}) {
-		if _, _, err := client.VirtualCircuits.Update(d.Id(), &ur, nil); err != nil {
-			return diag.FromErr(equinix_errors.FriendlyError(err))
+	if changed {
+		if _, resp, err := client.InterconnectionsApi.UpdateVirtualCircuit(ctx, d.Id()).VirtualCircuitUpdateInput(ur).Execute(); err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 		}
 	}
 	return resourceMetalVirtualCircuitRead(ctx, d, meta)
 }
 
 func resourceMetalVirtualCircuitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.VirtualCircuits.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return diag.FromErr(equinix_errors.FriendlyError(err))
+	_, resp, err := client.InterconnectionsApi.DeleteVirtualCircuit(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+		return diag.FromErr(err)
 	}
 
 	deleteWaiter := getVCStateWaiter(
+		ctx,
 		client,
 		d.Id(),
 		d.Timeout(schema.TimeoutDelete)-30*time.Second,
-		[]string{string(packngo.VCStatusDeleting)},
+		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_DELETING)},
 		[]string{},
 	)
 
 	_, err = deleteWaiter.WaitForStateContext(ctx)
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(nil, err) != nil {
+	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(nil, err) != nil {
 		return diag.Errorf("Error deleting virtual circuit %s: %s", d.Id(), err)
 	}
 	d.SetId("")
@@ -1,8 +1,10 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"testing"
 
@@ -11,7 +13,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func init() {
@@ -28,22 +29,35 @@ func testSweepVirtualCircuits(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping VirtualCircuits: %s", err)
 	}
-	metal := config.NewMetalClient()
-	orgList, _, err := metal.Organizations.List(nil)
+	metal := config.NewMetalGoClient()
+	orgList, err := metal.OrganizationsApi.FindOrganizations(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting organization list for sweeping VirtualCircuits: %s", err)
 	}
-	vcs := map[string]*packngo.VirtualCircuit{}
-	for _, org := range orgList {
-		conns, _, err := metal.Connections.OrganizationList(org.ID, &packngo.GetOptions{Includes: []string{"ports"}})
+	// the connections are decoded in the test as the speeds of metal-go
+	// connections overflow, see metalConnection
+	type virtualCircuit struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	vcs := map[string]virtualCircuit{}
+	for _, org := range orgList.Organizations {
+		conns := struct {
+			Interconnections []struct {
+				Ports []struct {
+					VirtualCircuits []virtualCircuit `json:"virtual_circuits"`
+				} `json:"ports"`
+			} `json:"interconnections"`
+		}{}
+		_, err := metalRequest(context.Background(), metal, http.MethodGet, "organizations/"+org.GetId()+"/connections?include=ports", nil, &conns)
 		if err != nil {
 			return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting connections list for sweeping VirtualCircuits: %s", err)
 		}
-		for _, conn := range conns {
+		for _, conn := range conns.Interconnections {
 			for _, port := range conn.Ports {
 				for _, vc := range port.VirtualCircuits {
 					if isSweepableTestResource(vc.Name) {
-						vcs[vc.ID] = &vc
+						vcs[vc.ID] = vc
 					}
 				}
 			}
@@ -51,7 +65,7 @@ func testSweepVirtualCircuits(region string) error {
 	}
 	for _, vc := range vcs {
 		log.Printf("[INFO][SWEEPER_LOG] Deleting VirtualCircuit: %s", vc.Name)
-		_, err := metal.VirtualCircuits.Delete(vc.ID)
+		_, err := metalRequest(context.Background(), metal, http.MethodDelete, "virtual-circuits/"+vc.ID, nil, nil)
 		if err != nil {
 			return fmt.Errorf("[INFO][SWEEPER_LOG] Error deleting VirtualCircuit: %s", err)
 		}
@@ -61,13 +75,13 @@ func testSweepVirtualCircuits(region string) error {
 }
 
 func testAccMetalVirtualCircuitCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_virtual_circuit" {
 			continue
 		}
-		if _, _, err := client.VirtualCircuits.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.InterconnectionsApi.GetVirtualCircuit(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal VirtualCircuit still exists")
 		}
 	}
@@ -0,0 +1,164 @@
+package equinix
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"testing"
+
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	recordedMetalVLANVirtualCircuit = `{
+  "id": "f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f",
+  "type": "vlan",
+  "name": "tf-vc",
+  "description": "recorded VLAN VC",
+  "speed": 50000000,
+  "status": "active",
+  "vnid": 1001,
+  "nni_vnid": 1234,
+  "nni_vlan": 1234,
+  "tags": ["tf", "vlan"],
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "name": "tf-project", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "port": {"href": "/metal/v1/connections/6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d/ports/9d8c7b6a-5f4e-4d3c-9b2a-1f0e9d8c7b6a"},
+  "virtual_network": {"id": "5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b", "vxlan": 1001, "href": "/metal/v1/virtual-networks/5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b"}
+}`
+	recordedMetalVRFVirtualCircuit = `{
+  "id": "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+  "type": "vrf",
+  "name": "tf-vrf-vc",
+  "description": "recorded VRF VC",
+  "speed": 0,
+  "status": "active",
+  "nni_vlan": 1235,
+  "peer_asn": 65530,
+  "subnet": "192.168.100.16/31",
+  "metal_ip": "192.168.100.16",
+  "customer_ip": "192.168.100.17",
+  "md5": "md5Password1",
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "port": {"href": "/metal/v1/connections/6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d/ports/9d8c7b6a-5f4e-4d3c-9b2a-1f0e9d8c7b6a"},
+  "vrf": {"id": "3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10", "name": "tf-vrf", "href": "/metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10"}
+}`
+)
+
+// packngoResourceMetalVirtualCircuitRead is the packngo implementation of
+// resourceMetalVirtualCircuitRead, kept to compare the states both write
+func packngoResourceMetalVirtualCircuitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+	vcId := d.Id()
+
+	vc, _, err := client.VirtualCircuits.Get(
+		vcId,
+		&packngo.GetOptions{Includes: []string{"project", "virtual_network", "vrf"}},
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	connectionID := ""
+	portID := ""
+	connectionRe := regexp.MustCompile("/connections/([0-9a-z-]+)/ports/([0-9a-z-]+)")
+	matches := connectionRe.FindStringSubmatch(vc.Port.Href.Href)
+	if len(matches) == 3 {
+		connectionID = matches[1]
+		portID = matches[2]
+	}
+
+	err = equinix_schema.SetMap(d, map[string]interface{}{
+		"project_id": vc.Project.ID,
+		"port_id":    portID,
+		"vlan_id": func(d *schema.ResourceData, k string) error {
+			if vc.VirtualNetwork != nil {
+				return d.Set(k, vc.VirtualNetwork.ID)
+			}
+			return nil
+		},
+		"vrf_id": func(d *schema.ResourceData, k string) error {
+			if vc.VRF != nil {
+				return d.Set(k, vc.VRF.ID)
+			}
+			return nil
+		},
+		"status":      vc.Status,
+		"nni_vlan":    vc.NniVLAN,
+		"vnid":        vc.VNID,
+		"nni_vnid":    vc.NniVNID,
+		"name":        vc.Name,
+		"speed":       strconv.Itoa(vc.Speed),
+		"description": vc.Description,
+		"tags":        vc.Tags,
+		"peer_asn":    vc.PeerASN,
+		"subnet":      vc.Subnet,
+		"metal_ip":    vc.MetalIP,
+		"customer_ip": vc.CustomerIP,
+		"md5":         vc.MD5,
+		"connection_id": func(d *schema.ResourceData, k string) error {
+			if connectionID != "" {
+				return d.Set(k, connectionID)
+			}
+			return nil
+		},
+	})
+
+	return diag.FromErr(err)
+}
+
+func TestResourceMetalVirtualCircuitRead_recorded(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		response string
+	}{
+		{
+			name:     "vlan",
+			id:       "f0e1d2c3-b4a5-4968-8776-5a4b3c2d1e0f",
+			response: recordedMetalVLANVirtualCircuit,
+		},
+		{
+			name:     "vrf",
+			id:       "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+			response: recordedMetalVRFVirtualCircuit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := assertMetalReadParity(t, resourceMetalVirtualCircuit(), tt.id, nil, map[string]string{
+				"GET /metal/v1/virtual-circuits/" + tt.id: tt.response,
+			}, resourceMetalVirtualCircuitRead, packngoResourceMetalVirtualCircuitRead)
+
+			assert.Equal(t, "6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d", state["connection_id"])
+			assert.Equal(t, "9d8c7b6a-5f4e-4d3c-9b2a-1f0e9d8c7b6a", state["port_id"])
+		})
+	}
+}
+
+func TestResourceMetalVirtualCircuitCustomizeDiff_hostAddresses(t *testing.T) {
+	diff := func(subnet, metalIP, customerIP string) error {
+		t.Helper()
+		config := map[string]interface{}{
+			"connection_id": "6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d",
+			"project_id":    "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de",
+			"port_id":       "9d8c7b6a-5f4e-4d3c-9b2a-1f0e9d8c7b6a",
+			"subnet":        subnet,
+			"metal_ip":      metalIP,
+			"customer_ip":   customerIP,
+		}
+		_, err := resourceMetalVirtualCircuit().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+		return err
+	}
+
+	assert.NoError(t, diff("192.168.100.16/30", "192.168.100.17", "192.168.100.18"))
+	assert.ErrorContains(t, diff("192.168.100.16/30", "192.168.100.16", "192.168.100.17"), "invalid metal_ip: 192.168.100.16 is the network address")
+	assert.ErrorContains(t, diff("192.168.100.16/30", "192.168.100.17", "192.168.100.19"), "invalid customer_ip: 192.168.100.19 is the broadcast address")
+	assert.NoError(t, diff("192.168.100.16/31", "192.168.100.16", "192.168.100.17"))
+}
@@ -1,26 +1,39 @@
 package equinix
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"path"
+	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// metalVlanPlacement allows to switch the VLAN attributes between a facility
+// and its metro without replacing the VLAN
+var metalVlanPlacement = facilityMetroAttributes{Facility: "facility", Metro: "metro"}
+
 func resourceMetalVlan() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceMetalVlanCreate,
-		Read:   resourceMetalVlanRead,
-		Delete: resourceMetalVlanDelete,
+	r := &schema.Resource{
+		CreateContext: resourceMetalVlanCreate,
+		ReadContext:   resourceMetalVlanRead,
+		UpdateContext: resourceMetalVlanUpdate,
+		DeleteContext: resourceMetalVlanDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"project_id": {
@@ -40,7 +53,6 @@ func resourceMetalVlan() *schema.Resource {
 				Description:   "Facility where to create the VLAN",
 				Deprecated:    "Use metro instead of facility.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
 				Optional:      true,
-				ForceNew:      true,
 				ConflictsWith: []string{"metro"},
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					// suppress diff when unsetting facility
@@ -54,7 +66,7 @@ func resourceMetalVlan() *schema.Resource {
 				Type:          schema.TypeString,
 				Description:   "Metro in which to create the VLAN",
 				Optional:      true,
-				ForceNew:      true,
+				Computed:      true,
 				ConflictsWith: []string{"facility"},
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					_, facOk := d.GetOk("facility")
@@ -77,100 +89,293 @@ func resourceMetalVlan() *schema.Resource {
 				Optional:    true,
 				Computed:    true,
 			},
+			"vxlan_range": {
+				Type:          schema.TypeList,
+				Description:   "Range of VXLANs to pick the VXLAN of the VLAN from. The lowest VXLAN of the range which is not used by another VLAN of the project in the metro is picked",
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"vxlan", "facility"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"min": {
+							Type:         schema.TypeInt,
+							Description:  "Lowest VXLAN of the range",
+							Required:     true,
+							ValidateFunc: validation.IntBetween(2, 3999),
+						},
+						"max": {
+							Type:         schema.TypeInt,
+							Description:  "Highest VXLAN of the range",
+							Required:     true,
+							ValidateFunc: validation.IntBetween(2, 3999),
+						},
+					},
+				},
+			},
 		},
+		CustomizeDiff: customdiff.Sequence(
+			metalVlanPlacement.customizeDiff,
+			resourceMetalVlanVXLANRangeCustomizeDiff,
+		),
+		SchemaVersion: 1,
 	}
+
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Type:    resourceMetalVlanResourceV0().CoreConfigSchema().ImpliedType(),
+			Upgrade: metalVlanPlacement.stateUpgrade,
+			Version: 0,
+		},
+	}
+
+	return r
 }
 
-func resourceMetalVlanCreate(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// resourceMetalVlanResourceV0 is the VLAN schema of version 0, before
+// facility and metro could be switched in place. It only keeps what makes up
+// the state type and must not follow later changes to the schema.
+func resourceMetalVlanResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"description": {Type: schema.TypeString, Optional: true},
+			"facility":    {Type: schema.TypeString, Optional: true},
+			"metro":       {Type: schema.TypeString, Optional: true},
+			"project_id":  {Type: schema.TypeString, Required: true},
+			"vxlan":       {Type: schema.TypeInt, Optional: true, Computed: true},
+		},
+	}
+}
+
+func resourceMetalVlanCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	facRaw, facOk := d.GetOk("facility")
 	metroRaw, metroOk := d.GetOk("metro")
 	vxlanRaw, vxlanOk := d.GetOk("vxlan")
 
 	if !facOk && !metroOk {
-		return equinix_errors.FriendlyError(errors.New("one of facility or metro must be configured"))
+		return diag.Errorf("one of facility or metro must be configured")
 	}
 	if facOk && vxlanOk {
-		return equinix_errors.FriendlyError(errors.New("you can set vxlan only for metro vlans"))
+		return diag.Errorf("you can set vxlan only for metro vlans")
 	}
 
-	createRequest := &packngo.VirtualNetworkCreateRequest{
-		ProjectID:   d.Get("project_id").(string),
-		Description: d.Get("description").(string),
+	projectID := d.Get("project_id").(string)
+	createRequest := metalv1.VirtualNetworkCreateInput{
+		Description: metalv1.PtrString(d.Get("description").(string)),
 	}
 	if metroOk {
-		createRequest.Metro = metroRaw.(string)
-		createRequest.VXLAN = vxlanRaw.(int)
+		createRequest.Metro = metalv1.PtrString(metroRaw.(string))
+		if vxlanOk {
+			createRequest.Vxlan = metalv1.PtrInt32(int32(vxlanRaw.(int)))
+		}
+	}
+	if vxlanRange, ok := expandVXLANRange(d.Get("vxlan_range").([]interface{})); ok && metroOk {
+		// VLANs picking from the same range in one run must not pick the
+		// same VXLAN, the VXLAN is picked and created under a lock
+		metro := createRequest.GetMetro()
+		lockID := fmt.Sprintf("vxlan-%s-%s", projectID, strings.ToLower(metro))
+		mutexkv.Metal.Lock(lockID)
+		defer mutexkv.Metal.Unlock(lockID)
+
+		vlans, err := listProjectVlans(ctx, client, projectID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		vxlan, err := lowestFreeVXLAN(vlans, metro, vxlanRange)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		createRequest.Vxlan = metalv1.PtrInt32(int32(vxlan))
 	}
 	if facOk {
-		createRequest.Facility = facRaw.(string)
+		createRequest.Facility = metalv1.PtrString(facRaw.(string))
 	}
-	vlan, _, err := client.ProjectVirtualNetworks.Create(createRequest)
+	vlan, resp, err := client.VLANsApi.CreateVirtualNetwork(ctx, projectID).VirtualNetworkCreateInput(createRequest).Execute()
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	d.SetId(vlan.GetId())
+	return resourceMetalVlanRead(ctx, d, meta)
+}
+
+// listProjectVlans lists the VLANs of the project
+func listProjectVlans(ctx context.Context, client *metalv1.APIClient, projectID string) ([]metalv1.VirtualNetwork, error) {
+	vlans, resp, err := client.VLANsApi.FindVirtualNetworks(ctx, projectID).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return vlans.VirtualNetworks, nil
+}
+
+// vlanProjectID returns the ID of the project the VLAN is assigned to
+func vlanProjectID(vlan *metalv1.VirtualNetwork) string {
+	if vlan.AssignedTo == nil {
+		return ""
 	}
-	d.SetId(vlan.ID)
-	return resourceMetalVlanRead(d, meta)
+	return path.Base(vlan.AssignedTo.GetHref())
 }
 
-func resourceMetalVlanRead(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// vlanFacilityCode returns the facility code of the VLAN. metal-go does not
+// model it, it is only kept in the additional properties.
+func vlanFacilityCode(vlan *metalv1.VirtualNetwork) string {
+	code, _ := vlan.AdditionalProperties["facility_code"].(string)
+	return code
+}
 
-	vlan, _, err := client.ProjectVirtualNetworks.Get(d.Id(),
-		&packngo.GetOptions{Includes: []string{"assigned_to"}})
+func resourceMetalVlanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+	vlan, resp, err := client.VLANsApi.GetVirtualNetwork(ctx, d.Id()).Include([]string{"assigned_to"}).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		if equinix_errors.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 
 	}
-	d.Set("description", vlan.Description)
-	d.Set("project_id", vlan.Project.ID)
-	d.Set("vxlan", vlan.VXLAN)
-	d.Set("facility", vlan.FacilityCode)
-	d.Set("metro", vlan.MetroCode)
+	d.Set("description", vlan.GetDescription())
+	d.Set("project_id", vlanProjectID(vlan))
+	d.Set("vxlan", vlan.GetVxlan())
+	d.Set("facility", vlanFacilityCode(vlan))
+	d.Set("metro", vlan.GetMetroCode())
 	return nil
 }
 
-func resourceMetalVlanDelete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func resourceMetalVlanUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only switches between the facility and the metro of the VLAN are
+	// updated in place, the VLAN itself does not change
+	return resourceMetalVlanRead(ctx, d, meta)
+}
+
+// vlanInstancePorts returns the ids of the ports of the devices the VLAN is
+// assigned to, by the VLANs attached to them. metal-go models the devices as
+// links, the included ports are only kept in the additional properties.
+func vlanInstancePorts(vlan *metalv1.VirtualNetwork) (map[string][]string, error) {
+	ports := map[string][]string{}
+	for _, link := range vlan.Instances {
+		raw, err := link.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		instance := struct {
+			NetworkPorts []struct {
+				ID              string `json:"id"`
+				VirtualNetworks []struct {
+					Href string `json:"href"`
+				} `json:"virtual_networks"`
+			} `json:"network_ports"`
+		}{}
+		if err := json.Unmarshal(raw, &instance); err != nil {
+			return nil, err
+		}
+		for _, p := range instance.NetworkPorts {
+			for _, v := range p.VirtualNetworks {
+				vlanID := path.Base(v.Href)
+				ports[vlanID] = append(ports[vlanID], p.ID)
+			}
+		}
+	}
+	return ports, nil
+}
+
+func resourceMetalVlanDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	id := d.Id()
-	vlan, resp, err := client.ProjectVirtualNetworks.Get(id, &packngo.GetOptions{Includes: []string{"instances", "instances.network_ports.virtual_networks", "internet_gateway"}})
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
-	} else if err != nil {
+	vlan, resp, err := client.VLANsApi.GetVirtualNetwork(ctx, id).Include([]string{"instances", "instances.network_ports.virtual_networks", "internet_gateway"}).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 		// missing vlans are deleted
 		return nil
 	}
 
 	// all device ports must be unassigned before delete
-	for _, i := range vlan.Instances {
-		for _, p := range i.NetworkPorts {
-			for _, a := range p.AttachedVirtualNetworks {
-				// a.ID is not set despite including instaces.network_ports.virtual_networks
-				// TODO(displague) packngo should offer GetID() that uses ID or Href
-				aID := path.Base(a.Href)
-
-				if aID == id {
-					_, resp, err := client.Ports.Unassign(p.ID, id)
-
-					if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-						return equinix_errors.FriendlyError(err)
-					}
-				}
-			}
+	ports, err := vlanInstancePorts(vlan)
+	if err != nil {
+		return diag.Errorf("error reading the device ports of VLAN %s: %s", id, err)
+	}
+	for _, portID := range ports[id] {
+		_, resp, err := client.PortsApi.UnassignPort(ctx, portID).PortAssignInput(metalv1.PortAssignInput{Vnid: &id}).Execute()
+		if err != nil {
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// TODO(displague) do we need to unassign gateway connections before delete?
 
-	return equinix_errors.FriendlyError(equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(client.ProjectVirtualNetworks.Delete(id)))
+	_, resp, err = client.VLANsApi.DeleteVirtualNetwork(ctx, id).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	return diag.FromErr(equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err))
+}
+
+// vxlanRange is an inclusive range of VXLANs
+type vxlanRange struct {
+	Min, Max int
+}
+
+func expandVXLANRange(raw []interface{}) (vxlanRange, bool) {
+	if len(raw) == 0 || raw[0] == nil {
+		return vxlanRange{}, false
+	}
+	m := raw[0].(map[string]interface{})
+	return vxlanRange{Min: m["min"].(int), Max: m["max"].(int)}, true
+}
+
+// lowestFreeVXLAN returns the lowest VXLAN of the range which is not used by
+// any of the VLANs in the metro
+func lowestFreeVXLAN(vlans []metalv1.VirtualNetwork, metro string, r vxlanRange) (int, error) {
+	used := map[int]bool{}
+	for _, v := range vlans {
+		if strings.EqualFold(v.GetMetroCode(), metro) {
+			used[int(v.GetVxlan())] = true
+		}
+	}
+	for vxlan := r.Min; vxlan <= r.Max; vxlan++ {
+		if !used[vxlan] {
+			return vxlan, nil
+		}
+	}
+	return 0, fmt.Errorf("all VXLANs from %d to %d are used in metro %s", r.Min, r.Max, metro)
+}
+
+// resourceMetalVlanVXLANRangeCustomizeDiff checks the vxlan_range of new
+// VLANs and fails the plan when all VXLANs of the range are already used
+func resourceMetalVlanVXLANRangeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		return nil
+	}
+	r, ok := expandVXLANRange(d.Get("vxlan_range").([]interface{}))
+	if !ok {
+		return nil
+	}
+	if r.Min > r.Max {
+		return fmt.Errorf("vxlan_range min %d is higher than max %d", r.Min, r.Max)
+	}
+	if !d.NewValueKnown("metro") || !d.NewValueKnown("project_id") {
+		return nil
+	}
+	metro := d.Get("metro").(string)
+	if metro == "" {
+		return errors.New("vxlan_range can only be used for metro VLANs")
+	}
+
+	client := meta.(*config.Config).Metalgo
+	vlans, err := listProjectVlans(ctx, client, d.Get("project_id").(string))
+	if err != nil {
+		return fmt.Errorf("error listing the VLANs of project %s to check vxlan_range: %w", d.Get("project_id").(string), err)
+	}
+	_, err = lowestFreeVXLAN(vlans, metro, r)
+	return err
 }
@@ -1,16 +1,17 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func init() {
@@ -27,34 +28,34 @@ func testSweepVlans(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping vlans: %s", err)
 	}
-	metal := config.NewMetalClient()
-	ps, _, err := metal.Projects.List(nil)
+	metal := config.NewMetalGoClient()
+	ps, err := metal.ProjectsApi.FindProjects(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting project list for sweeping vlans: %s", err)
 	}
 	pids := []string{}
-	for _, p := range ps {
-		if isSweepableTestResource(p.Name) {
-			pids = append(pids, p.ID)
+	for _, p := range ps.Projects {
+		if isSweepableTestResource(p.GetName()) {
+			pids = append(pids, p.GetId())
 		}
 	}
 	dids := []string{}
 	for _, pid := range pids {
-		ds, _, err := metal.ProjectVirtualNetworks.List(pid, nil)
+		ds, err := listProjectVlans(context.Background(), metal, pid)
 		if err != nil {
 			log.Printf("Error listing vlans to sweep: %s", err)
 			continue
 		}
-		for _, d := range ds.VirtualNetworks {
-			if isSweepableTestResource(d.Description) {
-				dids = append(dids, d.ID)
+		for _, d := range ds {
+			if isSweepableTestResource(d.GetDescription()) {
+				dids = append(dids, d.GetId())
 			}
 		}
 	}
 
 	for _, did := range dids {
 		log.Printf("Removing vlan %s", did)
-		_, err := metal.ProjectVirtualNetworks.Delete(did)
+		_, _, err := metal.VLANsApi.DeleteVirtualNetwork(context.Background(), did).Execute()
 		if err != nil {
 			return fmt.Errorf("Error deleting vlan %s", err)
 		}
@@ -101,7 +102,7 @@ func TestAccMetalVlan_metro(t *testing.T) {
 }
 
 func TestAccMetalVlan_basic(t *testing.T) {
-	var vlan packngo.VirtualNetwork
+	var vlan metalv1.VirtualNetwork
 	rs := acctest.RandString(10)
 	fac := "ny5"
 
@@ -125,7 +126,7 @@ func TestAccMetalVlan_basic(t *testing.T) {
 	})
 }
 
-func testAccCheckMetalVlanExists(n string, vlan *packngo.VirtualNetwork) resource.TestCheckFunc {
+func testAccCheckMetalVlanExists(n string, vlan *metalv1.VirtualNetwork) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
@@ -135,13 +136,13 @@ func testAccCheckMetalVlanExists(n string, vlan *packngo.VirtualNetwork) resourc
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*config.Config).Metal
+		client := testAccProvider.Meta().(*config.Config).Metalgo
 
-		foundVlan, _, err := client.ProjectVirtualNetworks.Get(rs.Primary.ID, nil)
+		foundVlan, _, err := client.VLANsApi.GetVirtualNetwork(context.Background(), rs.Primary.ID).Execute()
 		if err != nil {
 			return err
 		}
-		if foundVlan.ID != rs.Primary.ID {
+		if foundVlan.GetId() != rs.Primary.ID {
 			return fmt.Errorf("Record not found: %v - %v", rs.Primary.ID, foundVlan)
 		}
 
@@ -152,13 +153,13 @@ func testAccCheckMetalVlanExists(n string, vlan *packngo.VirtualNetwork) resourc
 }
 
 func testAccMetalVlanCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_vlan" {
 			continue
 		}
-		if _, _, err := client.ProjectVirtualNetworks.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.VLANsApi.GetVirtualNetwork(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal Vlan still exists")
 		}
 	}
@@ -0,0 +1,211 @@
+package equinix
+
+import (
+	"context"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalProjectVlans = `{
+  "virtual_networks": [
+    {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", "vxlan": 1000, "metro_code": "sv"},
+    {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b22", "vxlan": 1001, "metro_code": "sv"},
+    {"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b23", "vxlan": 1002, "metro_code": "da"}
+  ]
+}`
+
+func Test_lowestFreeVXLAN(t *testing.T) {
+	vlans := []metalv1.VirtualNetwork{
+		{Vxlan: metalv1.PtrInt32(1000), MetroCode: metalv1.PtrString("sv")},
+		{Vxlan: metalv1.PtrInt32(1001), MetroCode: metalv1.PtrString("sv")},
+		{Vxlan: metalv1.PtrInt32(1003), MetroCode: metalv1.PtrString("sv")},
+		{Vxlan: metalv1.PtrInt32(1002), MetroCode: metalv1.PtrString("da")},
+	}
+
+	vxlan, err := lowestFreeVXLAN(vlans, "SV", vxlanRange{Min: 1000, Max: 1010})
+	assert.NoError(t, err)
+	assert.Equal(t, 1002, vxlan, "VXLANs of other metros are free")
+
+	vxlan, err = lowestFreeVXLAN(vlans, "da", vxlanRange{Min: 1000, Max: 1010})
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, vxlan)
+
+	_, err = lowestFreeVXLAN(vlans, "sv", vxlanRange{Min: 1000, Max: 1001})
+	assert.ErrorContains(t, err, "all VXLANs from 1000 to 1001 are used in metro sv")
+}
+
+func TestResourceMetalVlanCreate_vxlanRange(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/virtual-networks":  recordedMetalProjectVlans,
+		"POST /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/virtual-networks": `{"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b24", "vxlan": 1002, "metro_code": "sv"}`,
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b24":           `{"id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b24", "vxlan": 1002, "metro_code": "sv", "assigned_to": {"href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"}}`,
+	})
+
+	d := resourceMetalVlan().TestResourceData()
+	d.Set("project_id", "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de")
+	d.Set("metro", "sv")
+	d.Set("vxlan_range", []interface{}{map[string]interface{}{"min": 1000, "max": 1099}})
+	diags := resourceMetalVlanCreate(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	body, _ := fake.request("POST /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/virtual-networks")
+	assert.JSONEq(t, `{"description":"","metro":"sv","vxlan":1002}`, body)
+	assert.Equal(t, 1002, d.Get("vxlan"))
+}
+
+func TestResourceMetalVlanCustomizeDiff_vxlanRangeExhausted(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/virtual-networks": recordedMetalProjectVlans,
+	})
+	r := resourceMetalVlan()
+	config := func(min, max int) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_id":  "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de",
+			"metro":       "sv",
+			"vxlan_range": []interface{}{map[string]interface{}{"min": min, "max": max}},
+		})
+	}
+
+	_, err := r.Diff(context.Background(), nil, config(1000, 1001), meta)
+	assert.ErrorContains(t, err, "all VXLANs from 1000 to 1001 are used in metro sv")
+
+	_, err = r.Diff(context.Background(), nil, config(1001, 1000), meta)
+	assert.ErrorContains(t, err, "min 1001 is higher than max 1000")
+
+	_, err = r.Diff(context.Background(), nil, config(1000, 1002), meta)
+	assert.NoError(t, err)
+}
+
+const recordedMetalVlan = `{
+  "id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+  "description": "recorded VLAN",
+  "vxlan": 1001,
+  "facility_code": "sv15",
+  "metro_code": "sv",
+  "assigned_to": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "name": "tf-project", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"
+}`
+
+// packngoResourceMetalVlanRead is the packngo implementation of
+// resourceMetalVlanRead, kept to compare the states both write
+func packngoResourceMetalVlanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	vlan, _, err := client.ProjectVirtualNetworks.Get(d.Id(),
+		&packngo.GetOptions{Includes: []string{"assigned_to"}})
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+
+	}
+	d.Set("description", vlan.Description)
+	d.Set("project_id", vlan.Project.ID)
+	d.Set("vxlan", vlan.VXLAN)
+	d.Set("facility", vlan.FacilityCode)
+	d.Set("metro", vlan.MetroCode)
+	return nil
+}
+
+func TestResourceMetalVlanRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalVlan(), "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21", nil, map[string]string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": recordedMetalVlan,
+	}, resourceMetalVlanRead, packngoResourceMetalVlanRead)
+
+	assert.Equal(t, "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", state["project_id"])
+	assert.Equal(t, "sv15", state["facility"])
+}
+
+// packngoDataSourceMetalVlanRead is the packngo implementation of
+// dataSourceMetalVlanRead for VLANs looked up by their id, kept to compare
+// the states both write
+func packngoDataSourceMetalVlanRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	vlan, _, err := client.ProjectVirtualNetworks.Get(
+		d.Get("vlan_id").(string),
+		&packngo.GetOptions{Includes: []string{"assigned_to"}},
+	)
+	if err != nil {
+		return diag.FromErr(packngoFriendlyError(err))
+	}
+
+	eventsPager := metalEventsPager(ctx, meta.(*config.Config).Metalgo, "", vlan.Project.ID)
+	lastModifiedBy, _ := metalLastModifiedBy(eventsPager, vlan.ID)
+
+	d.SetId(vlan.ID)
+
+	return diag.FromErr(equinix_schema.SetMap(d, map[string]interface{}{
+		"vlan_id":          vlan.ID,
+		"project_id":       vlan.Project.ID,
+		"vxlan":            vlan.VXLAN,
+		"facility":         vlan.FacilityCode,
+		"metro":            vlan.MetroCode,
+		"description":      vlan.Description,
+		"last_modified_by": lastModifiedBy,
+	}))
+}
+
+func TestDataSourceMetalVlanRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, dataSourceMetalVlan(), "", map[string]interface{}{
+		"vlan_id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+	}, map[string]string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": recordedMetalVlan,
+		"GET /metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de/events":  recordedMetalProjectEvents,
+	}, dataSourceMetalVlanRead, packngoDataSourceMetalVlanRead)
+
+	assert.Equal(t, "1001", state["vxlan"])
+	assert.Equal(t, "Bob", state["last_modified_by"])
+}
+
+func TestResourceMetalVlanDelete_unassignsPorts(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": `{
+  "id": "6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+  "vxlan": 1001,
+  "metro_code": "sv",
+  "instances": [{
+    "id": "1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f",
+    "href": "/metal/v1/devices/1c6d7e8f-2a3b-4c5d-8e9f-0a1b2c3d4e5f",
+    "network_ports": [
+      {"id": "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a", "name": "bond0", "virtual_networks": [
+        {"href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"},
+        {"href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b22"}
+      ]},
+      {"id": "0a9b8c7d-6e5f-4a3b-8c2d-1e0f9a8b7c6d", "name": "eth1", "virtual_networks": [
+        {"href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b22"}
+      ]}
+    ]
+  }],
+  "href": "/metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"
+}`,
+		"POST /metal/v1/ports/7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a/unassign":     `{"id": "7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a"}`,
+		"DELETE /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21": "",
+	})
+
+	d := resourceMetalVlan().TestResourceData()
+	d.SetId("6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21")
+	diags := resourceMetalVlanDelete(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, []string{
+		"GET /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+		"POST /metal/v1/ports/7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a/unassign",
+		"DELETE /metal/v1/virtual-networks/6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21",
+	}, fake.requestOrder(), "only the port the VLAN is attached to is unassigned")
+	body, _ := fake.request("POST /metal/v1/ports/7d6c4f0a-3e2b-4b1c-9a8d-5f6e7d8c9b0a/unassign")
+	assert.JSONEq(t, `{"vnid":"6a7e3c4b-1d2f-4e5a-9b8c-7d6e5f4a3b21"}`, body)
+}
@@ -2,16 +2,19 @@ package equinix
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func resourceMetalVRF() *schema.Resource {
@@ -50,7 +53,10 @@ func resourceMetalVRF() *schema.Resource {
 				Type:        schema.TypeSet,
 				Optional:    true,
 				Description: "All IPv4 and IPv6 Ranges that will be available to BGP Peers. IPv4 addresses must be /8 or smaller with a minimum size of /29. IPv6 must be /56 or smaller with a minimum size of /64. Ranges must not overlap other ranges within the VRF.",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: equinix_validation.StringIsCIDRWithPrefixLength(vrfIPv4RangeSizes, vrfIPv6RangeSizes),
+				},
 			},
 			"project_id": {
 				Type:        schema.TypeString,
@@ -59,70 +65,88 @@ func resourceMetalVRF() *schema.Resource {
 			},
 			// TODO: created_by, created_at, updated_at, href
 		},
+		CustomizeDiff: validateVRFIPRanges,
+	}
+}
+
+var (
+	vrfIPv4RangeSizes = equinix_validation.PrefixLengthRange{Min: 8, Max: 29}
+	vrfIPv6RangeSizes = equinix_validation.PrefixLengthRange{Min: 56, Max: 64}
+)
+
+// validateVRFIPRanges rejects ip_ranges that overlap each other. Sizes are
+// checked per element by the schema.
+func validateVRFIPRanges(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("ip_ranges") {
+		return nil
+	}
+	ipRanges := converters.SetToStringList(d.Get("ip_ranges").(*schema.Set))
+	if err := equinix_validation.CIDRsOverlap(ipRanges); err != nil {
+		return fmt.Errorf("invalid ip_ranges: %w", err)
 	}
+	return nil
 }
 
 func resourceMetalVRFCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	createRequest := &packngo.VRFCreateRequest{
-		Name:        d.Get("name").(string),
-		Description: d.Get("description").(string),
-		Metro:       d.Get("metro").(string),
-		LocalASN:    d.Get("local_asn").(int),
-		IPRanges:    converters.SetToStringList(d.Get("ip_ranges").(*schema.Set)),
+	createRequest := metalv1.VrfCreateInput{
+		Name:  d.Get("name").(string),
+		Metro: d.Get("metro").(string),
+	}
+	if ipRanges := converters.SetToStringList(d.Get("ip_ranges").(*schema.Set)); len(ipRanges) > 0 {
+		createRequest.IpRanges = ipRanges
+	}
+	if description, ok := d.GetOk("description"); ok {
+		createRequest.SetDescription(description.(string))
+	}
+	if localASN, ok := d.GetOk("local_asn"); ok {
+		createRequest.SetLocalAsn(int32(localASN.(int)))
 	}
 
 	projectId := d.Get("project_id").(string)
-	vrf, _, err := client.VRFs.Create(projectId, createRequest)
+	vrf, resp, err := client.VRFsApi.CreateVrf(ctx, projectId).VrfCreateInput(createRequest).Execute()
 	if err != nil {
-		return diag.FromErr(equinix_errors.FriendlyError(err))
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	d.SetId(vrf.ID)
+	d.SetId(vrf.GetId())
 
 	return resourceMetalVRFRead(ctx, d, meta)
 }
 
 func resourceMetalVRFUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
-
-	sPtr := func(s string) *string { return &s }
-	iPtr := func(i int) *int { return &i }
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	updateRequest := &packngo.VRFUpdateRequest{}
+	updateRequest := metalv1.VrfUpdateInput{}
 	if d.HasChange("name") {
-		updateRequest.Name = sPtr(d.Get("name").(string))
+		updateRequest.SetName(d.Get("name").(string))
 	}
 	if d.HasChange("description") {
-		updateRequest.Description = sPtr(d.Get("description").(string))
+		updateRequest.SetDescription(d.Get("description").(string))
 	}
 	if d.HasChange("local_asn") {
-		updateRequest.LocalASN = iPtr(d.Get("local_asn").(int))
+		updateRequest.SetLocalAsn(int32(d.Get("local_asn").(int)))
 	}
 	if d.HasChange("ip_ranges") {
-		ipRanges := converters.SetToStringList(d.Get("ip_ranges").(*schema.Set))
-		updateRequest.IPRanges = &ipRanges
+		// an empty, non-nil list is sent as [] and removes all the ranges
+		updateRequest.IpRanges = converters.SetToStringList(d.Get("ip_ranges").(*schema.Set))
 	}
 
-	_, _, err := client.VRFs.Update(d.Id(), updateRequest)
+	_, resp, err := client.VRFsApi.UpdateVrf(ctx, d.Id()).VrfUpdateInput(updateRequest).Execute()
 	if err != nil {
-		return diag.FromErr(equinix_errors.FriendlyError(err))
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	return resourceMetalVRFRead(ctx, d, meta)
 }
 
 func resourceMetalVRFRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	getOpts := &packngo.GetOptions{Includes: []string{"project", "metro"}}
-
-	vrf, _, err := client.VRFs.Get(d.Id(), getOpts)
+	vrf, resp, err := client.VRFsApi.FindVrfById(ctx, d.Id()).Include([]string{"project", "metro"}).Execute()
 	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
 			log.Printf("[WARN] VRF (%s) not accessible, removing from state", d.Id())
 			d.SetId("")
@@ -132,25 +156,28 @@ func resourceMetalVRFRead(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(err)
 	}
 	m := map[string]interface{}{
-		"name":        vrf.Name,
-		"description": vrf.Description,
-		"metro":       vrf.Metro.Code,
-		"local_asn":   vrf.LocalASN,
-		"ip_ranges":   vrf.IPRanges,
-		"project_id":  vrf.Project.ID,
+		"name":        vrf.GetName(),
+		"description": vrf.GetDescription(),
+		"metro":       vrf.Metro.GetCode(),
+		"local_asn":   int(vrf.GetLocalAsn()),
+		"ip_ranges":   vrf.GetIpRanges(),
+		"project_id":  vrf.Project.GetId(),
 	}
 
 	return diag.FromErr(equinix_schema.SetMap(d, m))
 }
 
 func resourceMetalVRFDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.VRFs.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) == nil {
+	resp, err := client.VRFsApi.DeleteVrf(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) == nil {
 		d.SetId("")
+		return nil
 	}
 
-	return diag.FromErr(equinix_errors.FriendlyError(err))
+	return diag.FromErr(err)
 }
@@ -1,6 +1,7 @@
 package equinix
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -9,10 +10,10 @@ import (
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func init() {
@@ -34,34 +35,34 @@ func testSweepVRFs(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping VRFs: %s", err)
 	}
-	metal := config.NewMetalClient()
-	ps, _, err := metal.Projects.List(nil)
+	metal := config.NewMetalGoClient()
+	ps, err := metal.ProjectsApi.FindProjects(context.Background()).ExecuteWithPagination()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting project list for sweeping VRFs: %s", err)
 	}
 	pids := []string{}
-	for _, p := range ps {
-		if isSweepableTestResource(p.Name) {
-			pids = append(pids, p.ID)
+	for _, p := range ps.Projects {
+		if isSweepableTestResource(p.GetName()) {
+			pids = append(pids, p.GetId())
 		}
 	}
 	dids := []string{}
 	for _, pid := range pids {
-		ds, _, err := metal.VRFs.List(pid, nil)
+		ds, _, err := metal.VRFsApi.FindVrfs(context.Background(), pid).Execute()
 		if err != nil {
 			log.Printf("Error listing VRFs to sweep: %s", err)
 			continue
 		}
-		for _, d := range ds {
-			if isSweepableTestResource(d.Name) {
-				dids = append(dids, d.ID)
+		for _, d := range ds.Vrfs {
+			if isSweepableTestResource(d.GetName()) {
+				dids = append(dids, d.GetId())
 			}
 		}
 	}
 
 	for _, did := range dids {
 		log.Printf("Removing VRFs %s", did)
-		_, err := metal.VRFs.Delete(did)
+		_, err := metal.VRFsApi.DeleteVrf(context.Background(), did).Execute()
 		if err != nil {
 			return fmt.Errorf("Error deleting VRFs %s", err)
 		}
@@ -70,7 +71,7 @@ func testSweepVRFs(region string) error {
 }
 
 func TestAccMetalVRF_basic(t *testing.T) {
-	var vrf packngo.VRF
+	var vrf metalv1.Vrf
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -99,7 +100,7 @@ func TestAccMetalVRF_basic(t *testing.T) {
 }
 
 func TestAccMetalVRF_withIPRanges(t *testing.T) {
-	var vrf packngo.VRF
+	var vrf metalv1.Vrf
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -142,7 +143,7 @@ func TestAccMetalVRF_withIPRanges(t *testing.T) {
 }
 
 func TestAccMetalVRF_withIPReservations(t *testing.T) {
-	var vrf packngo.VRF
+	var vrf metalv1.Vrf
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -184,7 +185,7 @@ func TestAccMetalVRF_withIPReservations(t *testing.T) {
 }
 
 func TestAccMetalVRF_withGateway(t *testing.T) {
-	var vrf packngo.VRF
+	var vrf metalv1.Vrf
 	rInt := acctest.RandInt()
 
 	resource.ParallelTest(t, resource.TestCase{
@@ -225,7 +226,7 @@ func TestAccMetalVRF_withGateway(t *testing.T) {
 }
 
 func TestAccMetalVRFConfig_withConnection(t *testing.T) {
-	var vrf packngo.VRF
+	var vrf metalv1.Vrf
 	rInt := acctest.RandInt()
 	nniVlan := acctest.RandIntRange(1024, 1093)
 
@@ -298,13 +299,13 @@ func TestAccMetalVRFConfig_withConnection(t *testing.T) {
 }
 
 func testAccMetalVRFCheckDestroyed(s *terraform.State) error {
-	client := testAccProvider.Meta().(*config.Config).Metal
+	client := testAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_vrf" {
 			continue
 		}
-		if _, _, err := client.VRFs.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.VRFsApi.FindVrfById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal VRF still exists")
 		}
 	}
@@ -312,7 +313,7 @@ func testAccMetalVRFCheckDestroyed(s *terraform.State) error {
 	return nil
 }
 
-func testAccMetalVRFExists(n string, vrf *packngo.VRF) resource.TestCheckFunc {
+func testAccMetalVRFExists(n string, vrf *metalv1.Vrf) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
 		if !ok {
@@ -322,13 +323,13 @@ func testAccMetalVRFExists(n string, vrf *packngo.VRF) resource.TestCheckFunc {
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*config.Config).Metal
+		client := testAccProvider.Meta().(*config.Config).Metalgo
 
-		foundResource, _, err := client.VRFs.Get(rs.Primary.ID, nil)
+		foundResource, _, err := client.VRFsApi.FindVrfById(context.Background(), rs.Primary.ID).Execute()
 		if err != nil {
 			return err
 		}
-		if foundResource.ID != rs.Primary.ID {
+		if foundResource.GetId() != rs.Primary.ID {
 			return fmt.Errorf("Record not found: %v - %v", rs.Primary.ID, foundResource)
 		}
 
@@ -0,0 +1,111 @@
+package equinix
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
+)
+
+const recordedMetalVRF = `{
+  "id": "3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10",
+  "name": "tf-vrf",
+  "description": "recorded VRF",
+  "local_asn": 65000,
+  "ip_ranges": ["192.168.100.0/25", "2001:db8:1234::/64"],
+  "project": {"id": "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de", "name": "tf-project", "href": "/metal/v1/projects/0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de"},
+  "metro": {"id": "108b2cfb-246b-45e3-885a-bf3e82fce1a0", "code": "da", "name": "Dallas", "country": "US", "href": "/metal/v1/locations/metros/108b2cfb-246b-45e3-885a-bf3e82fce1a0"},
+  "href": "/metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10",
+  "created_at": "2023-06-01T10:00:00Z",
+  "updated_at": "2023-06-01T10:00:00Z"
+}`
+
+// packngoResourceMetalVRFRead is the packngo implementation of
+// resourceMetalVRFRead, kept to compare the states both write
+func packngoResourceMetalVRFRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := packngoClient(meta)
+
+	getOpts := &packngo.GetOptions{Includes: []string{"project", "metro"}}
+
+	vrf, _, err := client.VRFs.Get(d.Id(), getOpts)
+	if err != nil {
+		err = packngoFriendlyError(err)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			log.Printf("[WARN] VRF (%s) not accessible, removing from state", d.Id())
+			d.SetId("")
+
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	m := map[string]interface{}{
+		"name":        vrf.Name,
+		"description": vrf.Description,
+		"metro":       vrf.Metro.Code,
+		"local_asn":   vrf.LocalASN,
+		"ip_ranges":   vrf.IPRanges,
+		"project_id":  vrf.Project.ID,
+	}
+
+	return diag.FromErr(equinix_schema.SetMap(d, m))
+}
+
+func TestResourceMetalVRFRead_recorded(t *testing.T) {
+	state := assertMetalReadParity(t, resourceMetalVRF(), "3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10", nil, map[string]string{
+		"GET /metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10": recordedMetalVRF,
+	}, resourceMetalVRFRead, packngoResourceMetalVRFRead)
+
+	assert.Equal(t, "tf-vrf", state["name"])
+	assert.Equal(t, "2", state["ip_ranges.#"])
+}
+
+func TestResourceMetalVRFRead_notFound(t *testing.T) {
+	_, meta := newMetalFakeAPI(t, map[string]string{})
+
+	d := resourceMetalVRF().TestResourceData()
+	d.SetId("3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10")
+	diags := resourceMetalVRFRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Empty(t, d.Id())
+}
+
+func TestResourceMetalVRFUpdate_removeIPRanges(t *testing.T) {
+	fake, meta := newMetalFakeAPI(t, map[string]string{
+		"PUT /metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10": recordedMetalVRF,
+		"GET /metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10": recordedMetalVRF,
+	})
+
+	r := resourceMetalVRF()
+	d := r.TestResourceData()
+	d.SetId("3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10")
+	diags := resourceMetalVRFRead(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	// plan the removal of ip_ranges from the configuration
+	state := d.State()
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "tf-vrf",
+		"description": "recorded VRF",
+		"metro":       "da",
+		"local_asn":   65000,
+		"project_id":  "0e2c1f10-5b2b-4dd4-8b6c-3bc0fa2cc0de",
+	}), meta)
+	assert.NoError(t, err)
+	d, err = schema.InternalMap(r.Schema).Data(state, diff)
+	assert.NoError(t, err)
+
+	diags = resourceMetalVRFUpdate(context.Background(), d, meta)
+	assert.False(t, diags.HasError(), "%v", diags)
+
+	body, ok := fake.request("PUT /metal/v1/vrfs/3bc0fa2c-5b2b-4dd4-8b6c-2b1b0e2c1f10")
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"ip_ranges":[]}`, body)
+}
@@ -0,0 +1,36 @@
+package acceptance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+)
+
+// NewFakeMetalAPI serves recorded Equinix Metal API responses, keyed by path,
+// and returns a provider configuration pointing at them. Paths without a
+// recorded response get a 404.
+func NewFakeMetalAPI(t *testing.T, responses map[string]string) *config.Config {
+	t.Helper()
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyErrorForMetalGo")
+		// metal-go requests have a double slash after the base path
+		response, ok := responses[strings.ReplaceAll(r.URL.Path, "//", "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			response = `{"errors":["Not found"]}`
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(mockAPI.Close)
+
+	meta := &config.Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock"}
+	if err := meta.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return meta
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
-	"github.com/packethost/packngo"
 	xoauth2 "golang.org/x/oauth2"
 )
 
@@ -35,6 +34,8 @@ const (
 	ClientTokenEnvVar    = "EQUINIX_API_TOKEN"
 	ClientTimeoutEnvVar  = "EQUINIX_API_TIMEOUT"
 	MetalAuthTokenEnvVar = "METAL_AUTH_TOKEN"
+
+	FabricCspProfileRulesFileEnvVar = "EQUINIX_FABRIC_CSP_PROFILE_RULES_FILE"
 )
 
 type ProviderMeta struct {
@@ -55,7 +56,6 @@ func (d *DumpTransport) RoundTrip(h *http.Request) (*http.Response, error) {
 }
 
 const (
-	consumerToken         = "aZ9GmqHTPtxevvFq9SK3Pi2yr9YCbRzduCSXF2SNem5sjB91mDq7Th3ZwTtRqMWZ"
 	metalBasePath         = "/metal/v1/"
 	uaEnvVar              = "TF_APPEND_USER_AGENT"
 	emptyCredentialsError = `the provider needs to be configured with the proper credentials before it
@@ -94,19 +94,29 @@ type Config struct {
 	PageSize       int
 	Token          string
 
-	Ecx     ecx.Client
-	Ne      ne.Client
-	Metal   *packngo.Client
+	Ecx ecx.Client
+	Ne  ne.Client
+	// Metalgo is the metal-go client of the requests which are not made for
+	// a resource, see NewMetalClientForSDK
 	Metalgo *metalv1.APIClient
 
 	ecxUserAgent     string
 	neUserAgent      string
-	metalUserAgent   string
 	metalGoUserAgent string
+	fabricUserAgent  string
+	// metalHTTPClient is the retrying HTTP client shared by the metal-go
+	// clients
+	metalHTTPClient *http.Client
 
 	TerraformVersion string
 	FabricClient     *v4.APIClient
 	FabricAuthToken  string
+	// FabricHTTPClient is the HTTP client of FabricClient, for the Fabric
+	// endpoints which are not in fabric-go yet
+	FabricHTTPClient *http.Client
+	// FabricCspProfileRulesFile is a JSON file of cloud service provider
+	// profile rules, added to the ones built into the provider
+	FabricCspProfileRulesFile string
 }
 
 // Load function validates configuration structure fields and configures
@@ -173,7 +183,6 @@ func (c *Config) Load(ctx context.Context) error {
 
 	c.Ecx = ecxClient
 	c.Ne = neClient
-	c.Metal = c.NewMetalClient()
 	c.Metalgo = c.NewMetalGoClient()
 	c.FabricClient = c.NewFabricClient()
 	return nil
@@ -192,33 +201,15 @@ func (c *Config) NewFabricClient() *v4.APIClient {
 		"X-SOURCE":         "API",
 		"X-CORRELATION-ID": correlationId(25),
 	}
+	c.fabricUserAgent = c.fullUserAgent("equinix/fabric-go")
 	v4Configuration := v4.Configuration{
 		BasePath:      c.BaseURL,
 		DefaultHeader: fabricHeaderMap,
-		UserAgent:     "equinix/fabric-go",
+		UserAgent:     c.fabricUserAgent,
 		HTTPClient:    authClient,
 	}
 	client := v4.NewAPIClient(&v4Configuration)
-	return client
-}
-
-// NewMetalClient returns a new packngo client for accessing Equinix Metal's API.
-func (c *Config) NewMetalClient() *packngo.Client {
-	transport := http.DefaultTransport
-	// transport = &DumpTransport{http.DefaultTransport} // Debug only
-	transport = logging.NewTransport("Equinix Metal (packngo)", transport)
-	retryClient := retryablehttp.NewClient()
-	retryClient.HTTPClient.Transport = transport
-	retryClient.RetryMax = c.MaxRetries
-	retryClient.RetryWaitMin = time.Second
-	retryClient.RetryWaitMax = c.MaxRetryWait
-	retryClient.CheckRetry = MetalRetryPolicy
-	standardClient := retryClient.StandardClient()
-	baseURL, _ := url.Parse(c.BaseURL)
-	baseURL.Path = path.Join(baseURL.Path, metalBasePath) + "/"
-	client, _ := packngo.NewClientWithBaseURL(consumerToken, c.AuthToken, standardClient, baseURL.String())
-	client.UserAgent = c.fullUserAgent(client.UserAgent)
-	c.metalUserAgent = client.UserAgent
+	c.FabricHTTPClient = authClient
 	return client
 }
 
@@ -232,8 +223,20 @@ func (c *Config) NewMetalGoClient() *metalv1.APIClient {
 	retryClient.RetryWaitMin = time.Second
 	retryClient.RetryWaitMax = c.MaxRetryWait
 	retryClient.CheckRetry = MetalRetryPolicy
-	standardClient := retryClient.StandardClient()
+	c.metalHTTPClient = retryClient.StandardClient()
+	c.metalGoUserAgent = c.fullUserAgent(metalv1.NewConfiguration().UserAgent)
+	return c.newMetalGoClient(c.metalGoUserAgent)
+}
 
+// NewMetalClientForSDK returns a metal-go client for the requests of a
+// resource or data source, with the module of the resource in its User-Agent.
+// The clients share the HTTP client of Metalgo, but not their configuration,
+// so that resources of different modules do not swap their User-Agents
+func (c *Config) NewMetalClientForSDK(d *schema.ResourceData) *metalv1.APIClient {
+	return c.newMetalGoClient(generateModuleUserAgentString(d, c.metalGoUserAgent))
+}
+
+func (c *Config) newMetalGoClient(userAgent string) *metalv1.APIClient {
 	baseURL, _ := url.Parse(c.BaseURL)
 	baseURL.Path = path.Join(baseURL.Path, metalBasePath) + "/"
 
@@ -243,12 +246,10 @@ func (c *Config) NewMetalGoClient() *metalv1.APIClient {
 			URL: baseURL.String(),
 		},
 	}
-	configuration.HTTPClient = standardClient
+	configuration.HTTPClient = c.metalHTTPClient
 	configuration.AddDefaultHeader("X-Auth-Token", c.AuthToken)
-	configuration.UserAgent = c.fullUserAgent(configuration.UserAgent)
-	client := metalv1.NewAPIClient(configuration)
-	c.metalGoUserAgent = client.GetConfig().UserAgent
-	return client
+	configuration.UserAgent = userAgent
+	return metalv1.NewAPIClient(configuration)
 }
 
 func (c *Config) requestTimeout() time.Duration {
@@ -310,17 +311,10 @@ func (c *Config) AddModuleToNEUserAgent(client *ne.Client, d *schema.ResourceDat
 	*client = rc
 }
 
-// TODO (ocobleseqx) - known issue, Metal services are initialized using the metal client pointer
-// if two or more modules in same project interact with metal resources they will override
-// the UserAgent resulting in swapped UserAgent.
-// This can be fixed by letting the headers be overwritten on the initialized Packngo ServiceOp
-// clients on a query-by-query basis.
-func (c *Config) AddModuleToMetalUserAgent(d *schema.ResourceData) {
-	c.Metal.UserAgent = generateModuleUserAgentString(d, c.metalUserAgent)
-}
-
-func (c *Config) AddModuleToMetalGoUserAgent(d *schema.ResourceData) {
-	c.Metalgo.GetConfig().UserAgent = generateModuleUserAgentString(d, c.metalGoUserAgent)
+// FabricUserAgent returns the User-Agent of the Fabric API requests, for the
+// requests which are not sent with FabricClient
+func (c *Config) FabricUserAgent() string {
+	return c.fabricUserAgent
 }
 
 func generateModuleUserAgentString(d *schema.ResourceData, baseUserAgent string) string {
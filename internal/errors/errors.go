@@ -6,28 +6,32 @@ import (
 	"strings"
 
 	fabric "github.com/equinix-labs/fabric-go/fabric/v4"
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/rest-go"
-	"github.com/packethost/packngo"
 )
 
-// FriendlyError improves error messages when the API error is blank or in an
-// alternate format (as is the case with invalid token or loadbalancer errors)
-func FriendlyError(err error) error {
-	if e, ok := err.(*packngo.ErrorResponse); ok {
-		resp := e.Response
-		errors := Errors(e.Errors)
-
-		if 0 == len(errors) {
-			errors = Errors{e.SingleError}
-		}
-
-		return convertToFriendlyError(errors, resp)
-	}
-	return err
-}
-
+// FriendlyErrorForMetalGo improves error messages when the API error is blank
+// or in an alternate format (as is the case with invalid token or
+// loadbalancer errors)
 func FriendlyErrorForMetalGo(err error, resp *http.Response) error {
+	if resp == nil {
+		// the request failed before a response was received
+		return err
+	}
 	errors := Errors([]string{err.Error()})
+	// metal-go joins the messages of the API error into one, keep them apart
+	// so that each can be matched
+	if apiErr, ok := err.(*metalv1.GenericOpenAPIError); ok {
+		if model, ok := apiErr.Model().(metalv1.Error); ok {
+			messages := model.GetErrors()
+			if model.GetError() != "" {
+				messages = append(messages, model.GetError())
+			}
+			if len(messages) > 0 {
+				errors = messages
+			}
+		}
+	}
 	return convertToFriendlyError(errors, resp)
 }
 
@@ -87,10 +91,6 @@ func FormatFabricError(err error) error {
 }
 
 func IsForbidden(err error) bool {
-	r, ok := err.(*packngo.ErrorResponse)
-	if ok && r.Response != nil {
-		return r.Response.StatusCode == http.StatusForbidden
-	}
 	if r, ok := err.(*ErrorResponse); ok {
 		return r.StatusCode == http.StatusForbidden
 	}
@@ -101,9 +101,6 @@ func IsNotFound(err error) bool {
 	if r, ok := err.(*ErrorResponse); ok {
 		return r.StatusCode == http.StatusNotFound && r.IsAPIError
 	}
-	if r, ok := err.(*packngo.ErrorResponse); ok && r.Response != nil {
-		return r.Response.StatusCode == http.StatusNotFound
-	}
 	return false
 }
 
@@ -136,8 +133,8 @@ func IsNotAssigned(resp *http.Response, err error) bool {
 	if resp.StatusCode != http.StatusUnprocessableEntity {
 		return false
 	}
-	if err, ok := err.(*packngo.ErrorResponse); ok {
-		for _, e := range append(err.Errors, err.SingleError) {
+	if err, ok := err.(*ErrorResponse); ok {
+		for _, e := range err.Errors {
 			if strings.HasPrefix(e, "Virtual network") && strings.HasSuffix(e, "not assigned") {
 				return true
 			}
@@ -151,12 +148,7 @@ func HttpForbidden(resp *http.Response, err error) bool {
 		return false
 	}
 
-	switch err := err.(type) {
-	case *ErrorResponse, *packngo.ErrorResponse:
-		return IsForbidden(err)
-	}
-
-	return false
+	return IsForbidden(err)
 }
 
 func HttpNotFound(resp *http.Response, err error) bool {
@@ -164,34 +156,7 @@ func HttpNotFound(resp *http.Response, err error) bool {
 		return false
 	}
 
-	switch err := err.(type) {
-	case *ErrorResponse, *packngo.ErrorResponse:
-		return IsNotFound(err)
-	}
-	return false
-}
-
-// ignoreResponseErrors ignores http response errors when matched by one of the
-// provided checks
-func IgnoreResponseErrors(ignore ...func(resp *http.Response, err error) bool) func(resp *packngo.Response, err error) error {
-	return func(resp *packngo.Response, err error) error {
-		var r *http.Response
-		if resp != nil && resp.Response != nil {
-			r = resp.Response
-		}
-		mute := false
-		for _, ignored := range ignore {
-			if ignored(r, err) {
-				mute = true
-				break
-			}
-		}
-
-		if mute {
-			return nil
-		}
-		return err
-	}
+	return IsNotFound(err)
 }
 
 func IsRestNotFoundError(err error) bool {
@@ -47,3 +47,32 @@ func TestProvider_IsRestNotFoundError(t *testing.T) {
 	// then
 	assert.Equal(t, expected, result, "Result matches expected output")
 }
+
+func TestProvider_FriendlyErrorForMetalGo(t *testing.T) {
+	// given
+	err := fmt.Errorf("404 Not Found")
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"X-Request-Id": []string{"request-id"},
+		},
+	}
+	// when
+	result := FriendlyErrorForMetalGo(err, resp)
+	noResponseResult := FriendlyErrorForMetalGo(err, nil)
+	// then
+	assert.True(t, IsNotFound(result), "API 404 errors are not found errors")
+	assert.Equal(t, err, noResponseResult, "Errors without a response are returned as they are")
+}
+
+func TestProvider_IsNotAssigned(t *testing.T) {
+	// given
+	resp := &http.Response{StatusCode: http.StatusUnprocessableEntity}
+	notAssigned := &ErrorResponse{StatusCode: http.StatusUnprocessableEntity, Errors: Errors{"Virtual network 1234 not assigned"}}
+	other := &ErrorResponse{StatusCode: http.StatusUnprocessableEntity, Errors: Errors{"Port is locked"}}
+	// when
+	result := []bool{IsNotAssigned(resp, notAssigned), IsNotAssigned(resp, other)}
+	// then
+	assert.Equal(t, []bool{true, false}, result, "Only unassigned virtual network errors match")
+}
@@ -1,17 +1,19 @@
 package metal_project_ssh_key
 
 import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
-	"fmt"
-	"path"
-	"strings"
-
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/metal_ssh_key"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func DataSource() *schema.Resource {
@@ -23,67 +25,65 @@ func DataSource() *schema.Resource {
 		Required:    true,
 	}
 	dataSource := &schema.Resource{
-		Read:   dataSourceRead,
-		Schema: dsSchema,
+		ReadContext: dataSourceRead,
+		Schema:      dsSchema,
 	}
 	return dataSource
 }
 
-func dataSourceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	search := d.Get("search").(string)
 	id := d.Get("id").(string)
 	projectID := d.Get("project_id").(string)
 
 	if id == "" && search == "" {
-		return fmt.Errorf("You must supply either search or id")
+		return diag.Errorf("You must supply either search or id")
 	}
 
-	var (
-		key        packngo.SSHKey
-		searchOpts *packngo.SearchOptions
-	)
+	var key metalv1.SSHKey
 
+	request := client.SSHKeysApi.FindProjectSSHKeys(ctx, projectID)
 	if search != "" {
-		searchOpts = &packngo.SearchOptions{Search: search}
+		request = request.Query(search)
 	}
-	keys, _, err := client.Projects.ListSSHKeys(projectID, searchOpts)
+	keys, resp, err := request.Execute()
 	if err != nil {
-		err = fmt.Errorf("Error listing project ssh keys: %s", equinix_errors.FriendlyError(err))
-		return err
+		return diag.Errorf("Error listing project ssh keys: %s", equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	for i := range keys {
+	for i := range keys.SshKeys {
 		// use the first match for searches
 		if search != "" {
-			key = keys[i]
+			key = keys.SshKeys[i]
 			break
 		}
 
 		// otherwise find the matching ID
-		if keys[i].ID == id {
-			key = keys[i]
+		if keys.SshKeys[i].GetId() == id {
+			key = keys.SshKeys[i]
 			break
 		}
 	}
 
-	if key.ID == "" {
+	if key.GetId() == "" {
 		// Not Found
-		return fmt.Errorf("Project %q SSH Key matching %q was not found", projectID, search)
+		return diag.Errorf("Project %q SSH Key matching %q was not found", projectID, search)
 	}
 
-	ownerID := path.Base(key.Owner.Href)
+	ownerHref := metal_ssh_key.OwnerHref(&key)
+	ownerID := path.Base(ownerHref)
 
-	d.SetId(key.ID)
-	d.Set("name", key.Label)
-	d.Set("public_key", key.Key)
-	d.Set("fingerprint", key.FingerPrint)
+	d.SetId(key.GetId())
+	d.Set("name", key.GetLabel())
+	d.Set("public_key", key.GetKey())
+	d.Set("fingerprint", key.GetFingerprint())
 	d.Set("owner_id", ownerID)
-	d.Set("created", key.Created)
-	d.Set("updated", key.Updated)
+	d.Set("created", key.GetCreatedAt().Format(time.RFC3339))
+	d.Set("updated", key.GetUpdatedAt().Format(time.RFC3339))
 
-	if strings.Contains(key.Owner.Href, "/projects/") {
+	if strings.Contains(ownerHref, "/projects/") {
 		d.Set("project_id", ownerID)
 	}
 
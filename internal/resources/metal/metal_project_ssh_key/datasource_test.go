@@ -1,14 +1,22 @@
 package metal_project_ssh_key_test
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/metal_project_ssh_key"
 )
 
 func TestAccDataSourceMetalProjectSSHKey_bySearch(t *testing.T) {
@@ -145,3 +153,100 @@ resource "equinix_metal_project_ssh_key" "foobar" {
 
 	return config
 }
+
+// recordedMetalProjectSSHKeys are Equinix Metal API responses listing the
+// keys of a project
+var recordedMetalProjectSSHKeys = map[string]string{
+	"/metal/v1/projects/d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19/ssh-keys": `{"ssh_keys":[` +
+		`{"id":"9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24","label":"tfacc-project-key","key":"ssh-rsa AAAAB3Nza project","fingerprint":"b2:07:c4","created_at":"2023-11-02T10:15:30Z","updated_at":"2023-11-02T10:15:30Z","owner":{"href":"/metal/v1/projects/d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19"},"href":"/metal/v1/ssh-keys/9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24"},` +
+		`{"id":"1c6e8a2f-4b7d-4f0e-b3a9-5d2c7e1f8b46","label":"tfacc-other-key","key":"ssh-rsa AAAAB3Nza other","fingerprint":"e1:5d:90","created_at":"2023-11-04T12:00:00Z","updated_at":"2023-11-05T09:30:00Z","owner":{"href":"/metal/v1/projects/d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19"},"href":"/metal/v1/ssh-keys/1c6e8a2f-4b7d-4f0e-b3a9-5d2c7e1f8b46"}` +
+		`]}`,
+}
+
+// packngoDataSourceRead is the packngo implementation of the data source
+// read, kept to compare the states both write
+func packngoDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.Config)
+	client, err := packngo.NewClientWithBaseURL("packngo-test", conf.AuthToken, nil, conf.BaseURL+"/metal/v1/")
+	if err != nil {
+		return err
+	}
+
+	search := d.Get("search").(string)
+	id := d.Get("id").(string)
+	projectID := d.Get("project_id").(string)
+
+	var searchOpts *packngo.SearchOptions
+	if search != "" {
+		searchOpts = &packngo.SearchOptions{Search: search}
+	}
+	keys, _, err := client.Projects.ListSSHKeys(projectID, searchOpts)
+	if err != nil {
+		return err
+	}
+
+	var key packngo.SSHKey
+	for i := range keys {
+		if search != "" || keys[i].ID == id {
+			key = keys[i]
+			break
+		}
+	}
+
+	if key.ID == "" {
+		return fmt.Errorf("Project %q SSH Key matching %q was not found", projectID, search)
+	}
+
+	ownerID := path.Base(key.Owner.Href)
+
+	d.SetId(key.ID)
+	d.Set("name", key.Label)
+	d.Set("public_key", key.Key)
+	d.Set("fingerprint", key.FingerPrint)
+	d.Set("owner_id", ownerID)
+	d.Set("created", key.Created)
+	d.Set("updated", key.Updated)
+
+	if strings.Contains(key.Owner.Href, "/projects/") {
+		d.Set("project_id", ownerID)
+	}
+
+	return nil
+}
+
+func TestDataSourceMetalProjectSSHKey_readParity(t *testing.T) {
+	meta := acceptance.NewFakeMetalAPI(t, recordedMetalProjectSSHKeys)
+	r := metal_project_ssh_key.DataSource()
+
+	for name, raw := range map[string]map[string]interface{}{
+		"by id":     {"id": "1c6e8a2f-4b7d-4f0e-b3a9-5d2c7e1f8b46"},
+		"by search": {"search": "tfacc-project-key"},
+	} {
+		raw["project_id"] = "d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19"
+
+		d := schema.TestResourceDataRaw(t, r.Schema, raw)
+		if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("%s: unexpected read error: %v", name, diags)
+		}
+		packngoD := schema.TestResourceDataRaw(t, r.Schema, raw)
+		if err := packngoDataSourceRead(packngoD, meta); err != nil {
+			t.Fatalf("%s: unexpected packngo read error: %v", name, err)
+		}
+		assert.Equal(t, packngoD.State().Attributes, d.State().Attributes, "%s: the metal-go state differs from the packngo state", name)
+	}
+}
+
+func TestDataSourceMetalProjectSSHKey_readNotFound(t *testing.T) {
+	meta := acceptance.NewFakeMetalAPI(t, recordedMetalProjectSSHKeys)
+	r := metal_project_ssh_key.DataSource()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"id":         "5b8e2f1a-0c3d-4e7f-a9b2-6d1c4e8f0a53",
+		"project_id": "d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19",
+	})
+	diags := r.ReadContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected an error for a missing key")
+	}
+	assert.Contains(t, diags[0].Summary, "was not found")
+}
@@ -1,16 +1,17 @@
 package metal_project_ssh_key_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/packethost/packngo"
 )
 
 func testAccMetalProjectSSHKeyConfig_basic(name, publicSshKey string) string {
@@ -49,7 +50,7 @@ resource "equinix_metal_device" "test" {
 
 func TestAccMetalProjectSSHKey_basic(t *testing.T) {
 	rs := acctest.RandString(10)
-	var key packngo.SSHKey
+	var key metalv1.SSHKey
 	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
 	if err != nil {
 		t.Fatalf("Cannot generate test SSH key pair: %s", err)
@@ -83,13 +84,13 @@ func TestAccMetalProjectSSHKey_basic(t *testing.T) {
 }
 
 func testAccMetalProjectSSHKeyCheckDestroyed(s *terraform.State) error {
-	client := acceptance.TestAccProvider.Meta().(*config.Config).Metal
+	client := acceptance.TestAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_project_ssh_key" {
 			continue
 		}
-		if _, _, err := client.SSHKeys.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.SSHKeysApi.FindSSHKeyById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal SSH key still exists")
 		}
 	}
@@ -1,63 +1,79 @@
 package metal_ssh_key
 
 import (
+	"context"
 	"log"
+	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func Resource() *schema.Resource {
 	return &schema.Resource{
-		Create: create,
-		Read:   read,
-		Update: update,
-		Delete: delete,
+		CreateContext: create,
+		ReadContext:   read,
+		UpdateContext: update,
+		DeleteContext: delete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 
 		Schema: CommonFieldsResource(),
 	}
 }
 
-func create(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+// OwnerHref returns the link to the user or the project owning the key.
+// metal-go does not model the owner, it is only kept in the additional
+// properties of the key.
+func OwnerHref(key *metalv1.SSHKey) string {
+	owner, _ := key.AdditionalProperties["owner"].(map[string]interface{})
+	href, _ := owner["href"].(string)
+	return href
+}
+
+func create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	createRequest := &packngo.SSHKeyCreateRequest{
-		Label: d.Get("name").(string),
-		Key:   d.Get("public_key").(string),
+	createRequest := metalv1.SSHKeyCreateInput{
+		Label: metalv1.PtrString(d.Get("name").(string)),
+		Key:   metalv1.PtrString(d.Get("public_key").(string)),
 	}
 
+	var (
+		key  *metalv1.SSHKey
+		resp *http.Response
+		err  error
+	)
 	projectID, isProjectKey := d.GetOk("project_id")
 	if isProjectKey {
-		createRequest.ProjectID = projectID.(string)
+		key, resp, err = client.SSHKeysApi.CreateProjectSSHKey(ctx, projectID.(string)).SSHKeyCreateInput(createRequest).Execute()
+	} else {
+		key, resp, err = client.SSHKeysApi.CreateSSHKey(ctx).SSHKeyCreateInput(createRequest).Execute()
 	}
-
-	key, _, err := client.SSHKeys.Create(createRequest)
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	d.SetId(key.ID)
+	d.SetId(key.GetId())
 
-	return read(d, meta)
+	return read(ctx, d, meta)
 }
 
-func read(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	key, _, err := client.SSHKeys.Get(d.Id(), nil)
+	key, resp, err := client.SSHKeysApi.FindSSHKeyById(ctx, d.Id()).Execute()
 	if err != nil {
-		err = equinix_errors.FriendlyError(err)
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 
 		// If the key is somehow already destroyed, mark as
 		// succesfully gone
@@ -67,31 +83,31 @@ func read(d *schema.ResourceData, meta interface{}) error {
 			return nil
 		}
 
-		return err
+		return diag.FromErr(err)
 	}
 
-	ownerID := path.Base(key.Owner.Href)
+	ownerHref := OwnerHref(key)
+	ownerID := path.Base(ownerHref)
 
-	d.SetId(key.ID)
-	d.Set("name", key.Label)
-	d.Set("public_key", key.Key)
-	d.Set("fingerprint", key.FingerPrint)
+	d.SetId(key.GetId())
+	d.Set("name", key.GetLabel())
+	d.Set("public_key", key.GetKey())
+	d.Set("fingerprint", key.GetFingerprint())
 	d.Set("owner_id", ownerID)
-	d.Set("created", key.Created)
-	d.Set("updated", key.Updated)
+	d.Set("created", key.GetCreatedAt().Format(time.RFC3339))
+	d.Set("updated", key.GetUpdatedAt().Format(time.RFC3339))
 
-	if strings.Contains(key.Owner.Href, "/projects/") {
+	if strings.Contains(ownerHref, "/projects/") {
 		d.Set("project_id", ownerID)
 	}
 
 	return nil
 }
 
-func update(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	updateRequest := &packngo.SSHKeyUpdateRequest{}
+	updateRequest := metalv1.SSHKeyInput{}
 
 	if d.HasChange("name") {
 		kName := d.Get("name").(string)
@@ -103,21 +119,23 @@ func update(d *schema.ResourceData, meta interface{}) error {
 		updateRequest.Key = &kKey
 	}
 
-	_, _, err := client.SSHKeys.Update(d.Id(), updateRequest)
+	_, resp, err := client.SSHKeysApi.UpdateSSHKey(ctx, d.Id()).SSHKeyInput(updateRequest).Execute()
 	if err != nil {
-		return equinix_errors.FriendlyError(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	return read(d, meta)
+	return read(ctx, d, meta)
 }
 
-func delete(d *schema.ResourceData, meta interface{}) error {
-	meta.(*config.Config).AddModuleToMetalUserAgent(d)
-	client := meta.(*config.Config).Metal
+func delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
-	resp, err := client.SSHKeys.Delete(d.Id())
-	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
-		return equinix_errors.FriendlyError(err)
+	resp, err := client.SSHKeysApi.DeleteSSHKey(ctx, d.Id()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	d.SetId("")
@@ -1,18 +1,26 @@
 package metal_ssh_key_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"path"
+	"strings"
 	"testing"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/metal_project_ssh_key"
+	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/metal_ssh_key"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -28,21 +36,21 @@ func testSweepSSHKeys(region string) error {
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting configuration for sweeping ssh keys: %s", err)
 	}
-	metal := config.NewMetalClient()
-	sshkeys, _, err := metal.SSHKeys.List()
+	metal := config.NewMetalGoClient()
+	sshkeys, _, err := metal.SSHKeysApi.FindSSHKeys(context.Background()).Execute()
 	if err != nil {
 		return fmt.Errorf("[INFO][SWEEPER_LOG] Error getting list for sweeping ssh keys: %s", err)
 	}
 	ids := []string{}
-	for _, k := range sshkeys {
-		if acceptance.IsSweepableTestResource(k.Label) {
-			ids = append(ids, k.ID)
+	for _, k := range sshkeys.SshKeys {
+		if acceptance.IsSweepableTestResource(k.GetLabel()) {
+			ids = append(ids, k.GetId())
 		}
 	}
 	for _, id := range ids {
 		log.Printf("Removing ssh key %s", id)
-		resp, err := metal.SSHKeys.Delete(id)
-		if err != nil && resp.StatusCode != http.StatusNotFound {
+		resp, err := metal.SSHKeysApi.DeleteSSHKey(context.Background(), id).Execute()
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
 			return fmt.Errorf("Error deleting ssh key %s", err)
 		}
 	}
@@ -50,7 +58,7 @@ func testSweepSSHKeys(region string) error {
 }
 
 func TestAccMetalSSHKey_basic(t *testing.T) {
-	var key packngo.SSHKey
+	var key metalv1.SSHKey
 	rInt := acctest.RandInt()
 	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
 	if err != nil {
@@ -104,7 +112,7 @@ func TestAccMetalSSHKey_projectBasic(t *testing.T) {
 }
 
 func TestAccMetalSSHKey_update(t *testing.T) {
-	var key packngo.SSHKey
+	var key metalv1.SSHKey
 	rInt := acctest.RandInt()
 	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
 	if err != nil {
@@ -186,13 +194,13 @@ func TestAccMetalSSHKey_importBasic(t *testing.T) {
 }
 
 func testAccMetalSSHKeyCheckDestroyed(s *terraform.State) error {
-	client := acceptance.TestAccProvider.Meta().(*config.Config).Metal
+	client := acceptance.TestAccProvider.Meta().(*config.Config).Metalgo
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "equinix_metal_ssh_key" {
 			continue
 		}
-		if _, _, err := client.SSHKeys.Get(rs.Primary.ID, nil); err == nil {
+		if _, _, err := client.SSHKeysApi.FindSSHKeyById(context.Background(), rs.Primary.ID).Execute(); err == nil {
 			return fmt.Errorf("Metal SSH key still exists")
 		}
 	}
@@ -221,3 +229,88 @@ resource "equinix_metal_project_ssh_key" "foobar" {
 	project_id = equinix_metal_project.test.id
 }`, rInt, rInt, publicSshKey)
 }
+
+// recordedMetalSSHKeys are Equinix Metal API responses for a user key and a
+// project key
+var recordedMetalSSHKeys = map[string]string{
+	"/metal/v1/ssh-keys/7f1e4d2c-5a8b-4e3f-9c21-0b6d8e4a1f37": `{"id":"7f1e4d2c-5a8b-4e3f-9c21-0b6d8e4a1f37","label":"tfacc-user-key","key":"ssh-rsa AAAAB3Nza user","fingerprint":"3f:8a:11","created_at":"2023-11-02T10:15:30Z","updated_at":"2023-11-03T08:00:00Z","owner":{"href":"/metal/v1/users/4c2b9e1a-7d3f-4b8e-a2c5-9e0f1d6b3a78"},"href":"/metal/v1/ssh-keys/7f1e4d2c-5a8b-4e3f-9c21-0b6d8e4a1f37"}`,
+	"/metal/v1/ssh-keys/9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24": `{"id":"9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24","label":"tfacc-project-key","key":"ssh-rsa AAAAB3Nza project","fingerprint":"b2:07:c4","created_at":"2023-11-02T10:15:30Z","updated_at":"2023-11-02T10:15:30Z","owner":{"href":"/metal/v1/projects/d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19"},"href":"/metal/v1/ssh-keys/9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24"}`,
+}
+
+// packngoReadSSHKey is the packngo implementation of the SSH key read, kept
+// to compare the states both write
+func packngoReadSSHKey(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.Config)
+	client, err := packngo.NewClientWithBaseURL("packngo-test", conf.AuthToken, nil, conf.BaseURL+"/metal/v1/")
+	if err != nil {
+		return err
+	}
+
+	key, _, err := client.SSHKeys.Get(d.Id(), nil)
+	if err != nil {
+		return err
+	}
+
+	ownerID := path.Base(key.Owner.Href)
+
+	d.SetId(key.ID)
+	d.Set("name", key.Label)
+	d.Set("public_key", key.Key)
+	d.Set("fingerprint", key.FingerPrint)
+	d.Set("owner_id", ownerID)
+	d.Set("created", key.Created)
+	d.Set("updated", key.Updated)
+
+	if strings.Contains(key.Owner.Href, "/projects/") {
+		d.Set("project_id", ownerID)
+	}
+
+	return nil
+}
+
+func TestMetalSSHKey_readParity(t *testing.T) {
+	meta := acceptance.NewFakeMetalAPI(t, recordedMetalSSHKeys)
+
+	for id, r := range map[string]*schema.Resource{
+		"7f1e4d2c-5a8b-4e3f-9c21-0b6d8e4a1f37": metal_ssh_key.Resource(),
+		"9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24": metal_project_ssh_key.Resource(),
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		d.SetId(id)
+		if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("unexpected read error: %v", diags)
+		}
+		packngoD := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		packngoD.SetId(id)
+		if err := packngoReadSSHKey(packngoD, meta); err != nil {
+			t.Fatalf("unexpected packngo read error: %v", err)
+		}
+		assert.Equal(t, packngoD.State().Attributes, d.State().Attributes, "the metal-go state differs from the packngo state")
+		assert.Equal(t, "2023-11-02T10:15:30Z", d.Get("created"))
+	}
+}
+
+func TestMetalProjectSSHKey_read(t *testing.T) {
+	meta := acceptance.NewFakeMetalAPI(t, recordedMetalSSHKeys)
+	r := metal_project_ssh_key.Resource()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("9a0c3b5e-2d4f-4a6b-8c1e-3f5a7b9d0e24")
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Equal(t, "d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19", d.Get("project_id"))
+	assert.Equal(t, "d2a7c4f0-8b1e-4c3a-9f5d-2e6a0b7c4d19", d.Get("owner_id"))
+}
+
+func TestMetalSSHKey_readRemoved(t *testing.T) {
+	meta := acceptance.NewFakeMetalAPI(t, recordedMetalSSHKeys)
+	r := metal_ssh_key.Resource()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("5b8e2f1a-0c3d-4e7f-a9b2-6d1c4e8f0a53")
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected read error: %v", diags)
+	}
+	assert.Empty(t, d.Id(), "a deleted key is removed from the state")
+}
@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// PrefixLengthRange is the inclusive range of prefix lengths accepted for one
+// address family. A zero value range rejects every prefix of that family.
+type PrefixLengthRange struct {
+	Min int
+	Max int
+}
+
+func (r PrefixLengthRange) contains(bits int) bool {
+	return r.Min <= bits && bits <= r.Max
+}
+
+// ParseNetworkCIDR parses a CIDR and verifies that it is written as a network
+// address, i.e. all host bits are zero.
+func ParseNetworkCIDR(cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%q is not a valid CIDR: %w", cidr, err)
+	}
+	if masked := prefix.Masked(); masked != prefix {
+		return netip.Prefix{}, fmt.Errorf("%q is not a network address, did you mean %q?", cidr, masked.String())
+	}
+	return prefix, nil
+}
+
+// StringIsCIDRWithPrefixLength returns a SchemaValidateFunc which checks that
+// the value is a network CIDR whose prefix length is within ipv4 or ipv6,
+// depending on its address family.
+func StringIsCIDRWithPrefixLength(ipv4, ipv6 PrefixLengthRange) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		prefix, err := ParseNetworkCIDR(v)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", k, err))
+			return
+		}
+
+		if err := CheckPrefixLength(prefix, ipv4, ipv6); err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", k, err))
+		}
+		return
+	}
+}
+
+// CheckPrefixLength returns an error when the prefix length of prefix is not
+// within the range allowed for its address family.
+func CheckPrefixLength(prefix netip.Prefix, ipv4, ipv6 PrefixLengthRange) error {
+	allowed, family := ipv4, "IPv4"
+	if prefix.Addr().Is6() {
+		allowed, family = ipv6, "IPv6"
+	}
+	if allowed == (PrefixLengthRange{}) {
+		return fmt.Errorf("%s ranges are not supported, got %s", family, prefix)
+	}
+	if !allowed.contains(prefix.Bits()) {
+		return fmt.Errorf("%s ranges must be between /%d and /%d, got %s", family, allowed.Min, allowed.Max, prefix)
+	}
+	return nil
+}
+
+// CIDRsOverlap returns an error naming the first pair of overlapping CIDRs in
+// cidrs. Unparsable CIDRs are reported as errors as well.
+func CIDRsOverlap(cidrs []string) error {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, c := range cidrs {
+		prefix, err := netip.ParsePrefix(c)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid CIDR: %w", c, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	for i := range prefixes {
+		for j := i + 1; j < len(prefixes); j++ {
+			if prefixes[i].Overlaps(prefixes[j]) {
+				return fmt.Errorf("ranges %s and %s overlap", cidrs[i], cidrs[j])
+			}
+		}
+	}
+	return nil
+}
+
+// CIDRContainsIP returns an error when ip is not a valid address inside cidr,
+// or when the two are of different address families.
+func CIDRContainsIP(cidr, ip string) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid CIDR: %w", cidr, err)
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid IP address: %w", ip, err)
+	}
+	if addr.Is4() != prefix.Addr().Is4() {
+		return fmt.Errorf("%s and %s are of different address families", ip, cidr)
+	}
+	if !prefix.Contains(addr) {
+		return fmt.Errorf("%s is not within %s", ip, cidr)
+	}
+	return nil
+}
+
+// CIDRContainsHost is CIDRContainsIP for addresses assigned to hosts. In an
+// IPv4 subnet of more than two addresses, the first one is the network
+// address and the last one the broadcast address, so neither is accepted.
+func CIDRContainsHost(cidr, ip string) error {
+	if err := CIDRContainsIP(cidr, ip); err != nil {
+		return err
+	}
+	prefix := netip.MustParsePrefix(cidr).Masked()
+	if !prefix.Addr().Is4() || prefix.Bits() > 30 {
+		return nil
+	}
+	addr := netip.MustParseAddr(ip)
+	if addr == prefix.Addr() {
+		return fmt.Errorf("%s is the network address of %s", ip, cidr)
+	}
+	broadcast := prefix.Addr().As4()
+	last := binary.BigEndian.Uint32(broadcast[:]) | (1<<(32-prefix.Bits()) - 1)
+	binary.BigEndian.PutUint32(broadcast[:], last)
+	if addr == netip.AddrFrom4(broadcast) {
+		return fmt.Errorf("%s is the broadcast address of %s", ip, cidr)
+	}
+	return nil
+}
+
+// CIDRWithinAny returns an error unless cidr is fully contained by at least
+// one of ranges.
+func CIDRWithinAny(cidr string, ranges []string) error {
+	inner, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid CIDR: %w", cidr, err)
+	}
+	inner = inner.Masked()
+
+	for _, r := range ranges {
+		outer, err := netip.ParsePrefix(r)
+		if err != nil {
+			continue
+		}
+		if outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not within any of the ranges %v", cidr, ranges)
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringIsCIDRWithPrefixLength(t *testing.T) {
+	validate := StringIsCIDRWithPrefixLength(PrefixLengthRange{8, 29}, PrefixLengthRange{56, 64})
+
+	tests := map[string]bool{
+		"10.0.0.0/8":       true,
+		"192.168.0.0/29":   true,
+		"2001:db8::/56":    true,
+		"2001:db8::/64":    true,
+		"10.0.0.0/7":       false,
+		"192.168.0.0/30":   false,
+		"2001:db8::/48":    false,
+		"2001:db8::/65":    false,
+		"192.168.0.1/24":   false,
+		"not-a-cidr":       false,
+		"192.168.0.0":      false,
+		"2001:db8::1:0/64": false,
+	}
+
+	for input, valid := range tests {
+		_, errs := validate(input, "ip_ranges")
+		assert.Equal(t, valid, len(errs) == 0, "%s: %v", input, errs)
+	}
+}
+
+func TestStringIsCIDRWithPrefixLength_unsupportedFamily(t *testing.T) {
+	validate := StringIsCIDRWithPrefixLength(PrefixLengthRange{30, 31}, PrefixLengthRange{})
+
+	_, errs := validate("2001:db8::/127", "subnet")
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "IPv6 ranges are not supported")
+}
+
+func TestCIDRsOverlap(t *testing.T) {
+	assert.NoError(t, CIDRsOverlap([]string{"10.0.0.0/24", "10.0.1.0/24", "2001:db8::/64"}))
+	assert.NoError(t, CIDRsOverlap(nil))
+
+	err := CIDRsOverlap([]string{"10.0.0.0/16", "192.168.0.0/24", "10.0.5.0/24"})
+	assert.EqualError(t, err, "ranges 10.0.0.0/16 and 10.0.5.0/24 overlap")
+
+	assert.Error(t, CIDRsOverlap([]string{"10.0.0.0/16", "bogus"}))
+}
+
+func TestCIDRContainsIP(t *testing.T) {
+	assert.NoError(t, CIDRContainsIP("169.254.100.0/30", "169.254.100.1"))
+	assert.ErrorContains(t, CIDRContainsIP("169.254.100.0/30", "169.254.100.4"), "is not within")
+	assert.ErrorContains(t, CIDRContainsIP("169.254.100.0/30", "2001:db8::1"), "different address families")
+	assert.Error(t, CIDRContainsIP("169.254.100.0/30", "bogus"))
+}
+
+func TestCIDRContainsHost(t *testing.T) {
+	assert.NoError(t, CIDRContainsHost("169.254.100.0/30", "169.254.100.1"))
+	assert.NoError(t, CIDRContainsHost("169.254.100.0/30", "169.254.100.2"))
+	assert.ErrorContains(t, CIDRContainsHost("169.254.100.0/30", "169.254.100.0"), "network address")
+	assert.ErrorContains(t, CIDRContainsHost("169.254.100.0/30", "169.254.100.3"), "broadcast address")
+	assert.ErrorContains(t, CIDRContainsHost("10.0.0.0/23", "10.0.1.255"), "broadcast address")
+	assert.NoError(t, CIDRContainsHost("169.254.100.0/31", "169.254.100.0"), "both addresses of a /31 are hosts")
+	assert.NoError(t, CIDRContainsHost("169.254.100.0/31", "169.254.100.1"))
+	assert.ErrorContains(t, CIDRContainsHost("169.254.100.0/31", "169.254.100.2"), "is not within")
+}
+
+func TestCIDRWithinAny(t *testing.T) {
+	ranges := []string{"10.0.0.0/16", "2001:db8::/56"}
+
+	assert.NoError(t, CIDRWithinAny("10.0.4.0/30", ranges))
+	assert.NoError(t, CIDRWithinAny("10.0.0.0/16", ranges))
+	assert.NoError(t, CIDRWithinAny("2001:db8:0:10::/64", ranges))
+	assert.Error(t, CIDRWithinAny("10.0.0.0/15", ranges))
+	assert.Error(t, CIDRWithinAny("10.1.0.0/30", ranges))
+	assert.Error(t, CIDRWithinAny("bogus", ranges))
+}